@@ -6,16 +6,19 @@ import (
 	"log"
 
 	"github.com/joho/godotenv"
+	"github.com/shuvo-paul/uptimebot/internal/api"
 	authHandler "github.com/shuvo-paul/uptimebot/internal/auth/handler"
 	authRepository "github.com/shuvo-paul/uptimebot/internal/auth/repository"
 	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
 	"github.com/shuvo-paul/uptimebot/internal/config"
 	"github.com/shuvo-paul/uptimebot/internal/database"
 	"github.com/shuvo-paul/uptimebot/internal/database/migrations"
+	"github.com/shuvo-paul/uptimebot/internal/email"
 	uptimeHandler "github.com/shuvo-paul/uptimebot/internal/monitor/handler"
 	uptimeRepository "github.com/shuvo-paul/uptimebot/internal/monitor/repository"
 	uptimeService "github.com/shuvo-paul/uptimebot/internal/monitor/service"
 	notificationHandler "github.com/shuvo-paul/uptimebot/internal/notification/handler"
+	"github.com/shuvo-paul/uptimebot/internal/notification/provider"
 	notificationRepository "github.com/shuvo-paul/uptimebot/internal/notification/repository"
 	notificationService "github.com/shuvo-paul/uptimebot/internal/notification/service"
 	"github.com/shuvo-paul/uptimebot/internal/renderer"
@@ -26,11 +29,22 @@ import (
 var db *sql.DB
 
 type App struct {
-	AuthService     *authService.AuthService
-	SessionService  *authService.SessionService
-	UserHandler     *authHandler.UserHandler
-	TargetHandler   *uptimeHandler.TargetHandler
-	NotifierHandler *notificationHandler.NotifierHandler
+	AuthService        *authService.AuthService
+	SessionService     *authService.SessionService
+	APITokenService    *authService.APITokenService
+	UserHandler        *authHandler.UserHandler
+	APITokenHandler    *authHandler.APITokenHandler
+	TargetHandler      *uptimeHandler.TargetHandler
+	IncidentHandler    *uptimeHandler.IncidentHandler
+	MaintenanceHandler *uptimeHandler.MaintenanceHandler
+	StatusPageHandler  *uptimeHandler.StatusPageHandler
+	EscalationHandler  *uptimeHandler.EscalationHandler
+	BadgeHandler       *uptimeHandler.BadgeHandler
+	NotifierHandler    *notificationHandler.NotifierHandler
+	APIHandler         *api.Handler
+
+	dispatcher *notificationService.Dispatcher
+	escalator  *uptimeService.EscalationService
 }
 
 func NewApp() *App {
@@ -59,16 +73,59 @@ func NewApp() *App {
 	authService2 := authService.NewAuthService(userRepository)
 
 	sessionService := authService.NewSessionService(sessionRepository)
+
+	apiTokenRepository := authRepository.NewAPITokenRepository(db)
+	apiTokenService := authService.NewAPITokenService(apiTokenRepository)
+	apiTokenHandler := authHandler.NewAPITokenHandler(apiTokenService, flashStore)
+	apiTokenHandler.Template.List = templateRenderer.GetTemplate("pages:settings/tokens")
+
 	authHandler := authHandler.NewUserHandler(authService2, sessionService, flashStore)
 	authHandler.Template.Register = templateRenderer.GetTemplate("pages:register")
 	authHandler.Template.Login = templateRenderer.GetTemplate("pages:login")
 
+	newMailer, err := email.NewMailerFactory(&config.Email)
+	if err != nil {
+		log.Fatalf("Failed to configure email: %v", err)
+	}
+	emailSender, err := provider.NewEmailSender(newMailer, templates.TemplateFS)
+	if err != nil {
+		log.Fatalf("Failed to load email templates: %v", err)
+	}
+
+	if config.TelegramAPIURL != "" {
+		notificationService.TelegramAPIURL = config.TelegramAPIURL
+	}
+	if config.PagerDutyEventsURL != "" {
+		notificationService.PagerDutyEventsURL = config.PagerDutyEventsURL
+	}
+	if config.OpsgenieAPIURL != "" {
+		notificationService.OpsgenieAPIURL = config.OpsgenieAPIURL
+	}
+
 	notifierRepository := notificationRepository.NewNotifierRepository(db)
-	notifierService := notificationService.NewNotifierService(notifierRepository, nil)
-	notifierHandler := notificationHandler.NewNotifierHandler(notifierService)
+	outboxRepository := notificationRepository.NewOutboxRepository(db)
+	emailRecipientRepository := notificationRepository.NewEmailRecipientRepository(db)
+	emailRecipientService := notificationService.NewEmailRecipientService(emailRecipientRepository, emailSender, config.BaseURL)
+	notifierService := notificationService.NewNotifierService(notifierRepository, outboxRepository, emailRecipientService, config.BaseURL)
+	// Deliver notifications queued before the last shutdown as well as new ones
+	dispatcher := notificationService.NewDispatcher(notifierService)
+	dispatcher.Start()
 
 	targetRepository := uptimeRepository.NewTargetRepository(db)
-	targetService := uptimeService.NewTargetService(targetRepository, notifierService)
+	checkResultRepository := uptimeRepository.NewCheckResultRepository(db)
+	certificateRepository := uptimeRepository.NewCertificateRepository(db)
+	dnsAnswerRepository := uptimeRepository.NewDNSAnswerRepository(db)
+	incidentRepository := uptimeRepository.NewIncidentRepository(db)
+	incidentService := uptimeService.NewIncidentService(incidentRepository)
+	maintenanceRepository := uptimeRepository.NewMaintenanceRepository(db)
+	maintenanceService := uptimeService.NewMaintenanceService(maintenanceRepository)
+	targetService := uptimeService.NewTargetService(targetRepository, checkResultRepository, certificateRepository, dnsAnswerRepository, incidentService, maintenanceService, notifierService)
+	incidentService.OnAcknowledge = targetService.HandleIncidentAcknowledged
+	slaService := uptimeService.NewSLAService(targetRepository, checkResultRepository)
+
+	notifierHandler := notificationHandler.NewNotifierHandler(notifierService, emailRecipientService, targetService, flashStore)
+	notifierHandler.Template.List = templateRenderer.GetTemplate("pages:targets/notifiers")
+	notifierHandler.Template.Verified = templateRenderer.GetTemplate("pages:notifiers/verified")
 
 	// Initialize monitoring for existing targets
 	if err := targetService.InitializeMonitoring(); err != nil {
@@ -77,22 +134,63 @@ func NewApp() *App {
 	}
 
 	// Initialize target controller
-	targetHandler := uptimeHandler.NewTargetHandler(targetService, flashStore)
+	targetHandler := uptimeHandler.NewTargetHandler(targetService, slaService, flashStore)
 	targetHandler.Template.List = templateRenderer.GetTemplate("pages:targets/list")
 	targetHandler.Template.Create = templateRenderer.GetTemplate("pages:targets/create")
 	targetHandler.Template.Edit = templateRenderer.GetTemplate("pages:targets/edit")
 
+	incidentHandler := uptimeHandler.NewIncidentHandler(incidentService, flashStore)
+	incidentHandler.Template.List = templateRenderer.GetTemplate("pages:incidents/list")
+	incidentHandler.Template.Show = templateRenderer.GetTemplate("pages:incidents/show")
+
+	maintenanceHandler := uptimeHandler.NewMaintenanceHandler(maintenanceService, targetService, flashStore)
+	maintenanceHandler.Template.List = templateRenderer.GetTemplate("pages:targets/maintenance")
+
+	statusPageRepository := uptimeRepository.NewStatusPageRepository(db)
+	statusPageService := uptimeService.NewStatusPageService(statusPageRepository, targetRepository, checkResultRepository, incidentRepository, maintenanceService)
+	statusPageHandler := uptimeHandler.NewStatusPageHandler(statusPageService, targetService, flashStore)
+	statusPageHandler.Template.List = templateRenderer.GetTemplate("pages:status/list")
+	statusPageHandler.Template.Form = templateRenderer.GetTemplate("pages:status/form")
+	statusPageHandler.Template.Public = templateRenderer.GetTemplate("pages:status/public")
+
+	escalationRepository := uptimeRepository.NewEscalationRepository(db)
+	escalationService := uptimeService.NewEscalationService(escalationRepository, targetRepository, incidentRepository, maintenanceService, notifierService)
+	// Page the next step of escalation policies while outages go unacknowledged
+	escalationService.Start()
+	escalationHandler := uptimeHandler.NewEscalationHandler(escalationService, targetService, flashStore)
+	escalationHandler.Template.List = templateRenderer.GetTemplate("pages:escalations/list")
+	escalationHandler.Template.Form = templateRenderer.GetTemplate("pages:escalations/form")
+
+	badgeRepository := uptimeRepository.NewBadgeRepository(db)
+	badgeService := uptimeService.NewBadgeService(badgeRepository, targetRepository, checkResultRepository, maintenanceService)
+	badgeHandler := uptimeHandler.NewBadgeHandler(badgeService, targetService, flashStore)
+	badgeHandler.Template.Settings = templateRenderer.GetTemplate("pages:targets/badges")
+
+	apiHandler := api.NewHandler(targetService, incidentService, notifierService)
+
 	fmt.Println("app initialized")
 
 	return &App{
-		AuthService:     authService2,
-		SessionService:  sessionService,
-		UserHandler:     authHandler,
-		TargetHandler:   targetHandler,
-		NotifierHandler: notifierHandler,
+		AuthService:        authService2,
+		SessionService:     sessionService,
+		APITokenService:    apiTokenService,
+		UserHandler:        authHandler,
+		APITokenHandler:    apiTokenHandler,
+		TargetHandler:      targetHandler,
+		IncidentHandler:    incidentHandler,
+		MaintenanceHandler: maintenanceHandler,
+		StatusPageHandler:  statusPageHandler,
+		EscalationHandler:  escalationHandler,
+		BadgeHandler:       badgeHandler,
+		NotifierHandler:    notifierHandler,
+		APIHandler:         apiHandler,
+		dispatcher:         dispatcher,
+		escalator:          escalationService,
 	}
 }
 
 func (a *App) Close() {
+	a.escalator.Stop()
+	a.dispatcher.Stop()
 	db.Close()
 }
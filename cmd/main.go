@@ -16,7 +16,15 @@ func main() {
 		*app.SessionService,
 		*app.AuthService,
 		app.TargetHandler,
+		app.IncidentHandler,
+		app.MaintenanceHandler,
+		app.StatusPageHandler,
+		app.EscalationHandler,
+		app.BadgeHandler,
 		app.NotifierHandler,
+		app.APIHandler,
+		app.APITokenHandler,
+		app.APITokenService,
 	)
 
 	// Start server
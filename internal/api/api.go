@@ -0,0 +1,154 @@
+// Package api serves the versioned JSON API under /api/v1. It is a thin
+// layer over the same services the HTML handlers use.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	uptimeService "github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	notificationService "github.com/shuvo-paul/uptimebot/internal/notification/service"
+)
+
+// Pagination limits for list endpoints
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// maxBodySize bounds request bodies; targets with assertions are the largest
+const maxBodySize = 1 << 20
+
+// Error codes sent in error bodies, one per status the API answers with
+var errorCodes = map[int]string{
+	http.StatusBadRequest:          "bad_request",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusUnprocessableEntity: "validation_failed",
+	http.StatusInternalServerError: "internal_error",
+}
+
+type Handler struct {
+	targetService   uptimeService.TargetServiceInterface
+	incidentService uptimeService.IncidentServiceInterface
+	notifierService notificationService.NotifierServiceInterface
+}
+
+func NewHandler(
+	targetService uptimeService.TargetServiceInterface,
+	incidentService uptimeService.IncidentServiceInterface,
+	notifierService notificationService.NotifierServiceInterface,
+) *Handler {
+	return &Handler{
+		targetService:   targetService,
+		incidentService: incidentService,
+		notifierService: notifierService,
+	}
+}
+
+// errorBody is the body of every error response
+type errorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// listBody is the body of every list response
+type listBody[T any] struct {
+	Data       []T        `json:"data"`
+	Pagination pagination `json:"pagination"`
+}
+
+type pagination struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	var body errorBody
+	body.Error.Code = errorCodes[status]
+	body.Error.Message = message
+	writeJSON(w, status, body)
+}
+
+// decodeJSON reads the request body into v, rejecting unknown fields so
+// that typos don't pass silently
+func decodeJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return nil
+}
+
+// parsePagination reads ?limit= and ?offset=
+func parsePagination(r *http.Request) (pagination, error) {
+	page := pagination{Limit: DefaultLimit}
+
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return pagination{}, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+		page.Limit = limit
+	}
+
+	if value := r.URL.Query().Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return pagination{}, errors.New("offset must be zero or more")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+// writeList answers with one page of items. The services return whole
+// lists, so paging happens here.
+func writeList[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	page, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page.Total = len(items)
+	from := min(page.Offset, len(items))
+	to := min(from+page.Limit, len(items))
+
+	body := listBody[T]{Data: items[from:to], Pagination: page}
+	if body.Data == nil {
+		body.Data = []T{}
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// pathID reads a numeric ID from the path, answering 400 when it isn't one
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid "+name)
+		return 0, false
+	}
+	return id, true
+}
+
+// NotFound answers requests for paths the API does not have
+func (h *Handler) NotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, "No such endpoint")
+}
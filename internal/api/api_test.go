@@ -0,0 +1,259 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	notifierModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mock TargetService
+type mockTargetService struct {
+	createFunc          func(userID int, target *monitor.Target) (*monitor.Target, error)
+	updateFunc          func(target *monitor.Target) (*monitor.Target, error)
+	deleteFunc          func(id int) error
+	setEnabledFunc      func(id int, enabled bool) (*monitor.Target, error)
+	getAllByUserIDFunc  func(userID int) ([]*monitor.Target, error)
+	getCheckResultsFunc func(targetID int, from, to time.Time) ([]monitor.CheckResult, error)
+}
+
+func (m *mockTargetService) Create(userID int, target *monitor.Target) (*monitor.Target, error) {
+	return m.createFunc(userID, target)
+}
+
+func (m *mockTargetService) GetByID(id int) (*monitor.Target, error) {
+	return nil, nil
+}
+
+func (m *mockTargetService) GetAll() ([]*monitor.Target, error) {
+	return nil, nil
+}
+
+func (m *mockTargetService) GetAllByUserID(userID int) ([]*monitor.Target, error) {
+	return m.getAllByUserIDFunc(userID)
+}
+
+func (m *mockTargetService) Update(target *monitor.Target) (*monitor.Target, error) {
+	return m.updateFunc(target)
+}
+
+func (m *mockTargetService) Delete(id int) error {
+	return m.deleteFunc(id)
+}
+
+func (m *mockTargetService) SetEnabled(id int, enabled bool) (*monitor.Target, error) {
+	return m.setEnabledFunc(id, enabled)
+}
+
+func (m *mockTargetService) InitializeMonitoring() error {
+	return nil
+}
+
+func (m *mockTargetService) GetCheckResults(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+	return m.getCheckResultsFunc(targetID, from, to)
+}
+
+func (m *mockTargetService) GetCertificate(targetID int) (*model.TargetCertificate, error) {
+	return nil, nil
+}
+
+func (m *mockTargetService) Ping(token, event string, exitCode int) error {
+	return nil
+}
+
+// Mock IncidentService
+type mockIncidentService struct {
+	getAllByUserIDFunc func(userID int) ([]model.Incident, error)
+	getByIDFunc        func(id int) (*model.Incident, error)
+	getEventsFunc      func(id int) ([]model.IncidentEvent, error)
+}
+
+func (m *mockIncidentService) HandleStatusChange(target *monitor.Target, status string) (*model.Incident, error) {
+	return nil, nil
+}
+
+func (m *mockIncidentService) RecordCheckFailure(target *monitor.Target, result monitor.CheckResult) error {
+	return nil
+}
+
+func (m *mockIncidentService) RecordNotification(incident *model.Incident, message string) error {
+	return nil
+}
+
+func (m *mockIncidentService) GetAllByUserID(userID int) ([]model.Incident, error) {
+	return m.getAllByUserIDFunc(userID)
+}
+
+func (m *mockIncidentService) GetByID(id int) (*model.Incident, error) {
+	return m.getByIDFunc(id)
+}
+
+func (m *mockIncidentService) GetEvents(id int) ([]model.IncidentEvent, error) {
+	return m.getEventsFunc(id)
+}
+
+func (m *mockIncidentService) Acknowledge(id, userID int) error {
+	return nil
+}
+
+func (m *mockIncidentService) AddUpdate(id, userID int, message string) error {
+	return nil
+}
+
+// Mock NotifierService
+type mockNotifierService struct {
+	createFunc        func(notifier *notifierModel.Notifier) error
+	getFunc           func(id int64) (*notifierModel.Notifier, error)
+	updateFunc        func(id int, config json.RawMessage) (*notifierModel.Notifier, error)
+	deleteFunc        func(id int64) error
+	getByTargetIDFunc func(targetID int) ([]*notifierModel.Notifier, error)
+	updateFilterFunc  func(id int64, filter notifierModel.NotifierFilter) error
+}
+
+func (m *mockNotifierService) Create(notifier *notifierModel.Notifier) error {
+	return m.createFunc(notifier)
+}
+
+func (m *mockNotifierService) Get(id int64) (*notifierModel.Notifier, error) {
+	return m.getFunc(id)
+}
+
+func (m *mockNotifierService) Update(id int, config json.RawMessage) (*notifierModel.Notifier, error) {
+	return m.updateFunc(id, config)
+}
+
+func (m *mockNotifierService) Delete(id int64) error {
+	return m.deleteFunc(id)
+}
+
+func (m *mockNotifierService) GetByTargetID(targetID int) ([]*notifierModel.Notifier, error) {
+	return m.getByTargetIDFunc(targetID)
+}
+
+func (m *mockNotifierService) UpdateFilter(id int64, filter notifierModel.NotifierFilter) error {
+	return m.updateFilterFunc(id, filter)
+}
+
+func (m *mockNotifierService) Enqueue(targetID int, state notification.State) error {
+	return nil
+}
+
+func (m *mockNotifierService) EnqueueAcknowledgement(targetID int, state notification.State) error {
+	return nil
+}
+
+func (m *mockNotifierService) Escalate(notifierIDs []int64, state notification.State) error {
+	return nil
+}
+
+func (m *mockNotifierService) EscalateAcknowledgement(notifierIDs []int64, state notification.State) error {
+	return nil
+}
+
+func (m *mockNotifierService) GetDeliveries(notifierID int64, limit int) ([]*notifierModel.DeliveryAttempt, error) {
+	return nil, nil
+}
+
+func (m *mockNotifierService) SendTest(notifier *notifierModel.Notifier) error {
+	return nil
+}
+
+func (m *mockNotifierService) HandleSlackCallback(code string, targetID int) (*notifierModel.Notifier, error) {
+	return nil, nil
+}
+
+func (m *mockNotifierService) ParseOAuthState(state string) (int, error) {
+	return 0, nil
+}
+
+// ownedTargets makes the target service report the given targets as user 1's
+func ownedTargets(targets ...*monitor.Target) *mockTargetService {
+	return &mockTargetService{
+		getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
+			if userID != 1 {
+				return nil, nil
+			}
+			return targets, nil
+		},
+	}
+}
+
+func withUser(req *http.Request, id int) *http.Request {
+	return req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: id, Name: "Alice"}))
+}
+
+// decodeError reads an error body, failing the test if it is not one
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) errorBody {
+	t.Helper()
+	var body errorBody
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, errorCodes[w.Code], body.Error.Code)
+	return body
+}
+
+func TestWriteList(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		want     []int
+	}{
+		{name: "defaults", want: []int{1, 2, 3, 4, 5}},
+		{name: "first page", query: "?limit=2", want: []int{1, 2}},
+		{name: "last page", query: "?limit=2&offset=4", want: []int{5}},
+		{name: "past the end", query: "?offset=10", want: []int{}},
+		{name: "limit too large", query: "?limit=1000", wantCode: http.StatusBadRequest},
+		{name: "negative offset", query: "?offset=-1", wantCode: http.StatusBadRequest},
+		{name: "invalid limit", query: "?limit=all", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/items"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			writeList(w, req, items)
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, w.Code)
+				decodeError(t, w)
+				return
+			}
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var body listBody[int]
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, tt.want, body.Data)
+			assert.Equal(t, len(items), body.Pagination.Total)
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	var in struct {
+		Name string `json:"name"`
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "api"}`))
+	assert.NoError(t, decodeJSON(req, &in))
+	assert.Equal(t, "api", in.Name)
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"nmae": "api"}`))
+	assert.Error(t, decodeJSON(req, &in), "unknown fields are rejected")
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":`))
+	assert.Error(t, decodeJSON(req, &in))
+}
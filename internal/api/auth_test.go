@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSessionService struct {
+	sessions map[string]*authModel.Session
+}
+
+func (m *mockSessionService) CreateSession(userID int) (*authModel.Session, string, error) {
+	return nil, "", nil
+}
+
+func (m *mockSessionService) ValidateSession(token string) (*authModel.Session, error) {
+	session, ok := m.sessions[token]
+	if !ok {
+		return nil, fmt.Errorf("invalid session")
+	}
+	return session, nil
+}
+
+func (m *mockSessionService) DeleteSession(sessionID string) error {
+	return nil
+}
+
+type mockAuthService struct{}
+
+func (m *mockAuthService) CreateUser(user *authModel.User) (*authModel.User, error) {
+	return user, nil
+}
+
+func (m *mockAuthService) Authenticate(email, password string) (*authModel.User, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) GetUserByID(id int) (*authModel.User, error) {
+	return &authModel.User{ID: id}, nil
+}
+
+type mockAPITokenService struct {
+	tokens map[string]*authModel.APIToken
+}
+
+func (m *mockAPITokenService) Create(token *authModel.APIToken) (*authModel.APIToken, string, error) {
+	return token, "", nil
+}
+
+func (m *mockAPITokenService) GetAllByUserID(userID int) ([]*authModel.APIToken, error) {
+	return nil, nil
+}
+
+func (m *mockAPITokenService) Revoke(id, userID int) error {
+	return nil
+}
+
+func (m *mockAPITokenService) Authenticate(plainToken string) (*authModel.APIToken, error) {
+	token, ok := m.tokens[plainToken]
+	if !ok {
+		return nil, authService.ErrInvalidAPIToken
+	}
+	return token, nil
+}
+
+func TestRequireAuth(t *testing.T) {
+	sessions := &mockSessionService{sessions: map[string]*authModel.Session{"valid": {UserID: 7}}}
+	tokens := &mockAPITokenService{tokens: map[string]*authModel.APIToken{"smt_valid": {UserID: 7}}}
+	handler := RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := authService.GetUser(r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, 7, user.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}), sessions, &mockAuthService{}, tokens)
+
+	tests := []struct {
+		name          string
+		cookie        string
+		authorization string
+		wantCode      int
+	}{
+		{name: "valid session", cookie: "valid", wantCode: http.StatusNoContent},
+		{name: "expired session", cookie: "expired", wantCode: http.StatusUnauthorized},
+		{name: "no session", wantCode: http.StatusUnauthorized},
+		{name: "valid token", authorization: "Bearer smt_valid", wantCode: http.StatusNoContent},
+		{name: "revoked token", authorization: "Bearer smt_revoked", wantCode: http.StatusUnauthorized},
+		{name: "other scheme", authorization: "Basic dXNlcjpwYXNz", wantCode: http.StatusUnauthorized},
+		{name: "bad token with session", cookie: "valid", authorization: "Bearer smt_revoked", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/targets", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "session_token", Value: tt.cookie})
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+				decodeError(t, w)
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	handler := RequireScope(authModel.ScopeTargetsWrite, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name     string
+		token    *authModel.APIToken
+		wantCode int
+	}{
+		{name: "session", wantCode: http.StatusNoContent},
+		{name: "token with scope", token: &authModel.APIToken{Scopes: []string{authModel.ScopeTargetsRead, authModel.ScopeTargetsWrite}}, wantCode: http.StatusNoContent},
+		{name: "token without scope", token: &authModel.APIToken{Scopes: []string{authModel.ScopeTargetsRead}}, wantCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/targets", nil)
+			if tt.token != nil {
+				req = req.WithContext(context.WithValue(req.Context(), apiTokenKey, tt.token))
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestCSRF(t *testing.T) {
+	handler := CSRF(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	t.Run("cookie authentication needs a CSRF token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/targets", nil)
+		req.AddCookie(&http.Cookie{Name: "session_token", Value: "valid"})
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("bearer tokens skip the check", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/targets", nil)
+		req.Header.Set("Authorization", "Bearer smt_valid")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+)
+
+type incidentJSON struct {
+	ID             int                 `json:"id"`
+	TargetID       int                 `json:"target_id"`
+	TargetURL      string              `json:"target_url"`
+	Cause          string              `json:"cause"`
+	StartedAt      time.Time           `json:"started_at"`
+	AcknowledgedAt *time.Time          `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string              `json:"acknowledged_by,omitempty"`
+	ResolvedAt     *time.Time          `json:"resolved_at,omitempty"`
+	Events         []incidentEventJSON `json:"events,omitempty"` // only when fetching a single incident
+}
+
+type incidentEventJSON struct {
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"`
+	UserName  string    `json:"user_name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newIncidentJSON(incident model.Incident) incidentJSON {
+	out := incidentJSON{
+		ID:             incident.ID,
+		TargetID:       incident.TargetID,
+		TargetURL:      incident.TargetURL,
+		Cause:          incident.Cause,
+		StartedAt:      incident.StartedAt,
+		AcknowledgedBy: incident.AcknowledgedBy,
+	}
+	if !incident.AcknowledgedAt.IsZero() {
+		out.AcknowledgedAt = &incident.AcknowledgedAt
+	}
+	if !incident.ResolvedAt.IsZero() {
+		out.ResolvedAt = &incident.ResolvedAt
+	}
+	return out
+}
+
+// ListIncidents lists the incidents on the user's targets. ?state=open or
+// ?state=resolved narrows the list.
+func (h *Handler) ListIncidents(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	if state != "" && state != "open" && state != "resolved" {
+		writeError(w, http.StatusBadRequest, "state must be open or resolved")
+		return
+	}
+
+	incidents, err := h.incidentService.GetAllByUserID(user.ID)
+	if err != nil {
+		slog.Error("Failed to fetch incidents", "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch incidents")
+		return
+	}
+
+	out := make([]incidentJSON, 0, len(incidents))
+	for _, incident := range incidents {
+		resolved := !incident.ResolvedAt.IsZero()
+		if (state == "open" && resolved) || (state == "resolved" && !resolved) {
+			continue
+		}
+		out = append(out, newIncidentJSON(incident))
+	}
+	writeList(w, r, out)
+}
+
+// GetIncident shows one of the user's incidents with its timeline
+func (h *Handler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	incident, err := h.incidentService.GetByID(id)
+	if err != nil || incident.UserID != user.ID {
+		writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+
+	events, err := h.incidentService.GetEvents(incident.ID)
+	if err != nil {
+		slog.Error("Failed to fetch incident events", "id", incident.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch incident")
+		return
+	}
+
+	out := newIncidentJSON(*incident)
+	out.Events = make([]incidentEventJSON, len(events))
+	for i, event := range events {
+		out.Events[i] = incidentEventJSON{
+			Kind:      event.Kind,
+			Message:   event.Message,
+			UserName:  event.UserName,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ListIncidents(t *testing.T) {
+	startedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	incidentService := &mockIncidentService{
+		getAllByUserIDFunc: func(userID int) ([]model.Incident, error) {
+			assert.Equal(t, 1, userID)
+			return []model.Incident{
+				{ID: 2, TargetID: 1, TargetURL: "https://example.com", StartedAt: startedAt},
+				{ID: 1, TargetID: 1, TargetURL: "https://example.com", StartedAt: startedAt.Add(-time.Hour), ResolvedAt: startedAt.Add(-time.Minute)},
+			}, nil
+		},
+	}
+	handler := NewHandler(&mockTargetService{}, incidentService, &mockNotifierService{})
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantIDs  []int
+	}{
+		{name: "all", wantCode: http.StatusOK, wantIDs: []int{2, 1}},
+		{name: "open", query: "?state=open", wantCode: http.StatusOK, wantIDs: []int{2}},
+		{name: "resolved", query: "?state=resolved", wantCode: http.StatusOK, wantIDs: []int{1}},
+		{name: "unknown state", query: "?state=forgotten", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodGet, "/incidents"+tt.query, nil), 1)
+			w := httptest.NewRecorder()
+
+			handler.ListIncidents(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode != http.StatusOK {
+				decodeError(t, w)
+				return
+			}
+
+			var body listBody[incidentJSON]
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			var ids []int
+			for _, incident := range body.Data {
+				ids = append(ids, incident.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestHandler_GetIncident(t *testing.T) {
+	incidentService := &mockIncidentService{
+		getByIDFunc: func(id int) (*model.Incident, error) {
+			if id != 1 {
+				return nil, errors.New("incident not found")
+			}
+			return &model.Incident{ID: 1, UserID: 1, TargetID: 1, StartedAt: time.Now()}, nil
+		},
+		getEventsFunc: func(id int) ([]model.IncidentEvent, error) {
+			return []model.IncidentEvent{
+				{Kind: model.IncidentOpened, Message: "HTTP error: 502"},
+				{Kind: model.IncidentUpdate, Message: "Rolling back", UserName: "Alice"},
+			}, nil
+		},
+	}
+	handler := NewHandler(&mockTargetService{}, incidentService, &mockNotifierService{})
+
+	tests := []struct {
+		name     string
+		id       string
+		userID   int
+		wantCode int
+	}{
+		{name: "own incident", id: "1", userID: 1, wantCode: http.StatusOK},
+		{name: "other user's incident", id: "1", userID: 2, wantCode: http.StatusNotFound},
+		{name: "unknown incident", id: "9", userID: 1, wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodGet, "/incidents/"+tt.id, nil), tt.userID)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.GetIncident(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode != http.StatusOK {
+				decodeError(t, w)
+				return
+			}
+
+			var body incidentJSON
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Nil(t, body.ResolvedAt)
+			if assert.Len(t, body.Events, 2) {
+				assert.Equal(t, "Alice", body.Events[1].UserName)
+			}
+		})
+	}
+}
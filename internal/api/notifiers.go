@@ -0,0 +1,199 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+)
+
+// notifierInput is the part of a notifier clients can set. The type cannot
+// be changed once created.
+type notifierInput struct {
+	Type   model.NotifierType   `json:"type"`
+	Config json.RawMessage      `json:"config"`
+	Filter model.NotifierFilter `json:"filter"`
+}
+
+type notifierJSON struct {
+	ID       int64                `json:"id"`
+	TargetID int                  `json:"target_id"`
+	Type     model.NotifierType   `json:"type"`
+	Config   json.RawMessage      `json:"config"`
+	Filter   model.NotifierFilter `json:"filter"`
+}
+
+// newNotifierJSON returns the notifier without its credential, which is
+// only shown in the response creating it
+func newNotifierJSON(notifier *model.Notifier) notifierJSON {
+	config, err := notifier.RedactedConfig()
+	if err != nil {
+		slog.Error("Failed to redact notifier config", "id", notifier.ID, "error", err)
+		config = nil
+	}
+
+	return notifierJSON{
+		ID:       notifier.ID,
+		TargetID: notifier.TargetId,
+		Type:     notifier.Type,
+		Config:   config,
+		Filter:   notifier.Filter,
+	}
+}
+
+// ListNotifiers lists the notifiers attached to one of the user's targets
+func (h *Handler) ListNotifiers(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.userTarget(w, r)
+	if !ok {
+		return
+	}
+
+	notifiers, err := h.notifierService.GetByTargetID(target.ID)
+	if err != nil {
+		slog.Error("Failed to fetch notifiers", "target", target.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch notifiers")
+		return
+	}
+
+	out := make([]notifierJSON, len(notifiers))
+	for i, notifier := range notifiers {
+		out[i] = newNotifierJSON(notifier)
+	}
+	writeList(w, r, out)
+}
+
+// CreateNotifier attaches a notifier to one of the user's targets
+func (h *Handler) CreateNotifier(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.userTarget(w, r)
+	if !ok {
+		return
+	}
+
+	var in notifierInput
+	if err := decodeJSON(r, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	notifier := &model.Notifier{
+		TargetId: target.ID,
+		Type:     in.Type,
+		Config:   in.Config,
+		Filter:   in.Filter,
+	}
+	if err := notifier.Validate(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err := h.notifierService.Create(notifier); err != nil {
+		slog.Error("Failed to create notifier", "target", target.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to create notifier")
+		return
+	}
+
+	// The caller needs the generated webhook secret to verify deliveries
+	out := newNotifierJSON(notifier)
+	out.Config = notifier.Config
+	writeJSON(w, http.StatusCreated, out)
+}
+
+// GetNotifier shows one of the user's notifiers
+func (h *Handler) GetNotifier(w http.ResponseWriter, r *http.Request) {
+	notifier, ok := h.userNotifier(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, newNotifierJSON(notifier))
+}
+
+// UpdateNotifier replaces a notifier's configuration and filter
+func (h *Handler) UpdateNotifier(w http.ResponseWriter, r *http.Request) {
+	notifier, ok := h.userNotifier(w, r)
+	if !ok {
+		return
+	}
+
+	var in notifierInput
+	if err := decodeJSON(r, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if in.Type != "" && in.Type != notifier.Type {
+		writeError(w, http.StatusUnprocessableEntity, "The type of a notifier cannot be changed")
+		return
+	}
+
+	previous := notifier.Config
+	notifier.Config = in.Config
+	notifier.Filter = in.Filter
+	if err := notifier.KeepSecrets(previous); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err := notifier.Validate(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	updated, err := h.notifierService.Update(int(notifier.ID), notifier.Config)
+	if err == nil {
+		err = h.notifierService.UpdateFilter(notifier.ID, notifier.Filter)
+	}
+	if err != nil {
+		slog.Error("Failed to update notifier", "id", notifier.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to update notifier")
+		return
+	}
+
+	updated.Filter = notifier.Filter
+	writeJSON(w, http.StatusOK, newNotifierJSON(updated))
+}
+
+// DeleteNotifier detaches a notifier from its target
+func (h *Handler) DeleteNotifier(w http.ResponseWriter, r *http.Request) {
+	notifier, ok := h.userNotifier(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.notifierService.Delete(notifier.ID); err != nil {
+		slog.Error("Failed to delete notifier", "id", notifier.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to delete notifier")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userNotifier loads the notifier named in the path, answering 404 unless
+// its target belongs to the current user
+func (h *Handler) userNotifier(w http.ResponseWriter, r *http.Request) (*model.Notifier, bool) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return nil, false
+	}
+
+	notifier, err := h.notifierService.Get(int64(id))
+	if err != nil {
+		slog.Error("Failed to fetch notifier", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch notifier")
+		return nil, false
+	}
+	if notifier == nil {
+		writeError(w, http.StatusNotFound, "Notifier not found")
+		return nil, false
+	}
+
+	if _, ok := h.ownedTarget(w, user, notifier.TargetId); !ok {
+		return nil, false
+	}
+
+	return notifier, true
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_CreateNotifier(t *testing.T) {
+	var created *model.Notifier
+	notifierService := &mockNotifierService{
+		createFunc: func(notifier *model.Notifier) error {
+			notifier.ID = 3
+			created = notifier
+			return nil
+		},
+	}
+	handler := NewHandler(ownedTargets(&monitor.Target{ID: 1}), &mockIncidentService{}, notifierService)
+
+	tests := []struct {
+		name     string
+		target   string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "slack",
+			target:   "1",
+			body:     `{"type": "slack", "config": {"webhook_url": "https://hooks.slack.com/test"}, "filter": {"statuses": ["down"]}}`,
+			wantCode: http.StatusCreated,
+		},
+		{name: "missing webhook URL", target: "1", body: `{"type": "slack", "config": {}}`, wantCode: http.StatusUnprocessableEntity},
+		{name: "unknown type", target: "1", body: `{"type": "pigeon", "config": {}}`, wantCode: http.StatusUnprocessableEntity},
+		{name: "other user's target", target: "2", body: `{"type": "slack", "config": {"webhook_url": "https://hooks.slack.com/test"}}`, wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodPost, "/targets/"+tt.target+"/notifiers", strings.NewReader(tt.body)), 1)
+			req.SetPathValue("id", tt.target)
+			w := httptest.NewRecorder()
+
+			handler.CreateNotifier(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode != http.StatusCreated {
+				decodeError(t, w)
+				return
+			}
+
+			var body notifierJSON
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, int64(3), body.ID)
+			assert.Equal(t, 1, created.TargetId)
+			assert.Equal(t, []string{"down"}, created.Filter.Statuses)
+		})
+	}
+}
+
+func TestHandler_UpdateNotifier(t *testing.T) {
+	notifiers := map[int64]*model.Notifier{
+		3: {ID: 3, TargetId: 1, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/old"}`)},
+		4: {ID: 4, TargetId: 2, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/other"}`)},
+		5: {ID: 5, TargetId: 1, Type: model.NotifierTypeWebhook, Config: json.RawMessage(`{"url": "https://example.com/old", "secret": "s3cret"}`)},
+		6: {ID: 6, TargetId: 1, Type: model.NotifierTypeTelegram, Config: json.RawMessage(`{"bot_token": "123456:ABC-def", "chat_id": "-100123"}`)},
+	}
+	var filter model.NotifierFilter
+	var stored json.RawMessage
+	notifierService := &mockNotifierService{
+		getFunc: func(id int64) (*model.Notifier, error) {
+			notifier, ok := notifiers[id]
+			if !ok {
+				return nil, nil
+			}
+			copied := *notifier
+			return &copied, nil
+		},
+		updateFunc: func(id int, config json.RawMessage) (*model.Notifier, error) {
+			notifier := *notifiers[int64(id)]
+			notifier.Config = config
+			stored = config
+			return &notifier, nil
+		},
+		updateFilterFunc: func(id int64, f model.NotifierFilter) error {
+			filter = f
+			return nil
+		},
+	}
+	handler := NewHandler(ownedTargets(&monitor.Target{ID: 1}), &mockIncidentService{}, notifierService)
+
+	tests := []struct {
+		name     string
+		id       string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "config and filter",
+			id:       "3",
+			body:     `{"config": {"webhook_url": "https://hooks.slack.com/new"}, "filter": {"statuses": ["down", "up"]}}`,
+			wantCode: http.StatusOK,
+		},
+		{name: "changing the type", id: "3", body: `{"type": "email", "config": {"recipients": ["ops@example.com"]}}`, wantCode: http.StatusUnprocessableEntity},
+		{name: "unknown status", id: "3", body: `{"config": {"webhook_url": "https://hooks.slack.com/new"}, "filter": {"statuses": ["sideways"]}}`, wantCode: http.StatusUnprocessableEntity},
+		{name: "invalid quiet hours", id: "3", body: `{"config": {"webhook_url": "https://hooks.slack.com/new"}, "filter": {"quiet_hours": {"start": "22:00", "end": "07:00", "timezone": "Mars/Olympus"}}}`, wantCode: http.StatusUnprocessableEntity},
+		{name: "other user's notifier", id: "4", body: `{"config": {"webhook_url": "https://hooks.slack.com/new"}}`, wantCode: http.StatusNotFound},
+		{name: "unknown notifier", id: "9", body: `{"config": {"webhook_url": "https://hooks.slack.com/new"}}`, wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodPut, "/notifiers/"+tt.id, strings.NewReader(tt.body)), 1)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.UpdateNotifier(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode != http.StatusOK {
+				decodeError(t, w)
+				return
+			}
+
+			var body notifierJSON
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.JSONEq(t, `{"webhook_url": "https://hooks.slack.com/new"}`, string(body.Config))
+			assert.Equal(t, []string{"down", "up"}, body.Filter.Statuses)
+			assert.Equal(t, []string{"down", "up"}, filter.Statuses)
+		})
+	}
+
+	secrets := []struct {
+		name       string
+		id         string
+		body       string
+		wantStored string
+		wantConfig string
+	}{
+		{
+			name:       "webhook keeps its secret",
+			id:         "5",
+			body:       `{"config": {"url": "https://example.com/new"}}`,
+			wantStored: `{"url": "https://example.com/new", "secret": "s3cret"}`,
+			wantConfig: `{"url": "https://example.com/new"}`,
+		},
+		{
+			name:       "telegram keeps its bot token",
+			id:         "6",
+			body:       `{"config": {"chat_id": "@uptime_alerts"}}`,
+			wantStored: `{"bot_token": "123456:ABC-def", "chat_id": "@uptime_alerts"}`,
+			wantConfig: `{"chat_id": "@uptime_alerts"}`,
+		},
+		{
+			name:       "telegram bot token replaced",
+			id:         "6",
+			body:       `{"config": {"bot_token": "654321:XYZ-abc", "chat_id": "-100123"}}`,
+			wantStored: `{"bot_token": "654321:XYZ-abc", "chat_id": "-100123"}`,
+			wantConfig: `{"chat_id": "-100123"}`,
+		},
+	}
+
+	for _, tt := range secrets {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodPut, "/notifiers/"+tt.id, strings.NewReader(tt.body)), 1)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.UpdateNotifier(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.wantStored, string(stored))
+			var body notifierJSON
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.JSONEq(t, tt.wantConfig, string(body.Config))
+		})
+	}
+}
+
+func TestHandler_GetNotifier(t *testing.T) {
+	notifiers := map[int64]*model.Notifier{
+		3: {ID: 3, TargetId: 1, Type: model.NotifierTypeWebhook, Config: json.RawMessage(`{"url": "https://example.com/hook", "secret": "s3cret"}`)},
+		4: {ID: 4, TargetId: 1, Type: model.NotifierTypePagerDuty, Config: json.RawMessage(`{"routing_key": "0123456789abcdef0123456789abcdef"}`)},
+		5: {ID: 5, TargetId: 1, Type: model.NotifierTypeOpsgenie, Config: json.RawMessage(`{"api_key": "opsgenie-key"}`)},
+		6: {ID: 6, TargetId: 1, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`)},
+	}
+	notifierService := &mockNotifierService{
+		getFunc: func(id int64) (*model.Notifier, error) {
+			return notifiers[id], nil
+		},
+	}
+	handler := NewHandler(ownedTargets(&monitor.Target{ID: 1}), &mockIncidentService{}, notifierService)
+
+	tests := []struct {
+		id         string
+		wantConfig string
+	}{
+		{id: "3", wantConfig: `{"url": "https://example.com/hook"}`},
+		{id: "4", wantConfig: `{}`},
+		{id: "5", wantConfig: `{}`},
+		{id: "6", wantConfig: `{"webhook_url": "https://hooks.slack.com/test"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodGet, "/notifiers/"+tt.id, nil), 1)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.GetNotifier(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var body notifierJSON
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.JSONEq(t, tt.wantConfig, string(body.Config))
+		})
+	}
+}
+
+func TestHandler_DeleteNotifier(t *testing.T) {
+	var deleted int64
+	notifierService := &mockNotifierService{
+		getFunc: func(id int64) (*model.Notifier, error) {
+			return &model.Notifier{ID: id, TargetId: 1}, nil
+		},
+		deleteFunc: func(id int64) error {
+			deleted = id
+			return nil
+		},
+	}
+	handler := NewHandler(ownedTargets(&monitor.Target{ID: 1}), &mockIncidentService{}, notifierService)
+
+	req := withUser(httptest.NewRequest(http.MethodDelete, "/notifiers/3", nil), 1)
+	req.SetPathValue("id", "3")
+	w := httptest.NewRecorder()
+
+	handler.DeleteNotifier(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, int64(3), deleted)
+}
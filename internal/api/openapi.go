@@ -0,0 +1,17 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPI describes the API for client generators
+//
+//go:embed openapi.json
+var openAPI []byte
+
+// OpenAPI serves the OpenAPI document, which is public
+func (h *Handler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPI)
+}
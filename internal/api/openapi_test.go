@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_OpenAPI(t *testing.T) {
+	handler := NewHandler(&mockTargetService{}, &mockIncidentService{}, &mockNotifierService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+
+	handler.OpenAPI(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var doc map[string]any
+	if !assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc)) {
+		return
+	}
+	assert.Equal(t, "3.0.3", doc["openapi"])
+
+	// Every operation the API serves is documented
+	paths := doc["paths"].(map[string]any)
+	for path, methods := range map[string][]string{
+		"/targets":                {"get", "post"},
+		"/targets/{id}":           {"get", "put", "delete"},
+		"/targets/{id}/pause":     {"post"},
+		"/targets/{id}/resume":    {"post"},
+		"/targets/{id}/results":   {"get"},
+		"/targets/{id}/notifiers": {"get", "post"},
+		"/notifiers/{id}":         {"get", "put", "delete"},
+		"/incidents":              {"get"},
+		"/incidents/{id}":         {"get"},
+	} {
+		operations, ok := paths[path].(map[string]any)
+		if !assert.True(t, ok, path) {
+			continue
+		}
+		for _, method := range methods {
+			if !assert.Contains(t, operations, method, path) {
+				continue
+			}
+			// and names the scope an API token needs for it
+			scope, _ := operations[method].(map[string]any)["x-scope"].(string)
+			assert.Contains(t, authModel.Scopes, scope, method+" "+path)
+		}
+	}
+
+	// Every reference resolves
+	var check func(v any)
+	check = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				assert.True(t, resolves(doc, ref), ref)
+			}
+			for _, child := range v {
+				check(child)
+			}
+		case []any:
+			for _, child := range v {
+				check(child)
+			}
+		}
+	}
+	check(doc)
+}
+
+// resolves reports whether a local reference such as
+// #/components/schemas/Target points at something in doc
+func resolves(doc map[string]any, ref string) bool {
+	var node any = doc
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return false
+		}
+		if node, ok = m[part]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,366 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+)
+
+// DefaultResultsWindow is how far back check results are listed when no
+// ?from= is given
+const DefaultResultsWindow = 24 * time.Hour
+
+// targetInput is the part of a target clients can set
+type targetInput struct {
+	Kind               string              `json:"kind"`
+	URL                string              `json:"url"`
+	IntervalSeconds    int                 `json:"interval_seconds"`
+	Request            monitor.RequestSpec `json:"request"`
+	Assertions         monitor.Assertions  `json:"assertions"`
+	LatencyThresholdMs int64               `json:"latency_threshold_ms"`
+	ConfirmAfter       int                 `json:"confirm_after"`
+	RecoverAfter       int                 `json:"recover_after"`
+	TLS                monitor.TLSSettings `json:"tls"`
+	TCP                monitor.TCPSpec     `json:"tcp"`
+	DNS                monitor.DNSSpec     `json:"dns"`
+	Heartbeat          heartbeatJSON       `json:"heartbeat"`
+}
+
+type heartbeatJSON struct {
+	GraceSeconds int    `json:"grace_seconds"`
+	PingURL      string `json:"ping_url,omitempty"` // read-only
+}
+
+// targetJSON is a target as the API returns it
+type targetJSON struct {
+	ID int `json:"id"`
+	targetInput
+	Status          string     `json:"status"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	Enabled         bool       `json:"enabled"`
+}
+
+type checkResultJSON struct {
+	ID          int       `json:"id"`
+	Status      string    `json:"status"`
+	CheckedAt   time.Time `json:"checked_at"`
+	LatencyMs   int64     `json:"latency_ms"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	ErrorClass  string    `json:"error_class,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	BytesRead   int64     `json:"bytes_read,omitempty"`
+	Maintenance bool      `json:"maintenance"`
+}
+
+func newTargetJSON(r *http.Request, target *monitor.Target) targetJSON {
+	out := targetJSON{
+		ID: target.ID,
+		targetInput: targetInput{
+			Kind:               target.GetKind(),
+			URL:                target.URL,
+			IntervalSeconds:    int(target.Interval / time.Second),
+			Request:            target.Request,
+			Assertions:         target.Assertions,
+			LatencyThresholdMs: target.LatencyThreshold.Milliseconds(),
+			ConfirmAfter:       target.ConfirmAfter,
+			RecoverAfter:       target.RecoverAfter,
+			TLS:                target.TLS,
+			TCP:                target.TCP,
+			DNS:                target.DNS,
+			Heartbeat:          heartbeatJSON{GraceSeconds: target.Heartbeat.GraceSeconds},
+		},
+		Status:       target.Status,
+		StatusReason: target.StatusReason,
+		Enabled:      target.Enabled,
+	}
+	// Stored credentials are write-only
+	out.Request.Auth = target.Request.Auth.Redacted()
+	if !target.StatusChangedAt.IsZero() {
+		out.StatusChangedAt = &target.StatusChangedAt
+	}
+	if target.Heartbeat.Token != "" {
+		out.Heartbeat.PingURL = baseURL(r) + "/ping/" + target.Heartbeat.Token
+	}
+	return out
+}
+
+// apply copies the input onto target and checks the result can be monitored
+func (in targetInput) apply(target *monitor.Target) error {
+	if in.URL == "" {
+		return errors.New("url is required")
+	}
+	if in.IntervalSeconds < 1 {
+		return errors.New("interval_seconds must be at least 1")
+	}
+	if in.LatencyThresholdMs < 0 {
+		return errors.New("latency_threshold_ms cannot be negative")
+	}
+	if in.Heartbeat.GraceSeconds < 0 {
+		return errors.New("heartbeat.grace_seconds cannot be negative")
+	}
+
+	target.Kind = in.Kind
+	target.URL = in.URL
+	target.Interval = time.Duration(in.IntervalSeconds) * time.Second
+	target.Request = in.Request
+	target.Assertions = in.Assertions
+	target.LatencyThreshold = time.Duration(in.LatencyThresholdMs) * time.Millisecond
+	target.ConfirmAfter = in.ConfirmAfter
+	target.RecoverAfter = in.RecoverAfter
+	target.TLS = in.TLS
+	target.TCP = in.TCP
+	target.DNS = in.DNS
+	// The ping token is assigned by the service and kept across updates
+	target.Heartbeat.GraceSeconds = in.Heartbeat.GraceSeconds
+
+	return target.Validate()
+}
+
+// ListTargets lists the user's targets
+func (h *Handler) ListTargets(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	targets, err := h.targetService.GetAllByUserID(user.ID)
+	if err != nil {
+		slog.Error("Failed to fetch targets", "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch targets")
+		return
+	}
+
+	out := make([]targetJSON, len(targets))
+	for i, target := range targets {
+		out[i] = newTargetJSON(r, target)
+	}
+	writeList(w, r, out)
+}
+
+// CreateTarget adds a target and starts monitoring it
+func (h *Handler) CreateTarget(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var in targetInput
+	if err := decodeJSON(r, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	target := &monitor.Target{}
+	if err := in.apply(target); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	created, err := h.targetService.Create(user.ID, target)
+	if err != nil {
+		slog.Error("Failed to create target", "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to create target")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newTargetJSON(r, created))
+}
+
+// GetTarget shows one of the user's targets
+func (h *Handler) GetTarget(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.userTarget(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, newTargetJSON(r, target))
+}
+
+// UpdateTarget replaces a target's configuration. Whether it is paused is
+// left alone; see PauseTarget and ResumeTarget.
+func (h *Handler) UpdateTarget(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.userTarget(w, r)
+	if !ok {
+		return
+	}
+
+	var in targetInput
+	if err := decodeJSON(r, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Credentials are never returned, so leaving them out keeps them
+	in.Request.Auth = in.Request.Auth.KeepSecrets(target.Request.Auth)
+	if err := in.apply(target); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	updated, err := h.targetService.Update(target)
+	if err != nil {
+		slog.Error("Failed to update target", "id", target.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to update target")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTargetJSON(r, updated))
+}
+
+// DeleteTarget stops monitoring a target and removes it
+func (h *Handler) DeleteTarget(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.userTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.targetService.Delete(target.ID); err != nil {
+		slog.Error("Failed to delete target", "id", target.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to delete target")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PauseTarget stops checking a target until it is resumed
+func (h *Handler) PauseTarget(w http.ResponseWriter, r *http.Request) {
+	h.setEnabled(w, r, false)
+}
+
+// ResumeTarget starts checking a paused target again
+func (h *Handler) ResumeTarget(w http.ResponseWriter, r *http.Request) {
+	h.setEnabled(w, r, true)
+}
+
+func (h *Handler) setEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	target, ok := h.userTarget(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.targetService.SetEnabled(target.ID, enabled)
+	if err != nil {
+		slog.Error("Failed to pause or resume target", "id", target.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to update target")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTargetJSON(r, updated))
+}
+
+// ListCheckResults lists a target's check results, newest first. The period
+// is given as RFC 3339 ?from= and ?to= and defaults to the last 24 hours.
+func (h *Handler) ListCheckResults(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.userTarget(w, r)
+	if !ok {
+		return
+	}
+
+	to := time.Now()
+	if value := r.URL.Query().Get("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid to, expected an RFC 3339 time")
+			return
+		}
+		to = parsed
+	}
+
+	from := to.Add(-DefaultResultsWindow)
+	if value := r.URL.Query().Get("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid from, expected an RFC 3339 time")
+			return
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		writeError(w, http.StatusBadRequest, "from must be before to")
+		return
+	}
+
+	results, err := h.targetService.GetCheckResults(target.ID, from, to)
+	if err != nil {
+		slog.Error("Failed to fetch check results", "id", target.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch check results")
+		return
+	}
+
+	out := make([]checkResultJSON, len(results))
+	for i, result := range results {
+		out[len(results)-1-i] = checkResultJSON{
+			ID:          result.ID,
+			Status:      result.Status,
+			CheckedAt:   result.CheckedAt,
+			LatencyMs:   result.Latency.Milliseconds(),
+			StatusCode:  result.StatusCode,
+			ErrorClass:  result.ErrorClass,
+			Reason:      result.Reason,
+			BytesRead:   result.BytesRead,
+			Maintenance: result.Maintenance,
+		}
+	}
+	writeList(w, r, out)
+}
+
+// userTarget loads the target named in the path, answering 404 unless it
+// belongs to the current user
+func (h *Handler) userTarget(w http.ResponseWriter, r *http.Request) (*monitor.Target, bool) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return nil, false
+	}
+
+	return h.ownedTarget(w, user, id)
+}
+
+// ownedTarget answers 404 unless the target belongs to the user
+func (h *Handler) ownedTarget(w http.ResponseWriter, user *model.User, id int) (*monitor.Target, bool) {
+	targets, err := h.targetService.GetAllByUserID(user.ID)
+	if err != nil {
+		slog.Error("Failed to fetch targets", "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch targets")
+		return nil, false
+	}
+
+	index := slices.IndexFunc(targets, func(target *monitor.Target) bool { return target.ID == id })
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "Target not found")
+		return nil, false
+	}
+
+	return targets[index], true
+}
+
+func currentUser(w http.ResponseWriter, r *http.Request) (*model.User, bool) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Authentication required")
+		return nil, false
+	}
+	return user, true
+}
+
+// baseURL returns the scheme and host the current request was made to
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, r.Host)
+}
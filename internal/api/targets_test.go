@@ -0,0 +1,282 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ListTargets(t *testing.T) {
+	targetService := ownedTargets(
+		&monitor.Target{ID: 1, URL: "https://example.com", Status: "up", Enabled: true, Interval: 30 * time.Second},
+		&monitor.Target{ID: 2, Kind: monitor.KindHeartbeat, URL: "nightly backup", Status: "pending", Interval: time.Hour, Heartbeat: monitor.HeartbeatSpec{Token: "secret", GraceSeconds: 60}},
+	)
+	handler := NewHandler(targetService, &mockIncidentService{}, &mockNotifierService{})
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/targets?limit=1&offset=1", nil), 1)
+	w := httptest.NewRecorder()
+
+	handler.ListTargets(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body listBody[targetJSON]
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, pagination{Limit: 1, Offset: 1, Total: 2}, body.Pagination)
+	if assert.Len(t, body.Data, 1) {
+		target := body.Data[0]
+		assert.Equal(t, 2, target.ID)
+		assert.Equal(t, monitor.KindHeartbeat, target.Kind)
+		assert.Equal(t, 3600, target.IntervalSeconds)
+		assert.False(t, target.Enabled)
+		assert.Equal(t, "http://example.com/ping/secret", target.Heartbeat.PingURL)
+	}
+}
+
+func TestHandler_CreateTarget(t *testing.T) {
+	var created *monitor.Target
+	targetService := &mockTargetService{
+		createFunc: func(userID int, target *monitor.Target) (*monitor.Target, error) {
+			assert.Equal(t, 1, userID)
+			target.ID = 5
+			target.Status = "pending"
+			target.Enabled = true
+			created = target
+			return target, nil
+		},
+	}
+	handler := NewHandler(targetService, &mockIncidentService{}, &mockNotifierService{})
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "valid target",
+			body:     `{"url": "https://example.com", "interval_seconds": 60, "request": {"method": "HEAD"}, "latency_threshold_ms": 500, "confirm_after": 3}`,
+			wantCode: http.StatusCreated,
+		},
+		{name: "malformed JSON", body: `{"url": `, wantCode: http.StatusBadRequest},
+		{name: "unknown field", body: `{"url": "https://example.com", "interval": 60}`, wantCode: http.StatusBadRequest},
+		{name: "missing URL", body: `{"interval_seconds": 60}`, wantCode: http.StatusUnprocessableEntity},
+		{name: "missing interval", body: `{"url": "https://example.com"}`, wantCode: http.StatusUnprocessableEntity},
+		{name: "invalid method", body: `{"url": "https://example.com", "interval_seconds": 60, "request": {"method": "BREW"}}`, wantCode: http.StatusUnprocessableEntity},
+		{name: "too many confirmations", body: `{"url": "https://example.com", "interval_seconds": 60, "confirm_after": 100}`, wantCode: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodPost, "/targets", strings.NewReader(tt.body)), 1)
+			w := httptest.NewRecorder()
+
+			handler.CreateTarget(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode != http.StatusCreated {
+				decodeError(t, w)
+				return
+			}
+
+			var body targetJSON
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, 5, body.ID)
+			assert.Equal(t, "pending", body.Status)
+			assert.Equal(t, "HEAD", created.Request.Method)
+			assert.Equal(t, time.Minute, created.Interval)
+			assert.Equal(t, 500*time.Millisecond, created.LatencyThreshold)
+			assert.Equal(t, 3, created.ConfirmAfter)
+		})
+	}
+}
+
+func TestHandler_UpdateTarget(t *testing.T) {
+	targetService := ownedTargets(&monitor.Target{ID: 1, URL: "https://example.com", Interval: 30 * time.Second, Enabled: false, Heartbeat: monitor.HeartbeatSpec{Token: "secret"}})
+	var updated *monitor.Target
+	targetService.updateFunc = func(target *monitor.Target) (*monitor.Target, error) {
+		updated = target
+		return target, nil
+	}
+	handler := NewHandler(targetService, &mockIncidentService{}, &mockNotifierService{})
+
+	tests := []struct {
+		name     string
+		id       string
+		userID   int
+		wantCode int
+	}{
+		{name: "own target", id: "1", userID: 1, wantCode: http.StatusOK},
+		{name: "other user's target", id: "1", userID: 2, wantCode: http.StatusNotFound},
+		{name: "unknown target", id: "9", userID: 1, wantCode: http.StatusNotFound},
+		{name: "invalid ID", id: "abc", userID: 1, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"url": "https://example.org", "interval_seconds": 120}`
+			req := withUser(httptest.NewRequest(http.MethodPut, "/targets/"+tt.id, strings.NewReader(body)), tt.userID)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.UpdateTarget(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode != http.StatusOK {
+				decodeError(t, w)
+				return
+			}
+			assert.Equal(t, "https://example.org", updated.URL)
+			assert.Equal(t, 2*time.Minute, updated.Interval)
+			assert.False(t, updated.Enabled, "updates leave a paused target paused")
+			assert.Equal(t, "secret", updated.Heartbeat.Token, "updates keep the ping token")
+		})
+	}
+}
+
+func TestHandler_UpdateTarget_Credentials(t *testing.T) {
+	stored := monitor.RequestAuth{Type: monitor.AuthBasic, Username: "admin", Password: "hunter2"}
+
+	tests := []struct {
+		name string
+		auth string
+		want monitor.RequestAuth
+	}{
+		{
+			name: "password left out",
+			auth: `{"type": "basic", "username": "root"}`,
+			want: monitor.RequestAuth{Type: monitor.AuthBasic, Username: "root", Password: "hunter2"},
+		},
+		{
+			name: "password replaced",
+			auth: `{"type": "basic", "username": "admin", "password": "correct horse"}`,
+			want: monitor.RequestAuth{Type: monitor.AuthBasic, Username: "admin", Password: "correct horse"},
+		},
+		{
+			name: "type changed",
+			auth: `{"type": "bearer", "token": "t0ken"}`,
+			want: monitor.RequestAuth{Type: monitor.AuthBearer, Token: "t0ken"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetService := ownedTargets(&monitor.Target{ID: 1, URL: "https://example.com", Interval: 30 * time.Second, Request: monitor.RequestSpec{Auth: stored}})
+			var updated *monitor.Target
+			targetService.updateFunc = func(target *monitor.Target) (*monitor.Target, error) {
+				updated = target
+				return target, nil
+			}
+			handler := NewHandler(targetService, &mockIncidentService{}, &mockNotifierService{})
+
+			body := `{"url": "https://example.com", "interval_seconds": 30, "request": {"auth": ` + tt.auth + `}}`
+			req := withUser(httptest.NewRequest(http.MethodPut, "/targets/1", strings.NewReader(body)), 1)
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+
+			handler.UpdateTarget(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, updated.Request.Auth)
+			var out targetJSON
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&out))
+			assert.Equal(t, tt.want.Redacted(), out.Request.Auth, "credentials are never returned")
+		})
+	}
+}
+
+func TestHandler_DeleteTarget(t *testing.T) {
+	targetService := ownedTargets(&monitor.Target{ID: 1})
+	var deleted int
+	targetService.deleteFunc = func(id int) error {
+		deleted = id
+		return nil
+	}
+	handler := NewHandler(targetService, &mockIncidentService{}, &mockNotifierService{})
+
+	req := withUser(httptest.NewRequest(http.MethodDelete, "/targets/1", nil), 1)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	handler.DeleteTarget(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 1, deleted)
+}
+
+func TestHandler_PauseTarget(t *testing.T) {
+	targetService := ownedTargets(&monitor.Target{ID: 1, Enabled: true})
+	targetService.setEnabledFunc = func(id int, enabled bool) (*monitor.Target, error) {
+		return &monitor.Target{ID: id, Enabled: enabled}, nil
+	}
+	handler := NewHandler(targetService, &mockIncidentService{}, &mockNotifierService{})
+
+	for _, tt := range []struct {
+		name    string
+		handle  http.HandlerFunc
+		enabled bool
+	}{
+		{name: "pause", handle: handler.PauseTarget, enabled: false},
+		{name: "resume", handle: handler.ResumeTarget, enabled: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodPost, "/targets/1/"+tt.name, nil), 1)
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+
+			tt.handle(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var body targetJSON
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, tt.enabled, body.Enabled)
+		})
+	}
+}
+
+func TestHandler_ListCheckResults(t *testing.T) {
+	to := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	targetService := ownedTargets(&monitor.Target{ID: 1})
+	targetService.getCheckResultsFunc = func(targetID int, from, gotTo time.Time) ([]monitor.CheckResult, error) {
+		assert.Equal(t, to.Add(-DefaultResultsWindow), from)
+		assert.Equal(t, to, gotTo)
+		return []monitor.CheckResult{
+			{ID: 1, Status: "up", CheckedAt: to.Add(-2 * time.Minute), Latency: 120 * time.Millisecond, StatusCode: 200},
+			{ID: 2, Status: "down", CheckedAt: to.Add(-time.Minute), StatusCode: 503, Reason: "status code 503"},
+		}, nil
+	}
+	handler := NewHandler(targetService, &mockIncidentService{}, &mockNotifierService{})
+
+	t.Run("newest first", func(t *testing.T) {
+		req := withUser(httptest.NewRequest(http.MethodGet, "/targets/1/results?to="+to.Format(time.RFC3339), nil), 1)
+		req.SetPathValue("id", "1")
+		w := httptest.NewRecorder()
+
+		handler.ListCheckResults(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body listBody[checkResultJSON]
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+		if assert.Len(t, body.Data, 2) {
+			assert.Equal(t, 2, body.Data[0].ID)
+			assert.Equal(t, "status code 503", body.Data[0].Reason)
+			assert.Equal(t, int64(120), body.Data[1].LatencyMs)
+		}
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		for _, query := range []string{"?from=yesterday", "?from=2025-06-02T00:00:00Z&to=2025-06-01T00:00:00Z"} {
+			req := withUser(httptest.NewRequest(http.MethodGet, "/targets/1/results"+query, nil), 1)
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+
+			handler.ListCheckResults(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+}
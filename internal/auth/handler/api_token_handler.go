@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+const apiTokensPath = "/settings/tokens"
+
+// APITokenHandler lets users manage the tokens their scripts use for the API
+type APITokenHandler struct {
+	Template struct {
+		List *renderer.Template
+	}
+	apiTokenService service.APITokenServiceInterface
+	flashStore      flash.FlashStoreInterface
+}
+
+func NewAPITokenHandler(apiTokenService service.APITokenServiceInterface, flashStore flash.FlashStoreInterface) *APITokenHandler {
+	return &APITokenHandler{
+		apiTokenService: apiTokenService,
+		flashStore:      flashStore,
+	}
+}
+
+// List shows the user's tokens, and a token just created
+func (h *APITokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	tokens, err := h.apiTokenService.GetAllByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to fetch API tokens", http.StatusInternalServerError)
+		return
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	data := map[string]any{
+		"title":    "API tokens",
+		"tokens":   tokens,
+		"scopes":   model.Scopes,
+		"newToken": h.flashStore.GetFlash(flashId, "token"),
+		"success":  h.flashStore.GetFlash(flashId, "success"),
+		"error":    h.flashStore.GetFlash(flashId, "error"),
+	}
+
+	h.Template.List.Render(w, r, data)
+}
+
+// Create adds a token. The plain token is passed to the list page through the
+// flash store, since this is the only time it can be shown.
+func (h *APITokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	token := &model.APIToken{
+		UserID: user.ID,
+		Name:   r.FormValue("name"),
+		Scopes: r.Form["scopes"],
+	}
+
+	if expiresAt := r.FormValue("expires_at"); expiresAt != "" {
+		date, err := time.Parse(time.DateOnly, expiresAt)
+		if err != nil {
+			h.flashStore.SetFlash(flashId, "error", "Invalid expiry date")
+			http.Redirect(w, r, apiTokensPath, http.StatusSeeOther)
+			return
+		}
+		token.ExpiresAt = date
+	}
+
+	_, plainToken, err := h.apiTokenService.Create(token)
+	if err != nil {
+		h.flashStore.SetFlash(flashId, "error", "Failed to create API token: "+err.Error())
+		http.Redirect(w, r, apiTokensPath, http.StatusSeeOther)
+		return
+	}
+
+	h.flashStore.SetFlash(flashId, "token", plainToken)
+	http.Redirect(w, r, apiTokensPath, http.StatusSeeOther)
+}
+
+// Revoke deletes one of the user's tokens. Scripts using it are locked out
+// straight away.
+func (h *APITokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	user, ok := service.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid token ID", http.StatusBadRequest)
+		return
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+	if err := h.apiTokenService.Revoke(id, user.ID); err != nil {
+		h.flashStore.SetFlash(flashId, "error", "Failed to revoke API token")
+	} else {
+		h.flashStore.SetFlash(flashId, "success", "API token revoked")
+	}
+
+	http.Redirect(w, r, apiTokensPath, http.StatusSeeOther)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mock APITokenService
+type mockAPITokenService struct {
+	createFunc         func(token *model.APIToken) (*model.APIToken, string, error)
+	getAllByUserIDFunc func(userID int) ([]*model.APIToken, error)
+	revokeFunc         func(id, userID int) error
+	authenticateFunc   func(plainToken string) (*model.APIToken, error)
+}
+
+func (m *mockAPITokenService) Create(token *model.APIToken) (*model.APIToken, string, error) {
+	return m.createFunc(token)
+}
+
+func (m *mockAPITokenService) GetAllByUserID(userID int) ([]*model.APIToken, error) {
+	return m.getAllByUserIDFunc(userID)
+}
+
+func (m *mockAPITokenService) Revoke(id, userID int) error {
+	return m.revokeFunc(id, userID)
+}
+
+func (m *mockAPITokenService) Authenticate(plainToken string) (*model.APIToken, error) {
+	return m.authenticateFunc(plainToken)
+}
+
+func withUser(req *http.Request, id int) *http.Request {
+	return req.WithContext(service.WithUser(req.Context(), &model.User{ID: id, Name: "Alice"}))
+}
+
+func TestAPITokenHandler_List(t *testing.T) {
+	mockService := &mockAPITokenService{
+		getAllByUserIDFunc: func(userID int) ([]*model.APIToken, error) {
+			assert.Equal(t, 1, userID)
+			return []*model.APIToken{{
+				ID:        3,
+				Name:      "Deploy pipeline",
+				Prefix:    "smt_abc123",
+				Scopes:    []string{model.ScopeTargetsRead},
+				CreatedAt: time.Now(),
+			}}, nil
+		},
+	}
+	handler := NewAPITokenHandler(mockService, &testutil.MockFlashStore{})
+	handler.Template.List = renderer.New(templates.TemplateFS).GetTemplate("pages:settings/tokens")
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/settings/tokens", nil), 1)
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "Deploy pipeline")
+	assert.Contains(t, body, "smt_abc123")
+	assert.Contains(t, body, "Never used")
+	assert.Contains(t, body, `action="/settings/tokens/3/delete"`)
+	assert.Contains(t, body, `value="notifiers:write"`)
+}
+
+func TestAPITokenHandler_Create(t *testing.T) {
+	var created *model.APIToken
+	mockService := &mockAPITokenService{
+		createFunc: func(token *model.APIToken) (*model.APIToken, string, error) {
+			created = token
+			return token, "smt_secret", nil
+		},
+	}
+	handler := NewAPITokenHandler(mockService, &testutil.MockFlashStore{})
+
+	tests := []struct {
+		name        string
+		form        url.Values
+		wantCreated bool
+	}{
+		{
+			name:        "with expiry",
+			form:        url.Values{"name": {"CI"}, "scopes": {model.ScopeTargetsRead, model.ScopeTargetsWrite}, "expires_at": {"2030-01-31"}},
+			wantCreated: true,
+		},
+		{
+			name: "invalid expiry",
+			form: url.Values{"name": {"CI"}, "scopes": {model.ScopeTargetsRead}, "expires_at": {"next week"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = nil
+			req := withUser(httptest.NewRequest(http.MethodPost, "/settings/tokens", strings.NewReader(tt.form.Encode())), 1)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, http.StatusSeeOther, w.Code)
+			assert.Equal(t, "/settings/tokens", w.Header().Get("Location"))
+			if !tt.wantCreated {
+				assert.Nil(t, created)
+				return
+			}
+			assert.Equal(t, 1, created.UserID)
+			assert.Equal(t, "CI", created.Name)
+			assert.Equal(t, []string{model.ScopeTargetsRead, model.ScopeTargetsWrite}, created.Scopes)
+			assert.Equal(t, time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC), created.ExpiresAt)
+		})
+	}
+}
+
+func TestAPITokenHandler_Revoke(t *testing.T) {
+	var revoked [2]int
+	mockService := &mockAPITokenService{
+		revokeFunc: func(id, userID int) error {
+			revoked = [2]int{id, userID}
+			return nil
+		},
+	}
+	handler := NewAPITokenHandler(mockService, &testutil.MockFlashStore{})
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/settings/tokens/3/delete", nil), 1)
+	req.SetPathValue("id", "3")
+	w := httptest.NewRecorder()
+
+	handler.Revoke(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, [2]int{3, 1}, revoked)
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Scopes an API token can be granted
+const (
+	ScopeTargetsRead    = "targets:read"
+	ScopeTargetsWrite   = "targets:write"
+	ScopeNotifiersRead  = "notifiers:read"
+	ScopeNotifiersWrite = "notifiers:write"
+	ScopeIncidentsRead  = "incidents:read"
+)
+
+// Scopes lists every scope, in the order the settings page shows them
+var Scopes = []string{
+	ScopeTargetsRead,
+	ScopeTargetsWrite,
+	ScopeNotifiersRead,
+	ScopeNotifiersWrite,
+	ScopeIncidentsRead,
+}
+
+// APIToken lets scripts use the API on behalf of a user. Only a hash of the
+// token is stored; the token itself is shown once, when it is created.
+type APIToken struct {
+	ID         int
+	UserID     int
+	Name       string
+	Hash       string
+	Prefix     string // start of the token, so users can tell tokens apart
+	Scopes     []string
+	CreatedAt  time.Time
+	LastUsedAt time.Time // zero until the token is first used
+	ExpiresAt  time.Time // zero if the token never expires
+}
+
+// IsExpired reports whether the token has stopped working at now
+func (t *APIToken) IsExpired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+// HasScope reports whether the token was granted scope
+func (t *APIToken) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+// Validate checks the token has a name and only known scopes
+func (t *APIToken) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(t.Scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range t.Scopes {
+		if !slices.Contains(Scopes, scope) {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIToken_IsExpired(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      bool
+	}{
+		{name: "never expires", want: false},
+		{name: "expires later", expiresAt: now.Add(time.Hour), want: false},
+		{name: "expires now", expiresAt: now, want: true},
+		{name: "expired", expiresAt: now.Add(-time.Hour), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &APIToken{ExpiresAt: tt.expiresAt}
+			assert.Equal(t, tt.want, token.IsExpired(now))
+		})
+	}
+}
+
+func TestAPIToken_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   APIToken
+		wantErr bool
+	}{
+		{name: "valid", token: APIToken{Name: "CI", Scopes: []string{ScopeTargetsRead, ScopeTargetsWrite}}},
+		{name: "missing name", token: APIToken{Scopes: []string{ScopeTargetsRead}}, wantErr: true},
+		{name: "no scopes", token: APIToken{Name: "CI"}, wantErr: true},
+		{name: "unknown scope", token: APIToken{Name: "CI", Scopes: []string{"users:write"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.token.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAPIToken_HasScope(t *testing.T) {
+	token := &APIToken{Scopes: []string{ScopeTargetsRead}}
+	assert.True(t, token.HasScope(ScopeTargetsRead))
+	assert.False(t, token.HasScope(ScopeTargetsWrite))
+}
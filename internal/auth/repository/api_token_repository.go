@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+)
+
+type APITokenRepositoryInterface interface {
+	Create(token *model.APIToken) (*model.APIToken, error)
+	GetByHash(hash string) (*model.APIToken, error)
+	GetAllByUserID(userID int) ([]*model.APIToken, error)
+	Delete(id, userID int) error
+	TouchLastUsed(id int, at time.Time) error
+}
+
+var _ APITokenRepositoryInterface = (*APITokenRepository)(nil)
+
+type APITokenRepository struct {
+	db *sql.DB
+}
+
+func NewAPITokenRepository(db *sql.DB) *APITokenRepository {
+	return &APITokenRepository{db: db}
+}
+
+const apiTokenQuery = `SELECT id, user_id, name, token_hash, prefix, scopes, created_at, last_used_at, expires_at
+			  FROM api_token`
+
+func (r *APITokenRepository) Create(token *model.APIToken) (*model.APIToken, error) {
+	query := `INSERT INTO api_token (user_id, name, token_hash, prefix, scopes, created_at, expires_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.Exec(query, token.UserID, token.Name, token.Hash, token.Prefix,
+		strings.Join(token.Scopes, " "), token.CreatedAt, nullTime(token.ExpiresAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api token: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	token.ID = int(id)
+	return token, nil
+}
+
+// GetByHash returns the token with the given hash, or nil if there is none
+func (r *APITokenRepository) GetByHash(hash string) (*model.APIToken, error) {
+	token, err := scanAPIToken(r.db.QueryRow(apiTokenQuery+` WHERE token_hash = ?`, hash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *APITokenRepository) GetAllByUserID(userID int) ([]*model.APIToken, error) {
+	rows, err := r.db.Query(apiTokenQuery+` WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*model.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Delete revokes one of the user's tokens
+func (r *APITokenRepository) Delete(id, userID int) error {
+	result, err := r.db.Exec(`DELETE FROM api_token WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete api token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("api token not found")
+	}
+
+	return nil
+}
+
+func (r *APITokenRepository) TouchLastUsed(id int, at time.Time) error {
+	if _, err := r.db.Exec(`UPDATE api_token SET last_used_at = ? WHERE id = ?`, at, id); err != nil {
+		return fmt.Errorf("failed to update api token: %w", err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIToken(row scanner) (*model.APIToken, error) {
+	var token model.APIToken
+	var scopes string
+	var lastUsedAt, expiresAt sql.NullTime
+
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.Hash,
+		&token.Prefix,
+		&scopes,
+		&token.CreatedAt,
+		&lastUsedAt,
+		&expiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Scopes = strings.Fields(scopes)
+	token.LastUsedAt = lastUsedAt.Time
+	token.ExpiresAt = expiresAt.Time
+	return &token, nil
+}
+
+// nullTime stores the zero time as NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
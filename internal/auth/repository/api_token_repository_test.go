@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITokenRepository(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+
+	repo := NewAPITokenRepository(db)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("Create", func(t *testing.T) {
+		token, err := repo.Create(&model.APIToken{
+			UserID:    1,
+			Name:      "CI",
+			Hash:      "hash-1",
+			Prefix:    "smt_abcd",
+			Scopes:    []string{model.ScopeTargetsRead, model.ScopeTargetsWrite},
+			CreatedAt: now,
+		})
+		assert.NoError(t, err)
+		assert.NotZero(t, token.ID)
+
+		_, err = repo.Create(&model.APIToken{UserID: 1, Name: "Expiring", Hash: "hash-2", Prefix: "smt_efgh", Scopes: []string{model.ScopeIncidentsRead}, CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+		assert.NoError(t, err)
+
+		_, err = repo.Create(&model.APIToken{UserID: 1, Name: "Duplicate", Hash: "hash-1", CreatedAt: now})
+		assert.Error(t, err, "hashes are unique")
+	})
+
+	t.Run("GetByHash", func(t *testing.T) {
+		token, err := repo.GetByHash("hash-1")
+		if assert.NoError(t, err) && assert.NotNil(t, token) {
+			assert.Equal(t, "CI", token.Name)
+			assert.Equal(t, []string{model.ScopeTargetsRead, model.ScopeTargetsWrite}, token.Scopes)
+			assert.True(t, token.CreatedAt.Equal(now))
+			assert.True(t, token.LastUsedAt.IsZero())
+			assert.True(t, token.ExpiresAt.IsZero())
+		}
+
+		token, err = repo.GetByHash("hash-2")
+		if assert.NoError(t, err) && assert.NotNil(t, token) {
+			assert.True(t, token.ExpiresAt.Equal(now.Add(time.Hour)))
+		}
+	})
+
+	t.Run("GetByHash_NotFound", func(t *testing.T) {
+		token, err := repo.GetByHash("missing")
+		assert.NoError(t, err)
+		assert.Nil(t, token)
+	})
+
+	t.Run("TouchLastUsed", func(t *testing.T) {
+		assert.NoError(t, repo.TouchLastUsed(1, now.Add(time.Minute)))
+
+		token, err := repo.GetByHash("hash-1")
+		assert.NoError(t, err)
+		assert.True(t, token.LastUsedAt.Equal(now.Add(time.Minute)))
+	})
+
+	t.Run("GetAllByUserID", func(t *testing.T) {
+		tokens, err := repo.GetAllByUserID(1)
+		assert.NoError(t, err)
+		assert.Len(t, tokens, 2)
+
+		tokens, err = repo.GetAllByUserID(2)
+		assert.NoError(t, err)
+		assert.Empty(t, tokens)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.Error(t, repo.Delete(1, 2), "users cannot revoke each other's tokens")
+		assert.NoError(t, repo.Delete(1, 1))
+
+		token, err := repo.GetByHash("hash-1")
+		assert.NoError(t, err)
+		assert.Nil(t, token)
+	})
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/shuvo-paul/uptimebot/internal/auth/repository"
+)
+
+// APITokenPrefix starts every API token, so leaked tokens are easy to spot
+const APITokenPrefix = "smt_"
+
+// LastUsedInterval is how often a token's last-used time is written. Scripts
+// can make many requests a minute; finer detail is not worth the writes.
+const LastUsedInterval = time.Minute
+
+var ErrInvalidAPIToken = errors.New("invalid api token")
+
+type APITokenServiceInterface interface {
+	Create(token *model.APIToken) (*model.APIToken, string, error)
+	GetAllByUserID(userID int) ([]*model.APIToken, error)
+	Revoke(id, userID int) error
+	Authenticate(plainToken string) (*model.APIToken, error)
+}
+
+var _ APITokenServiceInterface = (*APITokenService)(nil)
+
+type APITokenService struct {
+	repo repository.APITokenRepositoryInterface
+	now  func() time.Time
+}
+
+func NewAPITokenService(repo repository.APITokenRepositoryInterface) *APITokenService {
+	return &APITokenService{repo: repo, now: time.Now}
+}
+
+// Create stores a new token for token.UserID and returns it along with the
+// plain token, which cannot be recovered later
+func (s *APITokenService) Create(token *model.APIToken) (*model.APIToken, string, error) {
+	token.Name = strings.TrimSpace(token.Name)
+	if err := token.Validate(); err != nil {
+		return nil, "", err
+	}
+	if !token.ExpiresAt.IsZero() && !token.ExpiresAt.After(s.now()) {
+		return nil, "", fmt.Errorf("expiry must be in the future")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate api token: %w", err)
+	}
+	plainToken := APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token.Hash = hashAPIToken(plainToken)
+	token.Prefix = plainToken[:len(APITokenPrefix)+6]
+	token.CreatedAt = s.now().UTC()
+
+	created, err := s.repo.Create(token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return created, plainToken, nil
+}
+
+func (s *APITokenService) GetAllByUserID(userID int) ([]*model.APIToken, error) {
+	return s.repo.GetAllByUserID(userID)
+}
+
+func (s *APITokenService) Revoke(id, userID int) error {
+	return s.repo.Delete(id, userID)
+}
+
+// Authenticate returns the stored token matching plainToken, recording that
+// it was used. Unknown and expired tokens give ErrInvalidAPIToken.
+func (s *APITokenService) Authenticate(plainToken string) (*model.APIToken, error) {
+	if !strings.HasPrefix(plainToken, APITokenPrefix) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	token, err := s.repo.GetByHash(hashAPIToken(plainToken))
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if token == nil || token.IsExpired(now) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	if now.Sub(token.LastUsedAt) >= LastUsedInterval {
+		// A failed write should not lock the script out
+		if err := s.repo.TouchLastUsed(token.ID, now.UTC()); err != nil {
+			slog.Error("Failed to record api token use", "token_id", token.ID, "error", err)
+		} else {
+			token.LastUsedAt = now.UTC()
+		}
+	}
+
+	return token, nil
+}
+
+func hashAPIToken(plainToken string) string {
+	sum := sha256.Sum256([]byte(plainToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/auth/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAPITokenRepository keeps tokens in memory and counts last-used writes
+type mockAPITokenRepository struct {
+	tokens  []*model.APIToken
+	touches int
+}
+
+func (m *mockAPITokenRepository) Create(token *model.APIToken) (*model.APIToken, error) {
+	token.ID = len(m.tokens) + 1
+	m.tokens = append(m.tokens, token)
+	return token, nil
+}
+
+func (m *mockAPITokenRepository) GetByHash(hash string) (*model.APIToken, error) {
+	for _, token := range m.tokens {
+		if token.Hash == hash {
+			return token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockAPITokenRepository) GetAllByUserID(userID int) ([]*model.APIToken, error) {
+	return m.tokens, nil
+}
+
+func (m *mockAPITokenRepository) Delete(id, userID int) error {
+	return nil
+}
+
+func (m *mockAPITokenRepository) TouchLastUsed(id int, at time.Time) error {
+	m.touches++
+	m.tokens[id-1].LastUsedAt = at
+	return nil
+}
+
+func TestAPITokenService_Create(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		token   model.APIToken
+		wantErr bool
+	}{
+		{name: "valid token", token: model.APIToken{UserID: 1, Name: " CI ", Scopes: []string{model.ScopeTargetsRead}}},
+		{name: "with expiry", token: model.APIToken{UserID: 1, Name: "CI", Scopes: []string{model.ScopeTargetsRead}, ExpiresAt: now.Add(time.Hour)}},
+		{name: "expiry in the past", token: model.APIToken{UserID: 1, Name: "CI", Scopes: []string{model.ScopeTargetsRead}, ExpiresAt: now}, wantErr: true},
+		{name: "unknown scope", token: model.APIToken{UserID: 1, Name: "CI", Scopes: []string{"admin"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAPITokenService(&mockAPITokenRepository{})
+			service.now = func() time.Time { return now }
+
+			token, plainToken, err := service.Create(&tt.token)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "CI", token.Name)
+			assert.True(t, strings.HasPrefix(plainToken, APITokenPrefix))
+			assert.True(t, strings.HasPrefix(plainToken, token.Prefix))
+			assert.NotEqual(t, plainToken, token.Hash, "only the hash is stored")
+			assert.Equal(t, hashAPIToken(plainToken), token.Hash)
+			assert.Equal(t, now, token.CreatedAt)
+		})
+	}
+}
+
+func TestAPITokenService_Authenticate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockAPITokenRepository{}
+	service := NewAPITokenService(repo)
+	service.now = func() time.Time { return now }
+
+	_, plainToken, err := service.Create(&model.APIToken{UserID: 1, Name: "CI", Scopes: []string{model.ScopeTargetsRead}})
+	assert.NoError(t, err)
+	_, expiringToken, err := service.Create(&model.APIToken{UserID: 1, Name: "Temporary", Scopes: []string{model.ScopeTargetsRead}, ExpiresAt: now.Add(time.Hour)})
+	assert.NoError(t, err)
+
+	t.Run("valid token", func(t *testing.T) {
+		token, err := service.Authenticate(plainToken)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, token.UserID)
+			assert.Equal(t, now, token.LastUsedAt)
+		}
+	})
+
+	t.Run("last use is recorded once a minute", func(t *testing.T) {
+		touches := repo.touches
+		service.now = func() time.Time { return now.Add(30 * time.Second) }
+		_, err := service.Authenticate(plainToken)
+		assert.NoError(t, err)
+		assert.Equal(t, touches, repo.touches)
+
+		service.now = func() time.Time { return now.Add(LastUsedInterval) }
+		_, err = service.Authenticate(plainToken)
+		assert.NoError(t, err)
+		assert.Equal(t, touches+1, repo.touches)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		service.now = func() time.Time { return now.Add(time.Hour) }
+		_, err := service.Authenticate(expiringToken)
+		assert.ErrorIs(t, err, ErrInvalidAPIToken)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		for _, value := range []string{APITokenPrefix + "unknown", "not-a-token", ""} {
+			_, err := service.Authenticate(value)
+			assert.ErrorIs(t, err, ErrInvalidAPIToken, value)
+		}
+	})
+}
@@ -4,13 +4,26 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
 	Email    EmailConfig
 	Database DatabaseConfig
+	// BaseURL is where users reach the app, used for links in notifications
+	BaseURL string
+	// TelegramAPIURL replaces the Telegram Bot API address when set, such
+	// as with a local stand-in server
+	TelegramAPIURL string
+	// PagerDutyEventsURL and OpsgenieAPIURL replace the addresses of the
+	// paging services' APIs when set
+	PagerDutyEventsURL string
+	OpsgenieAPIURL     string
 }
 
+// DefaultBaseURL is used when BASE_URL is not set
+const DefaultBaseURL = "http://localhost:8080"
+
 type DatabaseConfig struct {
 	URL   string
 	Token string
@@ -36,11 +49,23 @@ func Load() (*Config, error) {
 	}
 
 	return &Config{
-		Email:    emailConfig,
-		Database: dbConfig,
+		Email:              emailConfig,
+		Database:           dbConfig,
+		BaseURL:            loadBaseURL(),
+		TelegramAPIURL:     strings.TrimRight(os.Getenv("TELEGRAM_API_URL"), "/"),
+		PagerDutyEventsURL: os.Getenv("PAGERDUTY_EVENTS_URL"),
+		OpsgenieAPIURL:     strings.TrimRight(os.Getenv("OPSGENIE_API_URL"), "/"),
 	}, nil
 }
 
+func loadBaseURL() string {
+	baseURL := strings.TrimRight(os.Getenv("BASE_URL"), "/")
+	if baseURL == "" {
+		return DefaultBaseURL
+	}
+	return baseURL
+}
+
 func loadDatabaseConfig() (DatabaseConfig, error) {
 	url := os.Getenv("TURSO_DATABASE_URL")
 	token := os.Getenv("TURSO_AUTH_TOKEN")
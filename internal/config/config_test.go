@@ -37,6 +37,68 @@ func TestLoad(t *testing.T) {
 					URL:   "libsql://test.turso.io",
 					Token: "valid-token",
 				},
+				BaseURL: DefaultBaseURL,
+			},
+			wantErr: false,
+		},
+		{
+			name: "base URL",
+			envVars: map[string]string{
+				"SMTP_HOST":          "smtp.example.com",
+				"SMTP_PORT":          "587",
+				"SMTP_USERNAME":      "test@example.com",
+				"SMTP_PASSWORD":      "password123",
+				"SMTP_EMAIL_FROM":    "sender@example.com",
+				"TURSO_DATABASE_URL": "libsql://test.turso.io",
+				"TURSO_AUTH_TOKEN":   "valid-token",
+				"BASE_URL":           "https://uptime.example.com/",
+			},
+			want: &Config{
+				Email: EmailConfig{
+					Host:     "smtp.example.com",
+					Port:     587,
+					Username: "test@example.com",
+					Password: "password123",
+					From:     "sender@example.com",
+				},
+				Database: DatabaseConfig{
+					URL:   "libsql://test.turso.io",
+					Token: "valid-token",
+				},
+				BaseURL: "https://uptime.example.com",
+			},
+			wantErr: false,
+		},
+		{
+			name: "service API URLs",
+			envVars: map[string]string{
+				"SMTP_HOST":            "smtp.example.com",
+				"SMTP_PORT":            "587",
+				"SMTP_USERNAME":        "test@example.com",
+				"SMTP_PASSWORD":        "password123",
+				"SMTP_EMAIL_FROM":      "sender@example.com",
+				"TURSO_DATABASE_URL":   "libsql://test.turso.io",
+				"TURSO_AUTH_TOKEN":     "valid-token",
+				"TELEGRAM_API_URL":     "http://localhost:8081/",
+				"PAGERDUTY_EVENTS_URL": "http://localhost:8082/v2/enqueue",
+				"OPSGENIE_API_URL":     "http://localhost:8083/",
+			},
+			want: &Config{
+				Email: EmailConfig{
+					Host:     "smtp.example.com",
+					Port:     587,
+					Username: "test@example.com",
+					Password: "password123",
+					From:     "sender@example.com",
+				},
+				Database: DatabaseConfig{
+					URL:   "libsql://test.turso.io",
+					Token: "valid-token",
+				},
+				BaseURL:            DefaultBaseURL,
+				TelegramAPIURL:     "http://localhost:8081",
+				PagerDutyEventsURL: "http://localhost:8082/v2/enqueue",
+				OpsgenieAPIURL:     "http://localhost:8083",
 			},
 			wantErr: false,
 		},
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE check_result (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    target_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    checked_at TIMESTAMP NOT NULL,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    error_class TEXT NOT NULL DEFAULT '',
+    bytes_read INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_check_result_target_checked_at ON check_result(target_id, checked_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_check_result_target_checked_at;
+DROP TABLE IF EXISTS check_result;
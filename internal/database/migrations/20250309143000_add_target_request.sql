@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN request TEXT NOT NULL DEFAULT '{}';
+
+-- +migrate Down
+ALTER TABLE target DROP COLUMN request;
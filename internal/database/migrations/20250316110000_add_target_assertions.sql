@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN assertions TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE check_result ADD COLUMN reason TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE check_result DROP COLUMN reason;
+ALTER TABLE target DROP COLUMN assertions;
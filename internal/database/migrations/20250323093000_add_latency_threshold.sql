@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN latency_threshold_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE notifier ADD COLUMN filter TEXT NOT NULL DEFAULT '{}';
+
+-- +migrate Down
+ALTER TABLE notifier DROP COLUMN filter;
+ALTER TABLE target DROP COLUMN latency_threshold_ms;
@@ -0,0 +1,22 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN tls TEXT NOT NULL DEFAULT '{}';
+
+CREATE TABLE target_certificate (
+    target_id INTEGER PRIMARY KEY,
+    subject TEXT NOT NULL,
+    issuer TEXT NOT NULL,
+    dns_names TEXT NOT NULL DEFAULT '[]',
+    not_before TIMESTAMP NOT NULL,
+    not_after TIMESTAMP NOT NULL,
+    version INTEGER NOT NULL,
+    chain_trusted BOOLEAN NOT NULL,
+    hostname_valid BOOLEAN NOT NULL,
+    verify_error TEXT NOT NULL DEFAULT '',
+    checked_at TIMESTAMP NOT NULL,
+    notified_days INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS target_certificate;
+ALTER TABLE target DROP COLUMN tls;
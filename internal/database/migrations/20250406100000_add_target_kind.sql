@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN kind TEXT NOT NULL DEFAULT 'http';
+ALTER TABLE target ADD COLUMN tcp TEXT NOT NULL DEFAULT '{}';
+
+-- +migrate Down
+ALTER TABLE target DROP COLUMN tcp;
+ALTER TABLE target DROP COLUMN kind;
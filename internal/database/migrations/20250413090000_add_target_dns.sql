@@ -0,0 +1,14 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN dns TEXT NOT NULL DEFAULT '{}';
+
+CREATE TABLE target_dns_answer (
+    target_id INTEGER PRIMARY KEY,
+    record_type TEXT NOT NULL,
+    records TEXT NOT NULL DEFAULT '[]',
+    resolved_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS target_dns_answer;
+ALTER TABLE target DROP COLUMN dns;
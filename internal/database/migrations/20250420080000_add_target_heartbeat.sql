@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN heartbeat TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE target ADD COLUMN heartbeat_token TEXT;
+CREATE UNIQUE INDEX idx_target_heartbeat_token ON target (heartbeat_token);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_target_heartbeat_token;
+ALTER TABLE target DROP COLUMN heartbeat_token;
+ALTER TABLE target DROP COLUMN heartbeat;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE target ADD COLUMN confirm_after INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE target ADD COLUMN recover_after INTEGER NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE target DROP COLUMN recover_after;
+ALTER TABLE target DROP COLUMN confirm_after;
@@ -0,0 +1,33 @@
+-- +migrate Up
+CREATE TABLE incident (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    target_id INTEGER NOT NULL,
+    cause TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL,
+    acknowledged_at TIMESTAMP NOT NULL DEFAULT '',
+    acknowledged_by INTEGER,
+    resolved_at TIMESTAMP NOT NULL DEFAULT '',
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE,
+    FOREIGN KEY (acknowledged_by) REFERENCES user (id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_incident_target_resolved_at ON incident(target_id, resolved_at);
+
+CREATE TABLE incident_event (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    incident_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    user_id INTEGER,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (incident_id) REFERENCES incident (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_incident_event_incident_id ON incident_event(incident_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_incident_event_incident_id;
+DROP TABLE IF EXISTS incident_event;
+DROP INDEX IF EXISTS idx_incident_target_resolved_at;
+DROP TABLE IF EXISTS incident;
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE maintenance_window (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    target_id INTEGER NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL DEFAULT '',
+    ends_at TIMESTAMP NOT NULL DEFAULT '',
+    weekdays TEXT NOT NULL DEFAULT '[]',
+    start_time TEXT NOT NULL DEFAULT '',
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    timezone TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_maintenance_window_target_id ON maintenance_window(target_id);
+
+ALTER TABLE check_result ADD COLUMN maintenance BOOLEAN NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE check_result DROP COLUMN maintenance;
+DROP INDEX IF EXISTS idx_maintenance_window_target_id;
+DROP TABLE IF EXISTS maintenance_window;
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE status_page (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+CREATE TABLE status_page_target (
+    status_page_id INTEGER NOT NULL,
+    target_id INTEGER NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    component TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (status_page_id, target_id),
+    FOREIGN KEY (status_page_id) REFERENCES status_page (id) ON DELETE CASCADE,
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS status_page_target;
+DROP TABLE IF EXISTS status_page;
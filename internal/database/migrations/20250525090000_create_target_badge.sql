@@ -0,0 +1,9 @@
+-- +migrate Up
+CREATE TABLE target_badge (
+    target_id INTEGER PRIMARY KEY,
+    token TEXT NOT NULL UNIQUE,
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS target_badge;
@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE api_token (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    prefix TEXT NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (datetime('now')),
+    last_used_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_api_token_user_id ON api_token (user_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_api_token_user_id;
+DROP TABLE IF EXISTS api_token;
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE email_recipient (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    notifier_id INTEGER NOT NULL,
+    email TEXT NOT NULL,
+    token TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT (datetime('now')),
+    verified_at TIMESTAMP,
+    UNIQUE (notifier_id, email),
+    FOREIGN KEY (notifier_id) REFERENCES notifier (id) ON DELETE CASCADE
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS email_recipient;
@@ -0,0 +1,35 @@
+-- +migrate Up
+CREATE TABLE notification_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    notifier_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    state TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (datetime('now')),
+    finished_at TIMESTAMP,
+    FOREIGN KEY (notifier_id) REFERENCES notifier (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notification_outbox_pending ON notification_outbox (status, notifier_id, id);
+
+CREATE TABLE notification_attempt (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    outbox_id INTEGER NOT NULL,
+    notifier_id INTEGER NOT NULL,
+    attempt INTEGER NOT NULL,
+    attempted_at TIMESTAMP NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (outbox_id) REFERENCES notification_outbox (id) ON DELETE CASCADE,
+    FOREIGN KEY (notifier_id) REFERENCES notifier (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notification_attempt_notifier ON notification_attempt (notifier_id, id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS notification_attempt;
+DROP TABLE IF EXISTS notification_outbox;
@@ -0,0 +1,38 @@
+-- +migrate Up
+CREATE TABLE escalation_policy (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    steps TEXT NOT NULL DEFAULT '[]',
+    FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE
+);
+
+-- A target follows at most one policy
+CREATE TABLE escalation_policy_target (
+    target_id INTEGER PRIMARY KEY,
+    policy_id INTEGER NOT NULL,
+    FOREIGN KEY (target_id) REFERENCES target (id) ON DELETE CASCADE,
+    FOREIGN KEY (policy_id) REFERENCES escalation_policy (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_escalation_policy_target_policy_id ON escalation_policy_target (policy_id);
+
+CREATE TABLE incident_escalation (
+    incident_id INTEGER PRIMARY KEY,
+    policy_id INTEGER NOT NULL,
+    step INTEGER NOT NULL DEFAULT 0,
+    next_at TIMESTAMP NOT NULL DEFAULT '',
+    notified TEXT NOT NULL DEFAULT '[]',
+    finished_at TIMESTAMP NOT NULL DEFAULT '',
+    FOREIGN KEY (incident_id) REFERENCES incident (id) ON DELETE CASCADE,
+    FOREIGN KEY (policy_id) REFERENCES escalation_policy (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_incident_escalation_finished_at ON incident_escalation (finished_at, next_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_incident_escalation_finished_at;
+DROP TABLE IF EXISTS incident_escalation;
+DROP INDEX IF EXISTS idx_escalation_policy_target_policy_id;
+DROP TABLE IF EXISTS escalation_policy_target;
+DROP TABLE IF EXISTS escalation_policy;
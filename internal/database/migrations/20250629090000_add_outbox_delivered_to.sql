@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE notification_outbox ADD COLUMN delivered_to TEXT NOT NULL DEFAULT '[]';
+
+-- +migrate Down
+ALTER TABLE notification_outbox DROP COLUMN delivered_to;
@@ -11,6 +11,7 @@ type Mailer interface {
 	SetTo(string) error
 	SetSubject(string) error
 	SetBody(string) error
+	SetTextBody(string) error
 	SendEmail() error
 }
 
@@ -39,6 +40,18 @@ func NewEmailService(config *config.EmailConfig) (*MailService, error) {
 	}, nil
 }
 
+// NewMailerFactory checks config once and returns a function giving a fresh
+// Mailer for every message, since a MailService builds up a single message
+func NewMailerFactory(config *config.EmailConfig) (func() Mailer, error) {
+	if _, err := NewEmailService(config); err != nil {
+		return nil, err
+	}
+	return func() Mailer {
+		mailer, _ := NewEmailService(config)
+		return mailer
+	}, nil
+}
+
 func NewEmail(from string) *mail.Email {
 	mail := mail.NewMSG()
 
@@ -52,6 +65,8 @@ func NewEmail(from string) *mail.Email {
 type MailService struct {
 	server *mail.SMTPServer
 	mail   *mail.Email
+	html   string
+	text   string
 }
 
 func (e *MailService) SetTo(to string) error {
@@ -74,10 +89,35 @@ func (e *MailService) SetBody(body string) error {
 	if body == "" {
 		return fmt.Errorf("email body cannot be empty")
 	}
-	e.mail.SetBody(mail.TextHTML, body)
+	e.html = body
+	e.setParts()
 	return nil
 }
 
+// SetTextBody sets a plain-text version of the body for mail clients that do
+// not show HTML
+func (e *MailService) SetTextBody(body string) error {
+	if body == "" {
+		return fmt.Errorf("email text body cannot be empty")
+	}
+	e.text = body
+	e.setParts()
+	return nil
+}
+
+// setParts writes the bodies into the message. Clients show the last
+// alternative they understand, so the plain text goes first.
+func (e *MailService) setParts() {
+	if e.text == "" {
+		e.mail.SetBody(mail.TextHTML, e.html)
+		return
+	}
+	e.mail.SetBody(mail.TextPlain, e.text)
+	if e.html != "" {
+		e.mail.AddAlternative(mail.TextHTML, e.html)
+	}
+}
+
 func (e *MailService) SendEmail() error {
 	server, err := e.server.Connect()
 	if err != nil {
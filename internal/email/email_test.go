@@ -1,6 +1,7 @@
 package email
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/shuvo-paul/uptimebot/internal/config"
@@ -50,6 +51,19 @@ func TestNewEmailService(t *testing.T) {
 	assert.Equal(t, "invalid port number: port must be between 1 and 65535", err.Error())
 }
 
+func TestNewMailerFactory(t *testing.T) {
+	newMailer, err := NewMailerFactory(&config.EmailConfig{Host: "smtp.example.com", Port: 587, From: "sender@example.com"})
+	assert.NoError(t, err)
+
+	first := newMailer().(*MailService)
+	first.SetTo("first@example.com")
+	second := newMailer().(*MailService)
+	assert.Empty(t, second.mail.GetRecipients(), "every mailer starts a new message")
+
+	_, err = NewMailerFactory(&config.EmailConfig{Host: "smtp.example.com"})
+	assert.Error(t, err)
+}
+
 func TestNewEmail(t *testing.T) {
 	// Test with from address
 	email := NewEmail("sender@example.com")
@@ -102,6 +116,22 @@ func TestEmailService_SetBody(t *testing.T) {
 	})
 }
 
+func TestEmailService_SetTextBody(t *testing.T) {
+	service := &MailService{
+		mail: NewEmail("sender@example.com"),
+	}
+	service.SetTo("recipient@example.com")
+	service.SetSubject("Test Subject")
+
+	assert.NoError(t, service.SetBody("<h1>Test Body</h1>"))
+	assert.NoError(t, service.SetTextBody("Test Body"))
+	assert.Error(t, service.SetTextBody(""))
+
+	message := service.mail.GetMessage()
+	assert.Contains(t, message, "multipart/alternative")
+	assert.Less(t, strings.Index(message, "text/plain"), strings.Index(message, "text/html"), "the plain text comes before the HTML")
+}
+
 func TestEmailService_SendEmail(t *testing.T) {
 	// Create test config
 	emailConfig := &config.EmailConfig{
@@ -9,16 +9,18 @@ import (
 type MailServiceMock struct {
 	mutex sync.Mutex
 
-	SetToFunc      func(to string) error
-	SetSubjectFunc func(subject string) error
-	SetBodyFunc    func(body string) error
-	SendEmailFunc  func() error
+	SetToFunc       func(to string) error
+	SetSubjectFunc  func(subject string) error
+	SetBodyFunc     func(body string) error
+	SetTextBodyFunc func(body string) error
+	SendEmailFunc   func() error
 
 	calls struct {
-		SetTo      []string
-		SetSubject []string
-		SetBody    []string
-		SendEmail  int
+		SetTo       []string
+		SetSubject  []string
+		SetBody     []string
+		SetTextBody []string
+		SendEmail   int
 	}
 }
 
@@ -58,6 +60,17 @@ func (m *MailServiceMock) SetBody(body string) error {
 	return nil
 }
 
+func (m *MailServiceMock) SetTextBody(body string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls.SetTextBody = append(m.calls.SetTextBody, body)
+	if m.SetTextBodyFunc != nil {
+		return m.SetTextBodyFunc(body)
+	}
+	return nil
+}
+
 func (m *MailServiceMock) SendEmail() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -89,6 +102,12 @@ func (m *MailServiceMock) GetSetBodyCalls() []string {
 	return append([]string{}, m.calls.SetBody...)
 }
 
+func (m *MailServiceMock) GetSetTextBodyCalls() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]string{}, m.calls.SetTextBody...)
+}
+
 func (m *MailServiceMock) GetSendEmailCallCount() int {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
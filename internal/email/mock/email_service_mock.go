@@ -1,10 +1,11 @@
 package mock
 
 type EmailServiceMock struct {
-	SetToFunc      func(to string) error
-	SetSubjectFunc func(subject string) error
-	SetBodyFunc    func(body string) error
-	SendEmailFunc  func() error
+	SetToFunc       func(to string) error
+	SetSubjectFunc  func(subject string) error
+	SetBodyFunc     func(body string) error
+	SetTextBodyFunc func(body string) error
+	SendEmailFunc   func() error
 }
 
 func (m *EmailServiceMock) SetTo(to string) error {
@@ -19,6 +20,10 @@ func (m *EmailServiceMock) SetBody(body string) error {
 	return m.SetBodyFunc(body)
 }
 
+func (m *EmailServiceMock) SetTextBody(body string) error {
+	return m.SetTextBodyFunc(body)
+}
+
 func (m *EmailServiceMock) SendEmail() error {
 	return m.SendEmailFunc()
 }
@@ -0,0 +1,329 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Assertion types a target can check its response against
+const (
+	AssertStatusCode  = "status"
+	AssertContains    = "contains"
+	AssertNotContains = "not_contains"
+	AssertRegex       = "regex"
+	AssertJSONPath    = "json"
+	AssertHeader      = "header"
+	AssertMaxBodySize = "max_body_size"
+)
+
+// MaxAssertionBodySize caps how much of a response body is kept in memory
+// for body assertions. Anything beyond it is counted but not inspected.
+const MaxAssertionBodySize = 1 << 20
+
+// Response is what assertions are evaluated against
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte // at most MaxAssertionBodySize bytes
+	Size       int64  // full size of the body
+}
+
+// Assertion is a single expectation on a response. Target names the JSON
+// path or header the assertion applies to; Value is what is expected.
+type Assertion struct {
+	Type   string `json:"type"`
+	Target string `json:"target,omitempty"`
+	Value  string `json:"value"`
+}
+
+// Validate checks that the assertion is well formed
+func (a Assertion) Validate() error {
+	switch a.Type {
+	case AssertStatusCode:
+		_, err := parseStatusCodes(a.Value)
+		return err
+	case AssertContains, AssertNotContains:
+		if a.Value == "" {
+			return fmt.Errorf("%s requires a keyword", a.Type)
+		}
+	case AssertRegex:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	case AssertJSONPath:
+		if _, err := parseJSONPath(a.Target); err != nil {
+			return err
+		}
+	case AssertHeader:
+		if a.Target == "" {
+			return fmt.Errorf("header requires a name")
+		}
+	case AssertMaxBodySize:
+		if size, err := strconv.ParseInt(a.Value, 10, 64); err != nil || size < 0 {
+			return fmt.Errorf("invalid body size: %q", a.Value)
+		}
+	default:
+		return fmt.Errorf("unknown assertion type: %q", a.Type)
+	}
+	return nil
+}
+
+// Evaluate returns an error describing why the response does not satisfy
+// the assertion, or nil if it does
+func (a Assertion) Evaluate(resp Response) error {
+	switch a.Type {
+	case AssertStatusCode:
+		codes, err := parseStatusCodes(a.Value)
+		if err != nil {
+			return err
+		}
+		if !codes.contains(resp.StatusCode) {
+			return fmt.Errorf("status code %d not in %s", resp.StatusCode, a.Value)
+		}
+	case AssertContains:
+		if !bytes.Contains(resp.Body, []byte(a.Value)) {
+			return fmt.Errorf("body does not contain %q", a.Value)
+		}
+	case AssertNotContains:
+		if bytes.Contains(resp.Body, []byte(a.Value)) {
+			return fmt.Errorf("body contains %q", a.Value)
+		}
+	case AssertRegex:
+		re, err := regexp.Compile(a.Value)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+		if !re.Match(resp.Body) {
+			return fmt.Errorf("body does not match /%s/", a.Value)
+		}
+	case AssertJSONPath:
+		actual, err := lookupJSONPath(resp.Body, a.Target)
+		if err != nil {
+			return fmt.Errorf("json %s: %w", a.Target, err)
+		}
+		if actual != a.Value {
+			return fmt.Errorf("json %s is %q, expected %q", a.Target, actual, a.Value)
+		}
+	case AssertHeader:
+		actual := resp.Header.Get(a.Target)
+		if actual != a.Value {
+			return fmt.Errorf("header %s is %q, expected %q", a.Target, actual, a.Value)
+		}
+	case AssertMaxBodySize:
+		limit, err := strconv.ParseInt(a.Value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid body size: %q", a.Value)
+		}
+		if resp.Size > limit {
+			return fmt.Errorf("body size %d exceeds %d bytes", resp.Size, limit)
+		}
+	default:
+		return fmt.Errorf("unknown assertion type: %q", a.Type)
+	}
+	return nil
+}
+
+// String formats the assertion as a line of the assertion DSL
+func (a Assertion) String() string {
+	if a.Target != "" {
+		return a.Type + " " + a.Target + " " + a.Value
+	}
+	return a.Type + " " + a.Value
+}
+
+// Assertions is the ordered list of expectations for a target
+type Assertions []Assertion
+
+// Validate checks every assertion in the list
+func (as Assertions) Validate() error {
+	for i, a := range as {
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("assertion %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the first failing assertion's error
+func (as Assertions) Evaluate(resp Response) error {
+	for _, a := range as {
+		if err := a.Evaluate(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasStatusCode reports whether the list decides which status codes are
+// acceptable, replacing the default "below 400" rule
+func (as Assertions) HasStatusCode() bool {
+	for _, a := range as {
+		if a.Type == AssertStatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the list as assertion DSL, one assertion per line
+func (as Assertions) String() string {
+	lines := make([]string, len(as))
+	for i, a := range as {
+		lines[i] = a.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseAssertions parses the assertion DSL, one assertion per line:
+//
+//	status 200,201,300-399
+//	contains OK
+//	not_contains Database connection failed
+//	regex "version":\s*"\d+
+//	json data.items[0].status healthy
+//	header Content-Type application/json
+//	max_body_size 65536
+//
+// Blank lines and lines starting with # are ignored.
+func ParseAssertions(text string) (Assertions, error) {
+	var assertions Assertions
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kind, rest, _ := strings.Cut(line, " ")
+		a := Assertion{Type: kind}
+		rest = strings.TrimSpace(rest)
+		if kind == AssertJSONPath || kind == AssertHeader {
+			a.Target, rest, _ = strings.Cut(rest, " ")
+			rest = strings.TrimSpace(rest)
+		}
+		a.Value = rest
+
+		if err := a.Validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		assertions = append(assertions, a)
+	}
+	return assertions, nil
+}
+
+type statusRange struct{ from, to int }
+
+type statusCodes []statusRange
+
+func (c statusCodes) contains(code int) bool {
+	for _, r := range c {
+		if code >= r.from && code <= r.to {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStatusCodes parses a comma separated list of codes and ranges such as
+// "200,204,300-399"
+func parseStatusCodes(value string) (statusCodes, error) {
+	var codes statusCodes
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fromStr, toStr, isRange := strings.Cut(part, "-")
+		if !isRange {
+			toStr = fromStr
+		}
+		from, err1 := strconv.Atoi(strings.TrimSpace(fromStr))
+		to, err2 := strconv.Atoi(strings.TrimSpace(toStr))
+		if err1 != nil || err2 != nil || from < 100 || to > 599 || from > to {
+			return nil, fmt.Errorf("invalid status code: %q", part)
+		}
+		codes = append(codes, statusRange{from, to})
+	}
+
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("status requires at least one code")
+	}
+	return codes, nil
+}
+
+// parseJSONPath splits a dotted path such as "$.data.items[0].status" into
+// object keys and array indexes
+func parseJSONPath(path string) ([]string, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return nil, fmt.Errorf("json requires a path")
+	}
+
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		key, index, hasIndex := strings.Cut(part, "[")
+		if key != "" {
+			segments = append(segments, key)
+		}
+		for hasIndex {
+			var idx string
+			idx, index, _ = strings.Cut(index, "]")
+			if _, err := strconv.Atoi(idx); err != nil {
+				return nil, fmt.Errorf("invalid array index in path %q", path)
+			}
+			segments = append(segments, idx)
+			_, index, hasIndex = strings.Cut(index, "[")
+		}
+		if key == "" && !strings.Contains(part, "[") {
+			return nil, fmt.Errorf("empty segment in path %q", path)
+		}
+	}
+	return segments, nil
+}
+
+// lookupJSONPath returns the value at path formatted for comparison: strings
+// as-is, everything else as compact JSON
+func lookupJSONPath(body []byte, path string) (string, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	var current any
+	if err := json.Unmarshal(body, &current); err != nil {
+		return "", fmt.Errorf("response is not valid JSON")
+	}
+
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return "", fmt.Errorf("key %q not found", segment)
+			}
+			current = value
+		case []any:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return "", fmt.Errorf("index %s out of range", segment)
+			}
+			current = node[idx]
+		default:
+			return "", fmt.Errorf("cannot descend into %q", segment)
+		}
+	}
+
+	if s, ok := current.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(current)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,167 @@
+package monitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssertion_Evaluate(t *testing.T) {
+	resp := Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       []byte(`{"status":"ok","data":{"items":[{"id":1,"healthy":true}]},"version":"1.4.2"}`),
+		Size:       76,
+	}
+
+	tests := []struct {
+		name      string
+		assertion Assertion
+		wantErr   string
+	}{
+		{name: "status in list", assertion: Assertion{Type: AssertStatusCode, Value: "200,204"}},
+		{name: "status in range", assertion: Assertion{Type: AssertStatusCode, Value: "200-299"}},
+		{name: "status outside set", assertion: Assertion{Type: AssertStatusCode, Value: "201,300-399"}, wantErr: "status code 200 not in 201,300-399"},
+		{name: "contains", assertion: Assertion{Type: AssertContains, Value: `"status":"ok"`}},
+		{name: "contains missing", assertion: Assertion{Type: AssertContains, Value: "ready"}, wantErr: `body does not contain "ready"`},
+		{name: "not contains", assertion: Assertion{Type: AssertNotContains, Value: "Database connection failed"}},
+		{name: "not contains present", assertion: Assertion{Type: AssertNotContains, Value: "healthy"}, wantErr: `body contains "healthy"`},
+		{name: "regex", assertion: Assertion{Type: AssertRegex, Value: `"version":"1\.\d+\.\d+"`}},
+		{name: "regex mismatch", assertion: Assertion{Type: AssertRegex, Value: `"version":"2\.`}, wantErr: "body does not match"},
+		{name: "json string", assertion: Assertion{Type: AssertJSONPath, Target: "$.status", Value: "ok"}},
+		{name: "json nested bool", assertion: Assertion{Type: AssertJSONPath, Target: "data.items[0].healthy", Value: "true"}},
+		{name: "json number", assertion: Assertion{Type: AssertJSONPath, Target: "data.items[0].id", Value: "1"}},
+		{name: "json mismatch", assertion: Assertion{Type: AssertJSONPath, Target: "status", Value: "degraded"}, wantErr: `json status is "ok", expected "degraded"`},
+		{name: "json missing key", assertion: Assertion{Type: AssertJSONPath, Target: "data.missing", Value: "x"}, wantErr: `key "missing" not found`},
+		{name: "json index out of range", assertion: Assertion{Type: AssertJSONPath, Target: "data.items[3].id", Value: "1"}, wantErr: "index 3 out of range"},
+		{name: "header", assertion: Assertion{Type: AssertHeader, Target: "content-type", Value: "application/json"}},
+		{name: "header mismatch", assertion: Assertion{Type: AssertHeader, Target: "Cache-Control", Value: "no-store"}, wantErr: `header Cache-Control is "", expected "no-store"`},
+		{name: "body size", assertion: Assertion{Type: AssertMaxBodySize, Value: "1024"}},
+		{name: "body too large", assertion: Assertion{Type: AssertMaxBodySize, Value: "10"}, wantErr: "body size 76 exceeds 10 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.assertion.Evaluate(resp)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected assertion to pass, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseAssertions(t *testing.T) {
+	text := `
+# health endpoint
+status 200-299
+not_contains Database connection failed
+json data.status healthy
+header X-Frame-Options DENY
+max_body_size 65536
+`
+	assertions, err := ParseAssertions(text)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := Assertions{
+		{Type: AssertStatusCode, Value: "200-299"},
+		{Type: AssertNotContains, Value: "Database connection failed"},
+		{Type: AssertJSONPath, Target: "data.status", Value: "healthy"},
+		{Type: AssertHeader, Target: "X-Frame-Options", Value: "DENY"},
+		{Type: AssertMaxBodySize, Value: "65536"},
+	}
+	if len(assertions) != len(want) {
+		t.Fatalf("Expected %d assertions, got %d", len(want), len(assertions))
+	}
+	for i := range want {
+		if assertions[i] != want[i] {
+			t.Errorf("Assertion %d: expected %+v, got %+v", i, want[i], assertions[i])
+		}
+	}
+
+	roundTrip, err := ParseAssertions(assertions.String())
+	if err != nil || len(roundTrip) != len(want) {
+		t.Errorf("Expected formatted assertions to parse back, got %v (%v)", roundTrip, err)
+	}
+
+	invalid := []string{
+		"status 200-",
+		"status 700",
+		"contains",
+		"regex [",
+		"json",
+		"header",
+		"max_body_size lots",
+		"exists foo",
+	}
+	for _, line := range invalid {
+		if _, err := ParseAssertions(line); err == nil {
+			t.Errorf("Expected %q to be rejected", line)
+		}
+	}
+}
+
+func TestTargetCheck_Assertions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("Database connection failed"))
+	}))
+	defer ts.Close()
+
+	t.Run("failing assertion marks target down", func(t *testing.T) {
+		var recorded CheckResult
+		target := &Target{
+			ID:         1,
+			URL:        ts.URL,
+			Interval:   time.Minute,
+			Client:     DefaultClient,
+			Assertions: Assertions{{Type: AssertNotContains, Value: "Database connection failed"}},
+			OnCheckResult: func(_ *Target, result CheckResult) {
+				recorded = result
+			},
+		}
+
+		if err := target.Check(); err == nil {
+			t.Fatal("Expected assertion failure")
+		}
+		if target.Status != statusDown {
+			t.Errorf("Expected status %s, got %s", statusDown, target.Status)
+		}
+		if recorded.ErrorClass != ErrorClassAssertion {
+			t.Errorf("Expected error class %s, got %s", ErrorClassAssertion, recorded.ErrorClass)
+		}
+		wantReason := `assertion failed: body contains "Database connection failed"`
+		if recorded.Reason != wantReason || target.StatusReason != wantReason {
+			t.Errorf("Expected reason %q, got %q / %q", wantReason, recorded.Reason, target.StatusReason)
+		}
+	})
+
+	t.Run("status assertion overrides default rule", func(t *testing.T) {
+		target := &Target{
+			ID:         2,
+			URL:        ts.URL + "/missing",
+			Interval:   time.Minute,
+			Client:     DefaultClient,
+			Assertions: Assertions{{Type: AssertStatusCode, Value: "404"}},
+		}
+
+		if err := target.Check(); err != nil {
+			t.Fatalf("Expected 404 to be accepted, got %v", err)
+		}
+		if target.Status != statusUp || target.StatusReason != "" {
+			t.Errorf("Expected target up without reason, got %s (%q)", target.Status, target.StatusReason)
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// Kinds of target the engine can check
+const (
+	KindHTTP      = "http"
+	KindTCP       = "tcp"
+	KindTCPExpect = "tcp_expect"
+	KindDNS       = "dns"
+	KindHeartbeat = "heartbeat"
+)
+
+// Checker probes one kind of target
+type Checker interface {
+	// Validate reports whether the target is configured correctly for this kind
+	Validate(target *Target) error
+	// Probe checks the target once within ctx's deadline and reports what
+	// happened without touching the target's status
+	Probe(ctx context.Context, target *Target) (CheckResult, error)
+}
+
+var checkers = map[string]Checker{
+	KindHTTP:      httpChecker{},
+	KindTCP:       tcpChecker{},
+	KindTCPExpect: tcpChecker{expect: true},
+	KindDNS:       dnsChecker{},
+	KindHeartbeat: heartbeatChecker{},
+}
+
+// Kinds lists the target kinds in the order they are offered to users
+var Kinds = []string{KindHTTP, KindTCP, KindTCPExpect, KindDNS, KindHeartbeat}
+
+// GetKind returns the target's kind, defaulting to HTTP
+func (s *Target) GetKind() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Kind == "" {
+		return KindHTTP
+	}
+	return s.Kind
+}
+
+func (s *Target) checker() (Checker, error) {
+	checker, ok := checkers[s.GetKind()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported target kind: %s", s.Kind)
+	}
+	return checker, nil
+}
+
+// Validate checks that the target can be probed
+func (s *Target) Validate() error {
+	checker, err := s.checker()
+	if err != nil {
+		return err
+	}
+	if err := s.validateConfirmation(); err != nil {
+		return err
+	}
+	return checker.Validate(s)
+}
+
+// timeout bounds a single probe, following the HTTP client's timeout so
+// every kind of check gives up at the same point
+func (s *Target) timeout() time.Duration {
+	if s.Client != nil && s.Client.Timeout > 0 {
+		return s.Client.Timeout
+	}
+	return DefaultClientConfig.Timeout
+}
+
+// applyLatencyThreshold marks an otherwise successful result degraded when
+// it was slower than the target allows
+func (s *Target) applyLatencyThreshold(result *CheckResult) {
+	if s.LatencyThreshold > 0 && result.Latency > s.LatencyThreshold {
+		result.Status = statusDegraded
+		result.Reason = fmt.Sprintf("response time %dms exceeded threshold of %dms",
+			result.Latency.Milliseconds(), s.LatencyThreshold.Milliseconds())
+		return
+	}
+	result.Status = statusUp
+}
+
+// httpChecker sends the target's request spec and evaluates its assertions
+type httpChecker struct{}
+
+func (httpChecker) Validate(s *Target) error {
+	if err := s.Request.Validate(); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	if err := s.Assertions.Validate(); err != nil {
+		return fmt.Errorf("invalid assertions: %w", err)
+	}
+	if err := s.TLS.Validate(s.URL); err != nil {
+		return fmt.Errorf("invalid tls settings: %w", err)
+	}
+	return nil
+}
+
+func (httpChecker) Probe(ctx context.Context, s *Target) (CheckResult, error) {
+	result := CheckResult{
+		TargetID:  s.ID,
+		CheckedAt: time.Now(),
+	}
+
+	if s.TLS.Enabled {
+		// A failed handshake is left for the HTTP request below to report
+		if info, err := s.inspectTLS(ctx); err == nil {
+			result.TLS = info
+			if problem := info.Problem(s.TLS, info.CheckedAt); problem != nil {
+				result.Status = statusDown
+				result.ErrorClass = ErrorClassTLS
+				return result, fmt.Errorf("TLS check failed: %w", problem)
+			}
+		}
+		// Latency measures the HTTP request alone
+		result.CheckedAt = time.Now()
+	}
+
+	req, err := s.Request.NewRequest(s.URL)
+	if err != nil {
+		result.Status = statusError
+		return result, err
+	}
+
+	r, err := s.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		result.Latency = time.Since(result.CheckedAt)
+		result.Status = statusError
+		result.ErrorClass = classifyError(err)
+		return result, fmt.Errorf("connection error: %w", err)
+	}
+	defer r.Body.Close()
+
+	result.StatusCode = r.StatusCode
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxAssertionBodySize))
+	if err == nil {
+		var rest int64
+		rest, err = io.Copy(io.Discard, r.Body)
+		result.BytesRead = int64(len(body)) + rest
+	}
+	result.Latency = time.Since(result.CheckedAt)
+	if err != nil {
+		result.Status = statusError
+		result.ErrorClass = classifyError(err)
+		return result, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if r.StatusCode >= 400 && !s.Assertions.HasStatusCode() {
+		result.Status = statusDown
+		result.ErrorClass = ErrorClassHTTP
+		return result, fmt.Errorf("HTTP error: %d", r.StatusCode)
+	}
+
+	resp := Response{
+		StatusCode: r.StatusCode,
+		Header:     r.Header,
+		Body:       body,
+		Size:       result.BytesRead,
+	}
+	if err := s.Assertions.Evaluate(resp); err != nil {
+		result.Status = statusDown
+		result.ErrorClass = ErrorClassAssertion
+		return result, fmt.Errorf("assertion failed: %w", err)
+	}
+
+	s.applyLatencyThreshold(&result)
+	return result, nil
+}
+
+// parseHostPort extracts host:port from a scheme://host:port target URL
+func parseHostPort(rawURL, scheme string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != scheme {
+		return "", fmt.Errorf("URL must look like %s://host:port", scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return "", fmt.Errorf("URL must include a host and port")
+	}
+	return u.Host, nil
+}
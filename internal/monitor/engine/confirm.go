@@ -0,0 +1,73 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecheckTimeout bounds the immediate recheck that follows a first failure,
+// so a target that is really down is confirmed quickly
+var RecheckTimeout = 5 * time.Second
+
+// MaxConfirmChecks caps ConfirmAfter and RecoverAfter
+const MaxConfirmChecks = 10
+
+// IsFailing reports whether status means the target is not answering
+// correctly, as opposed to up or merely degraded
+func IsFailing(status string) bool {
+	return status == statusDown || status == statusError
+}
+
+// checkStreak counts how many checks in a row agreed with the latest one
+type checkStreak struct {
+	failing bool
+	count   int
+}
+
+// record adds a check that came back with status and reports whether it
+// may replace current. Moving between failing and passing statuses needs
+// confirmAfter or recoverAfter checks in a row; anything else applies at once.
+func (c *checkStreak) record(status, current string, confirmAfter, recoverAfter int) bool {
+	failing := IsFailing(status)
+	if failing == c.failing {
+		c.count++
+	} else {
+		c.failing, c.count = failing, 1
+	}
+
+	switch {
+	case failing && !IsFailing(current):
+		return c.count >= max(confirmAfter, 1)
+	case !failing && IsFailing(current):
+		return c.count >= max(recoverAfter, 1)
+	}
+	return true
+}
+
+// shouldRecheck reports whether a failed check is the first of a streak and
+// worth repeating straight away, to rule out a transient network blip.
+// Heartbeats judge pings already received, so repeating them tells nothing.
+func (s *Target) shouldRecheck() bool {
+	if s.GetKind() == KindHeartbeat {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !s.streak.failing || s.streak.count == 0
+}
+
+// recheckTimeout returns the shorter of RecheckTimeout and the usual timeout
+func (s *Target) recheckTimeout() time.Duration {
+	return min(RecheckTimeout, s.timeout())
+}
+
+// validateConfirmation checks the ConfirmAfter and RecoverAfter settings
+func (s *Target) validateConfirmation() error {
+	if s.ConfirmAfter < 0 || s.ConfirmAfter > MaxConfirmChecks {
+		return fmt.Errorf("failures before alerting must be between 0 and %d", MaxConfirmChecks)
+	}
+	if s.RecoverAfter < 0 || s.RecoverAfter > MaxConfirmChecks {
+		return fmt.Errorf("successes before recovery must be between 0 and %d", MaxConfirmChecks)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package monitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newToggleServer answers 200 while healthy is true and 503 otherwise,
+// counting the requests it receives
+func newToggleServer(t *testing.T, healthy *atomic.Bool, requests *atomic.Int32) string {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+func TestTargetCheck_ConfirmAfter(t *testing.T) {
+	var healthy atomic.Bool
+	var requests atomic.Int32
+	url := newToggleServer(t, &healthy, &requests)
+
+	var updates []string
+	target := &Target{
+		ID:           1,
+		URL:          url,
+		Status:       statusUp,
+		Client:       DefaultClient,
+		ConfirmAfter: 3,
+		OnStatusUpdate: func(_ *Target, status string) error {
+			updates = append(updates, status)
+			return nil
+		},
+	}
+
+	target.Check()
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected the first failure to be rechecked, got %d requests", got)
+	}
+
+	target.Check()
+	if got := requests.Load(); got != 3 {
+		t.Errorf("Expected later failures not to be rechecked, got %d requests", got)
+	}
+	if target.Status != statusUp || len(updates) != 0 {
+		t.Fatalf("Expected status to stay up before confirmation, got %s with updates %v", target.Status, updates)
+	}
+
+	target.Check()
+	if target.Status != statusDown {
+		t.Errorf("Expected status %s after 3 failures, got %s", statusDown, target.Status)
+	}
+	if target.StatusReason != "HTTP error: 503" {
+		t.Errorf("Expected failure reason, got %q", target.StatusReason)
+	}
+	if len(updates) != 1 {
+		t.Errorf("Expected one status update, got %v", updates)
+	}
+}
+
+func TestTargetCheck_RecoverAfter(t *testing.T) {
+	var healthy atomic.Bool
+	var requests atomic.Int32
+	url := newToggleServer(t, &healthy, &requests)
+	healthy.Store(true)
+
+	target := &Target{
+		ID:           1,
+		URL:          url,
+		Status:       statusDown,
+		StatusReason: "HTTP error: 503",
+		Client:       DefaultClient,
+		RecoverAfter: 2,
+	}
+
+	target.Check()
+	if target.Status != statusDown {
+		t.Fatalf("Expected status to stay down after one success, got %s", target.Status)
+	}
+	if target.StatusReason != "HTTP error: 503" {
+		t.Errorf("Expected reason to be kept until recovery, got %q", target.StatusReason)
+	}
+
+	// A failure in between restarts the count
+	healthy.Store(false)
+	target.Check()
+	healthy.Store(true)
+	target.Check()
+	if target.Status != statusDown {
+		t.Fatalf("Expected status to stay down after an interrupted streak, got %s", target.Status)
+	}
+
+	target.Check()
+	if target.Status != statusUp {
+		t.Errorf("Expected status %s after 2 successes, got %s", statusUp, target.Status)
+	}
+	if target.StatusReason != "" {
+		t.Errorf("Expected reason to be cleared, got %q", target.StatusReason)
+	}
+}
+
+func TestTargetCheck_RecheckClearsTransientFailure(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+
+	var recorded []CheckResult
+	target := &Target{
+		ID:     1,
+		URL:    ts.URL,
+		Status: statusUp,
+		Client: DefaultClient,
+		OnStatusUpdate: func(_ *Target, status string) error {
+			t.Errorf("Unexpected status update to %s", status)
+			return nil
+		},
+		OnCheckResult: func(_ *Target, result CheckResult) {
+			recorded = append(recorded, result)
+		},
+	}
+
+	if err := target.Check(); err != nil {
+		t.Fatalf("Expected the recheck to succeed, got %v", err)
+	}
+	if len(recorded) != 1 || !recorded[0].IsUp() {
+		t.Errorf("Expected only the recheck to be recorded, got %+v", recorded)
+	}
+}
+
+func TestTarget_recheckTimeout(t *testing.T) {
+	target := &Target{Client: &http.Client{Timeout: 30 * time.Second}}
+	if got := target.recheckTimeout(); got != RecheckTimeout {
+		t.Errorf("Expected %s, got %s", RecheckTimeout, got)
+	}
+
+	target.Client.Timeout = time.Second
+	if got := target.recheckTimeout(); got != time.Second {
+		t.Errorf("Expected the client timeout when shorter, got %s", got)
+	}
+}
+
+func TestCheckStreak_record(t *testing.T) {
+	tests := []struct {
+		name         string
+		current      string
+		statuses     []string
+		confirmAfter int
+		recoverAfter int
+		want         []bool
+	}{
+		{
+			name:     "immediate by default",
+			current:  statusUp,
+			statuses: []string{statusDown, statusUp},
+			want:     []bool{true, true},
+		},
+		{
+			name:         "down confirmed on third failure",
+			current:      statusUp,
+			statuses:     []string{statusDown, statusError, statusDown},
+			confirmAfter: 3,
+			want:         []bool{false, false, true},
+		},
+		{
+			name:         "degraded is not a failure",
+			current:      statusUp,
+			statuses:     []string{statusDegraded},
+			confirmAfter: 3,
+			want:         []bool{true},
+		},
+		{
+			name:         "down to error applies at once",
+			current:      statusDown,
+			statuses:     []string{statusError},
+			confirmAfter: 3,
+			recoverAfter: 3,
+			want:         []bool{true},
+		},
+		{
+			name:         "recovery needs successes in a row",
+			current:      statusDown,
+			statuses:     []string{statusUp, statusDown, statusUp, statusDegraded},
+			recoverAfter: 2,
+			want:         []bool{false, true, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var streak checkStreak
+			current := tt.current
+			for i, status := range tt.statuses {
+				got := streak.record(status, current, tt.confirmAfter, tt.recoverAfter)
+				if got != tt.want[i] {
+					t.Errorf("check %d (%s): got %v, want %v", i+1, status, got, tt.want[i])
+				}
+				if got {
+					current = status
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DNS record types a DNS check can query
+const (
+	RecordA     = "A"
+	RecordAAAA  = "AAAA"
+	RecordCNAME = "CNAME"
+	RecordMX    = "MX"
+	RecordTXT   = "TXT"
+	RecordNS    = "NS"
+)
+
+// RecordTypes lists the supported record types in the order they are offered
+var RecordTypes = []string{RecordA, RecordAAAA, RecordCNAME, RecordMX, RecordTXT, RecordNS}
+
+// DNSSpec describes the query a DNS check sends and what the answer must be
+type DNSSpec struct {
+	RecordType   string   `json:"record_type,omitempty"`    // defaults to A
+	Resolver     string   `json:"resolver,omitempty"`       // host[:port], empty uses the system resolver
+	Expected     []string `json:"expected,omitempty"`       // the exact answer set, in any order; empty accepts any answer
+	MaxResolveMs int64    `json:"max_resolve_ms,omitempty"` // slower resolutions fail the check, zero disables
+}
+
+// GetRecordType returns the queried record type, defaulting to A
+func (d DNSSpec) GetRecordType() string {
+	if d.RecordType == "" {
+		return RecordA
+	}
+	return strings.ToUpper(d.RecordType)
+}
+
+// GetMaxResolveTime returns the slowest acceptable resolution, zero when unlimited
+func (d DNSSpec) GetMaxResolveTime() time.Duration {
+	return time.Duration(d.MaxResolveMs) * time.Millisecond
+}
+
+// resolverAddr returns the resolver as host:port, defaulting the port to 53
+func (d DNSSpec) resolverAddr() (string, error) {
+	if _, _, err := net.SplitHostPort(d.Resolver); err == nil {
+		return d.Resolver, nil
+	}
+	host := strings.Trim(d.Resolver, "[]")
+	if net.ParseIP(host) == nil && (host == "" || strings.ContainsAny(host, ":/ ")) {
+		return "", fmt.Errorf("invalid resolver address: %s", d.Resolver)
+	}
+	return net.JoinHostPort(host, "53"), nil
+}
+
+// DNSAnswer is the normalised, sorted answer set a DNS check received
+type DNSAnswer struct {
+	Records    []string
+	ResolvedAt time.Time
+}
+
+// Equal reports whether both answers hold the same records
+func (a DNSAnswer) Equal(other DNSAnswer) bool {
+	return slices.Equal(a.Records, other.Records)
+}
+
+// String lists the records, e.g. "1.1.1.1, 1.0.0.1"
+func (a DNSAnswer) String() string {
+	if len(a.Records) == 0 {
+		return "(empty)"
+	}
+	return strings.Join(a.Records, ", ")
+}
+
+// normaliseRecords sorts the records so the same answer always compares
+// equal. Names are lower-cased without the trailing root dot; TXT values are
+// case-sensitive and kept as they are.
+func normaliseRecords(recordType string, records []string) []string {
+	normalised := make([]string, 0, len(records))
+	for _, record := range records {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+		if recordType != RecordTXT {
+			record = strings.TrimSuffix(strings.ToLower(record), ".")
+		}
+		normalised = append(normalised, record)
+	}
+	slices.Sort(normalised)
+	return slices.Compact(normalised)
+}
+
+// dnsChecker resolves dns://name with the target's DNSSpec
+type dnsChecker struct{}
+
+// parseDNSName extracts the queried name from a dns://name target URL
+func parseDNSName(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "dns" || u.Hostname() == "" || u.Port() != "" {
+		return "", fmt.Errorf("URL must look like dns://name")
+	}
+	return u.Hostname(), nil
+}
+
+func (dnsChecker) Validate(s *Target) error {
+	if _, err := parseDNSName(s.URL); err != nil {
+		return err
+	}
+	if len(s.Assertions) > 0 {
+		return fmt.Errorf("assertions are only supported for HTTP targets")
+	}
+	if s.TLS.Enabled {
+		return fmt.Errorf("TLS checks are only supported for HTTP targets")
+	}
+	if !slices.Contains(RecordTypes, s.DNS.GetRecordType()) {
+		return fmt.Errorf("unsupported record type: %s", s.DNS.RecordType)
+	}
+	if s.DNS.Resolver != "" {
+		if _, err := s.DNS.resolverAddr(); err != nil {
+			return err
+		}
+	}
+	if s.DNS.MaxResolveMs < 0 {
+		return fmt.Errorf("max resolution time cannot be negative")
+	}
+	return nil
+}
+
+func (dnsChecker) Probe(ctx context.Context, s *Target) (CheckResult, error) {
+	result := CheckResult{
+		TargetID:  s.ID,
+		CheckedAt: time.Now(),
+	}
+
+	name, err := parseDNSName(s.URL)
+	if err != nil {
+		result.Status = statusError
+		return result, err
+	}
+
+	resolver := net.DefaultResolver
+	if s.DNS.Resolver != "" {
+		addr, err := s.DNS.resolverAddr()
+		if err != nil {
+			result.Status = statusError
+			return result, err
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, addr)
+			},
+		}
+	}
+
+	recordType := s.DNS.GetRecordType()
+	records, err := lookup(ctx, resolver, recordType, name)
+	result.Latency = time.Since(result.CheckedAt)
+	if err != nil {
+		result.Status = statusError
+		result.ErrorClass = classifyError(err)
+		return result, fmt.Errorf("DNS lookup failed: %w", err)
+	}
+
+	answer := DNSAnswer{Records: normaliseRecords(recordType, records), ResolvedAt: time.Now()}
+	result.DNS = &answer
+
+	if limit := s.DNS.GetMaxResolveTime(); limit > 0 && result.Latency > limit {
+		result.Status = statusDown
+		result.ErrorClass = ErrorClassTimeout
+		return result, fmt.Errorf("resolution took %dms, exceeding limit of %dms",
+			result.Latency.Milliseconds(), limit.Milliseconds())
+	}
+
+	if len(s.DNS.Expected) > 0 {
+		expected := DNSAnswer{Records: normaliseRecords(recordType, s.DNS.Expected)}
+		if !answer.Equal(expected) {
+			result.Status = statusDown
+			result.ErrorClass = ErrorClassAssertion
+			return result, fmt.Errorf("unexpected %s answer: got %s, want %s", recordType, answer, expected)
+		}
+	}
+
+	s.applyLatencyThreshold(&result)
+	return result, nil
+}
+
+// lookup resolves name as an absolute name so resolv.conf search domains
+// never apply, and formats each record as it is compared
+func lookup(ctx context.Context, resolver *net.Resolver, recordType, name string) ([]string, error) {
+	fqdn := name
+	if !strings.HasSuffix(fqdn, ".") {
+		fqdn += "."
+	}
+
+	var records []string
+	switch recordType {
+	case RecordA, RecordAAAA:
+		network := "ip4"
+		if recordType == RecordAAAA {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			records = append(records, ip.String())
+		}
+	case RecordCNAME:
+		cname, err := resolver.LookupCNAME(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, cname)
+	case RecordMX:
+		mxs, err := resolver.LookupMX(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			records = append(records, strconv.Itoa(int(mx.Pref))+" "+mx.Host)
+		}
+	case RecordTXT:
+		txts, err := resolver.LookupTXT(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, txts...)
+	case RecordNS:
+		nss, err := resolver.LookupNS(ctx, fqdn)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			records = append(records, ns.Host)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,317 @@
+package monitor
+
+import (
+	"encoding/binary"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	dnsTypeA     = 1
+	dnsTypeNS    = 2
+	dnsTypeCNAME = 5
+	dnsTypeMX    = 15
+	dnsTypeTXT   = 16
+	dnsTypeAAAA  = 28
+)
+
+type dnsRecord struct {
+	typ  uint16
+	data []byte
+}
+
+// dnsServer is a minimal authoritative UDP server answering from records.
+// Names without records get NXDOMAIN.
+type dnsServer struct {
+	addr    string
+	mu      sync.Mutex
+	records map[string][]dnsRecord
+	delay   time.Duration
+}
+
+func newDNSServer(t *testing.T) *dnsServer {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	s := &dnsServer{addr: conn.LocalAddr().String(), records: make(map[string][]dnsRecord)}
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := s.answer(buf[:n]); resp != nil {
+				conn.WriteTo(resp, from)
+			}
+		}
+	}()
+	return s
+}
+
+func (s *dnsServer) set(name string, records ...dnsRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[strings.ToLower(name)] = records
+}
+
+func (s *dnsServer) answer(req []byte) []byte {
+	if len(req) < 12 {
+		return nil
+	}
+
+	// Walk the question name to find where the question ends
+	var labels []string
+	i := 12
+	for i < len(req) && req[i] != 0 {
+		end := i + 1 + int(req[i])
+		if end > len(req) {
+			return nil
+		}
+		labels = append(labels, string(req[i+1:end]))
+		i = end
+	}
+	if i+5 > len(req) {
+		return nil
+	}
+	qtype := binary.BigEndian.Uint16(req[i+1:])
+	question := req[12 : i+5]
+	name := strings.ToLower(strings.Join(labels, "."))
+
+	s.mu.Lock()
+	records, found := s.records[name]
+	delay := s.delay
+	s.mu.Unlock()
+	time.Sleep(delay)
+
+	var answers []dnsRecord
+	for _, record := range records {
+		if record.typ == qtype || record.typ == dnsTypeCNAME {
+			answers = append(answers, record)
+		}
+	}
+
+	flags := uint16(0x8000 | 0x0400 | 0x0080) // response, authoritative, recursion available
+	flags |= binary.BigEndian.Uint16(req[2:]) & 0x0100
+	if !found {
+		flags |= 3 // NXDOMAIN
+	}
+
+	resp := append([]byte{}, req[0:2]...)
+	resp = binary.BigEndian.AppendUint16(resp, flags)
+	resp = binary.BigEndian.AppendUint16(resp, 1)
+	resp = binary.BigEndian.AppendUint16(resp, uint16(len(answers)))
+	resp = binary.BigEndian.AppendUint16(resp, 0)
+	resp = binary.BigEndian.AppendUint16(resp, 0)
+	resp = append(resp, question...)
+	for _, record := range answers {
+		resp = append(resp, 0xc0, 12) // pointer to the question name
+		resp = binary.BigEndian.AppendUint16(resp, record.typ)
+		resp = binary.BigEndian.AppendUint16(resp, 1)
+		resp = binary.BigEndian.AppendUint32(resp, 60)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(record.data)))
+		resp = append(resp, record.data...)
+	}
+	return resp
+}
+
+func encodeDNSName(name string) []byte {
+	var b []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+func aRecord(ip string) dnsRecord {
+	return dnsRecord{typ: dnsTypeA, data: net.ParseIP(ip).To4()}
+}
+
+func aaaaRecord(ip string) dnsRecord {
+	return dnsRecord{typ: dnsTypeAAAA, data: net.ParseIP(ip).To16()}
+}
+
+func cnameRecord(host string) dnsRecord {
+	return dnsRecord{typ: dnsTypeCNAME, data: encodeDNSName(host)}
+}
+
+func nsRecord(host string) dnsRecord {
+	return dnsRecord{typ: dnsTypeNS, data: encodeDNSName(host)}
+}
+
+func mxRecord(pref uint16, host string) dnsRecord {
+	return dnsRecord{typ: dnsTypeMX, data: append(binary.BigEndian.AppendUint16(nil, pref), encodeDNSName(host)...)}
+}
+
+func txtRecord(text string) dnsRecord {
+	return dnsRecord{typ: dnsTypeTXT, data: append([]byte{byte(len(text))}, text...)}
+}
+
+func TestTargetCheck_DNS(t *testing.T) {
+	server := newDNSServer(t)
+	server.set("example.test", aRecord("192.0.2.2"), aRecord("192.0.2.1"), aaaaRecord("2001:db8::1"),
+		mxRecord(10, "Mail.Example.Test"), txtRecord("v=spf1 -all"), nsRecord("ns1.example.test"))
+	server.set("www.example.test", cnameRecord("example.test"))
+
+	tests := []struct {
+		name        string
+		url         string
+		spec        DNSSpec
+		wantStatus  string
+		wantClass   string
+		wantRecords []string
+		wantReason  string
+	}{
+		{
+			name:        "A records",
+			url:         "dns://example.test",
+			spec:        DNSSpec{Expected: []string{"192.0.2.1", "192.0.2.2"}},
+			wantStatus:  statusUp,
+			wantRecords: []string{"192.0.2.1", "192.0.2.2"},
+		},
+		{
+			name:        "AAAA record",
+			url:         "dns://example.test",
+			spec:        DNSSpec{RecordType: RecordAAAA},
+			wantStatus:  statusUp,
+			wantRecords: []string{"2001:db8::1"},
+		},
+		{
+			name:        "CNAME record",
+			url:         "dns://www.example.test",
+			spec:        DNSSpec{RecordType: RecordCNAME, Expected: []string{"example.test."}},
+			wantStatus:  statusUp,
+			wantRecords: []string{"example.test"},
+		},
+		{
+			name:        "MX record",
+			url:         "dns://example.test",
+			spec:        DNSSpec{RecordType: RecordMX, Expected: []string{"10 mail.example.test"}},
+			wantStatus:  statusUp,
+			wantRecords: []string{"10 mail.example.test"},
+		},
+		{
+			name:        "TXT record",
+			url:         "dns://example.test",
+			spec:        DNSSpec{RecordType: RecordTXT},
+			wantStatus:  statusUp,
+			wantRecords: []string{"v=spf1 -all"},
+		},
+		{
+			name:        "NS record",
+			url:         "dns://example.test",
+			spec:        DNSSpec{RecordType: RecordNS},
+			wantStatus:  statusUp,
+			wantRecords: []string{"ns1.example.test"},
+		},
+		{
+			name:        "unexpected answer",
+			url:         "dns://example.test",
+			spec:        DNSSpec{Expected: []string{"192.0.2.1"}},
+			wantStatus:  statusDown,
+			wantClass:   ErrorClassAssertion,
+			wantRecords: []string{"192.0.2.1", "192.0.2.2"},
+			wantReason:  "unexpected A answer: got 192.0.2.1, 192.0.2.2, want 192.0.2.1",
+		},
+		{
+			name:       "unknown name",
+			url:        "dns://missing.example.test",
+			wantStatus: statusError,
+			wantClass:  ErrorClassDNS,
+			wantReason: "DNS lookup failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec.Resolver = server.addr
+
+			var recorded CheckResult
+			target := &Target{
+				ID:   1,
+				Kind: KindDNS,
+				URL:  tt.url,
+				DNS:  tt.spec,
+				OnCheckResult: func(_ *Target, result CheckResult) {
+					recorded = result
+				},
+			}
+
+			target.Check()
+
+			if target.Status != tt.wantStatus {
+				t.Errorf("Expected status %s, got %s (%s)", tt.wantStatus, target.Status, target.StatusReason)
+			}
+			if recorded.ErrorClass != tt.wantClass {
+				t.Errorf("Expected error class %q, got %q", tt.wantClass, recorded.ErrorClass)
+			}
+			if tt.wantRecords != nil && (recorded.DNS == nil || !reflect.DeepEqual(recorded.DNS.Records, tt.wantRecords)) {
+				t.Errorf("Expected records %v, got %+v", tt.wantRecords, recorded.DNS)
+			}
+			if !strings.HasPrefix(target.StatusReason, tt.wantReason) {
+				t.Errorf("Expected reason starting %q, got %q", tt.wantReason, target.StatusReason)
+			}
+		})
+	}
+}
+
+func TestTargetCheck_DNSMaxResolveTime(t *testing.T) {
+	server := newDNSServer(t)
+	server.set("example.test", aRecord("192.0.2.1"))
+	server.mu.Lock()
+	server.delay = 50 * time.Millisecond
+	server.mu.Unlock()
+
+	target := &Target{
+		ID:   1,
+		Kind: KindDNS,
+		URL:  "dns://example.test",
+		DNS:  DNSSpec{Resolver: server.addr, MaxResolveMs: 10},
+	}
+
+	if err := target.Check(); err == nil {
+		t.Fatal("Expected slow resolution to fail the check")
+	}
+	if target.Status != statusDown {
+		t.Errorf("Expected status %s, got %s", statusDown, target.Status)
+	}
+	if !strings.HasPrefix(target.StatusReason, "resolution took") {
+		t.Errorf("Expected resolution time reason, got %q", target.StatusReason)
+	}
+}
+
+func TestDNSChecker_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  *Target
+		wantErr bool
+	}{
+		{name: "default record type", target: &Target{Kind: KindDNS, URL: "dns://example.com"}},
+		{name: "resolver without port", target: &Target{Kind: KindDNS, URL: "dns://example.com", DNS: DNSSpec{RecordType: "mx", Resolver: "1.1.1.1"}}},
+		{name: "ipv6 resolver with port", target: &Target{Kind: KindDNS, URL: "dns://example.com", DNS: DNSSpec{Resolver: "[2606:4700::1111]:53"}}},
+		{name: "missing name", target: &Target{Kind: KindDNS, URL: "dns://"}, wantErr: true},
+		{name: "http URL", target: &Target{Kind: KindDNS, URL: "https://example.com"}, wantErr: true},
+		{name: "unsupported record type", target: &Target{Kind: KindDNS, URL: "dns://example.com", DNS: DNSSpec{RecordType: "SRV"}}, wantErr: true},
+		{name: "invalid resolver", target: &Target{Kind: KindDNS, URL: "dns://example.com", DNS: DNSSpec{Resolver: "not a resolver"}}, wantErr: true},
+		{name: "negative max resolution time", target: &Target{Kind: KindDNS, URL: "dns://example.com", DNS: DNSSpec{MaxResolveMs: -1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.target.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Ping events a heartbeat target receives
+const (
+	PingSuccess = "success"
+	PingStart   = "start"
+	PingFail    = "fail"
+)
+
+// HeartbeatPollInterval caps how long a missed ping can go unnoticed when
+// the expected period is longer
+var HeartbeatPollInterval = time.Minute
+
+// HeartbeatSpec configures a push-based target. Pings are expected every
+// Target.Interval, and may arrive up to GraceSeconds late. A job that pinged
+// start must also report back within the grace time, when one is set.
+type HeartbeatSpec struct {
+	Token        string `json:"-"` // secret part of the ping URL, stored in its own column
+	GraceSeconds int    `json:"grace_seconds,omitempty"`
+}
+
+// GetGrace returns how late a ping may arrive
+func (h HeartbeatSpec) GetGrace() time.Duration {
+	return time.Duration(h.GraceSeconds) * time.Second
+}
+
+// heartbeatState is what the pings received so far tell about the job
+type heartbeatState struct {
+	waitingSince time.Time     // when monitoring started, until the first ping
+	lastPingAt   time.Time     // last success or failure ping
+	startedAt    time.Time     // set by a start ping until the job reports back
+	duration     time.Duration // how long the last run took, when it pinged start
+	failed       bool
+	exitCode     int
+}
+
+// Ping records a ping for a heartbeat target and checks it straight away.
+// A non-zero exitCode fails the run like a fail ping does.
+func (s *Target) Ping(event string, exitCode int) error {
+	if s.GetKind() != KindHeartbeat {
+		return fmt.Errorf("target %d does not accept pings", s.ID)
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	switch event {
+	case PingStart:
+		s.heartbeat.startedAt = now
+		s.mu.Unlock()
+		return nil
+	case PingSuccess, PingFail:
+		s.heartbeat.duration = 0
+		if !s.heartbeat.startedAt.IsZero() {
+			s.heartbeat.duration = now.Sub(s.heartbeat.startedAt)
+		}
+		s.heartbeat.startedAt = time.Time{}
+		s.heartbeat.lastPingAt = now
+		s.heartbeat.failed = event == PingFail || exitCode != 0
+		s.heartbeat.exitCode = exitCode
+	default:
+		s.mu.Unlock()
+		return fmt.Errorf("unknown ping event: %s", event)
+	}
+	s.mu.Unlock()
+
+	s.Check()
+	return nil
+}
+
+// pollInterval returns how often the manager checks the target
+func (s *Target) pollInterval() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Kind == KindHeartbeat && s.Interval > HeartbeatPollInterval {
+		return HeartbeatPollInterval
+	}
+	return s.Interval
+}
+
+// heartbeatChecker judges a push-based target by the pings it has received
+type heartbeatChecker struct{}
+
+func (heartbeatChecker) Validate(s *Target) error {
+	if _, err := parseHeartbeatName(s.URL); err != nil {
+		return err
+	}
+	if len(s.Assertions) > 0 {
+		return fmt.Errorf("assertions are only supported for HTTP targets")
+	}
+	if s.TLS.Enabled {
+		return fmt.Errorf("TLS checks are only supported for HTTP targets")
+	}
+	if s.Heartbeat.GraceSeconds < 0 {
+		return fmt.Errorf("grace time cannot be negative")
+	}
+	return nil
+}
+
+// parseHeartbeatName extracts the job name from a heartbeat://name target URL
+func parseHeartbeatName(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "heartbeat" || u.Hostname() == "" {
+		return "", fmt.Errorf("URL must look like heartbeat://name")
+	}
+	return u.Hostname(), nil
+}
+
+func (heartbeatChecker) Probe(_ context.Context, s *Target) (CheckResult, error) {
+	now := time.Now()
+	result := CheckResult{
+		TargetID:  s.ID,
+		CheckedAt: now,
+	}
+
+	s.mu.Lock()
+	if s.heartbeat.waitingSince.IsZero() {
+		s.heartbeat.waitingSince = now
+	}
+	state := s.heartbeat
+	s.mu.Unlock()
+
+	grace := s.Heartbeat.GetGrace()
+	result.Latency = state.duration
+
+	if !state.startedAt.IsZero() && grace > 0 && now.Sub(state.startedAt) > grace {
+		result.Status = statusDown
+		result.ErrorClass = ErrorClassHeartbeat
+		return result, fmt.Errorf("job started at %s did not finish within %s",
+			state.startedAt.UTC().Format(time.RFC3339), grace)
+	}
+
+	if state.lastPingAt.IsZero() {
+		if now.Sub(state.waitingSince) <= s.Interval+grace {
+			// Nothing to judge until the first ping is due
+			return result, nil
+		}
+		result.Status = statusDown
+		result.ErrorClass = ErrorClassHeartbeat
+		return result, fmt.Errorf("no ping received since monitoring started at %s", state.waitingSince.UTC().Format(time.RFC3339))
+	}
+
+	if now.Sub(state.lastPingAt) > s.Interval+grace {
+		result.Status = statusDown
+		result.ErrorClass = ErrorClassHeartbeat
+		return result, fmt.Errorf("ping missed, last received at %s", state.lastPingAt.UTC().Format(time.RFC3339))
+	}
+
+	if state.failed {
+		result.Status = statusDown
+		result.ErrorClass = ErrorClassHeartbeat
+		if state.exitCode != 0 {
+			return result, fmt.Errorf("job exited with code %d", state.exitCode)
+		}
+		return result, fmt.Errorf("job reported failure")
+	}
+
+	s.applyLatencyThreshold(&result)
+	return result, nil
+}
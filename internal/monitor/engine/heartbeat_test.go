@@ -0,0 +1,169 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newHeartbeatTarget(interval time.Duration, graceSeconds int) (*Target, *[]CheckResult) {
+	var results []CheckResult
+	target := &Target{
+		ID:        1,
+		Kind:      KindHeartbeat,
+		URL:       "heartbeat://nightly-backup",
+		Interval:  interval,
+		Heartbeat: HeartbeatSpec{GraceSeconds: graceSeconds},
+		OnCheckResult: func(_ *Target, result CheckResult) {
+			results = append(results, result)
+		},
+	}
+	return target, &results
+}
+
+func TestTargetPing(t *testing.T) {
+	t.Run("success ping marks the target up", func(t *testing.T) {
+		target, results := newHeartbeatTarget(time.Hour, 0)
+
+		if err := target.Ping(PingSuccess, 0); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if target.Status != statusUp {
+			t.Errorf("Expected status %s, got %s", statusUp, target.Status)
+		}
+		if len(*results) != 1 {
+			t.Errorf("Expected the ping to be recorded, got %d results", len(*results))
+		}
+	})
+
+	t.Run("start then success measures the run", func(t *testing.T) {
+		target, results := newHeartbeatTarget(time.Hour, 0)
+
+		target.Ping(PingStart, 0)
+		if len(*results) != 0 {
+			t.Fatalf("Expected a start ping to record nothing, got %d results", len(*results))
+		}
+		time.Sleep(20 * time.Millisecond)
+		target.Ping(PingSuccess, 0)
+
+		if got := (*results)[0].Latency; got < 20*time.Millisecond {
+			t.Errorf("Expected the run duration as latency, got %s", got)
+		}
+	})
+
+	t.Run("failure pings mark the target down", func(t *testing.T) {
+		target, _ := newHeartbeatTarget(time.Hour, 0)
+
+		target.Ping(PingFail, 0)
+		if target.Status != statusDown || target.StatusReason != "job reported failure" {
+			t.Errorf("Expected down with failure reason, got %s: %q", target.Status, target.StatusReason)
+		}
+
+		target.Ping(PingSuccess, 3)
+		if target.Status != statusDown || target.StatusReason != "job exited with code 3" {
+			t.Errorf("Expected down with exit code reason, got %s: %q", target.Status, target.StatusReason)
+		}
+
+		target.Ping(PingSuccess, 0)
+		if target.Status != statusUp {
+			t.Errorf("Expected recovery on success, got %s", target.Status)
+		}
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		target, _ := newHeartbeatTarget(time.Hour, 0)
+		if err := target.Ping("restart", 0); err == nil {
+			t.Error("Expected unknown event to be rejected")
+		}
+	})
+
+	t.Run("pull targets reject pings", func(t *testing.T) {
+		target := &Target{ID: 1, URL: "https://example.com"}
+		if err := target.Ping(PingSuccess, 0); err == nil {
+			t.Error("Expected HTTP target to reject pings")
+		}
+	})
+}
+
+func TestTargetCheck_Heartbeat(t *testing.T) {
+	t.Run("nothing to report before the first ping is due", func(t *testing.T) {
+		target, results := newHeartbeatTarget(time.Hour, 0)
+		target.Status = "pending"
+
+		if err := target.Check(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(*results) != 0 || target.Status != "pending" {
+			t.Errorf("Expected no result and pending status, got %d results, %s", len(*results), target.Status)
+		}
+	})
+
+	t.Run("first ping never arrives", func(t *testing.T) {
+		target, _ := newHeartbeatTarget(10*time.Millisecond, 0)
+		target.heartbeat.waitingSince = time.Now().Add(-time.Second)
+
+		if err := target.Check(); err == nil {
+			t.Fatal("Expected missing first ping to fail the check")
+		}
+		if !strings.HasPrefix(target.StatusReason, "no ping received") {
+			t.Errorf("Unexpected reason %q", target.StatusReason)
+		}
+	})
+
+	t.Run("missed ping", func(t *testing.T) {
+		target, results := newHeartbeatTarget(20*time.Millisecond, 0)
+		target.Ping(PingSuccess, 0)
+
+		target.Check()
+		if target.Status != statusUp {
+			t.Fatalf("Expected up within the period, got %s", target.Status)
+		}
+
+		time.Sleep(40 * time.Millisecond)
+		target.Check()
+		if target.Status != statusDown {
+			t.Errorf("Expected down after a missed ping, got %s", target.Status)
+		}
+		if last := (*results)[len(*results)-1]; last.ErrorClass != ErrorClassHeartbeat {
+			t.Errorf("Expected error class %s, got %s", ErrorClassHeartbeat, last.ErrorClass)
+		}
+		if !strings.HasPrefix(target.StatusReason, "ping missed") {
+			t.Errorf("Unexpected reason %q", target.StatusReason)
+		}
+	})
+
+	t.Run("grace time", func(t *testing.T) {
+		target, _ := newHeartbeatTarget(20*time.Millisecond, 60)
+		target.Ping(PingSuccess, 0)
+
+		time.Sleep(40 * time.Millisecond)
+		target.Check()
+		if target.Status != statusUp {
+			t.Errorf("Expected late ping within grace to stay up, got %s", target.Status)
+		}
+	})
+
+	t.Run("started job must finish within grace", func(t *testing.T) {
+		target, _ := newHeartbeatTarget(time.Hour, 1)
+		target.Ping(PingSuccess, 0)
+		target.Ping(PingStart, 0)
+		target.heartbeat.startedAt = time.Now().Add(-2 * time.Second)
+
+		target.Check()
+		if target.Status != statusDown || !strings.Contains(target.StatusReason, "did not finish within 1s") {
+			t.Errorf("Expected down for an unfinished run, got %s: %q", target.Status, target.StatusReason)
+		}
+	})
+}
+
+func TestTarget_pollInterval(t *testing.T) {
+	daily := &Target{Kind: KindHeartbeat, Interval: 24 * time.Hour}
+	if got := daily.pollInterval(); got != HeartbeatPollInterval {
+		t.Errorf("Expected heartbeat poll capped at %s, got %s", HeartbeatPollInterval, got)
+	}
+
+	http := &Target{Interval: 24 * time.Hour}
+	if got := http.pollInterval(); got != 24*time.Hour {
+		t.Errorf("Expected pull targets to poll every interval, got %s", got)
+	}
+}
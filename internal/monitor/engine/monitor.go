@@ -10,10 +10,11 @@ import (
 )
 
 const (
-	statusUp     = "up"
-	statusError  = "error"
-	statusDown   = "down"
-	statusPaused = "paused"
+	statusUp       = "up"
+	statusDegraded = "degraded"
+	statusError    = "error"
+	statusDown     = "down"
+	statusPaused   = "paused"
 )
 
 // ClientConfig holds HTTP client configuration
@@ -41,17 +42,37 @@ var DefaultClient = &http.Client{
 
 type StatusUpdateCallback func(*Target, string) error
 
+// CheckResultCallback receives the outcome of every probe, whether or not
+// the status changed
+type CheckResultCallback func(*Target, CheckResult)
+
 type Target struct {
-	ID              int
-	URL             string
-	Status          string
-	Enabled         bool
-	Interval        time.Duration
-	StatusChangedAt time.Time
-	mu              sync.RWMutex
-	cancelFunc      context.CancelFunc
-	Client          *http.Client
-	OnStatusUpdate  StatusUpdateCallback
+	ID               int
+	Kind             string // one of the Kind constants, empty means HTTP
+	URL              string
+	Status           string
+	Enabled          bool
+	Interval         time.Duration
+	StatusChangedAt  time.Time
+	Request          RequestSpec
+	Assertions       Assertions
+	LatencyThreshold time.Duration // slower successful checks are degraded, zero disables
+	TLS              TLSSettings
+	TCP              TCPSpec
+	DNS              DNSSpec
+	Heartbeat        HeartbeatSpec
+	ConfirmAfter     int           // consecutive failed checks before the target is reported down, zero reports the first
+	RecoverAfter     int           // consecutive successful checks before a failing target is reported up, zero reports the first
+	StatusReason     string        // why the last check was not up, empty when up
+	PreviousStatus   string        // status before the last change, empty until it first changes
+	LastLatency      time.Duration // latency of the last check counted towards Status
+	mu               sync.RWMutex
+	heartbeat        heartbeatState
+	streak           checkStreak
+	cancelFunc       context.CancelFunc
+	Client           *http.Client
+	OnStatusUpdate   StatusUpdateCallback
+	OnCheckResult    CheckResultCallback
 }
 
 func (s *Target) Check() error {
@@ -59,29 +80,56 @@ func (s *Target) Check() error {
 		slog.Info("Target check completed", "URL", s.URL, "fromStatus", startStatus, "toStatus", s.Status)
 	}(s.Status)
 
-	r, err := s.Client.Get(s.URL)
-
+	result, err := s.probe(s.timeout())
+	if result.Status == "" {
+		// Nothing to report yet, e.g. a heartbeat before its first ping is due
+		return err
+	}
+	if IsFailing(result.Status) && s.shouldRecheck() {
+		slog.Info("Rechecking target after failure", "URL", s.URL, "error", err)
+		result, err = s.probe(s.recheckTimeout())
+	}
 	if err != nil {
-		s.updateStatus(statusError)
-		return fmt.Errorf("connection error: %w", err)
+		result.Reason = err.Error()
 	}
 
-	defer r.Body.Close()
+	s.updateStatus(result.Status, result.Reason, result.Latency)
+	s.recordResult(result)
 
-	if r.StatusCode >= 400 {
-		s.updateStatus(statusDown)
-		return fmt.Errorf("HTTP error: %d", r.StatusCode)
+	return err
+}
+
+// probe checks the target once with the checker for its kind, giving up
+// after timeout
+func (s *Target) probe(timeout time.Duration) (CheckResult, error) {
+	checker, err := s.checker()
+	if err != nil {
+		return CheckResult{TargetID: s.ID, CheckedAt: time.Now(), Status: statusError}, err
 	}
 
-	s.updateStatus(statusUp)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return checker.Probe(ctx, s)
+}
 
-	return nil
+func (s *Target) recordResult(result CheckResult) {
+	if s.OnCheckResult != nil {
+		s.OnCheckResult(s, result)
+	}
 }
 
-func (s *Target) updateStatus(status string) {
+// updateStatus applies a check's status. Switching between failing and
+// passing waits until ConfirmAfter or RecoverAfter checks in a row agree.
+func (s *Target) updateStatus(status, reason string, latency time.Duration) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if !s.streak.record(status, s.Status, s.ConfirmAfter, s.RecoverAfter) {
+		return
+	}
+	s.StatusReason = reason
+	s.LastLatency = latency
 	if s.Status != status {
+		s.PreviousStatus = s.Status
 		s.Status = status
 		s.StatusChangedAt = time.Now()
 
@@ -97,9 +145,27 @@ func (s *Target) Update(updatedTarget *Target) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.Kind = updatedTarget.Kind
 	s.URL = updatedTarget.URL
 	s.Interval = updatedTarget.Interval
 	s.Enabled = updatedTarget.Enabled
+	s.Request = updatedTarget.Request
+	s.Assertions = updatedTarget.Assertions
+	s.LatencyThreshold = updatedTarget.LatencyThreshold
+	s.TLS = updatedTarget.TLS
+	s.TCP = updatedTarget.TCP
+	s.DNS = updatedTarget.DNS
+	s.Heartbeat = updatedTarget.Heartbeat
+	s.ConfirmAfter = updatedTarget.ConfirmAfter
+	s.RecoverAfter = updatedTarget.RecoverAfter
+}
+
+// isEnabled reports whether the target is being checked. Update may change
+// it while the target's ticker is running.
+func (s *Target) isEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Enabled
 }
 
 type Manager struct {
@@ -127,23 +193,24 @@ func (m *Manager) RegisterTarget(target *Target) error {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	target.cancelFunc = cancel
+	// A heartbeat's first ping is due one period after monitoring starts
+	target.heartbeat.waitingSince = time.Now()
 
 	m.Targets[target.ID] = target
 
 	go func() {
-		ticker := time.NewTicker(target.Interval)
+		ticker := time.NewTicker(target.pollInterval())
 		defer ticker.Stop()
 
 		for {
 			select {
 			case <-ctx.Done():
+				// RevokeTarget has already removed the target, and a target
+				// registered again under the same ID must be left alone
 				slog.Info("Monitoring stopped", "Target", target.URL)
-				m.mu.Lock()
-				delete(m.Targets, target.ID)
-				m.mu.Unlock()
 				return
 			case <-ticker.C:
-				if !target.Enabled {
+				if !target.isEnabled() {
 					continue
 				}
 				if err := target.Check(); err != nil {
@@ -158,6 +225,14 @@ func (m *Manager) RegisterTarget(target *Target) error {
 	return nil
 }
 
+// Get returns the monitored target with the given ID
+func (m *Manager) Get(targetID int) (*Target, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target, ok := m.Targets[targetID]
+	return target, ok
+}
+
 func (m *Manager) RevokeTarget(targetID int) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
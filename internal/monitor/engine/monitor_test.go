@@ -3,6 +3,7 @@ package monitor
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -40,13 +41,138 @@ func TestTarget_updateStatus(t *testing.T) {
 	}
 
 	newStatus := statusDown
-	target.updateStatus(newStatus)
+	target.updateStatus(newStatus, "", 120*time.Millisecond)
 
 	if target.Status != newStatus {
 		t.Errorf("expected status %q, got %q", newStatus, target.Status)
 	}
 
+	if target.PreviousStatus != statusUp {
+		t.Errorf("expected previous status %q, got %q", statusUp, target.PreviousStatus)
+	}
+
+	if target.LastLatency != 120*time.Millisecond {
+		t.Errorf("expected latency 120ms, got %v", target.LastLatency)
+	}
+
 	if time.Since(target.StatusChangedAt) > time.Second {
 		t.Errorf("StatusChangedAt was not updated correctly")
 	}
 }
+
+func TestTargetCheck_RecordsResult(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("maintenance"))
+	}))
+	defer ts.Close()
+
+	var results []CheckResult
+	target := &Target{
+		ID:       7,
+		URL:      ts.URL,
+		Interval: time.Minute,
+		Enabled:  true,
+		Client:   DefaultClient,
+		OnCheckResult: func(_ *Target, result CheckResult) {
+			results = append(results, result)
+		},
+	}
+
+	if err := target.Check(); err == nil {
+		t.Fatal("Expected check to fail for 503 response")
+	}
+	// A second check with the same outcome must still be recorded
+	target.Check()
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 recorded results, got %d", len(results))
+	}
+
+	result := results[0]
+	if result.TargetID != 7 {
+		t.Errorf("Expected target ID 7, got %d", result.TargetID)
+	}
+	if result.Status != statusDown {
+		t.Errorf("Expected status %s, got %s", statusDown, result.Status)
+	}
+	if result.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code 503, got %d", result.StatusCode)
+	}
+	if result.ErrorClass != ErrorClassHTTP {
+		t.Errorf("Expected error class %s, got %s", ErrorClassHTTP, result.ErrorClass)
+	}
+	if result.BytesRead != int64(len("maintenance")) {
+		t.Errorf("Expected %d bytes read, got %d", len("maintenance"), result.BytesRead)
+	}
+	if result.Latency <= 0 {
+		t.Errorf("Expected positive latency, got %v", result.Latency)
+	}
+}
+
+func TestTargetCheck_ConnectionError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	var recorded CheckResult
+	target := &Target{
+		URL:    url,
+		Client: DefaultClient,
+		OnCheckResult: func(_ *Target, result CheckResult) {
+			recorded = result
+		},
+	}
+
+	if err := target.Check(); err == nil {
+		t.Fatal("Expected connection error")
+	}
+
+	if recorded.Status != statusError {
+		t.Errorf("Expected status %s, got %s", statusError, recorded.Status)
+	}
+	if recorded.ErrorClass != ErrorClassRefused {
+		t.Errorf("Expected error class %s, got %s", ErrorClassRefused, recorded.ErrorClass)
+	}
+}
+
+func TestTargetCheck_LatencyThreshold(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var recorded CheckResult
+	target := &Target{
+		ID:               1,
+		URL:              ts.URL,
+		Interval:         time.Minute,
+		Client:           DefaultClient,
+		LatencyThreshold: time.Millisecond,
+		OnCheckResult: func(_ *Target, result CheckResult) {
+			recorded = result
+		},
+	}
+
+	if err := target.Check(); err != nil {
+		t.Fatalf("Expected slow check to succeed, got %v", err)
+	}
+	if target.Status != statusDegraded {
+		t.Errorf("Expected status %s, got %s", statusDegraded, target.Status)
+	}
+	if !recorded.IsAvailable() || recorded.IsUp() {
+		t.Errorf("Expected degraded result to be available but not up")
+	}
+	if !strings.Contains(target.StatusReason, "exceeded threshold of 1ms") {
+		t.Errorf("Expected threshold reason, got %q", target.StatusReason)
+	}
+
+	target.LatencyThreshold = time.Minute
+	if err := target.Check(); err != nil {
+		t.Fatalf("Expected check to succeed, got %v", err)
+	}
+	if target.Status != statusUp || target.StatusReason != "" {
+		t.Errorf("Expected target to recover to up, got %s (%q)", target.Status, target.StatusReason)
+	}
+}
@@ -0,0 +1,144 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Authentication schemes a request spec can use
+const (
+	AuthNone   = ""
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+)
+
+// RequestAuth holds the credentials sent with every check
+type RequestAuth struct {
+	Type     string `json:"type,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// Redacted returns the credentials without the password and token, which
+// are never shown back once stored
+func (a RequestAuth) Redacted() RequestAuth {
+	a.Password = ""
+	a.Token = ""
+	return a
+}
+
+// KeepSecrets carries the stored password and token over when a change
+// leaves them blank while the type stays the same
+func (a RequestAuth) KeepSecrets(stored RequestAuth) RequestAuth {
+	if a.Type != stored.Type {
+		return a
+	}
+	if a.Password == "" {
+		a.Password = stored.Password
+	}
+	if a.Token == "" {
+		a.Token = stored.Token
+	}
+	return a
+}
+
+// RequestSpec describes the HTTP request sent to a target. The zero value
+// is a plain GET.
+type RequestSpec struct {
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Query   map[string]string `json:"query,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Auth    RequestAuth       `json:"auth"`
+}
+
+var allowedMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// GetMethod returns the configured method, defaulting to GET
+func (r RequestSpec) GetMethod() string {
+	if r.Method == "" {
+		return http.MethodGet
+	}
+	return strings.ToUpper(r.Method)
+}
+
+// Validate checks that the spec can be turned into a request
+func (r RequestSpec) Validate() error {
+	if !allowedMethods[r.GetMethod()] {
+		return fmt.Errorf("unsupported HTTP method: %s", r.Method)
+	}
+
+	for name := range r.Headers {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("header name cannot be empty")
+		}
+	}
+
+	switch r.Auth.Type {
+	case AuthNone:
+	case AuthBasic:
+		if r.Auth.Username == "" {
+			return fmt.Errorf("username is required for basic auth")
+		}
+	case AuthBearer:
+		if r.Auth.Token == "" {
+			return fmt.Errorf("token is required for bearer auth")
+		}
+	default:
+		return fmt.Errorf("unsupported auth type: %s", r.Auth.Type)
+	}
+
+	return nil
+}
+
+// NewRequest builds the request for rawURL. Query parameters from the spec
+// are merged into any already present in the URL.
+func (r RequestSpec) NewRequest(rawURL string) (*http.Request, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if len(r.Query) > 0 {
+		query := u.Query()
+		for key, value := range r.Query {
+			query.Set(key, value)
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	var body io.Reader
+	if r.Body != "" {
+		body = strings.NewReader(r.Body)
+	}
+
+	req, err := http.NewRequest(r.GetMethod(), u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for name, value := range r.Headers {
+		req.Header.Set(name, value)
+	}
+
+	switch r.Auth.Type {
+	case AuthBasic:
+		req.SetBasicAuth(r.Auth.Username, r.Auth.Password)
+	case AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+r.Auth.Token)
+	}
+
+	return req, nil
+}
@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    RequestSpec
+		wantErr bool
+	}{
+		{name: "zero value", spec: RequestSpec{}, wantErr: false},
+		{name: "lowercase method", spec: RequestSpec{Method: "post"}, wantErr: false},
+		{name: "unknown method", spec: RequestSpec{Method: "FETCH"}, wantErr: true},
+		{name: "empty header name", spec: RequestSpec{Headers: map[string]string{" ": "x"}}, wantErr: true},
+		{name: "basic auth", spec: RequestSpec{Auth: RequestAuth{Type: AuthBasic, Username: "admin"}}, wantErr: false},
+		{name: "basic auth without username", spec: RequestSpec{Auth: RequestAuth{Type: AuthBasic}}, wantErr: true},
+		{name: "bearer without token", spec: RequestSpec{Auth: RequestAuth{Type: AuthBearer}}, wantErr: true},
+		{name: "unknown auth", spec: RequestSpec{Auth: RequestAuth{Type: "digest"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTargetCheck_UsesRequestSpec(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	target := &Target{
+		URL:      ts.URL + "/health?existing=1",
+		Interval: time.Minute,
+		Client:   DefaultClient,
+		Request: RequestSpec{
+			Method:  "post",
+			Headers: map[string]string{"X-Probe": "uptimebot", "Content-Type": "application/json"},
+			Query:   map[string]string{"deep": "true"},
+			Body:    `{"ping":true}`,
+			Auth:    RequestAuth{Type: AuthBearer, Token: "secret"},
+		},
+	}
+
+	if err := target.Check(); err != nil {
+		t.Fatalf("Expected successful check, got error: %v", err)
+	}
+
+	if got.Method != http.MethodPost {
+		t.Errorf("Expected POST, got %s", got.Method)
+	}
+	if got.URL.Path != "/health" {
+		t.Errorf("Expected path /health, got %s", got.URL.Path)
+	}
+	if got.URL.Query().Get("existing") != "1" || got.URL.Query().Get("deep") != "true" {
+		t.Errorf("Expected merged query parameters, got %s", got.URL.RawQuery)
+	}
+	if got.Header.Get("X-Probe") != "uptimebot" {
+		t.Errorf("Expected custom header, got %q", got.Header.Get("X-Probe"))
+	}
+	if got.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("Expected bearer token, got %q", got.Header.Get("Authorization"))
+	}
+	if gotBody != `{"ping":true}` {
+		t.Errorf("Expected request body, got %q", gotBody)
+	}
+}
+
+func TestRequestSpec_BasicAuth(t *testing.T) {
+	spec := RequestSpec{Auth: RequestAuth{Type: AuthBasic, Username: "admin", Password: "hunter2"}}
+
+	req, err := spec.NewRequest("http://example.com")
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	username, password, ok := req.BasicAuth()
+	if !ok || username != "admin" || password != "hunter2" {
+		t.Errorf("Expected basic auth admin/hunter2, got %s/%s", username, password)
+	}
+	if req.Method != http.MethodGet {
+		t.Errorf("Expected default GET, got %s", req.Method)
+	}
+}
+
+func TestRequestAuth_KeepSecrets(t *testing.T) {
+	stored := RequestAuth{Type: AuthBearer, Token: "t0ken"}
+
+	tests := []struct {
+		name string
+		auth RequestAuth
+		want RequestAuth
+	}{
+		{name: "blank token keeps the stored one", auth: RequestAuth{Type: AuthBearer}, want: stored},
+		{name: "new token", auth: RequestAuth{Type: AuthBearer, Token: "rotated"}, want: RequestAuth{Type: AuthBearer, Token: "rotated"}},
+		{name: "type changed", auth: RequestAuth{Type: AuthBasic, Username: "admin"}, want: RequestAuth{Type: AuthBasic, Username: "admin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.auth.KeepSecrets(stored); got != tt.want {
+				t.Errorf("KeepSecrets() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+// Error classes recorded alongside a failed check
+const (
+	ErrorClassTimeout    = "timeout"
+	ErrorClassDNS        = "dns"
+	ErrorClassRefused    = "connection_refused"
+	ErrorClassTLS        = "tls"
+	ErrorClassConnection = "connection"
+	ErrorClassHTTP       = "http"
+	ErrorClassAssertion  = "assertion"
+	ErrorClassResponse   = "unexpected_response"
+	ErrorClassHeartbeat  = "heartbeat"
+)
+
+// CheckResult is the outcome of a single probe against a target
+type CheckResult struct {
+	ID         int
+	TargetID   int
+	Status     string
+	CheckedAt  time.Time
+	Latency    time.Duration
+	StatusCode int
+	ErrorClass string
+	BytesRead  int64
+	Reason     string     // why the check was not up, empty when up
+	TLS        *TLSInfo   // certificate details when TLS inspection is enabled
+	DNS        *DNSAnswer // answer received by a DNS check that resolved
+	// Maintenance is set on results recorded during a planned maintenance
+	// window, which are left out of SLA reports
+	Maintenance bool
+}
+
+// IsUp reports whether the probe considered the target healthy
+func (r CheckResult) IsUp() bool {
+	return r.Status == statusUp
+}
+
+// IsAvailable reports whether the target answered correctly, even if slowly
+func (r CheckResult) IsAvailable() bool {
+	return r.Status == statusUp || r.Status == statusDegraded
+}
+
+// classifyError maps a transport error to one of the ErrorClass constants
+func classifyError(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certInvalidErr x509.CertificateInvalidError
+	var recordErr tls.RecordHeaderError
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return ErrorClassDNS
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorClassRefused
+	case errors.As(err, &certErr),
+		errors.As(err, &unknownAuthErr),
+		errors.As(err, &hostnameErr),
+		errors.As(err, &certInvalidErr),
+		errors.As(err, &recordErr):
+		return ErrorClassTLS
+	default:
+		return ErrorClassConnection
+	}
+}
@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// MaxTCPExpectSize caps how much of a response a send/expect check reads
+const MaxTCPExpectSize = 1024
+
+// TCPSpec describes the exchange of a send/expect check: Send is written
+// once connected, then the response must start with Expect
+type TCPSpec struct {
+	Send   string `json:"send,omitempty"`
+	Expect string `json:"expect,omitempty"`
+}
+
+// tcpChecker opens a connection to tcp://host:port and, with expect set,
+// exchanges the target's TCPSpec
+type tcpChecker struct {
+	expect bool
+}
+
+func (c tcpChecker) Validate(s *Target) error {
+	if _, err := parseHostPort(s.URL, "tcp"); err != nil {
+		return err
+	}
+	if len(s.Assertions) > 0 {
+		return fmt.Errorf("assertions are only supported for HTTP targets")
+	}
+	if s.TLS.Enabled {
+		return fmt.Errorf("TLS checks are only supported for HTTP targets")
+	}
+	if c.expect {
+		if s.TCP.Expect == "" {
+			return fmt.Errorf("expected response cannot be empty")
+		}
+		if len(s.TCP.Expect) > MaxTCPExpectSize {
+			return fmt.Errorf("expected response cannot exceed %d bytes", MaxTCPExpectSize)
+		}
+	}
+	return nil
+}
+
+func (c tcpChecker) Probe(ctx context.Context, s *Target) (CheckResult, error) {
+	result := CheckResult{
+		TargetID:  s.ID,
+		CheckedAt: time.Now(),
+	}
+
+	addr, err := parseHostPort(s.URL, "tcp")
+	if err != nil {
+		result.Status = statusError
+		return result, err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		result.Latency = time.Since(result.CheckedAt)
+		result.Status = statusError
+		result.ErrorClass = classifyError(err)
+		return result, fmt.Errorf("connection error: %w", err)
+	}
+	defer conn.Close()
+
+	if c.expect {
+		if err := c.exchange(ctx, conn, s.TCP, &result); err != nil {
+			return result, err
+		}
+	}
+
+	result.Latency = time.Since(result.CheckedAt)
+	s.applyLatencyThreshold(&result)
+	return result, nil
+}
+
+// exchange writes spec.Send and reads until the response either matches
+// spec.Expect or can no longer match it
+func (c tcpChecker) exchange(ctx context.Context, conn net.Conn, spec TCPSpec, result *CheckResult) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if spec.Send != "" {
+		if _, err := io.WriteString(conn, spec.Send); err != nil {
+			result.Latency = time.Since(result.CheckedAt)
+			result.Status = statusError
+			result.ErrorClass = classifyError(err)
+			return fmt.Errorf("failed to send probe: %w", err)
+		}
+	}
+
+	expect := []byte(spec.Expect)
+	buf := make([]byte, len(expect))
+	n, err := io.ReadFull(conn, buf)
+	result.BytesRead = int64(n)
+	result.Latency = time.Since(result.CheckedAt)
+
+	if !bytes.HasPrefix(expect, buf[:n]) {
+		result.Status = statusDown
+		result.ErrorClass = ErrorClassResponse
+		return fmt.Errorf("unexpected response: got %q, want prefix %q", buf[:n], expect)
+	}
+	if err != nil {
+		result.Status = statusError
+		result.ErrorClass = classifyError(err)
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return nil
+}
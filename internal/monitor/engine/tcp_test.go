@@ -0,0 +1,221 @@
+package monitor
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTCPServer accepts connections on a local port and hands each to handle
+func newTCPServer(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+
+	return "tcp://" + ln.Addr().String()
+}
+
+// redisLike answers PING with +PONG and anything else with an error
+func redisLike(conn net.Conn) {
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	if strings.TrimSpace(line) == "PING" {
+		conn.Write([]byte("+PONG\r\n"))
+		return
+	}
+	conn.Write([]byte("-ERR unknown command\r\n"))
+}
+
+func TestTargetCheck_TCP(t *testing.T) {
+	addr := newTCPServer(t, func(net.Conn) {})
+
+	var recorded CheckResult
+	target := &Target{
+		ID:   1,
+		Kind: KindTCP,
+		URL:  addr,
+		OnCheckResult: func(_ *Target, result CheckResult) {
+			recorded = result
+		},
+	}
+
+	if err := target.Check(); err != nil {
+		t.Fatalf("Expected connect check to succeed, got %v", err)
+	}
+	if target.Status != statusUp {
+		t.Errorf("Expected status %s, got %s", statusUp, target.Status)
+	}
+	if recorded.Latency <= 0 {
+		t.Error("Expected connect latency to be recorded")
+	}
+}
+
+func TestTargetCheck_TCPRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	var recorded CheckResult
+	target := &Target{
+		ID:   1,
+		Kind: KindTCP,
+		URL:  "tcp://" + addr,
+		OnCheckResult: func(_ *Target, result CheckResult) {
+			recorded = result
+		},
+	}
+
+	if err := target.Check(); err == nil {
+		t.Fatal("Expected closed port to fail the check")
+	}
+	if target.Status != statusError {
+		t.Errorf("Expected status %s, got %s", statusError, target.Status)
+	}
+	if recorded.ErrorClass != ErrorClassRefused {
+		t.Errorf("Expected error class %s, got %s", ErrorClassRefused, recorded.ErrorClass)
+	}
+}
+
+func TestTargetCheck_TCPExpect(t *testing.T) {
+	redis := newTCPServer(t, redisLike)
+	smtp := newTCPServer(t, func(conn net.Conn) {
+		conn.Write([]byte("220 mail.example.com ESMTP ready\r\n"))
+	})
+	silent := newTCPServer(t, func(conn net.Conn) {
+		io.Copy(io.Discard, conn)
+	})
+
+	tests := []struct {
+		name       string
+		url        string
+		spec       TCPSpec
+		wantStatus string
+		wantClass  string
+		wantReason string
+	}{
+		{
+			name:       "redis ping",
+			url:        redis,
+			spec:       TCPSpec{Send: "PING\r\n", Expect: "+PONG"},
+			wantStatus: statusUp,
+		},
+		{
+			name:       "smtp banner without sending",
+			url:        smtp,
+			spec:       TCPSpec{Expect: "220 "},
+			wantStatus: statusUp,
+		},
+		{
+			name:       "unexpected response",
+			url:        redis,
+			spec:       TCPSpec{Send: "INFO\r\n", Expect: "+PONG"},
+			wantStatus: statusDown,
+			wantClass:  ErrorClassResponse,
+			wantReason: `unexpected response: got "-ERR "`,
+		},
+		{
+			name:       "connection closed before full response",
+			url:        smtp,
+			spec:       TCPSpec{Expect: "220 mail.example.com ESMTP ready\r\nmore"},
+			wantStatus: statusError,
+			wantClass:  ErrorClassConnection,
+			wantReason: "failed to read response",
+		},
+		{
+			name:       "no response",
+			url:        silent,
+			spec:       TCPSpec{Send: "PING\r\n", Expect: "+PONG"},
+			wantStatus: statusError,
+			wantClass:  ErrorClassTimeout,
+			wantReason: "failed to read response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded CheckResult
+			target := &Target{
+				ID:     1,
+				Kind:   KindTCPExpect,
+				URL:    tt.url,
+				TCP:    tt.spec,
+				Client: &http.Client{Timeout: 200 * time.Millisecond},
+				OnCheckResult: func(_ *Target, result CheckResult) {
+					recorded = result
+				},
+			}
+
+			err := target.Check()
+			if (err != nil) != (tt.wantStatus != statusUp) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if target.Status != tt.wantStatus {
+				t.Errorf("Expected status %s, got %s", tt.wantStatus, target.Status)
+			}
+			if recorded.ErrorClass != tt.wantClass {
+				t.Errorf("Expected error class %q, got %q", tt.wantClass, recorded.ErrorClass)
+			}
+			if !strings.HasPrefix(target.StatusReason, tt.wantReason) {
+				t.Errorf("Expected reason starting %q, got %q", tt.wantReason, target.StatusReason)
+			}
+		})
+	}
+}
+
+func TestTarget_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  *Target
+		wantErr bool
+	}{
+		{name: "http", target: &Target{URL: "http://example.com"}},
+		{name: "http with invalid request", target: &Target{URL: "http://example.com", Request: RequestSpec{Method: "FETCH"}}, wantErr: true},
+		{name: "tcp", target: &Target{Kind: KindTCP, URL: "tcp://db.internal:6379"}},
+		{name: "tcp without port", target: &Target{Kind: KindTCP, URL: "tcp://db.internal"}, wantErr: true},
+		{name: "tcp with http URL", target: &Target{Kind: KindTCP, URL: "http://example.com:80"}, wantErr: true},
+		{name: "tcp with assertions", target: &Target{Kind: KindTCP, URL: "tcp://db.internal:6379", Assertions: Assertions{{Type: AssertStatusCode, Value: "200"}}}, wantErr: true},
+		{name: "tcp expect", target: &Target{Kind: KindTCPExpect, URL: "tcp://db.internal:6379", TCP: TCPSpec{Send: "PING\r\n", Expect: "+PONG"}}},
+		{name: "tcp expect without expectation", target: &Target{Kind: KindTCPExpect, URL: "tcp://db.internal:6379", TCP: TCPSpec{Send: "PING\r\n"}}, wantErr: true},
+		{name: "heartbeat", target: &Target{Kind: KindHeartbeat, URL: "heartbeat://nightly-backup", Heartbeat: HeartbeatSpec{GraceSeconds: 300}}},
+		{name: "heartbeat with negative grace", target: &Target{Kind: KindHeartbeat, URL: "heartbeat://nightly-backup", Heartbeat: HeartbeatSpec{GraceSeconds: -1}}, wantErr: true},
+		{name: "confirmation thresholds", target: &Target{URL: "http://example.com", ConfirmAfter: 3, RecoverAfter: 2}},
+		{name: "negative confirmation threshold", target: &Target{URL: "http://example.com", ConfirmAfter: -1}, wantErr: true},
+		{name: "recovery threshold too high", target: &Target{URL: "http://example.com", RecoverAfter: MaxConfirmChecks + 1}, wantErr: true},
+		{name: "unknown kind", target: &Target{Kind: "icmp", URL: "icmp://example.com"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.target.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultExpiryDays are the days before expiry a certificate warning is sent
+var DefaultExpiryDays = []int{30, 14, 7, 1}
+
+// TLSSettings enables certificate inspection on every check of an https target
+type TLSSettings struct {
+	Enabled    bool   `json:"enabled"`
+	MinVersion uint16 `json:"min_version,omitempty"` // defaults to TLS 1.2
+	ExpiryDays []int  `json:"expiry_days,omitempty"` // defaults to DefaultExpiryDays
+}
+
+// GetMinVersion returns the lowest acceptable protocol version
+func (s TLSSettings) GetMinVersion() uint16 {
+	if s.MinVersion == 0 {
+		return tls.VersionTLS12
+	}
+	return s.MinVersion
+}
+
+// GetExpiryDays returns the warning thresholds, largest first
+func (s TLSSettings) GetExpiryDays() []int {
+	days := s.ExpiryDays
+	if len(days) == 0 {
+		days = DefaultExpiryDays
+	}
+	days = slices.Clone(days)
+	slices.Sort(days)
+	slices.Reverse(days)
+	return days
+}
+
+// Validate checks the settings can be applied to rawURL
+func (s TLSSettings) Validate(rawURL string) error {
+	if !s.Enabled {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" {
+		return fmt.Errorf("TLS checks require an https URL")
+	}
+
+	switch s.MinVersion {
+	case 0, tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13:
+	default:
+		return fmt.Errorf("unsupported TLS version: %#x", s.MinVersion)
+	}
+
+	for _, days := range s.ExpiryDays {
+		if days <= 0 {
+			return fmt.Errorf("expiry thresholds must be positive, got %d", days)
+		}
+	}
+
+	return nil
+}
+
+// TLSInfo describes the certificate a target served
+type TLSInfo struct {
+	Subject       string
+	Issuer        string
+	DNSNames      []string
+	NotBefore     time.Time
+	NotAfter      time.Time
+	Version       uint16
+	ChainTrusted  bool
+	HostnameValid bool
+	VerifyError   string // why the chain or hostname failed verification
+	CheckedAt     time.Time
+}
+
+// VersionName returns the negotiated protocol, e.g. "TLS 1.3"
+func (i TLSInfo) VersionName() string {
+	return tls.VersionName(i.Version)
+}
+
+// DaysRemaining returns the whole days left before the certificate expires,
+// negative once it has
+func (i TLSInfo) DaysRemaining(now time.Time) int {
+	remaining := i.NotAfter.Sub(now)
+	days := int(remaining / (24 * time.Hour))
+	if remaining < 0 {
+		days--
+	}
+	return days
+}
+
+// Problem returns why the certificate should fail the check, or nil
+func (i TLSInfo) Problem(settings TLSSettings, now time.Time) error {
+	switch {
+	case now.After(i.NotAfter):
+		return fmt.Errorf("certificate expired on %s", i.NotAfter.UTC().Format(time.DateOnly))
+	case now.Before(i.NotBefore):
+		return fmt.Errorf("certificate not valid before %s", i.NotBefore.UTC().Format(time.DateOnly))
+	case !i.HostnameValid:
+		return fmt.Errorf("certificate hostname mismatch: %s", i.VerifyError)
+	case !i.ChainTrusted:
+		return fmt.Errorf("untrusted certificate chain: %s", i.VerifyError)
+	case i.Version < settings.GetMinVersion():
+		return fmt.Errorf("weak protocol %s, require at least %s", i.VersionName(), tls.VersionName(settings.GetMinVersion()))
+	}
+	return nil
+}
+
+// InspectTLS connects to addr and describes the certificate it serves. The
+// handshake itself skips verification so that a broken certificate can still
+// be described; the chain and hostname are verified afterwards against roots,
+// or the system roots when nil.
+func InspectTLS(ctx context.Context, addr, serverName string, roots *x509.CertPool) (*TLSInfo, error) {
+	dialer := &tls.Dialer{
+		Config: &tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: true,
+			MinVersion:         tls.VersionTLS10,
+		},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("server presented no certificate")
+	}
+
+	leaf := state.PeerCertificates[0]
+	info := &TLSInfo{
+		Subject:   leaf.Subject.String(),
+		Issuer:    leaf.Issuer.String(),
+		DNSNames:  leaf.DNSNames,
+		NotBefore: leaf.NotBefore,
+		NotAfter:  leaf.NotAfter,
+		Version:   state.Version,
+		CheckedAt: time.Now(),
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	// Expiry is reported on its own, so verify the chain at a moment the leaf
+	// is valid to tell an expired certificate from an untrusted one
+	verifyAt := info.CheckedAt
+	if verifyAt.After(leaf.NotAfter) {
+		verifyAt = leaf.NotAfter
+	} else if verifyAt.Before(leaf.NotBefore) {
+		verifyAt = leaf.NotBefore
+	}
+
+	var problems []string
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   verifyAt,
+	}); err != nil {
+		problems = append(problems, err.Error())
+	} else {
+		info.ChainTrusted = true
+	}
+
+	if err := leaf.VerifyHostname(serverName); err != nil {
+		problems = append(problems, err.Error())
+	} else {
+		info.HostnameValid = true
+	}
+	info.VerifyError = strings.Join(problems, "; ")
+
+	return info, nil
+}
+
+// inspectTLS runs InspectTLS against the target's host, trusting the same
+// roots as the target's HTTP client
+func (s *Target) inspectTLS(ctx context.Context) (*TLSInfo, error) {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	var roots *x509.CertPool
+	if transport, ok := s.Client.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		roots = transport.TLSClientConfig.RootCAs
+	}
+
+	return InspectTLS(ctx, net.JoinHostPort(u.Hostname(), port), u.Hostname(), roots)
+}
@@ -0,0 +1,256 @@
+package monitor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTLSServer starts a server presenting a self-signed certificate for
+// 127.0.0.1 and example.com, valid between notBefore and notAfter
+func newTLSServer(t *testing.T, notBefore, notAfter time.Time) *httptest.Server {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "example.com"},
+		DNSNames:              []string{"example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func serverRoots(ts *httptest.Server) *x509.CertPool {
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+	return roots
+}
+
+func TestInspectTLS(t *testing.T) {
+	now := time.Now()
+	ts := newTLSServer(t, now.Add(-time.Hour), now.Add(10*24*time.Hour+time.Hour))
+	addr := ts.Listener.Addr().String()
+
+	t.Run("valid certificate", func(t *testing.T) {
+		info, err := InspectTLS(context.Background(), addr, "127.0.0.1", serverRoots(ts))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !info.ChainTrusted || !info.HostnameValid {
+			t.Errorf("Expected trusted chain and valid hostname, got %+v", info)
+		}
+		if info.Subject != "CN=example.com" {
+			t.Errorf("Expected subject CN=example.com, got %s", info.Subject)
+		}
+		if info.Version != tls.VersionTLS13 {
+			t.Errorf("Expected TLS 1.3, got %s", info.VersionName())
+		}
+		if days := info.DaysRemaining(now); days != 10 {
+			t.Errorf("Expected 10 days remaining, got %d", days)
+		}
+	})
+
+	t.Run("untrusted chain", func(t *testing.T) {
+		info, err := InspectTLS(context.Background(), addr, "127.0.0.1", x509.NewCertPool())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if info.ChainTrusted || info.VerifyError == "" {
+			t.Errorf("Expected untrusted chain, got %+v", info)
+		}
+		if !info.HostnameValid {
+			t.Errorf("Expected hostname to remain valid")
+		}
+	})
+
+	t.Run("hostname mismatch", func(t *testing.T) {
+		info, err := InspectTLS(context.Background(), addr, "other.test", serverRoots(ts))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if info.HostnameValid {
+			t.Errorf("Expected hostname mismatch")
+		}
+	})
+
+	t.Run("connection refused", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closedAddr := closed.Listener.Addr().String()
+		closed.Close()
+
+		if _, err := InspectTLS(context.Background(), closedAddr, "127.0.0.1", nil); err == nil {
+			t.Error("Expected handshake error")
+		}
+	})
+}
+
+func TestTLSInfo_Problem(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	valid := TLSInfo{
+		NotBefore:     now.AddDate(0, -1, 0),
+		NotAfter:      now.AddDate(0, 2, 0),
+		Version:       tls.VersionTLS13,
+		ChainTrusted:  true,
+		HostnameValid: true,
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(*TLSInfo)
+		settings TLSSettings
+		wantErr  string
+	}{
+		{name: "valid", modify: func(*TLSInfo) {}},
+		{name: "expired", modify: func(i *TLSInfo) { i.NotAfter = now.Add(-time.Hour) }, wantErr: "certificate expired on 2025-02-28"},
+		{name: "not yet valid", modify: func(i *TLSInfo) { i.NotBefore = now.Add(time.Hour) }, wantErr: "certificate not valid before"},
+		{name: "hostname mismatch", modify: func(i *TLSInfo) { i.HostnameValid = false }, wantErr: "hostname mismatch"},
+		{name: "untrusted", modify: func(i *TLSInfo) { i.ChainTrusted = false }, wantErr: "untrusted certificate chain"},
+		{name: "weak protocol", modify: func(i *TLSInfo) { i.Version = tls.VersionTLS11 }, wantErr: "weak protocol TLS 1.1, require at least TLS 1.2"},
+		{
+			name:     "custom minimum version",
+			modify:   func(i *TLSInfo) { i.Version = tls.VersionTLS12 },
+			settings: TLSSettings{MinVersion: tls.VersionTLS13},
+			wantErr:  "weak protocol TLS 1.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := valid
+			tt.modify(&info)
+			err := info.Problem(tt.settings, now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no problem, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTLSSettings(t *testing.T) {
+	if got := (TLSSettings{}).GetExpiryDays(); !reflect.DeepEqual(got, []int{30, 14, 7, 1}) {
+		t.Errorf("Expected default thresholds, got %v", got)
+	}
+	if got := (TLSSettings{ExpiryDays: []int{3, 21}}).GetExpiryDays(); !reflect.DeepEqual(got, []int{21, 3}) {
+		t.Errorf("Expected thresholds sorted largest first, got %v", got)
+	}
+
+	tests := []struct {
+		name     string
+		settings TLSSettings
+		url      string
+		wantErr  bool
+	}{
+		{name: "disabled", settings: TLSSettings{}, url: "http://example.com", wantErr: false},
+		{name: "https", settings: TLSSettings{Enabled: true}, url: "https://example.com", wantErr: false},
+		{name: "plain http", settings: TLSSettings{Enabled: true}, url: "http://example.com", wantErr: true},
+		{name: "unknown version", settings: TLSSettings{Enabled: true, MinVersion: 0x0200}, url: "https://example.com", wantErr: true},
+		{name: "negative threshold", settings: TLSSettings{Enabled: true, ExpiryDays: []int{-1}}, url: "https://example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTargetCheck_TLS(t *testing.T) {
+	now := time.Now()
+
+	t.Run("valid certificate is recorded", func(t *testing.T) {
+		ts := newTLSServer(t, now.Add(-time.Hour), now.AddDate(0, 0, 90))
+
+		var recorded CheckResult
+		target := &Target{
+			ID:     1,
+			URL:    ts.URL,
+			Client: ts.Client(),
+			TLS:    TLSSettings{Enabled: true},
+			OnCheckResult: func(_ *Target, result CheckResult) {
+				recorded = result
+			},
+		}
+
+		if err := target.Check(); err != nil {
+			t.Fatalf("Expected check to succeed, got %v", err)
+		}
+		if recorded.TLS == nil || !recorded.TLS.ChainTrusted {
+			t.Errorf("Expected trusted certificate details, got %+v", recorded.TLS)
+		}
+	})
+
+	t.Run("expired certificate fails the check", func(t *testing.T) {
+		ts := newTLSServer(t, now.AddDate(0, -3, 0), now.Add(-time.Hour))
+
+		var recorded CheckResult
+		target := &Target{
+			ID:     2,
+			URL:    ts.URL,
+			Client: ts.Client(),
+			TLS:    TLSSettings{Enabled: true},
+			OnCheckResult: func(_ *Target, result CheckResult) {
+				recorded = result
+			},
+		}
+
+		if err := target.Check(); err == nil {
+			t.Fatal("Expected expired certificate to fail the check")
+		}
+		if target.Status != statusDown {
+			t.Errorf("Expected status %s, got %s", statusDown, target.Status)
+		}
+		if recorded.ErrorClass != ErrorClassTLS {
+			t.Errorf("Expected error class %s, got %s", ErrorClassTLS, recorded.ErrorClass)
+		}
+		if !strings.HasPrefix(target.StatusReason, "TLS check failed: certificate expired on") {
+			t.Errorf("Expected expiry reason, got %q", target.StatusReason)
+		}
+		if recorded.TLS == nil {
+			t.Error("Expected certificate details on a failed check")
+		}
+	})
+}
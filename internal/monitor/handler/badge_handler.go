@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+// badgeMaxAge is how long clients and proxies may cache a badge, in seconds.
+// Short enough for a README to show an outage within a minute or so.
+const badgeMaxAge = 60
+
+// badgeNames are the badges offered on the settings page
+var badgeNames = []string{
+	service.BadgeStatus,
+	service.BadgeUptime + "24h",
+	service.BadgeUptime + "30d",
+	service.BadgeLatency,
+}
+
+type BadgeHandler struct {
+	badgeService  service.BadgeServiceInterface
+	targetService service.TargetServiceInterface
+	flash         flash.FlashStoreInterface
+	Template      struct {
+		Settings *renderer.Template
+	}
+}
+
+func NewBadgeHandler(
+	badgeService service.BadgeServiceInterface,
+	targetService service.TargetServiceInterface,
+	flash flash.FlashStoreInterface,
+) *BadgeHandler {
+	return &BadgeHandler{
+		badgeService:  badgeService,
+		targetService: targetService,
+		flash:         flash,
+	}
+}
+
+// Badge serves a badge as SVG to anyone who knows the target's badge token
+func (h *BadgeHandler) Badge(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("badge"), ".svg")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	b, err := h.badgeService.Render(r.PathValue("token"), name)
+	if err == repository.ErrBadgeNotFound || err == service.ErrUnknownBadge {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to render badge", "badge", name, "error", err)
+		http.Error(w, "Failed to render badge", http.StatusInternalServerError)
+		return
+	}
+
+	svg := b.SVG()
+	sum := sha1.Sum(svg)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", badgeMaxAge))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml;charset=utf-8")
+	w.Write(svg)
+}
+
+// badgeLink is a badge as offered for embedding
+type badgeLink struct {
+	Name     string
+	URL      string
+	Markdown string
+}
+
+// Settings shows the target's badge URLs, or the button to turn badges on
+func (h *BadgeHandler) Settings(w http.ResponseWriter, r *http.Request) {
+	target, ok := userTarget(w, r, h.targetService, "targetId")
+	if !ok {
+		return
+	}
+	targetId := target.ID
+
+	targetBadge, err := h.badgeService.GetByTargetID(targetId)
+	if err != nil {
+		http.Error(w, "Failed to fetch badges", http.StatusInternalServerError)
+		return
+	}
+
+	var links []badgeLink
+	if targetBadge != nil {
+		for _, name := range badgeNames {
+			url := badgeURL(r, targetBadge.Token, name)
+			links = append(links, badgeLink{
+				Name:     name,
+				URL:      url,
+				Markdown: fmt.Sprintf("![%s](%s)", name, url),
+			})
+		}
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	data := map[string]any{
+		"title":    "badges",
+		"targetID": targetId,
+		"enabled":  targetBadge != nil,
+		"badges":   links,
+		"success":  h.flash.GetFlash(flashId, "success"),
+		"error":    h.flash.GetFlash(flashId, "error"),
+	}
+
+	h.Template.Settings.Render(w, r, data)
+}
+
+// Enable turns badges on for the target, or gives them a new token
+func (h *BadgeHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	target, ok := userTarget(w, r, h.targetService, "targetId")
+	if !ok {
+		return
+	}
+	targetId := target.ID
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+	if _, err := h.badgeService.Enable(targetId); err != nil {
+		h.flash.SetFlash(flashId, "error", "Failed to enable badges")
+	} else {
+		h.flash.SetFlash(flashId, "success", "Badge URLs generated")
+	}
+
+	http.Redirect(w, r, badgesPath(targetId), http.StatusSeeOther)
+}
+
+// Disable turns the target's badges off, breaking every embedded badge URL
+func (h *BadgeHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	target, ok := userTarget(w, r, h.targetService, "targetId")
+	if !ok {
+		return
+	}
+	targetId := target.ID
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+	if err := h.badgeService.Disable(targetId); err != nil {
+		h.flash.SetFlash(flashId, "error", "Failed to disable badges")
+	} else {
+		h.flash.SetFlash(flashId, "success", "Badges disabled")
+	}
+
+	http.Redirect(w, r, badgesPath(targetId), http.StatusSeeOther)
+}
+
+// badgeURL returns the absolute URL of a badge as seen by the current request
+func badgeURL(r *http.Request, token, name string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/badge/%s/%s.svg", scheme, r.Host, token, name)
+}
+
+func badgesPath(targetId int) string {
+	return fmt.Sprintf("/targets/%d/badges", targetId)
+}
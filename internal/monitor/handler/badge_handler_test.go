@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/shuvo-paul/uptimebot/pkg/badge"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mock BadgeService
+type mockBadgeService struct {
+	getByTargetIDFunc func(targetID int) (*model.TargetBadge, error)
+	enableFunc        func(targetID int) (*model.TargetBadge, error)
+	disableFunc       func(targetID int) error
+	renderFunc        func(token, name string) (badge.Badge, error)
+}
+
+func (m *mockBadgeService) GetByTargetID(targetID int) (*model.TargetBadge, error) {
+	return m.getByTargetIDFunc(targetID)
+}
+
+func (m *mockBadgeService) Enable(targetID int) (*model.TargetBadge, error) {
+	return m.enableFunc(targetID)
+}
+
+func (m *mockBadgeService) Disable(targetID int) error {
+	return m.disableFunc(targetID)
+}
+
+func (m *mockBadgeService) Render(token, name string) (badge.Badge, error) {
+	return m.renderFunc(token, name)
+}
+
+func TestBadgeHandler_Badge(t *testing.T) {
+	mockService := &mockBadgeService{
+		renderFunc: func(token, name string) (badge.Badge, error) {
+			if token != "secret" {
+				return badge.Badge{}, repository.ErrBadgeNotFound
+			}
+			if name != "status" {
+				return badge.Badge{}, service.ErrUnknownBadge
+			}
+			return badge.Badge{Label: "status", Message: "up", Color: badge.ColorBrightGreen}, nil
+		},
+	}
+	handler := NewBadgeHandler(mockService, ownerTargetService(), &testutil.MockFlashStore{})
+
+	serve := func(token, name, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/badge/"+token+"/"+name, nil)
+		req.SetPathValue("token", token)
+		req.SetPathValue("badge", name)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		handler.Badge(w, req)
+		return w
+	}
+
+	w := serve("secret", "status.svg", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/svg+xml;charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), `aria-label="status: up"`)
+
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	t.Run("unchanged badge", func(t *testing.T) {
+		w := serve("secret", "status.svg", etag)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	tests := []struct {
+		name  string
+		token string
+		badge string
+	}{
+		{name: "unknown token", token: "guess", badge: "status.svg"},
+		{name: "unknown badge", token: "secret", badge: "response.svg"},
+		{name: "not an SVG", token: "secret", badge: "status.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusNotFound, serve(tt.token, tt.badge, "").Code)
+		})
+	}
+}
+
+func TestBadgeHandler_Settings(t *testing.T) {
+	tests := []struct {
+		name        string
+		badge       *model.TargetBadge
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "badges disabled",
+			contains:    []string{"Enable badges"},
+			notContains: []string{"/badge/"},
+		},
+		{
+			name:  "badges enabled",
+			badge: &model.TargetBadge{TargetID: 1, Token: "secret"},
+			contains: []string{
+				`src="http://example.com/badge/secret/status.svg"`,
+				"![uptime-30d](http://example.com/badge/secret/uptime-30d.svg)",
+				"Replace token",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockBadgeService{
+				getByTargetIDFunc: func(targetID int) (*model.TargetBadge, error) {
+					assert.Equal(t, 1, targetID)
+					return tt.badge, nil
+				},
+			}
+			handler := NewBadgeHandler(mockService, ownerTargetService(), &testutil.MockFlashStore{})
+			handler.Template.Settings = renderer.New(templates.TemplateFS).GetTemplate("pages:targets/badges")
+
+			req := httptest.NewRequest(http.MethodGet, "/targets/1/badges", nil)
+			req.SetPathValue("targetId", "1")
+			req = withUser(req, 1)
+			w := httptest.NewRecorder()
+
+			handler.Settings(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			for _, s := range tt.contains {
+				assert.Contains(t, w.Body.String(), s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, w.Body.String(), s)
+			}
+		})
+	}
+}
+
+func TestBadgeHandler_Enable(t *testing.T) {
+	var enabled int
+	mockService := &mockBadgeService{
+		enableFunc: func(targetID int) (*model.TargetBadge, error) {
+			enabled = targetID
+			return &model.TargetBadge{TargetID: targetID, Token: "secret"}, nil
+		},
+	}
+	handler := NewBadgeHandler(mockService, ownerTargetService(), &testutil.MockFlashStore{})
+
+	req := httptest.NewRequest(http.MethodPost, "/targets/1/badges", nil)
+	req.SetPathValue("targetId", "1")
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+
+	handler.Enable(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/targets/1/badges", w.Header().Get("Location"))
+	assert.Equal(t, 1, enabled)
+}
+
+func TestBadgeHandler_OtherUsersTarget(t *testing.T) {
+	mockService := &mockBadgeService{
+		getByTargetIDFunc: func(targetID int) (*model.TargetBadge, error) {
+			t.Fatal("badges of another user's target must not be read")
+			return nil, nil
+		},
+		enableFunc: func(targetID int) (*model.TargetBadge, error) {
+			t.Fatal("badges must not be enabled on another user's target")
+			return nil, nil
+		},
+		disableFunc: func(targetID int) error {
+			t.Fatal("badges must not be disabled on another user's target")
+			return nil
+		},
+	}
+	handler := NewBadgeHandler(mockService, ownerTargetService(), &testutil.MockFlashStore{})
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"settings", handler.Settings},
+		{"enable", handler.Enable},
+		{"disable", handler.Disable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/targets/1/badges", nil)
+			req.SetPathValue("targetId", "1")
+			req = withUser(req, 2)
+			w := httptest.NewRecorder()
+
+			tt.handler(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+	}
+}
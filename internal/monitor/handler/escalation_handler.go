@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+// defaultEscalationWait is how long a new step waits before the next one
+const defaultEscalationWait = 15
+
+type EscalationHandler struct {
+	escalationService service.EscalationServiceInterface
+	targetService     service.TargetServiceInterface
+	flash             flash.FlashStoreInterface
+	Template          struct {
+		List *renderer.Template
+		Form *renderer.Template
+	}
+}
+
+func NewEscalationHandler(
+	escalationService service.EscalationServiceInterface,
+	targetService service.TargetServiceInterface,
+	flash flash.FlashStoreInterface,
+) *EscalationHandler {
+	return &EscalationHandler{
+		escalationService: escalationService,
+		targetService:     targetService,
+		flash:             flash,
+	}
+}
+
+// escalationStepField is a row of the escalation policy form
+type escalationStepField struct {
+	model.EscalationStep
+	Index  int
+	Number int
+	Last   bool
+}
+
+// List shows the user's escalation policies
+func (h *EscalationHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	policies, err := h.escalationService.GetAllByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to fetch escalation policies", http.StatusInternalServerError)
+		return
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	data := map[string]any{
+		"title":    "escalation policies",
+		"policies": policies,
+		"success":  h.flash.GetFlash(flashId, "success"),
+		"error":    h.flash.GetFlash(flashId, "error"),
+	}
+
+	h.Template.List.Render(w, r, data)
+}
+
+// Create shows the form for a new escalation policy and saves it
+func (h *EscalationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	policy := model.EscalationPolicy{UserID: user.ID}
+	if r.Method == http.MethodGet {
+		h.renderForm(w, r, policy)
+		return
+	}
+
+	flashID := flash.GetFlashIDFromContext(r.Context())
+	parseEscalationForm(r, &policy)
+	if _, err := h.escalationService.Create(policy); err != nil {
+		h.flash.SetFlash(flashID, "error", "Failed to create escalation policy: "+err.Error())
+		http.Redirect(w, r, "/escalation-policies/create", http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetFlash(flashID, "success", "Escalation policy created")
+	http.Redirect(w, r, "/escalation-policies", http.StatusSeeOther)
+}
+
+// Edit shows the form for one of the user's escalation policies and saves it
+func (h *EscalationHandler) Edit(w http.ResponseWriter, r *http.Request) {
+	policy, ok := h.userPolicy(w, r)
+	if !ok {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		h.renderForm(w, r, *policy)
+		return
+	}
+
+	flashID := flash.GetFlashIDFromContext(r.Context())
+	parseEscalationForm(r, policy)
+	if err := h.escalationService.Update(*policy); err != nil {
+		h.flash.SetFlash(flashID, "error", "Failed to update escalation policy: "+err.Error())
+		http.Redirect(w, r, escalationPolicyPath(policy.ID)+"/edit", http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetFlash(flashID, "success", "Escalation policy updated")
+	http.Redirect(w, r, "/escalation-policies", http.StatusSeeOther)
+}
+
+// Delete removes one of the user's escalation policies
+func (h *EscalationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	policy, ok := h.userPolicy(w, r)
+	if !ok {
+		return
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+	if err := h.escalationService.Delete(policy.ID); err != nil {
+		h.flash.SetFlash(flashId, "error", "Failed to delete escalation policy")
+	} else {
+		h.flash.SetFlash(flashId, "success", "Escalation policy deleted")
+	}
+
+	http.Redirect(w, r, "/escalation-policies", http.StatusSeeOther)
+}
+
+// renderForm shows the create or edit form, listing every target the policy
+// could cover and every notifier its steps could page. An empty step is
+// added after the policy's own so another can be filled in.
+func (h *EscalationHandler) renderForm(w http.ResponseWriter, r *http.Request, policy model.EscalationPolicy) {
+	targets, err := h.targetService.GetAllByUserID(policy.UserID)
+	if err != nil {
+		http.Error(w, "Failed to fetch targets", http.StatusInternalServerError)
+		return
+	}
+
+	notifiers, err := h.escalationService.NotifierOptions(policy.UserID)
+	if err != nil {
+		http.Error(w, "Failed to fetch notifiers", http.StatusInternalServerError)
+		return
+	}
+
+	steps := append(slices.Clone(policy.Steps), model.EscalationStep{WaitMinutes: defaultEscalationWait})
+	fields := make([]escalationStepField, len(steps))
+	for i, step := range steps {
+		if step.WaitMinutes == 0 {
+			step.WaitMinutes = defaultEscalationWait
+		}
+		fields[i] = escalationStepField{EscalationStep: step, Index: i, Number: i + 1, Last: i == len(steps)-1}
+	}
+
+	flashID := flash.GetFlashIDFromContext(r.Context())
+
+	data := map[string]any{
+		"title":     "escalation policy",
+		"policy":    policy,
+		"steps":     fields,
+		"targets":   targets,
+		"notifiers": notifiers,
+		"maxWait":   model.MaxEscalationWait,
+		"error":     h.flash.GetFlash(flashID, "error"),
+	}
+
+	h.Template.Form.Render(w, r, data)
+}
+
+// parseEscalationForm applies the submitted form onto policy. Steps are
+// numbered step_notifiers_N and step_wait_N from zero; steps left without
+// notifiers are dropped, so clearing one removes it.
+func parseEscalationForm(r *http.Request, policy *model.EscalationPolicy) {
+	r.ParseForm()
+
+	policy.Name = r.FormValue("name")
+	policy.Steps = nil
+	for i := 0; r.Form.Has("step_wait_" + strconv.Itoa(i)); i++ {
+		index := strconv.Itoa(i)
+		var step model.EscalationStep
+		for _, value := range r.Form["step_notifiers_"+index] {
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			step.NotifierIDs = append(step.NotifierIDs, id)
+		}
+		if len(step.NotifierIDs) == 0 {
+			continue
+		}
+		step.WaitMinutes, _ = strconv.Atoi(r.FormValue("step_wait_" + index))
+		policy.Steps = append(policy.Steps, step)
+	}
+
+	policy.TargetIDs = nil
+	for _, value := range r.Form["targets"] {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		policy.TargetIDs = append(policy.TargetIDs, id)
+	}
+}
+
+// userPolicy loads the escalation policy named in the path, answering 404
+// unless it belongs to the current user
+func (h *EscalationHandler) userPolicy(w http.ResponseWriter, r *http.Request) (*model.EscalationPolicy, bool) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid escalation policy ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	policy, err := h.escalationService.GetByID(id)
+	if err != nil || policy.UserID != user.ID {
+		http.Error(w, "Escalation policy not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	return policy, true
+}
+
+func escalationPolicyPath(id int) string {
+	return "/escalation-policies/" + strconv.Itoa(id)
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mock EscalationService
+type mockEscalationService struct {
+	createFunc          func(policy model.EscalationPolicy) (model.EscalationPolicy, error)
+	getByIDFunc         func(id int) (*model.EscalationPolicy, error)
+	getAllByUserIDFunc  func(userID int) ([]model.EscalationPolicy, error)
+	updateFunc          func(policy model.EscalationPolicy) error
+	deleteFunc          func(id int) error
+	notifierOptionsFunc func(userID int) ([]service.NotifierOption, error)
+}
+
+func (m *mockEscalationService) Create(policy model.EscalationPolicy) (model.EscalationPolicy, error) {
+	return m.createFunc(policy)
+}
+
+func (m *mockEscalationService) GetByID(id int) (*model.EscalationPolicy, error) {
+	return m.getByIDFunc(id)
+}
+
+func (m *mockEscalationService) GetAllByUserID(userID int) ([]model.EscalationPolicy, error) {
+	return m.getAllByUserIDFunc(userID)
+}
+
+func (m *mockEscalationService) Update(policy model.EscalationPolicy) error {
+	return m.updateFunc(policy)
+}
+
+func (m *mockEscalationService) Delete(id int) error {
+	return m.deleteFunc(id)
+}
+
+func (m *mockEscalationService) NotifierOptions(userID int) ([]service.NotifierOption, error) {
+	return m.notifierOptionsFunc(userID)
+}
+
+func TestEscalationHandler_List(t *testing.T) {
+	mockService := &mockEscalationService{
+		getAllByUserIDFunc: func(userID int) ([]model.EscalationPolicy, error) {
+			return []model.EscalationPolicy{{ID: 4, UserID: userID, Name: "Night shift", Steps: make([]model.EscalationStep, 2)}}, nil
+		},
+	}
+	handler := NewEscalationHandler(mockService, &mockTargetService{}, &testutil.MockFlashStore{})
+	handler.Template.List = renderer.New(templates.TemplateFS).GetTemplate("pages:escalations/list")
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/escalation-policies", nil), 1)
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "Night shift")
+	assert.Contains(t, body, "2 step(s)")
+	assert.Contains(t, body, `action="/escalation-policies/4/delete"`)
+}
+
+func TestEscalationHandler_Create(t *testing.T) {
+	t.Run("GET request", func(t *testing.T) {
+		targetService := &mockTargetService{
+			getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
+				return []*monitor.Target{{ID: 7, URL: "https://api.example.com"}}, nil
+			},
+		}
+		mockService := &mockEscalationService{
+			notifierOptionsFunc: func(userID int) ([]service.NotifierOption, error) {
+				return []service.NotifierOption{{ID: 12, Type: "pagerduty", TargetURL: "https://api.example.com"}}, nil
+			},
+		}
+		handler := NewEscalationHandler(mockService, targetService, &testutil.MockFlashStore{})
+		handler.Template.Form = renderer.New(templates.TemplateFS).GetTemplate("pages:escalations/form")
+
+		req := withUser(httptest.NewRequest(http.MethodGet, "/escalation-policies/create", nil), 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, `action="/escalation-policies/create"`)
+		assert.Contains(t, body, `name="step_notifiers_0" value="12"`)
+		assert.Contains(t, body, `name="step_wait_0"`)
+		assert.Contains(t, body, "pagerduty on https://api.example.com")
+		assert.Contains(t, body, `name="targets" value="7"`)
+	})
+
+	t.Run("POST request", func(t *testing.T) {
+		var created model.EscalationPolicy
+		mockService := &mockEscalationService{
+			createFunc: func(policy model.EscalationPolicy) (model.EscalationPolicy, error) {
+				created = policy
+				return policy, nil
+			},
+		}
+		handler := NewEscalationHandler(mockService, &mockTargetService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("name", "Night shift")
+		form.Add("step_notifiers_0", "12")
+		form.Add("step_wait_0", "10")
+		form.Add("step_notifiers_1", "13")
+		form.Add("step_notifiers_1", "14")
+		form.Add("step_wait_1", "15")
+		form.Add("step_wait_2", "15") // the empty step left for adding another
+		form.Add("targets", "7")
+
+		req := withUser(httptest.NewRequest(http.MethodPost, "/escalation-policies/create", strings.NewReader(form.Encode())), 1)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/escalation-policies", w.Header().Get("Location"))
+		assert.Equal(t, model.EscalationPolicy{
+			UserID: 1,
+			Name:   "Night shift",
+			Steps: []model.EscalationStep{
+				{NotifierIDs: []int64{12}, WaitMinutes: 10},
+				{NotifierIDs: []int64{13, 14}, WaitMinutes: 15},
+			},
+			TargetIDs: []int{7},
+		}, created)
+	})
+}
+
+func TestEscalationHandler_Edit(t *testing.T) {
+	mockService := &mockEscalationService{
+		getByIDFunc: func(id int) (*model.EscalationPolicy, error) {
+			if id != 3 {
+				return nil, repository.ErrEscalationPolicyNotFound
+			}
+			return &model.EscalationPolicy{ID: 3, UserID: 1, Name: "Night shift"}, nil
+		},
+		updateFunc: func(policy model.EscalationPolicy) error {
+			return nil
+		},
+		deleteFunc: func(id int) error {
+			return nil
+		},
+	}
+	handler := NewEscalationHandler(mockService, &mockTargetService{}, &testutil.MockFlashStore{})
+
+	tests := []struct {
+		name     string
+		id       string
+		userID   int
+		wantCode int
+	}{
+		{name: "own policy", id: "3", userID: 1, wantCode: http.StatusSeeOther},
+		{name: "other user's policy", id: "3", userID: 2, wantCode: http.StatusNotFound},
+		{name: "unknown policy", id: "9", userID: 1, wantCode: http.StatusNotFound},
+		{name: "invalid ID", id: "abc", userID: 1, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{"name": {"Night shift"}, "step_notifiers_0": {"12"}, "step_wait_0": {"10"}}
+			req := withUser(httptest.NewRequest(http.MethodPost, "/escalation-policies/"+tt.id+"/edit", strings.NewReader(form.Encode())), tt.userID)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.Edit(w, req)
+			assert.Equal(t, tt.wantCode, w.Code)
+
+			req = withUser(httptest.NewRequest(http.MethodPost, "/escalation-policies/"+tt.id+"/delete", nil), tt.userID)
+			req.SetPathValue("id", tt.id)
+			w = httptest.NewRecorder()
+
+			handler.Delete(w, req)
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
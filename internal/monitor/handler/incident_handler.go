@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	incidentService "github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+type IncidentHandler struct {
+	incidentService incidentService.IncidentServiceInterface
+	flash           flash.FlashStoreInterface
+	Template        struct {
+		List *renderer.Template
+		Show *renderer.Template
+	}
+}
+
+func NewIncidentHandler(
+	incidentService incidentService.IncidentServiceInterface,
+	flash flash.FlashStoreInterface,
+) *IncidentHandler {
+	return &IncidentHandler{
+		incidentService: incidentService,
+		flash:           flash,
+	}
+}
+
+// List shows the incidents on the user's targets, open ones first
+func (h *IncidentHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	incidents, err := h.incidentService.GetAllByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to fetch incidents", http.StatusInternalServerError)
+		return
+	}
+
+	var open, resolved []model.Incident
+	for _, incident := range incidents {
+		if incident.IsResolved() {
+			resolved = append(resolved, incident)
+		} else {
+			open = append(open, incident)
+		}
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	data := map[string]any{
+		"title":    "incidents",
+		"open":     open,
+		"resolved": resolved,
+		"now":      time.Now(),
+		"success":  h.flash.GetFlash(flashId, "success"),
+		"error":    h.flash.GetFlash(flashId, "error"),
+	}
+
+	h.Template.List.Render(w, r, data)
+}
+
+// Show displays an incident with its timeline
+func (h *IncidentHandler) Show(w http.ResponseWriter, r *http.Request) {
+	incident, ok := h.userIncident(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := h.incidentService.GetEvents(incident.ID)
+	if err != nil {
+		http.Error(w, "Failed to fetch incident timeline", http.StatusInternalServerError)
+		return
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	data := map[string]any{
+		"title":    "incident",
+		"incident": incident,
+		"events":   events,
+		"now":      time.Now(),
+		"success":  h.flash.GetFlash(flashId, "success"),
+		"error":    h.flash.GetFlash(flashId, "error"),
+	}
+
+	h.Template.Show.Render(w, r, data)
+}
+
+// Acknowledge marks the incident as being handled, which silences further
+// status alerts for the target until it recovers
+func (h *IncidentHandler) Acknowledge(w http.ResponseWriter, r *http.Request) {
+	incident, ok := h.userIncident(w, r)
+	if !ok {
+		return
+	}
+
+	user, _ := authService.GetUser(r.Context())
+	flashID := flash.GetFlashIDFromContext(r.Context())
+	if err := h.incidentService.Acknowledge(incident.ID, user.ID); err != nil {
+		slog.Error("Failed to acknowledge incident", "incident", incident.ID, "error", err)
+		h.flash.SetFlash(flashID, "error", "Failed to acknowledge incident: "+err.Error())
+	} else {
+		h.flash.SetFlash(flashID, "success", "Incident acknowledged")
+	}
+
+	http.Redirect(w, r, incidentPath(incident.ID), http.StatusSeeOther)
+}
+
+// AddUpdate posts a free-text note to the incident's timeline
+func (h *IncidentHandler) AddUpdate(w http.ResponseWriter, r *http.Request) {
+	incident, ok := h.userIncident(w, r)
+	if !ok {
+		return
+	}
+
+	user, _ := authService.GetUser(r.Context())
+	flashID := flash.GetFlashIDFromContext(r.Context())
+	if err := h.incidentService.AddUpdate(incident.ID, user.ID, r.FormValue("message")); err != nil {
+		h.flash.SetFlash(flashID, "error", "Failed to add update: "+err.Error())
+	}
+
+	http.Redirect(w, r, incidentPath(incident.ID), http.StatusSeeOther)
+}
+
+// userIncident loads the incident named in the path, answering 404 unless it
+// belongs to one of the current user's targets
+func (h *IncidentHandler) userIncident(w http.ResponseWriter, r *http.Request) (*model.Incident, bool) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid incident ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	incident, err := h.incidentService.GetByID(id)
+	if err != nil || incident.UserID != user.ID {
+		http.Error(w, "Incident not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	return incident, true
+}
+
+func incidentPath(id int) string {
+	return "/incidents/" + strconv.Itoa(id)
+}
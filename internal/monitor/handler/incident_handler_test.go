@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mock IncidentService
+type mockIncidentService struct {
+	getAllByUserIDFunc func(userID int) ([]model.Incident, error)
+	getByIDFunc        func(id int) (*model.Incident, error)
+	getEventsFunc      func(id int) ([]model.IncidentEvent, error)
+	acknowledgeFunc    func(id, userID int) error
+	addUpdateFunc      func(id, userID int, message string) error
+}
+
+func (m *mockIncidentService) HandleStatusChange(target *monitor.Target, status string) (*model.Incident, error) {
+	return nil, nil
+}
+
+func (m *mockIncidentService) RecordCheckFailure(target *monitor.Target, result monitor.CheckResult) error {
+	return nil
+}
+
+func (m *mockIncidentService) RecordNotification(incident *model.Incident, message string) error {
+	return nil
+}
+
+func (m *mockIncidentService) GetAllByUserID(userID int) ([]model.Incident, error) {
+	return m.getAllByUserIDFunc(userID)
+}
+
+func (m *mockIncidentService) GetByID(id int) (*model.Incident, error) {
+	return m.getByIDFunc(id)
+}
+
+func (m *mockIncidentService) GetEvents(id int) ([]model.IncidentEvent, error) {
+	return m.getEventsFunc(id)
+}
+
+func (m *mockIncidentService) Acknowledge(id, userID int) error {
+	return m.acknowledgeFunc(id, userID)
+}
+
+func (m *mockIncidentService) AddUpdate(id, userID int, message string) error {
+	return m.addUpdateFunc(id, userID, message)
+}
+
+func withUser(req *http.Request, id int) *http.Request {
+	return req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: id, Name: "Alice"}))
+}
+
+func TestIncidentHandler_List(t *testing.T) {
+	startedAt := time.Now().Add(-time.Hour)
+	mockService := &mockIncidentService{
+		getAllByUserIDFunc: func(userID int) ([]model.Incident, error) {
+			assert.Equal(t, 1, userID)
+			return []model.Incident{
+				{ID: 2, TargetURL: "https://api.example.com", Cause: "HTTP error: 502", StartedAt: startedAt},
+				{ID: 1, TargetURL: "https://www.example.com", StartedAt: startedAt, ResolvedAt: startedAt.Add(5 * time.Minute)},
+			}, nil
+		},
+	}
+
+	handler := NewIncidentHandler(mockService, &testutil.MockFlashStore{})
+	handler.Template.List = renderer.New(templates.TemplateFS).GetTemplate("pages:incidents/list")
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/incidents/", nil), 1)
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "HTTP error: 502")
+	assert.Contains(t, body, `action="/incidents/2/acknowledge"`)
+	assert.NotContains(t, body, `action="/incidents/1/acknowledge"`)
+	assert.Contains(t, body, "5m0s")
+}
+
+func TestIncidentHandler_Show(t *testing.T) {
+	incident := &model.Incident{ID: 3, TargetURL: "https://example.com", UserID: 1, StartedAt: time.Now().Add(-time.Minute)}
+	mockService := &mockIncidentService{
+		getByIDFunc: func(id int) (*model.Incident, error) {
+			if id != incident.ID {
+				return nil, repository.ErrIncidentNotFound
+			}
+			return incident, nil
+		},
+		getEventsFunc: func(id int) ([]model.IncidentEvent, error) {
+			return []model.IncidentEvent{
+				{Kind: model.IncidentOpened, Message: "Target is down"},
+				{Kind: model.IncidentUpdate, Message: "Rolling back the deploy", UserName: "Alice"},
+			}, nil
+		},
+	}
+
+	handler := NewIncidentHandler(mockService, &testutil.MockFlashStore{})
+	handler.Template.Show = renderer.New(templates.TemplateFS).GetTemplate("pages:incidents/show")
+
+	tests := []struct {
+		name     string
+		id       string
+		userID   int
+		wantCode int
+	}{
+		{name: "own incident", id: "3", userID: 1, wantCode: http.StatusOK},
+		{name: "other user's incident", id: "3", userID: 2, wantCode: http.StatusNotFound},
+		{name: "unknown incident", id: "9", userID: 1, wantCode: http.StatusNotFound},
+		{name: "invalid ID", id: "abc", userID: 1, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := withUser(httptest.NewRequest(http.MethodGet, "/incidents/"+tt.id, nil), tt.userID)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.Show(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Contains(t, w.Body.String(), "Rolling back the deploy")
+			}
+		})
+	}
+}
+
+func TestIncidentHandler_Acknowledge(t *testing.T) {
+	var acknowledged []int
+	mockService := &mockIncidentService{
+		getByIDFunc: func(id int) (*model.Incident, error) {
+			return &model.Incident{ID: id, UserID: 1}, nil
+		},
+		acknowledgeFunc: func(id, userID int) error {
+			acknowledged = append(acknowledged, id, userID)
+			return nil
+		},
+	}
+	handler := NewIncidentHandler(mockService, &testutil.MockFlashStore{})
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/incidents/3/acknowledge", nil), 1)
+	req.SetPathValue("id", "3")
+	w := httptest.NewRecorder()
+
+	handler.Acknowledge(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/incidents/3", w.Header().Get("Location"))
+	assert.Equal(t, []int{3, 1}, acknowledged)
+}
+
+func TestIncidentHandler_AddUpdate(t *testing.T) {
+	var message string
+	mockService := &mockIncidentService{
+		getByIDFunc: func(id int) (*model.Incident, error) {
+			return &model.Incident{ID: id, UserID: 1}, nil
+		},
+		addUpdateFunc: func(id, userID int, text string) error {
+			message = text
+			return nil
+		},
+	}
+	handler := NewIncidentHandler(mockService, &testutil.MockFlashStore{})
+
+	form := url.Values{}
+	form.Add("message", "Database failed over")
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/incidents/3/updates", strings.NewReader(form.Encode())), 1)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetPathValue("id", "3")
+	w := httptest.NewRecorder()
+
+	handler.AddUpdate(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "Database failed over", message)
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+// dateTimeLocal is the value format of an HTML datetime-local input
+const dateTimeLocal = "2006-01-02T15:04"
+
+type MaintenanceHandler struct {
+	maintenanceService service.MaintenanceServiceInterface
+	targetService      service.TargetServiceInterface
+	flash              flash.FlashStoreInterface
+	Template           struct {
+		List *renderer.Template
+	}
+}
+
+func NewMaintenanceHandler(
+	maintenanceService service.MaintenanceServiceInterface,
+	targetService service.TargetServiceInterface,
+	flash flash.FlashStoreInterface,
+) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+		targetService:      targetService,
+		flash:              flash,
+	}
+}
+
+// List shows a target's maintenance windows with the form to plan another
+func (h *MaintenanceHandler) List(w http.ResponseWriter, r *http.Request) {
+	target, ok := userTarget(w, r, h.targetService, "targetId")
+	if !ok {
+		return
+	}
+	targetId := target.ID
+
+	windows, err := h.maintenanceService.GetByTargetID(targetId)
+	if err != nil {
+		http.Error(w, "Failed to fetch maintenance windows", http.StatusInternalServerError)
+		return
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	data := map[string]any{
+		"title":    "maintenance",
+		"targetID": targetId,
+		"windows":  windows,
+		"weekdays": []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday},
+		"now":      time.Now(),
+		"success":  h.flash.GetFlash(flashId, "success"),
+		"error":    h.flash.GetFlash(flashId, "error"),
+	}
+
+	h.Template.List.Render(w, r, data)
+}
+
+// Create plans a new maintenance window for the target
+func (h *MaintenanceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	target, ok := userTarget(w, r, h.targetService, "targetId")
+	if !ok {
+		return
+	}
+	targetId := target.ID
+
+	redirect := maintenancePath(targetId)
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	window, err := parseMaintenanceForm(r)
+	if err == nil {
+		window.TargetID = targetId
+		_, err = h.maintenanceService.Create(window)
+	}
+	if err != nil {
+		h.flash.SetFlash(flashId, "error", "Failed to plan maintenance: "+err.Error())
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetFlash(flashId, "success", "Maintenance window planned")
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// Delete removes one of the target's maintenance windows
+func (h *MaintenanceHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	target, ok := userTarget(w, r, h.targetService, "targetId")
+	if !ok {
+		return
+	}
+	targetId := target.ID
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid maintenance window ID", http.StatusBadRequest)
+		return
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+	if err := h.maintenanceService.Delete(targetId, id); err != nil {
+		h.flash.SetFlash(flashId, "error", "Failed to delete maintenance window")
+	} else {
+		h.flash.SetFlash(flashId, "success", "Maintenance window deleted")
+	}
+
+	http.Redirect(w, r, maintenancePath(targetId), http.StatusSeeOther)
+}
+
+// parseMaintenanceForm reads a maintenance window from the submitted form.
+// Times are entered in the chosen timezone.
+func parseMaintenanceForm(r *http.Request) (model.MaintenanceWindow, error) {
+	if err := r.ParseForm(); err != nil {
+		return model.MaintenanceWindow{}, fmt.Errorf("invalid form")
+	}
+
+	window := model.MaintenanceWindow{
+		Description: r.FormValue("description"),
+		Kind:        r.FormValue("kind"),
+		Timezone:    r.FormValue("timezone"),
+	}
+
+	loc := time.UTC
+	if window.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(window.Timezone); err != nil {
+			return model.MaintenanceWindow{}, fmt.Errorf("unknown timezone %q", window.Timezone)
+		}
+	}
+
+	switch window.Kind {
+	case model.MaintenanceOnce:
+		startsAt, err := time.ParseInLocation(dateTimeLocal, r.FormValue("starts_at"), loc)
+		if err != nil {
+			return model.MaintenanceWindow{}, fmt.Errorf("invalid start")
+		}
+		endsAt, err := time.ParseInLocation(dateTimeLocal, r.FormValue("ends_at"), loc)
+		if err != nil {
+			return model.MaintenanceWindow{}, fmt.Errorf("invalid end")
+		}
+		window.StartsAt = startsAt
+		window.EndsAt = endsAt
+	case model.MaintenanceWeekly:
+		for _, value := range r.Form["weekdays"] {
+			day, err := strconv.Atoi(value)
+			if err != nil {
+				return model.MaintenanceWindow{}, fmt.Errorf("invalid weekday")
+			}
+			window.Weekdays = append(window.Weekdays, time.Weekday(day))
+		}
+		minutes, err := strconv.Atoi(r.FormValue("duration_minutes"))
+		if err != nil {
+			return model.MaintenanceWindow{}, fmt.Errorf("invalid duration")
+		}
+		window.StartTime = r.FormValue("start_time")
+		window.Duration = time.Duration(minutes) * time.Minute
+	}
+
+	return window, nil
+}
+
+func maintenancePath(targetId int) string {
+	return fmt.Sprintf("/targets/%d/maintenance", targetId)
+}
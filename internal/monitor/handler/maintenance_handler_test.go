@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mock MaintenanceService
+type mockMaintenanceService struct {
+	createFunc        func(window model.MaintenanceWindow) (model.MaintenanceWindow, error)
+	getByTargetIDFunc func(targetID int) ([]model.MaintenanceWindow, error)
+	deleteFunc        func(targetID, id int) error
+}
+
+func (m *mockMaintenanceService) Create(window model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+	return m.createFunc(window)
+}
+
+func (m *mockMaintenanceService) GetByTargetID(targetID int) ([]model.MaintenanceWindow, error) {
+	return m.getByTargetIDFunc(targetID)
+}
+
+func (m *mockMaintenanceService) Delete(targetID, id int) error {
+	return m.deleteFunc(targetID, id)
+}
+
+func (m *mockMaintenanceService) IsActive(targetID int, at time.Time) (bool, error) {
+	return false, nil
+}
+
+// ownerTargetService is a target service where user 1 owns target 1
+func ownerTargetService() *mockTargetService {
+	return &mockTargetService{
+		getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
+			if userID != 1 {
+				return nil, nil
+			}
+			return []*monitor.Target{{ID: 1, URL: "https://example.com"}}, nil
+		},
+	}
+}
+
+func TestMaintenanceHandler_List(t *testing.T) {
+	mockService := &mockMaintenanceService{
+		getByTargetIDFunc: func(targetID int) ([]model.MaintenanceWindow, error) {
+			assert.Equal(t, 1, targetID)
+			return []model.MaintenanceWindow{
+				{ID: 4, TargetID: 1, Description: "Weekly deploy", Kind: model.MaintenanceWeekly, Weekdays: []time.Weekday{time.Tuesday}, StartTime: "22:00", Duration: 2 * time.Hour, Timezone: "Europe/Berlin"},
+			}, nil
+		},
+	}
+
+	handler := NewMaintenanceHandler(mockService, ownerTargetService(), &testutil.MockFlashStore{})
+	handler.Template.List = renderer.New(templates.TemplateFS).GetTemplate("pages:targets/maintenance")
+
+	req := httptest.NewRequest(http.MethodGet, "/targets/1/maintenance", nil)
+	req.SetPathValue("targetId", "1")
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "Weekly deploy")
+	assert.Contains(t, body, "Tue 22:00 for 2h0m0s (Europe/Berlin)")
+	assert.Contains(t, body, `action="/targets/1/maintenance/4/delete"`)
+	assert.Contains(t, body, `name="weekdays" value="2"`)
+}
+
+func TestMaintenanceHandler_Create(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+
+	tests := []struct {
+		name       string
+		form       url.Values
+		wantWindow *model.MaintenanceWindow
+	}{
+		{
+			name: "one-off",
+			form: url.Values{
+				"kind":      {"once"},
+				"timezone":  {"Europe/Berlin"},
+				"starts_at": {"2025-05-13T22:00"},
+				"ends_at":   {"2025-05-14T01:00"},
+			},
+			wantWindow: &model.MaintenanceWindow{
+				TargetID: 1,
+				Kind:     model.MaintenanceOnce,
+				Timezone: "Europe/Berlin",
+				StartsAt: time.Date(2025, 5, 13, 22, 0, 0, 0, berlin),
+				EndsAt:   time.Date(2025, 5, 14, 1, 0, 0, 0, berlin),
+			},
+		},
+		{
+			name: "weekly",
+			form: url.Values{
+				"kind":             {"weekly"},
+				"description":      {"Weekly deploy"},
+				"weekdays":         {"2", "4"},
+				"start_time":       {"22:00"},
+				"duration_minutes": {"90"},
+			},
+			wantWindow: &model.MaintenanceWindow{
+				TargetID:    1,
+				Description: "Weekly deploy",
+				Kind:        model.MaintenanceWeekly,
+				Weekdays:    []time.Weekday{time.Tuesday, time.Thursday},
+				StartTime:   "22:00",
+				Duration:    90 * time.Minute,
+			},
+		},
+		{
+			name: "invalid start",
+			form: url.Values{"kind": {"once"}, "starts_at": {"tomorrow"}},
+		},
+		{
+			name: "unknown timezone",
+			form: url.Values{"kind": {"once"}, "timezone": {"Mars/Olympus"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *model.MaintenanceWindow
+			mockService := &mockMaintenanceService{
+				createFunc: func(window model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+					created = &window
+					return window, nil
+				},
+			}
+			handler := NewMaintenanceHandler(mockService, ownerTargetService(), &testutil.MockFlashStore{})
+
+			req := httptest.NewRequest(http.MethodPost, "/targets/1/maintenance", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetPathValue("targetId", "1")
+			req = withUser(req, 1)
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, http.StatusSeeOther, w.Code)
+			assert.Equal(t, "/targets/1/maintenance", w.Header().Get("Location"))
+			assert.Equal(t, tt.wantWindow, created)
+		})
+	}
+}
+
+func TestMaintenanceHandler_Delete(t *testing.T) {
+	var deleted []int
+	mockService := &mockMaintenanceService{
+		deleteFunc: func(targetID, id int) error {
+			deleted = append(deleted, targetID, id)
+			return nil
+		},
+	}
+	handler := NewMaintenanceHandler(mockService, ownerTargetService(), &testutil.MockFlashStore{})
+
+	req := httptest.NewRequest(http.MethodPost, "/targets/1/maintenance/4/delete", nil)
+	req.SetPathValue("targetId", "1")
+	req.SetPathValue("id", "4")
+	req = withUser(req, 1)
+	w := httptest.NewRecorder()
+
+	handler.Delete(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, []int{1, 4}, deleted)
+}
+
+func TestMaintenanceHandler_OtherUsersTarget(t *testing.T) {
+	mockService := &mockMaintenanceService{
+		getByTargetIDFunc: func(targetID int) ([]model.MaintenanceWindow, error) {
+			t.Fatal("windows of another user's target must not be read")
+			return nil, nil
+		},
+		createFunc: func(window model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+			t.Fatal("windows must not be planned on another user's target")
+			return window, nil
+		},
+		deleteFunc: func(targetID, id int) error {
+			t.Fatal("windows of another user's target must not be deleted")
+			return nil
+		},
+	}
+	handler := NewMaintenanceHandler(mockService, ownerTargetService(), &testutil.MockFlashStore{})
+
+	tests := []struct {
+		name    string
+		method  string
+		handler http.HandlerFunc
+	}{
+		{"list", http.MethodGet, handler.List},
+		{"create", http.MethodPost, handler.Create},
+		{"delete", http.MethodPost, handler.Delete},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{"kind": {model.MaintenanceOnce}, "starts_at": {"2025-06-01T22:00"}, "ends_at": {"2025-06-01T23:00"}}
+			req := httptest.NewRequest(tt.method, "/targets/1/maintenance", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetPathValue("targetId", "1")
+			req.SetPathValue("id", "4")
+			req = withUser(req, 2)
+			w := httptest.NewRecorder()
+
+			tt.handler(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
+)
+
+type StatusPageHandler struct {
+	statusPageService service.StatusPageServiceInterface
+	targetService     service.TargetServiceInterface
+	flash             flash.FlashStoreInterface
+	Template          struct {
+		List   *renderer.Template
+		Form   *renderer.Template
+		Public *renderer.Template
+	}
+}
+
+func NewStatusPageHandler(
+	statusPageService service.StatusPageServiceInterface,
+	targetService service.TargetServiceInterface,
+	flash flash.FlashStoreInterface,
+) *StatusPageHandler {
+	return &StatusPageHandler{
+		statusPageService: statusPageService,
+		targetService:     targetService,
+		flash:             flash,
+	}
+}
+
+// Public renders a status page for anyone, signed in or not
+func (h *StatusPageHandler) Public(w http.ResponseWriter, r *http.Request) {
+	page, err := h.statusPageService.GetPublic(r.PathValue("slug"))
+	if err == repository.ErrStatusPageNotFound {
+		http.Error(w, "Status page not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to build status page", "slug", r.PathValue("slug"), "error", err)
+		http.Error(w, "Failed to load status page", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{
+		"title": page.Title,
+		"page":  page,
+	}
+
+	h.Template.Public.Render(w, r, data)
+}
+
+// List shows the user's status pages
+func (h *StatusPageHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	pages, err := h.statusPageService.GetAllByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to fetch status pages", http.StatusInternalServerError)
+		return
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	data := map[string]any{
+		"title":   "status pages",
+		"pages":   pages,
+		"success": h.flash.GetFlash(flashId, "success"),
+		"error":   h.flash.GetFlash(flashId, "error"),
+	}
+
+	h.Template.List.Render(w, r, data)
+}
+
+// Create shows the form for a new status page and saves it
+func (h *StatusPageHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	page := model.StatusPage{UserID: user.ID}
+	if r.Method == http.MethodGet {
+		h.renderForm(w, r, page)
+		return
+	}
+
+	flashID := flash.GetFlashIDFromContext(r.Context())
+	parseStatusPageForm(r, &page)
+	if _, err := h.statusPageService.Create(page); err != nil {
+		h.flash.SetFlash(flashID, "error", "Failed to create status page: "+err.Error())
+		http.Redirect(w, r, "/status-pages/create", http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetFlash(flashID, "success", "Status page created")
+	http.Redirect(w, r, "/status-pages", http.StatusSeeOther)
+}
+
+// Edit shows the form for one of the user's status pages and saves it
+func (h *StatusPageHandler) Edit(w http.ResponseWriter, r *http.Request) {
+	page, ok := h.userStatusPage(w, r)
+	if !ok {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		h.renderForm(w, r, *page)
+		return
+	}
+
+	flashID := flash.GetFlashIDFromContext(r.Context())
+	parseStatusPageForm(r, page)
+	if err := h.statusPageService.Update(*page); err != nil {
+		h.flash.SetFlash(flashID, "error", "Failed to update status page: "+err.Error())
+		http.Redirect(w, r, statusPagePath(page.ID)+"/edit", http.StatusSeeOther)
+		return
+	}
+
+	h.flash.SetFlash(flashID, "success", "Status page updated")
+	http.Redirect(w, r, "/status-pages", http.StatusSeeOther)
+}
+
+// Delete removes one of the user's status pages
+func (h *StatusPageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	page, ok := h.userStatusPage(w, r)
+	if !ok {
+		return
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+	if err := h.statusPageService.Delete(page.ID); err != nil {
+		h.flash.SetFlash(flashId, "error", "Failed to delete status page")
+	} else {
+		h.flash.SetFlash(flashId, "success", "Status page deleted")
+	}
+
+	http.Redirect(w, r, "/status-pages", http.StatusSeeOther)
+}
+
+// renderForm shows the create or edit form, listing every target the user
+// could add to the page
+func (h *StatusPageHandler) renderForm(w http.ResponseWriter, r *http.Request, page model.StatusPage) {
+	targets, err := h.targetService.GetAllByUserID(page.UserID)
+	if err != nil {
+		http.Error(w, "Failed to fetch targets", http.StatusInternalServerError)
+		return
+	}
+
+	flashID := flash.GetFlashIDFromContext(r.Context())
+
+	data := map[string]any{
+		"title":   "status page",
+		"page":    page,
+		"targets": targets,
+		"error":   h.flash.GetFlash(flashID, "error"),
+	}
+
+	h.Template.Form.Render(w, r, data)
+}
+
+// parseStatusPageForm applies the submitted form onto page. Targets are
+// shown in the order they appear in the form.
+func parseStatusPageForm(r *http.Request, page *model.StatusPage) {
+	r.ParseForm()
+
+	page.Title = r.FormValue("title")
+	page.Slug = r.FormValue("slug")
+	page.Targets = nil
+	for _, value := range r.Form["targets"] {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		page.Targets = append(page.Targets, model.StatusPageTarget{
+			TargetID:  id,
+			Name:      r.FormValue("name_" + value),
+			Component: r.FormValue("component_" + value),
+		})
+	}
+}
+
+// userStatusPage loads the status page named in the path, answering 404
+// unless it belongs to the current user
+func (h *StatusPageHandler) userStatusPage(w http.ResponseWriter, r *http.Request) (*model.StatusPage, bool) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid status page ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	page, err := h.statusPageService.GetByID(id)
+	if err != nil || page.UserID != user.ID {
+		http.Error(w, "Status page not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	return page, true
+}
+
+func statusPagePath(id int) string {
+	return "/status-pages/" + strconv.Itoa(id)
+}
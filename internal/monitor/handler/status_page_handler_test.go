@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mock StatusPageService
+type mockStatusPageService struct {
+	createFunc         func(page model.StatusPage) (model.StatusPage, error)
+	getByIDFunc        func(id int) (*model.StatusPage, error)
+	getAllByUserIDFunc func(userID int) ([]model.StatusPage, error)
+	updateFunc         func(page model.StatusPage) error
+	deleteFunc         func(id int) error
+	getPublicFunc      func(slug string) (*service.PublicStatusPage, error)
+}
+
+func (m *mockStatusPageService) Create(page model.StatusPage) (model.StatusPage, error) {
+	return m.createFunc(page)
+}
+
+func (m *mockStatusPageService) GetByID(id int) (*model.StatusPage, error) {
+	return m.getByIDFunc(id)
+}
+
+func (m *mockStatusPageService) GetAllByUserID(userID int) ([]model.StatusPage, error) {
+	return m.getAllByUserIDFunc(userID)
+}
+
+func (m *mockStatusPageService) Update(page model.StatusPage) error {
+	return m.updateFunc(page)
+}
+
+func (m *mockStatusPageService) Delete(id int) error {
+	return m.deleteFunc(id)
+}
+
+func (m *mockStatusPageService) GetPublic(slug string) (*service.PublicStatusPage, error) {
+	return m.getPublicFunc(slug)
+}
+
+func TestStatusPageHandler_Public(t *testing.T) {
+	days := make([]service.SLAReport, service.StatusPageDays)
+	for i := range days {
+		days[i] = service.SLAReport{Window: service.SLAWindow{Label: "2025-05-18"}, Checks: 1, Uptime: 100}
+	}
+	days[len(days)-1].Uptime = 50
+
+	mockService := &mockStatusPageService{
+		getPublicFunc: func(slug string) (*service.PublicStatusPage, error) {
+			if slug != "example" {
+				return nil, repository.ErrStatusPageNotFound
+			}
+			return &service.PublicStatusPage{
+				Title:  "Example status",
+				Status: service.PublicStatusOutage,
+				Components: []service.PublicComponent{{
+					Name:   "Backend",
+					Status: service.PublicStatusOutage,
+					Targets: []service.PublicTarget{
+						{Name: "API", Status: service.PublicStatusOutage, Uptime: service.SLAReport{Checks: 2, Uptime: 99.5}, Days: days},
+					},
+				}},
+				Incidents: []service.PublicIncident{{
+					Name:      "API",
+					StartedAt: time.Now(),
+					Updates:   []model.IncidentEvent{{Message: "Rolling back the deploy", CreatedAt: time.Now()}},
+				}},
+				UpdatedAt: time.Now(),
+			}, nil
+		},
+	}
+
+	handler := NewStatusPageHandler(mockService, &mockTargetService{}, &testutil.MockFlashStore{})
+	handler.Template.Public = renderer.New(templates.TemplateFS).GetTemplate("pages:status/public")
+
+	t.Run("renders the page", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/status/example", nil)
+		req.SetPathValue("slug", "example")
+		w := httptest.NewRecorder()
+
+		handler.Public(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, "<title>Example status</title>")
+		assert.Contains(t, body, "Some systems are down")
+		assert.Contains(t, body, "Backend")
+		assert.Contains(t, body, "Rolling back the deploy")
+		assert.Contains(t, body, "99.50% uptime")
+		assert.Equal(t, service.StatusPageDays, strings.Count(body, `title="2025-05-18:`))
+		assert.NotContains(t, body, "Logout", "the public page does not use the app layout")
+	})
+
+	t.Run("unknown slug", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/status/missing", nil)
+		req.SetPathValue("slug", "missing")
+		w := httptest.NewRecorder()
+
+		handler.Public(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestStatusPageHandler_Create(t *testing.T) {
+	t.Run("GET request", func(t *testing.T) {
+		targetService := &mockTargetService{
+			getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
+				return []*monitor.Target{{ID: 7, URL: "https://api.example.com"}}, nil
+			},
+		}
+		handler := NewStatusPageHandler(&mockStatusPageService{}, targetService, &testutil.MockFlashStore{})
+		handler.Template.Form = renderer.New(templates.TemplateFS).GetTemplate("pages:status/form")
+
+		req := withUser(httptest.NewRequest(http.MethodGet, "/status-pages/create", nil), 1)
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, `action="/status-pages/create"`)
+		assert.Contains(t, body, `name="component_7"`)
+	})
+
+	t.Run("POST request", func(t *testing.T) {
+		var created model.StatusPage
+		mockService := &mockStatusPageService{
+			createFunc: func(page model.StatusPage) (model.StatusPage, error) {
+				created = page
+				return page, nil
+			},
+		}
+		handler := NewStatusPageHandler(mockService, &mockTargetService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("title", "Example status")
+		form.Add("slug", "example")
+		form.Add("targets", "8")
+		form.Add("targets", "7")
+		form.Add("name_7", "API")
+		form.Add("component_7", "Backend")
+		form.Add("name_9", "Not selected")
+
+		req := withUser(httptest.NewRequest(http.MethodPost, "/status-pages/create", strings.NewReader(form.Encode())), 1)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/status-pages", w.Header().Get("Location"))
+		assert.Equal(t, model.StatusPage{
+			UserID: 1,
+			Title:  "Example status",
+			Slug:   "example",
+			Targets: []model.StatusPageTarget{
+				{TargetID: 8},
+				{TargetID: 7, Name: "API", Component: "Backend"},
+			},
+		}, created)
+	})
+}
+
+func TestStatusPageHandler_Edit(t *testing.T) {
+	mockService := &mockStatusPageService{
+		getByIDFunc: func(id int) (*model.StatusPage, error) {
+			if id != 3 {
+				return nil, repository.ErrStatusPageNotFound
+			}
+			return &model.StatusPage{ID: 3, UserID: 1, Title: "Example", Slug: "example"}, nil
+		},
+		updateFunc: func(page model.StatusPage) error {
+			return nil
+		},
+	}
+	handler := NewStatusPageHandler(mockService, &mockTargetService{}, &testutil.MockFlashStore{})
+
+	tests := []struct {
+		name     string
+		id       string
+		userID   int
+		wantCode int
+	}{
+		{name: "own page", id: "3", userID: 1, wantCode: http.StatusSeeOther},
+		{name: "other user's page", id: "3", userID: 2, wantCode: http.StatusNotFound},
+		{name: "unknown page", id: "9", userID: 1, wantCode: http.StatusNotFound},
+		{name: "invalid ID", id: "abc", userID: 1, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{"title": {"Example"}, "slug": {"example"}}
+			req := withUser(httptest.NewRequest(http.MethodPost, "/status-pages/"+tt.id+"/edit", strings.NewReader(form.Encode())), tt.userID)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.Edit(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+)
+
+// parseTargetForm applies the submitted create/edit form onto target
+func parseTargetForm(r *http.Request, target *monitor.Target) error {
+	interval, err := strconv.Atoi(r.FormValue("interval"))
+	if err != nil {
+		return fmt.Errorf("Invalid interval value")
+	}
+
+	headers, err := parseKeyValueLines(r.FormValue("headers"), ":")
+	if err != nil {
+		return fmt.Errorf("Invalid headers: %w", err)
+	}
+
+	query, err := parseKeyValueLines(r.FormValue("query"), "=")
+	if err != nil {
+		return fmt.Errorf("Invalid query parameters: %w", err)
+	}
+
+	var latencyThreshold int
+	if value := r.FormValue("latency_threshold"); value != "" {
+		latencyThreshold, err = strconv.Atoi(value)
+		if err != nil || latencyThreshold < 0 {
+			return fmt.Errorf("Invalid response time threshold")
+		}
+	}
+
+	confirmAfter, err := parseCheckCount(r.FormValue("confirm_after"))
+	if err != nil {
+		return fmt.Errorf("Invalid number of failures before alerting")
+	}
+
+	recoverAfter, err := parseCheckCount(r.FormValue("recover_after"))
+	if err != nil {
+		return fmt.Errorf("Invalid number of successes before recovery")
+	}
+
+	tlsSettings, err := parseTLSSettings(r)
+	if err != nil {
+		return err
+	}
+
+	var maxResolveMs int64
+	if value := r.FormValue("dns_max_resolve_ms"); value != "" {
+		maxResolveMs, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || maxResolveMs < 0 {
+			return fmt.Errorf("Invalid max resolution time")
+		}
+	}
+
+	var graceSeconds int
+	if value := r.FormValue("heartbeat_grace"); value != "" {
+		graceSeconds, err = strconv.Atoi(value)
+		if err != nil || graceSeconds < 0 {
+			return fmt.Errorf("Invalid grace time")
+		}
+	}
+
+	assertions, err := monitor.ParseAssertions(r.FormValue("assertions"))
+	if err != nil {
+		return fmt.Errorf("Invalid assertions: %w", err)
+	}
+
+	target.Kind = r.FormValue("kind")
+	target.URL = r.FormValue("url")
+	target.Interval = time.Duration(interval) * time.Second
+	target.Request = monitor.RequestSpec{
+		Method:  r.FormValue("method"),
+		Headers: headers,
+		Query:   query,
+		Body:    r.FormValue("body"),
+		Auth:    parseRequestAuth(r, target.Request.Auth),
+	}
+	target.Assertions = assertions
+	target.LatencyThreshold = time.Duration(latencyThreshold) * time.Millisecond
+	target.ConfirmAfter = confirmAfter
+	target.RecoverAfter = recoverAfter
+	target.TLS = tlsSettings
+	target.TCP = monitor.TCPSpec{
+		Send:   unescapeControl(r.FormValue("tcp_send")),
+		Expect: unescapeControl(r.FormValue("tcp_expect")),
+	}
+	target.DNS = monitor.DNSSpec{
+		RecordType:   r.FormValue("dns_record_type"),
+		Resolver:     strings.TrimSpace(r.FormValue("dns_resolver")),
+		Expected:     parseLines(r.FormValue("dns_expected")),
+		MaxResolveMs: maxResolveMs,
+	}
+	// The ping token is assigned by the service and kept across edits
+	target.Heartbeat.GraceSeconds = graceSeconds
+
+	return nil
+}
+
+// parseRequestAuth reads the request credentials. Stored secrets are never
+// sent back to the browser, so a blank password or token keeps the stored
+// one while the type stays the same.
+func parseRequestAuth(r *http.Request, stored monitor.RequestAuth) monitor.RequestAuth {
+	auth := monitor.RequestAuth{
+		Type:     r.FormValue("auth_type"),
+		Username: r.FormValue("auth_username"),
+		Password: r.FormValue("auth_password"),
+		Token:    r.FormValue("auth_token"),
+	}
+	return auth.KeepSecrets(stored)
+}
+
+// targetFormData returns the template data shared by the create and edit forms
+func targetFormData(target *monitor.Target) map[string]any {
+	return map[string]any{
+		"target":      target,
+		"headers":     formatKeyValueLines(target.Request.Headers, ": "),
+		"query":       formatKeyValueLines(target.Request.Query, "="),
+		"assertions":  target.Assertions.String(),
+		"tlsVersion":  tlsVersionNames[target.TLS.MinVersion],
+		"expiryDays":  formatExpiryDays(target.TLS.ExpiryDays),
+		"tcpSend":     escapeControl(target.TCP.Send),
+		"tcpExpect":   escapeControl(target.TCP.Expect),
+		"dnsExpected": strings.Join(target.DNS.Expected, "\n"),
+	}
+}
+
+// parseCheckCount reads a consecutive check count, where empty means zero
+func parseCheckCount(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 || count > monitor.MaxConfirmChecks {
+		return 0, fmt.Errorf("invalid check count: %s", value)
+	}
+	return count, nil
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "1.0",
+	tls.VersionTLS11: "1.1",
+	tls.VersionTLS12: "1.2",
+	tls.VersionTLS13: "1.3",
+}
+
+// parseTLSSettings reads the certificate monitoring fields. An empty minimum
+// version or threshold list falls back to the engine defaults.
+func parseTLSSettings(r *http.Request) (monitor.TLSSettings, error) {
+	settings := monitor.TLSSettings{Enabled: r.FormValue("tls_enabled") != ""}
+
+	if value := r.FormValue("tls_min_version"); value != "" {
+		version, ok := tlsVersions[value]
+		if !ok {
+			return settings, fmt.Errorf("Invalid TLS version: %s", value)
+		}
+		settings.MinVersion = version
+	}
+
+	for _, field := range strings.Split(r.FormValue("tls_expiry_days"), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		days, err := strconv.Atoi(field)
+		if err != nil || days <= 0 {
+			return settings, fmt.Errorf("Invalid expiry threshold: %s", field)
+		}
+		settings.ExpiryDays = append(settings.ExpiryDays, days)
+	}
+
+	return settings, nil
+}
+
+func formatExpiryDays(days []int) string {
+	fields := make([]string, len(days))
+	for i, d := range days {
+		fields[i] = strconv.Itoa(d)
+	}
+	return strings.Join(fields, ",")
+}
+
+var (
+	controlEscaper   = strings.NewReplacer(`\`, `\\`, "\r", `\r`, "\n", `\n`, "\t", `\t`)
+	controlUnescaper = strings.NewReplacer(`\\`, `\`, `\r`, "\r", `\n`, "\n", `\t`, "\t")
+)
+
+// escapeControl writes control characters as \r, \n and \t so TCP payloads
+// fit in a single-line input; unescapeControl reverses it
+func escapeControl(s string) string {
+	return controlEscaper.Replace(s)
+}
+
+func unescapeControl(s string) string {
+	return controlUnescaper.Replace(s)
+}
+
+// parseLines returns the non-blank lines of text, trimmed
+func parseLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseKeyValueLines parses one "key<sep>value" pair per line, skipping blank lines
+func parseKeyValueLines(text, sep string) (map[string]string, error) {
+	var values map[string]string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, sep)
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("expected key%svalue, got %q", sep, line)
+		}
+
+		if values == nil {
+			values = make(map[string]string)
+		}
+		values[key] = strings.TrimSpace(value)
+	}
+	return values, nil
+}
+
+func formatKeyValueLines(values map[string]string, sep string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		lines[i] = key + sep + values[key]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyValueLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		sep     string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			text: "  \n",
+			sep:  ":",
+			want: nil,
+		},
+		{
+			name: "headers",
+			text: "Accept: application/json\r\nAuthorization: Bearer a:b\n",
+			sep:  ":",
+			want: map[string]string{"Accept": "application/json", "Authorization": "Bearer a:b"},
+		},
+		{
+			name: "query with empty value",
+			text: "a=1\nb=",
+			sep:  "=",
+			want: map[string]string{"a": "1", "b": ""},
+		},
+		{
+			name:    "missing separator",
+			text:    "Accept",
+			sep:     ":",
+			wantErr: true,
+		},
+		{
+			name:    "missing key",
+			text:    "=value",
+			sep:     "=",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKeyValueLines(tt.text, tt.sep)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatKeyValueLines(t *testing.T) {
+	text := formatKeyValueLines(map[string]string{"b": "2", "a": "1"}, "=")
+	assert.Equal(t, "a=1\nb=2", text)
+
+	parsed, err := parseKeyValueLines(text, "=")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, parsed)
+}
+
+func TestEscapeControl(t *testing.T) {
+	tests := []struct {
+		raw     string
+		escaped string
+	}{
+		{raw: "PING\r\n", escaped: `PING\r\n`},
+		{raw: "a\tb", escaped: `a\tb`},
+		{raw: `C:\new`, escaped: `C:\\new`},
+		{raw: "+PONG", escaped: "+PONG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.escaped, func(t *testing.T) {
+			assert.Equal(t, tt.escaped, escapeControl(tt.raw))
+			assert.Equal(t, tt.raw, unescapeControl(tt.escaped))
+		})
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	assert.Nil(t, parseLines(" \r\n"))
+	assert.Equal(t, []string{"10 mail.example.com", "20 backup.example.com"}, parseLines("10 mail.example.com\r\n\n 20 backup.example.com \n"))
+}
+
+func TestParseRequestAuth(t *testing.T) {
+	stored := monitor.RequestAuth{Type: "basic", Username: "admin", Password: "hunter2"}
+
+	tests := []struct {
+		name string
+		form url.Values
+		want monitor.RequestAuth
+	}{
+		{
+			name: "blank password keeps the stored one",
+			form: url.Values{"auth_type": {"basic"}, "auth_username": {"root"}},
+			want: monitor.RequestAuth{Type: "basic", Username: "root", Password: "hunter2"},
+		},
+		{
+			name: "new password replaces it",
+			form: url.Values{"auth_type": {"basic"}, "auth_username": {"admin"}, "auth_password": {"s3cret"}},
+			want: monitor.RequestAuth{Type: "basic", Username: "admin", Password: "s3cret"},
+		},
+		{
+			name: "changing the type drops stored secrets",
+			form: url.Values{"auth_type": {"bearer"}, "auth_token": {"abc"}},
+			want: monitor.RequestAuth{Type: "bearer", Token: "abc"},
+		},
+		{
+			name: "no auth",
+			form: url.Values{},
+			want: monitor.RequestAuth{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/targets/1/edit", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			assert.Equal(t, tt.want, parseRequestAuth(req, stored))
+		})
+	}
+}
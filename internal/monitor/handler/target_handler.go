@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
 	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
 	targetService "github.com/shuvo-paul/uptimebot/internal/monitor/service"
 	"github.com/shuvo-paul/uptimebot/internal/renderer"
 	"github.com/shuvo-paul/uptimebot/pkg/flash"
@@ -14,6 +21,7 @@ import (
 
 type TargetHandler struct {
 	targetService targetService.TargetServiceInterface
+	slaService    targetService.SLAServiceInterface
 	flash         flash.FlashStoreInterface
 	Template      struct {
 		List   *renderer.Template
@@ -22,9 +30,14 @@ type TargetHandler struct {
 	}
 }
 
-func NewTargetHandler(targetService targetService.TargetServiceInterface, flash flash.FlashStoreInterface) *TargetHandler {
+func NewTargetHandler(
+	targetService targetService.TargetServiceInterface,
+	slaService targetService.SLAServiceInterface,
+	flash flash.FlashStoreInterface,
+) *TargetHandler {
 	c := &TargetHandler{
 		targetService: targetService,
+		slaService:    slaService,
 		flash:         flash,
 	}
 
@@ -44,12 +57,37 @@ func (c *TargetHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	now := time.Now()
+	sla := make(map[int][]targetService.SLAReport, len(targets))
+	certificates := make(map[int]*model.TargetCertificate)
+	for _, target := range targets {
+		reports, err := c.slaService.GetStandardReports(target.ID, now)
+		if err != nil {
+			slog.Error("Failed to compute SLA", "Target", target.URL, "error", err)
+		} else {
+			sla[target.ID] = reports
+		}
+
+		if target.TLS.Enabled {
+			cert, err := c.targetService.GetCertificate(target.ID)
+			if err != nil {
+				slog.Error("Failed to fetch certificate", "Target", target.URL, "error", err)
+			} else if cert != nil {
+				certificates[target.ID] = cert
+			}
+		}
+	}
+
 	flashId := flash.GetFlashIDFromContext(r.Context())
 
 	data := map[string]any{
-		"title":   "all targets",
-		"targets": targets,
-		"success": c.flash.GetFlash(flashId, "success"),
+		"title":        "all targets",
+		"targets":      targets,
+		"sla":          sla,
+		"certificates": certificates,
+		"lastMonth":    previousMonth(now).Format("2006-01"),
+		"success":      c.flash.GetFlash(flashId, "success"),
+		"error":        c.flash.GetFlash(flashId, "error"),
 	}
 
 	c.Template.List.Render(w, r, data)
@@ -57,20 +95,18 @@ func (c *TargetHandler) List(w http.ResponseWriter, r *http.Request) {
 
 func (c *TargetHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodGet {
-		data := map[string]any{
-			"title": "add a target",
-		}
+		flashID := flash.GetFlashIDFromContext(r.Context())
+		data := targetFormData(&monitor.Target{Interval: 30 * time.Second})
+		data["title"] = "add a target"
+		data["error"] = c.flash.GetFlash(flashID, "error")
 		c.Template.Create.Render(w, r, data)
 		return
 	}
 
-	url := r.FormValue("url")
-	intervalStr := r.FormValue("interval")
-
-	interval, err := strconv.Atoi(intervalStr)
-	if err != nil {
+	target := &monitor.Target{}
+	if err := parseTargetForm(r, target); err != nil {
 		flashID := flash.GetFlashIDFromContext(r.Context())
-		c.flash.SetFlash(flashID, "error", "Invalid interval value")
+		c.flash.SetFlash(flashID, "error", err.Error())
 		http.Redirect(w, r, "/targets/create", http.StatusSeeOther)
 		return
 	}
@@ -81,7 +117,7 @@ func (c *TargetHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = c.targetService.Create(user.ID, url, time.Duration(interval)*time.Second)
+	_, err := c.targetService.Create(user.ID, target)
 	if err != nil {
 		flashID := flash.GetFlashIDFromContext(r.Context())
 		c.flash.SetFlash(flashID, "error", "Failed to create target: "+err.Error())
@@ -95,48 +131,39 @@ func (c *TargetHandler) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c *TargetHandler) Edit(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.Atoi(r.PathValue("id"))
-	if err != nil {
-		http.Error(w, "Invalid target ID", http.StatusBadRequest)
-		slog.Error("Invalid target ID", "error", err)
+	target, ok := userTarget(w, r, c.targetService, "id")
+	if !ok {
 		return
 	}
+	id := target.ID
 
 	if r.Method == http.MethodGet {
-		target, err := c.targetService.GetByID(id)
+		cert, err := c.targetService.GetCertificate(id)
 		if err != nil {
-			http.Error(w, "Target not found", http.StatusNotFound)
-			return
+			slog.Error("Failed to fetch certificate", "Target", target.URL, "error", err)
 		}
 
-		data := map[string]any{
-			"Title":  "Edit Target",
-			"target": target,
+		flashID := flash.GetFlashIDFromContext(r.Context())
+		data := targetFormData(target)
+		data["Title"] = "Edit Target"
+		data["certificate"] = cert
+		if target.Heartbeat.Token != "" {
+			data["pingURL"] = pingURL(r, target.Heartbeat.Token)
 		}
+		data["error"] = c.flash.GetFlash(flashID, "error")
 
 		c.Template.Edit.Render(w, r, data)
 		return
 	}
 
-	target, err := c.targetService.GetByID(id)
-	if err != nil {
-		http.Error(w, "Target not found", http.StatusNotFound)
-		return
-	}
-
-	target.URL = r.FormValue("url")
-	intervalStr := r.FormValue("interval")
-	interval, err := strconv.Atoi(intervalStr)
-	if err != nil {
+	if err := parseTargetForm(r, target); err != nil {
 		flashID := flash.GetFlashIDFromContext(r.Context())
-		c.flash.SetFlash(flashID, "error", "Invalid interval value")
+		c.flash.SetFlash(flashID, "error", err.Error())
 		http.Redirect(w, r, "/targets/"+strconv.Itoa(id)+"/edit", http.StatusSeeOther)
 		return
 	}
-	target.Interval = time.Duration(interval) * time.Second
 
-	_, err = c.targetService.Update(target)
-	if err != nil {
+	if _, err := c.targetService.Update(target); err != nil {
 		flashID := flash.GetFlashIDFromContext(r.Context())
 		c.flash.SetFlash(flashID, "error", "Failed to update target: "+err.Error())
 		http.Redirect(w, r, "/targets/"+strconv.Itoa(id)+"/edit", http.StatusSeeOther)
@@ -148,15 +175,54 @@ func (c *TargetHandler) Edit(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/targets", http.StatusSeeOther)
 }
 
-func (c *TargetHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.Atoi(r.PathValue("id"))
+// Ping receives a heartbeat from a cron job or worker. The optional event
+// is start, fail, or the job's exit code; without one the run succeeded.
+func (c *TargetHandler) Ping(w http.ResponseWriter, r *http.Request) {
+	event, exitCode := monitor.PingSuccess, 0
+	switch value := r.PathValue("event"); value {
+	case "":
+	case monitor.PingStart, monitor.PingFail:
+		event = value
+	default:
+		code, err := strconv.Atoi(value)
+		if err != nil || code < 0 || code > 255 {
+			http.Error(w, "Invalid ping event", http.StatusBadRequest)
+			return
+		}
+		exitCode = code
+	}
 
+	err := c.targetService.Ping(r.PathValue("token"), event, exitCode)
+	if errors.Is(err, repository.ErrTargetNotFound) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		slog.Error("Failed to record ping", "error", err)
+		http.Error(w, "Failed to record ping", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("OK"))
+}
+
+// pingURL returns the absolute ping URL for token as seen by the current request
+func pingURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/ping/%s", scheme, r.Host, token)
+}
+
+func (c *TargetHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	target, ok := userTarget(w, r, c.targetService, "id")
+	if !ok {
 		return
 	}
 
-	err = c.targetService.Delete(id)
+	err := c.targetService.Delete(target.ID)
 	flashID := flash.GetFlashIDFromContext(r.Context())
 	if err != nil {
 		c.flash.SetFlash(flashID, "error", "Failed to delete target: "+err.Error())
@@ -166,3 +232,124 @@ func (c *TargetHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	http.Redirect(w, r, "/targets", http.StatusSeeOther)
 }
+
+// Enable resumes checking a paused target
+func (c *TargetHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	c.setEnabled(w, r, true)
+}
+
+// Disable pauses checking a target
+func (c *TargetHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	c.setEnabled(w, r, false)
+}
+
+func (c *TargetHandler) setEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	target, ok := userTarget(w, r, c.targetService, "id")
+	if !ok {
+		return
+	}
+
+	flashID := flash.GetFlashIDFromContext(r.Context())
+	if _, err := c.targetService.SetEnabled(target.ID, enabled); err != nil {
+		c.flash.SetFlash(flashID, "error", "Failed to update target: "+err.Error())
+	} else if enabled {
+		c.flash.SetFlash(flashID, "success", "Target resumed")
+	} else {
+		c.flash.SetFlash(flashID, "success", "Target paused")
+	}
+
+	http.Redirect(w, r, "/targets", http.StatusSeeOther)
+}
+
+// userTarget resolves the target named by the request's param among the
+// signed-in user's targets. Anyone else's target answers 404.
+func userTarget(w http.ResponseWriter, r *http.Request, targets targetService.TargetServiceInterface, param string) (*monitor.Target, bool) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	id, err := strconv.Atoi(r.PathValue(param))
+	if err != nil {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	owned, err := targets.GetAllByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to fetch targets", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	index := slices.IndexFunc(owned, func(target *monitor.Target) bool { return target.ID == id })
+	if index < 0 {
+		http.Error(w, "Target not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	return owned[index], true
+}
+
+// previousMonth returns the first day of the month before now's. Stepping
+// back a month from the 31st would otherwise land in the current month
+// whenever the previous one is shorter.
+func previousMonth(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -1, 0)
+}
+
+// MonthlyReport downloads the calendar month SLA report for all of the
+// user's targets as CSV. The month is given as ?month=YYYY-MM and defaults
+// to the previous month.
+func (c *TargetHandler) MonthlyReport(w http.ResponseWriter, r *http.Request) {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return
+	}
+
+	month := previousMonth(time.Now())
+	if value := r.URL.Query().Get("month"); value != "" {
+		parsed, err := time.Parse("2006-01", value)
+		if err != nil {
+			http.Error(w, "Invalid month, expected YYYY-MM", http.StatusBadRequest)
+			return
+		}
+		month = parsed
+	}
+
+	reports, err := c.slaService.GetMonthlyReport(user.ID, month.Year(), month.Month())
+	if err != nil {
+		slog.Error("Failed to build monthly report", "error", err)
+		http.Error(w, "Failed to build report", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("sla-report-%s.csv", month.Format("2006-01"))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"target", "month", "checks", "uptime_percent", "downtime_seconds",
+		"incidents", "mttr_seconds", "mtbf_seconds",
+	})
+	for _, entry := range reports {
+		report := entry.Report
+		writer.Write([]string{
+			entry.Target.URL,
+			report.Window.Label,
+			strconv.Itoa(report.Checks),
+			strconv.FormatFloat(report.Uptime, 'f', 3, 64),
+			strconv.FormatInt(int64(report.Downtime.Seconds()), 10),
+			strconv.Itoa(report.Incidents),
+			strconv.FormatInt(int64(report.MTTR.Seconds()), 10),
+			strconv.FormatInt(int64(report.MTBF.Seconds()), 10),
+		})
+	}
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		slog.Error("Failed to write monthly report", "error", err)
+	}
+}
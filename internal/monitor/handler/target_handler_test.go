@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"crypto/tls"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -11,6 +12,9 @@ import (
 	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
 	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
 	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	targetService "github.com/shuvo-paul/uptimebot/internal/monitor/service"
 	"github.com/shuvo-paul/uptimebot/internal/renderer"
 	"github.com/shuvo-paul/uptimebot/internal/templates"
 	"github.com/shuvo-paul/uptimebot/internal/testutil"
@@ -21,11 +25,15 @@ import (
 type mockTargetService struct {
 	getAllFunc               func() ([]*monitor.Target, error)
 	getByIDFunc              func(id int) (*monitor.Target, error)
-	createFunc               func(userID int, url string, interval time.Duration) (*monitor.Target, error)
+	createFunc               func(userID int, target *monitor.Target) (*monitor.Target, error)
 	updateFunc               func(target *monitor.Target) (*monitor.Target, error)
 	deleteFunc               func(id int) error
+	setEnabledFunc           func(id int, enabled bool) (*monitor.Target, error)
 	getAllByUserIDFunc       func(userID int) ([]*monitor.Target, error)
 	initializeMonitoringFunc func() error
+	getCheckResultsFunc      func(targetID int, from, to time.Time) ([]monitor.CheckResult, error)
+	getCertificateFunc       func(targetID int) (*model.TargetCertificate, error)
+	pingFunc                 func(token, event string, exitCode int) error
 }
 
 func (m *mockTargetService) GetAll() ([]*monitor.Target, error) {
@@ -36,8 +44,8 @@ func (m *mockTargetService) GetByID(id int) (*monitor.Target, error) {
 	return m.getByIDFunc(id)
 }
 
-func (m *mockTargetService) Create(userID int, url string, interval time.Duration) (*monitor.Target, error) {
-	return m.createFunc(userID, url, interval)
+func (m *mockTargetService) Create(userID int, target *monitor.Target) (*monitor.Target, error) {
+	return m.createFunc(userID, target)
 }
 
 func (m *mockTargetService) Update(target *monitor.Target) (*monitor.Target, error) {
@@ -48,6 +56,10 @@ func (m *mockTargetService) Delete(id int) error {
 	return m.deleteFunc(id)
 }
 
+func (m *mockTargetService) SetEnabled(id int, enabled bool) (*monitor.Target, error) {
+	return m.setEnabledFunc(id, enabled)
+}
+
 func (m *mockTargetService) GetAllByUserID(userID int) ([]*monitor.Target, error) {
 	return m.getAllByUserIDFunc(userID)
 }
@@ -59,6 +71,46 @@ func (m *mockTargetService) InitializeMonitoring() error {
 	return nil
 }
 
+func (m *mockTargetService) GetCheckResults(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+	if m.getCheckResultsFunc != nil {
+		return m.getCheckResultsFunc(targetID, from, to)
+	}
+	return nil, nil
+}
+
+func (m *mockTargetService) GetCertificate(targetID int) (*model.TargetCertificate, error) {
+	if m.getCertificateFunc != nil {
+		return m.getCertificateFunc(targetID)
+	}
+	return nil, nil
+}
+
+func (m *mockTargetService) Ping(token, event string, exitCode int) error {
+	return m.pingFunc(token, event, exitCode)
+}
+
+// Mock SLAService
+type mockSLAService struct {
+	getReportFunc          func(targetID int, window targetService.SLAWindow) (targetService.SLAReport, error)
+	getStandardReportsFunc func(targetID int, now time.Time) ([]targetService.SLAReport, error)
+	getMonthlyReportFunc   func(userID int, year int, month time.Month) ([]targetService.TargetSLA, error)
+}
+
+func (m *mockSLAService) GetReport(targetID int, window targetService.SLAWindow) (targetService.SLAReport, error) {
+	return m.getReportFunc(targetID, window)
+}
+
+func (m *mockSLAService) GetStandardReports(targetID int, now time.Time) ([]targetService.SLAReport, error) {
+	if m.getStandardReportsFunc != nil {
+		return m.getStandardReportsFunc(targetID, now)
+	}
+	return nil, nil
+}
+
+func (m *mockSLAService) GetMonthlyReport(userID int, year int, month time.Month) ([]targetService.TargetSLA, error) {
+	return m.getMonthlyReportFunc(userID, year, month)
+}
+
 func TestTargetHandler_List(t *testing.T) {
 	mockService := &mockTargetService{
 		getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
@@ -67,7 +119,17 @@ func TestTargetHandler_List(t *testing.T) {
 		initializeMonitoringFunc: func() error { return nil },
 	}
 
-	handler := NewTargetHandler(mockService, &testutil.MockFlashStore{})
+	slaService := &mockSLAService{
+		getStandardReportsFunc: func(targetID int, now time.Time) ([]targetService.SLAReport, error) {
+			reports := []targetService.SLAReport{}
+			for _, window := range targetService.StandardWindows(now) {
+				reports = append(reports, targetService.SLAReport{Window: window, Checks: 10, Uptime: 99.95})
+			}
+			return reports, nil
+		},
+	}
+
+	handler := NewTargetHandler(mockService, slaService, &testutil.MockFlashStore{})
 	templateRenderer := renderer.New(templates.TemplateFS)
 	handler.Template.List = templateRenderer.GetTemplate("pages:targets/list")
 
@@ -80,6 +142,49 @@ func TestTargetHandler_List(t *testing.T) {
 	handler.List(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "99.950%")
+}
+
+func TestTargetHandler_List_Certificate(t *testing.T) {
+	now := time.Now()
+	mockService := &mockTargetService{
+		getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
+			return []*monitor.Target{{
+				ID:       1,
+				URL:      "https://example.com",
+				Interval: 60 * time.Second,
+				TLS:      monitor.TLSSettings{Enabled: true},
+			}}, nil
+		},
+		getCertificateFunc: func(targetID int) (*model.TargetCertificate, error) {
+			return &model.TargetCertificate{
+				TargetID: targetID,
+				TLSInfo: monitor.TLSInfo{
+					Issuer:        "CN=Test CA",
+					NotAfter:      now.Add(12*24*time.Hour + time.Hour),
+					Version:       tls.VersionTLS13,
+					ChainTrusted:  true,
+					HostnameValid: true,
+					CheckedAt:     now,
+				},
+			}, nil
+		},
+	}
+
+	handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+	templateRenderer := renderer.New(templates.TemplateFS)
+	handler.Template.List = templateRenderer.GetTemplate("pages:targets/list")
+
+	req := httptest.NewRequest(http.MethodGet, "/targets", nil)
+	req = req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: 1}))
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "(12 days)")
+	assert.Contains(t, w.Body.String(), "TLS 1.3")
+	assert.Contains(t, w.Body.String(), "CN=Test CA")
 }
 
 func TestTargetHandler_Create(t *testing.T) {
@@ -87,7 +192,7 @@ func TestTargetHandler_Create(t *testing.T) {
 		mockService := &mockTargetService{
 			initializeMonitoringFunc: func() error { return nil },
 		}
-		handler := NewTargetHandler(mockService, &testutil.MockFlashStore{})
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
 		templateRenderer := renderer.New(templates.TemplateFS)
 		handler.Template.Create = templateRenderer.GetTemplate("pages:targets/create")
 
@@ -101,13 +206,14 @@ func TestTargetHandler_Create(t *testing.T) {
 
 	t.Run("POST request - success", func(t *testing.T) {
 		mockService := &mockTargetService{
-			createFunc: func(userID int, url string, interval time.Duration) (*monitor.Target, error) {
-				return &monitor.Target{ID: 1, URL: url, Interval: interval}, nil
+			createFunc: func(userID int, target *monitor.Target) (*monitor.Target, error) {
+				target.ID = 1
+				return target, nil
 			},
 			initializeMonitoringFunc: func() error { return nil },
 		}
 
-		handler := NewTargetHandler(mockService, &testutil.MockFlashStore{})
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
 
 		form := url.Values{}
 		form.Add("url", "http://example.com")
@@ -131,13 +237,14 @@ func TestTargetHandler_Create(t *testing.T) {
 
 	t.Run("POST request - no user in context", func(t *testing.T) {
 		mockService := &mockTargetService{
-			createFunc: func(userID int, url string, interval time.Duration) (*monitor.Target, error) {
-				return &monitor.Target{ID: 1, URL: url, Interval: interval}, nil
+			createFunc: func(userID int, target *monitor.Target) (*monitor.Target, error) {
+				target.ID = 1
+				return target, nil
 			},
 			initializeMonitoringFunc: func() error { return nil },
 		}
 
-		handler := NewTargetHandler(mockService, &testutil.MockFlashStore{})
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
 
 		form := url.Values{}
 		form.Add("url", "http://example.com")
@@ -151,43 +258,358 @@ func TestTargetHandler_Create(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
+
+	t.Run("POST request - request spec", func(t *testing.T) {
+		var created *monitor.Target
+		mockService := &mockTargetService{
+			createFunc: func(userID int, target *monitor.Target) (*monitor.Target, error) {
+				created = target
+				return target, nil
+			},
+			initializeMonitoringFunc: func() error { return nil },
+		}
+
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("url", "http://example.com/api")
+		form.Add("interval", "60")
+		form.Add("method", "POST")
+		form.Add("headers", "Accept: application/json\r\nX-Trace:  abc \r\n")
+		form.Add("query", "page=1")
+		form.Add("body", `{"ping":true}`)
+		form.Add("auth_type", "bearer")
+		form.Add("auth_token", "secret")
+		form.Add("assertions", "status 200-299\njson status ok\n")
+		form.Add("latency_threshold", "2000")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: 1}))
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/targets", w.Header().Get("Location"))
+		assert.Equal(t, 60*time.Second, created.Interval)
+		assert.Equal(t, monitor.RequestSpec{
+			Method:  "POST",
+			Headers: map[string]string{"Accept": "application/json", "X-Trace": "abc"},
+			Query:   map[string]string{"page": "1"},
+			Body:    `{"ping":true}`,
+			Auth:    monitor.RequestAuth{Type: "bearer", Token: "secret"},
+		}, created.Request)
+		assert.Equal(t, monitor.Assertions{
+			{Type: monitor.AssertStatusCode, Value: "200-299"},
+			{Type: monitor.AssertJSONPath, Target: "status", Value: "ok"},
+		}, created.Assertions)
+		assert.Equal(t, 2*time.Second, created.LatencyThreshold)
+	})
+
+	t.Run("POST request - invalid assertion", func(t *testing.T) {
+		handler := NewTargetHandler(&mockTargetService{}, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("url", "http://example.com")
+		form.Add("interval", "60")
+		form.Add("assertions", "regex [")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/targets/create", w.Header().Get("Location"))
+	})
+
+	t.Run("POST request - TLS settings", func(t *testing.T) {
+		var created *monitor.Target
+		mockService := &mockTargetService{
+			createFunc: func(userID int, target *monitor.Target) (*monitor.Target, error) {
+				created = target
+				return target, nil
+			},
+		}
+
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("url", "https://example.com")
+		form.Add("interval", "60")
+		form.Add("tls_enabled", "1")
+		form.Add("tls_min_version", "1.3")
+		form.Add("tls_expiry_days", "21, 3")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: 1}))
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/targets", w.Header().Get("Location"))
+		assert.Equal(t, monitor.TLSSettings{
+			Enabled:    true,
+			MinVersion: tls.VersionTLS13,
+			ExpiryDays: []int{21, 3},
+		}, created.TLS)
+	})
+
+	t.Run("POST request - TCP send/expect", func(t *testing.T) {
+		var created *monitor.Target
+		mockService := &mockTargetService{
+			createFunc: func(userID int, target *monitor.Target) (*monitor.Target, error) {
+				created = target
+				return target, nil
+			},
+		}
+
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("kind", monitor.KindTCPExpect)
+		form.Add("url", "tcp://redis.internal:6379")
+		form.Add("interval", "60")
+		form.Add("tcp_send", `PING\r\n`)
+		form.Add("tcp_expect", "+PONG")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: 1}))
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, monitor.KindTCPExpect, created.Kind)
+		assert.Equal(t, monitor.TCPSpec{Send: "PING\r\n", Expect: "+PONG"}, created.TCP)
+	})
+
+	t.Run("POST request - DNS record", func(t *testing.T) {
+		var created *monitor.Target
+		mockService := &mockTargetService{
+			createFunc: func(userID int, target *monitor.Target) (*monitor.Target, error) {
+				created = target
+				return target, nil
+			},
+		}
+
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("kind", monitor.KindDNS)
+		form.Add("url", "dns://example.com")
+		form.Add("interval", "60")
+		form.Add("dns_record_type", "MX")
+		form.Add("dns_resolver", " 1.1.1.1 ")
+		form.Add("dns_expected", "10 mail.example.com\r\n20 backup.example.com\r\n")
+		form.Add("dns_max_resolve_ms", "500")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: 1}))
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, monitor.KindDNS, created.Kind)
+		assert.Equal(t, monitor.DNSSpec{
+			RecordType:   "MX",
+			Resolver:     "1.1.1.1",
+			Expected:     []string{"10 mail.example.com", "20 backup.example.com"},
+			MaxResolveMs: 500,
+		}, created.DNS)
+	})
+
+	t.Run("POST request - heartbeat", func(t *testing.T) {
+		var created *monitor.Target
+		mockService := &mockTargetService{
+			createFunc: func(userID int, target *monitor.Target) (*monitor.Target, error) {
+				created = target
+				return target, nil
+			},
+		}
+
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("kind", monitor.KindHeartbeat)
+		form.Add("url", "heartbeat://nightly-backup")
+		form.Add("interval", "86400")
+		form.Add("heartbeat_grace", "1800")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: 1}))
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, monitor.KindHeartbeat, created.Kind)
+		assert.Equal(t, 1800, created.Heartbeat.GraceSeconds)
+	})
+
+	t.Run("POST request - confirmation thresholds", func(t *testing.T) {
+		var created *monitor.Target
+		mockService := &mockTargetService{
+			createFunc: func(userID int, target *monitor.Target) (*monitor.Target, error) {
+				created = target
+				return target, nil
+			},
+		}
+
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("url", "https://example.com")
+		form.Add("interval", "60")
+		form.Add("confirm_after", "3")
+		form.Add("recover_after", "2")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: 1}))
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, 3, created.ConfirmAfter)
+		assert.Equal(t, 2, created.RecoverAfter)
+	})
+
+	t.Run("POST request - invalid confirmation threshold", func(t *testing.T) {
+		mockService := &mockTargetService{
+			createFunc: func(userID int, target *monitor.Target) (*monitor.Target, error) {
+				t.Error("Create should not be called")
+				return target, nil
+			},
+		}
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("url", "https://example.com")
+		form.Add("interval", "60")
+		form.Add("confirm_after", "11")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: 1}))
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+	})
+
+	t.Run("POST request - invalid TLS version", func(t *testing.T) {
+		handler := NewTargetHandler(&mockTargetService{}, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("url", "https://example.com")
+		form.Add("interval", "60")
+		form.Add("tls_enabled", "1")
+		form.Add("tls_min_version", "2.0")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/targets/create", w.Header().Get("Location"))
+	})
+
+	t.Run("POST request - malformed headers", func(t *testing.T) {
+		handler := NewTargetHandler(&mockTargetService{}, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("url", "http://example.com")
+		form.Add("interval", "60")
+		form.Add("headers", "no separator")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		handler.Create(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/targets/create", w.Header().Get("Location"))
+	})
 }
 
 func TestTargetHandler_Edit(t *testing.T) {
 	t.Run("GET request", func(t *testing.T) {
 		mockService := &mockTargetService{
-			getByIDFunc: func(id int) (*monitor.Target, error) {
-				return &monitor.Target{ID: id, URL: "http://example.com", Interval: 60 * time.Second}, nil
+			getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
+				assert.Equal(t, 1, userID)
+				return []*monitor.Target{{
+					ID:       1,
+					URL:      "http://example.com",
+					Interval: 60 * time.Second,
+					Request:  monitor.RequestSpec{Auth: monitor.RequestAuth{Type: "basic", Username: "admin", Password: "hunter2"}},
+				}}, nil
 			},
 			initializeMonitoringFunc: func() error { return nil },
 		}
 
-		handler := NewTargetHandler(mockService, &testutil.MockFlashStore{})
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
 		templateRenderer := renderer.New(templates.TemplateFS)
 		handler.Template.Edit = templateRenderer.GetTemplate("pages:targets/edit")
 
 		req := httptest.NewRequest(http.MethodGet, "/targets/1/edit", nil)
 		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
 		w := httptest.NewRecorder()
 
 		handler.Edit(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "hunter2", "stored passwords are not sent back")
+		assert.Contains(t, w.Body.String(), "Leave blank to keep")
 	})
 
-	t.Run("POST request - success", func(t *testing.T) {
-		target := &monitor.Target{ID: 1, URL: "http://example.com", Interval: 60 * time.Second}
+	t.Run("GET request - target of another user", func(t *testing.T) {
 		mockService := &mockTargetService{
-			getByIDFunc: func(id int) (*monitor.Target, error) {
-				return target, nil
-			},
-			updateFunc: func(t *monitor.Target) (*monitor.Target, error) {
-				return t, nil
+			getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
+				if userID != 1 {
+					return nil, nil
+				}
+				return []*monitor.Target{{ID: 1, URL: "heartbeat://backup", Kind: monitor.KindHeartbeat, Heartbeat: monitor.HeartbeatSpec{Token: "s3cret-token"}}}, nil
 			},
-			initializeMonitoringFunc: func() error { return nil },
 		}
 
-		handler := NewTargetHandler(mockService, &testutil.MockFlashStore{})
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+		handler.Template.Edit = renderer.New(templates.TemplateFS).GetTemplate("pages:targets/edit")
+
+		req := httptest.NewRequest(http.MethodGet, "/targets/1/edit", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.NotContains(t, w.Body.String(), "s3cret-token")
+	})
+
+	t.Run("POST request - success", func(t *testing.T) {
+		var updated *monitor.Target
+		mockService := ownerTargetService()
+		mockService.updateFunc = func(t *monitor.Target) (*monitor.Target, error) {
+			updated = t
+			return t, nil
+		}
+
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
 
 		form := url.Values{}
 		form.Add("url", "http://example.com")
@@ -196,44 +618,131 @@ func TestTargetHandler_Edit(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/targets/1/edit", strings.NewReader(form.Encode()))
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
 		w := httptest.NewRecorder()
 
 		handler.Edit(w, req)
 
 		assert.Equal(t, http.StatusSeeOther, w.Code)
 		assert.Equal(t, "/targets", w.Header().Get("Location"))
+		if assert.NotNil(t, updated) {
+			assert.Equal(t, 1, updated.ID)
+		}
 	})
-}
 
-func TestTargetHandler_Delete(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		mockService := &mockTargetService{
-			deleteFunc: func(id int) error {
-				return nil
-			},
-			initializeMonitoringFunc: func() error { return nil },
+	t.Run("POST request - target of another user", func(t *testing.T) {
+		mockService := ownerTargetService()
+		mockService.updateFunc = func(target *monitor.Target) (*monitor.Target, error) {
+			t.Error("another user's target must not be updated")
+			return target, nil
 		}
 
-		handler := NewTargetHandler(mockService, &testutil.MockFlashStore{})
+		handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+
+		form := url.Values{}
+		form.Add("url", "http://attacker.example.com")
+		form.Add("interval", "60")
+
+		req := httptest.NewRequest(http.MethodPost, "/targets/1/edit", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("id", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Edit(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestTargetHandler_Ping(t *testing.T) {
+	tests := []struct {
+		name         string
+		token        string
+		event        string
+		wantCode     int
+		wantEvent    string
+		wantExitCode int
+	}{
+		{name: "success", token: "abc", wantCode: http.StatusOK, wantEvent: monitor.PingSuccess},
+		{name: "start", token: "abc", event: "start", wantCode: http.StatusOK, wantEvent: monitor.PingStart},
+		{name: "fail", token: "abc", event: "fail", wantCode: http.StatusOK, wantEvent: monitor.PingFail},
+		{name: "exit code", token: "abc", event: "2", wantCode: http.StatusOK, wantEvent: monitor.PingSuccess, wantExitCode: 2},
+		{name: "invalid event", token: "abc", event: "restart", wantCode: http.StatusBadRequest},
+		{name: "exit code out of range", token: "abc", event: "256", wantCode: http.StatusBadRequest},
+		{name: "unknown token", token: "missing", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotEvent string
+			var gotExitCode int
+			mockService := &mockTargetService{
+				pingFunc: func(token, event string, exitCode int) error {
+					if token != "abc" {
+						return repository.ErrTargetNotFound
+					}
+					gotEvent, gotExitCode = event, exitCode
+					return nil
+				},
+			}
+			handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+
+			req := httptest.NewRequest(http.MethodPost, "/ping/"+tt.token, nil)
+			req.SetPathValue("token", tt.token)
+			req.SetPathValue("event", tt.event)
+			w := httptest.NewRecorder()
+
+			handler.Ping(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.wantEvent, gotEvent)
+				assert.Equal(t, tt.wantExitCode, gotExitCode)
+			}
+		})
+	}
+}
+
+func TestTargetHandler_Delete(t *testing.T) {
+	var deleted int
+	mockService := ownerTargetService()
+	mockService.deleteFunc = func(id int) error {
+		deleted = id
+		return nil
+	}
+	handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
 
+	t.Run("success", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/targets/1/delete", nil)
 		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
 		w := httptest.NewRecorder()
 
 		handler.Delete(w, req)
 
 		assert.Equal(t, http.StatusSeeOther, w.Code)
 		assert.Equal(t, "/targets", w.Header().Get("Location"))
+		assert.Equal(t, 1, deleted)
 	})
 
-	t.Run("invalid ID", func(t *testing.T) {
-		mockService := &mockTargetService{
-			initializeMonitoringFunc: func() error { return nil },
-		}
-		handler := NewTargetHandler(mockService, &testutil.MockFlashStore{})
+	t.Run("target of another user", func(t *testing.T) {
+		deleted = 0
+		req := httptest.NewRequest(http.MethodPost, "/targets/1/delete", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
 
+		handler.Delete(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Zero(t, deleted)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/targets/invalid/delete", nil)
 		req.SetPathValue("id", "invalid")
+		req = withUser(req, 1)
 		w := httptest.NewRecorder()
 
 		handler.Delete(w, req)
@@ -241,3 +750,106 @@ func TestTargetHandler_Delete(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
+
+func TestTargetHandler_Disable(t *testing.T) {
+	var paused int
+	mockService := ownerTargetService()
+	mockService.setEnabledFunc = func(id int, enabled bool) (*monitor.Target, error) {
+		assert.False(t, enabled)
+		paused = id
+		return &monitor.Target{ID: id}, nil
+	}
+	handler := NewTargetHandler(mockService, &mockSLAService{}, &testutil.MockFlashStore{})
+
+	t.Run("own target", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/targets/1/disable", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.Disable(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/targets", w.Header().Get("Location"))
+		assert.Equal(t, 1, paused)
+	})
+
+	t.Run("target of another user", func(t *testing.T) {
+		paused = 0
+		req := httptest.NewRequest(http.MethodPost, "/targets/1/disable", nil)
+		req.SetPathValue("id", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.Disable(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Zero(t, paused)
+	})
+}
+
+func TestTargetHandler_MonthlyReport(t *testing.T) {
+	slaService := &mockSLAService{
+		getMonthlyReportFunc: func(userID int, year int, month time.Month) ([]targetService.TargetSLA, error) {
+			assert.Equal(t, 1, userID)
+			assert.Equal(t, 2025, year)
+			assert.Equal(t, time.January, month)
+			return []targetService.TargetSLA{
+				{
+					Target: &monitor.Target{ID: 1, URL: "http://example.com"},
+					Report: targetService.SLAReport{
+						Window:    targetService.CalendarMonth(2025, time.January),
+						Checks:    100,
+						Uptime:    99.5,
+						Downtime:  2 * time.Hour,
+						Incidents: 2,
+						MTTR:      time.Hour,
+						MTBF:      300 * time.Hour,
+					},
+				},
+			}, nil
+		},
+	}
+	handler := NewTargetHandler(&mockTargetService{}, slaService, &testutil.MockFlashStore{})
+
+	t.Run("csv download", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/targets/reports/monthly?month=2025-01", nil)
+		req = req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: 1}))
+		w := httptest.NewRecorder()
+
+		handler.MonthlyReport(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "sla-report-2025-01.csv")
+		assert.Contains(t, w.Body.String(), "http://example.com,2025-01,100,99.500,7200,2,3600,1080000")
+	})
+
+	t.Run("invalid month", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/targets/reports/monthly?month=January", nil)
+		req = req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: 1}))
+		w := httptest.NewRecorder()
+
+		handler.MonthlyReport(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPreviousMonth(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{name: "end of a long month", now: time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC), want: "2025-02"},
+		{name: "start of the year", now: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), want: "2024-12"},
+		{name: "first of the month", now: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), want: "2025-04"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, previousMonth(tt.now).Format("2006-01"))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxEscalationWait is the longest a step can wait for an acknowledgement
+const MaxEscalationWait = 24 * 60
+
+// EscalationPolicy pages people in turn while an outage on one of its
+// targets goes unacknowledged. Each step notifies its notifiers, then waits
+// for someone to acknowledge the incident before moving on to the next.
+type EscalationPolicy struct {
+	ID        int
+	UserID    int
+	Name      string
+	Steps     []EscalationStep
+	TargetIDs []int
+}
+
+// EscalationStep is one round of notifications in an escalation policy
+type EscalationStep struct {
+	NotifierIDs []int64 `json:"notifier_ids"`
+	// WaitMinutes is how long to wait for an acknowledgement before the
+	// next step; unused on the last step
+	WaitMinutes int `json:"wait_minutes"`
+}
+
+// Includes reports whether the policy is assigned to the target
+func (p EscalationPolicy) Includes(targetID int) bool {
+	return slices.Contains(p.TargetIDs, targetID)
+}
+
+// Notifies reports whether the step pages the notifier
+func (s EscalationStep) Notifies(notifierID int64) bool {
+	return slices.Contains(s.NotifierIDs, notifierID)
+}
+
+// Validate reports the first problem that would keep the policy from
+// paging anyone
+func (p EscalationPolicy) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("add at least one step")
+	}
+	for i, step := range p.Steps {
+		if len(step.NotifierIDs) == 0 {
+			return fmt.Errorf("step %d notifies nobody", i+1)
+		}
+		if i < len(p.Steps)-1 && (step.WaitMinutes < 1 || step.WaitMinutes > MaxEscalationWait) {
+			return fmt.Errorf("step %d must wait between 1 and %d minutes", i+1, MaxEscalationWait)
+		}
+	}
+	return nil
+}
+
+// Escalation tracks an escalation policy working through an incident
+type Escalation struct {
+	IncidentID int
+	PolicyID   int
+	Step       int       // index of the next step to fire
+	NextAt     time.Time // when the next step fires, zero once every step has
+	Notified   []int64   // notifiers reached so far, told when the incident ends
+	FinishedAt time.Time // zero while the escalation runs
+}
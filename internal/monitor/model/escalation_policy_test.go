@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscalationPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  EscalationPolicy
+		wantErr string
+	}{
+		{
+			name: "valid",
+			policy: EscalationPolicy{Name: "On-call", Steps: []EscalationStep{
+				{NotifierIDs: []int64{1}, WaitMinutes: 15},
+				{NotifierIDs: []int64{2, 3}},
+			}},
+		},
+		{name: "no name", policy: EscalationPolicy{Name: " ", Steps: []EscalationStep{{NotifierIDs: []int64{1}}}}, wantErr: "name is required"},
+		{name: "no steps", policy: EscalationPolicy{Name: "On-call"}, wantErr: "add at least one step"},
+		{
+			name:    "step without notifiers",
+			policy:  EscalationPolicy{Name: "On-call", Steps: []EscalationStep{{NotifierIDs: []int64{1}, WaitMinutes: 5}, {}}},
+			wantErr: "step 2 notifies nobody",
+		},
+		{
+			name:    "step without wait",
+			policy:  EscalationPolicy{Name: "On-call", Steps: []EscalationStep{{NotifierIDs: []int64{1}}, {NotifierIDs: []int64{2}}}},
+			wantErr: "step 1 must wait between 1 and 1440 minutes",
+		},
+		{
+			name:    "wait too long",
+			policy:  EscalationPolicy{Name: "On-call", Steps: []EscalationStep{{NotifierIDs: []int64{1}, WaitMinutes: MaxEscalationWait + 1}, {NotifierIDs: []int64{2}}}},
+			wantErr: "step 1 must wait between 1 and 1440 minutes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEscalationPolicy_Includes(t *testing.T) {
+	policy := EscalationPolicy{TargetIDs: []int{1, 3}}
+	assert.True(t, policy.Includes(3))
+	assert.False(t, policy.Includes(2))
+}
+
+func TestEscalationStep_Notifies(t *testing.T) {
+	step := EscalationStep{NotifierIDs: []int64{4, 7}}
+	assert.True(t, step.Notifies(7))
+	assert.False(t, step.Notifies(5))
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Kinds of entry on an incident's timeline
+const (
+	IncidentOpened       = "opened"
+	IncidentCheckFailed  = "check_failed"
+	IncidentStatus       = "status_changed"
+	IncidentNotification = "notification"
+	IncidentAcknowledged = "acknowledged"
+	IncidentUpdate       = "update"
+	IncidentResolved     = "resolved"
+)
+
+// Incident is a period during which a target was failing, from the first
+// confirmed failure until it recovered
+type Incident struct {
+	ID             int
+	TargetID       int
+	TargetURL      string
+	UserID         int // owner of the target
+	Cause          string
+	StartedAt      time.Time
+	AcknowledgedAt time.Time // zero until someone acknowledges it
+	AcknowledgedBy string    // name of the acknowledging user
+	ResolvedAt     time.Time // zero while the incident is open
+}
+
+// IsAcknowledged reports whether someone has taken the incident on
+func (i Incident) IsAcknowledged() bool {
+	return !i.AcknowledgedAt.IsZero()
+}
+
+// IsResolved reports whether the target has recovered
+func (i Incident) IsResolved() bool {
+	return !i.ResolvedAt.IsZero()
+}
+
+// Duration returns how long the incident lasted, or has lasted so far, to
+// the second
+func (i Incident) Duration(now time.Time) time.Duration {
+	end := now
+	if i.IsResolved() {
+		end = i.ResolvedAt
+	}
+	return end.Sub(i.StartedAt).Round(time.Second)
+}
+
+// IncidentEvent is one entry on an incident's timeline
+type IncidentEvent struct {
+	ID         int
+	IncidentID int
+	Kind       string
+	Message    string
+	UserID     int    // zero for entries recorded by the monitor
+	UserName   string // name of the user behind the entry, if any
+	CreatedAt  time.Time
+}
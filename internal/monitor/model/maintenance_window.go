@@ -0,0 +1,130 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kinds of maintenance window
+const (
+	MaintenanceOnce   = "once"
+	MaintenanceWeekly = "weekly"
+)
+
+// maxWeeklyDuration keeps a weekly window from overlapping its next occurrence
+const maxWeeklyDuration = 7 * 24 * time.Hour
+
+// MaintenanceWindow is a planned period during which a target is still
+// checked, but its status changes are not alerted on and its downtime does
+// not count against the SLA
+type MaintenanceWindow struct {
+	ID          int
+	TargetID    int
+	Description string
+	Kind        string
+	StartsAt    time.Time      // once: when the window opens
+	EndsAt      time.Time      // once: when the window closes
+	Weekdays    []time.Weekday // weekly: days the window opens on
+	StartTime   string         // weekly: time of day the window opens, as 15:04
+	Duration    time.Duration  // weekly: how long the window stays open
+	Timezone    string         // weekly: IANA zone StartTime is in, UTC when empty
+}
+
+// Validate reports the first problem that would keep the window from ever
+// being active
+func (w MaintenanceWindow) Validate() error {
+	switch w.Kind {
+	case MaintenanceOnce:
+		if w.StartsAt.IsZero() || w.EndsAt.IsZero() {
+			return fmt.Errorf("start and end are required")
+		}
+		if !w.StartsAt.Before(w.EndsAt) {
+			return fmt.Errorf("end must be after start")
+		}
+	case MaintenanceWeekly:
+		if len(w.Weekdays) == 0 {
+			return fmt.Errorf("at least one weekday is required")
+		}
+		for _, day := range w.Weekdays {
+			if day < time.Sunday || day > time.Saturday {
+				return fmt.Errorf("invalid weekday: %d", day)
+			}
+		}
+		if _, err := time.Parse("15:04", w.StartTime); err != nil {
+			return fmt.Errorf("invalid start time %q, expected HH:MM", w.StartTime)
+		}
+		if w.Duration < time.Minute || w.Duration > maxWeeklyDuration {
+			return fmt.Errorf("duration must be between a minute and 7 days")
+		}
+		if _, err := w.location(); err != nil {
+			return fmt.Errorf("unknown timezone %q", w.Timezone)
+		}
+	default:
+		return fmt.Errorf("unknown maintenance kind %q", w.Kind)
+	}
+	return nil
+}
+
+// IsActive reports whether at falls within the window. Weekly windows are
+// matched in their own timezone, so they follow daylight saving changes.
+func (w MaintenanceWindow) IsActive(at time.Time) bool {
+	switch w.Kind {
+	case MaintenanceOnce:
+		return !at.Before(w.StartsAt) && at.Before(w.EndsAt)
+	case MaintenanceWeekly:
+		loc, err := w.location()
+		if err != nil {
+			return false
+		}
+		clock, err := time.Parse("15:04", w.StartTime)
+		if err != nil {
+			return false
+		}
+
+		local := at.In(loc)
+		// A window that opened on an earlier day may still be open
+		for daysBack := 0; daysBack <= int(w.Duration/(24*time.Hour))+1; daysBack++ {
+			day := local.AddDate(0, 0, -daysBack)
+			start := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+			if w.opensOn(start.Weekday()) && !at.Before(start) && at.Before(start.Add(w.Duration)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Schedule describes when the window is open, e.g. "Tue 22:00 for 2h0m0s (Europe/Berlin)"
+func (w MaintenanceWindow) Schedule() string {
+	if w.Kind == MaintenanceOnce {
+		return fmt.Sprintf("%s to %s",
+			w.StartsAt.UTC().Format("2006-01-02 15:04"), w.EndsAt.UTC().Format("2006-01-02 15:04 MST"))
+	}
+
+	days := make([]string, len(w.Weekdays))
+	for i, day := range w.Weekdays {
+		days[i] = day.String()[:3]
+	}
+	timezone := w.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	return fmt.Sprintf("%s %s for %s (%s)", strings.Join(days, ", "), w.StartTime, w.Duration, timezone)
+}
+
+func (w MaintenanceWindow) opensOn(weekday time.Weekday) bool {
+	for _, day := range w.Weekdays {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func (w MaintenanceWindow) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(w.Timezone)
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceWindow_Validate(t *testing.T) {
+	start := time.Date(2025, 5, 13, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		window  MaintenanceWindow
+		wantErr bool
+	}{
+		{
+			name:   "one-off",
+			window: MaintenanceWindow{Kind: MaintenanceOnce, StartsAt: start, EndsAt: start.Add(time.Hour)},
+		},
+		{
+			name:    "one-off ending before it starts",
+			window:  MaintenanceWindow{Kind: MaintenanceOnce, StartsAt: start, EndsAt: start.Add(-time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "one-off without end",
+			window:  MaintenanceWindow{Kind: MaintenanceOnce, StartsAt: start},
+			wantErr: true,
+		},
+		{
+			name:   "weekly",
+			window: MaintenanceWindow{Kind: MaintenanceWeekly, Weekdays: []time.Weekday{time.Tuesday}, StartTime: "22:00", Duration: 2 * time.Hour, Timezone: "Europe/Berlin"},
+		},
+		{
+			name:    "weekly without weekdays",
+			window:  MaintenanceWindow{Kind: MaintenanceWeekly, StartTime: "22:00", Duration: time.Hour},
+			wantErr: true,
+		},
+		{
+			name:    "weekly with invalid start time",
+			window:  MaintenanceWindow{Kind: MaintenanceWeekly, Weekdays: []time.Weekday{time.Tuesday}, StartTime: "10pm", Duration: time.Hour},
+			wantErr: true,
+		},
+		{
+			name:    "weekly longer than a week",
+			window:  MaintenanceWindow{Kind: MaintenanceWeekly, Weekdays: []time.Weekday{time.Tuesday}, StartTime: "22:00", Duration: 8 * 24 * time.Hour},
+			wantErr: true,
+		},
+		{
+			name:    "weekly with unknown timezone",
+			window:  MaintenanceWindow{Kind: MaintenanceWeekly, Weekdays: []time.Weekday{time.Tuesday}, StartTime: "22:00", Duration: time.Hour, Timezone: "Mars/Olympus"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown kind",
+			window:  MaintenanceWindow{Kind: "monthly"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindow_IsActive(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+
+	start := time.Date(2025, 5, 13, 22, 0, 0, 0, time.UTC)
+	once := MaintenanceWindow{Kind: MaintenanceOnce, StartsAt: start, EndsAt: start.Add(time.Hour)}
+
+	// Tuesday 23:00 to Wednesday 01:00 Berlin time
+	tuesdayNight := MaintenanceWindow{
+		Kind:      MaintenanceWeekly,
+		Weekdays:  []time.Weekday{time.Tuesday},
+		StartTime: "23:00",
+		Duration:  2 * time.Hour,
+		Timezone:  "Europe/Berlin",
+	}
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		at     time.Time
+		want   bool
+	}{
+		{name: "one-off before start", window: once, at: start.Add(-time.Second), want: false},
+		{name: "one-off at start", window: once, at: start, want: true},
+		{name: "one-off at end", window: once, at: start.Add(time.Hour), want: false},
+		{name: "weekly before opening", window: tuesdayNight, at: time.Date(2025, 5, 13, 22, 59, 0, 0, berlin), want: false},
+		{name: "weekly after opening", window: tuesdayNight, at: time.Date(2025, 5, 13, 23, 30, 0, 0, berlin), want: true},
+		{name: "weekly past midnight", window: tuesdayNight, at: time.Date(2025, 5, 14, 0, 30, 0, 0, berlin), want: true},
+		{name: "weekly in another zone", window: tuesdayNight, at: time.Date(2025, 5, 13, 21, 30, 0, 0, time.UTC), want: true},
+		{name: "weekly after closing", window: tuesdayNight, at: time.Date(2025, 5, 14, 1, 0, 0, 0, berlin), want: false},
+		{name: "weekly on another day", window: tuesdayNight, at: time.Date(2025, 5, 14, 23, 30, 0, 0, berlin), want: false},
+		{name: "weekly in winter time", window: tuesdayNight, at: time.Date(2025, 12, 2, 23, 30, 0, 0, berlin), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.window.IsActive(tt.at))
+		})
+	}
+}
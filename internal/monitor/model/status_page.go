@@ -0,0 +1,34 @@
+package model
+
+// StatusPage is a public page showing the health of a selection of a user's
+// targets, grouped into components
+type StatusPage struct {
+	ID      int
+	UserID  int
+	Title   string
+	Slug    string // the page is served at /status/{slug}
+	Targets []StatusPageTarget
+}
+
+// StatusPageTarget is a target shown on a status page, in page order
+type StatusPageTarget struct {
+	TargetID  int
+	Name      string // shown instead of the target's URL, which may be internal
+	Component string // targets sharing a component are shown together
+}
+
+// Includes reports whether the target is shown on the page
+func (p StatusPage) Includes(targetID int) bool {
+	return p.Target(targetID).TargetID != 0
+}
+
+// Target returns how the target is shown on the page, or the zero value if
+// it is not
+func (p StatusPage) Target(targetID int) StatusPageTarget {
+	for _, target := range p.Targets {
+		if target.TargetID == targetID {
+			return target
+		}
+	}
+	return StatusPageTarget{}
+}
@@ -0,0 +1,7 @@
+package model
+
+// TargetBadge holds the secret token that exposes a target's public badges
+type TargetBadge struct {
+	TargetID int
+	Token    string
+}
@@ -0,0 +1,10 @@
+package model
+
+import monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+
+// TargetCertificate is the latest certificate seen on a target
+type TargetCertificate struct {
+	TargetID int
+	monitor.TLSInfo
+	NotifiedDays int // smallest expiry threshold already notified, zero when none
+}
@@ -0,0 +1,10 @@
+package model
+
+import monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+
+// TargetDNSAnswer is the latest answer a DNS target resolved to
+type TargetDNSAnswer struct {
+	TargetID   int
+	RecordType string // the answer is only comparable to one of the same type
+	monitor.DNSAnswer
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+)
+
+var ErrBadgeNotFound = errors.New("badge not found")
+
+type BadgeRepositoryInterface interface {
+	Save(model.TargetBadge) error
+	GetByTargetID(targetID int) (*model.TargetBadge, error)
+	GetByToken(token string) (*model.TargetBadge, error)
+	Delete(targetID int) error
+}
+
+var _ BadgeRepositoryInterface = (*BadgeRepository)(nil)
+
+// BadgeRepository keeps the badge token of every target that has badges
+type BadgeRepository struct {
+	db *sql.DB
+}
+
+func NewBadgeRepository(db *sql.DB) *BadgeRepository {
+	return &BadgeRepository{db: db}
+}
+
+// Save stores the badge, replacing the target's previous token
+func (r *BadgeRepository) Save(badge model.TargetBadge) error {
+	if badge.TargetID <= 0 {
+		return fmt.Errorf("invalid TargetID: %d", badge.TargetID)
+	}
+	if badge.Token == "" {
+		return fmt.Errorf("token cannot be empty")
+	}
+
+	query := `
+		INSERT INTO target_badge (target_id, token)
+		VALUES (?, ?)
+		ON CONFLICT (target_id) DO UPDATE SET token = excluded.token`
+
+	if _, err := r.db.Exec(query, badge.TargetID, badge.Token); err != nil {
+		return fmt.Errorf("failed to save badge: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTargetID returns the target's badge, or nil if badges are off
+func (r *BadgeRepository) GetByTargetID(targetID int) (*model.TargetBadge, error) {
+	badge := &model.TargetBadge{}
+	err := r.db.QueryRow(`SELECT target_id, token FROM target_badge WHERE target_id = ?`, targetID).
+		Scan(&badge.TargetID, &badge.Token)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get badge: %w", err)
+	}
+
+	return badge, nil
+}
+
+func (r *BadgeRepository) GetByToken(token string) (*model.TargetBadge, error) {
+	badge := &model.TargetBadge{}
+	err := r.db.QueryRow(`SELECT target_id, token FROM target_badge WHERE token = ?`, token).
+		Scan(&badge.TargetID, &badge.Token)
+	if err == sql.ErrNoRows {
+		return nil, ErrBadgeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get badge: %w", err)
+	}
+
+	return badge, nil
+}
+
+// Delete turns the target's badges off
+func (r *BadgeRepository) Delete(targetID int) error {
+	if _, err := r.db.Exec(`DELETE FROM target_badge WHERE target_id = ?`, targetID); err != nil {
+		return fmt.Errorf("failed to delete badge: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadgeRepository(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewBadgeRepository(db)
+
+	t.Run("no badge", func(t *testing.T) {
+		badge, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.Nil(t, badge)
+
+		_, err = repo.GetByToken("missing")
+		assert.ErrorIs(t, err, ErrBadgeNotFound)
+	})
+
+	t.Run("invalid badge", func(t *testing.T) {
+		assert.Error(t, repo.Save(model.TargetBadge{Token: "abc"}))
+		assert.Error(t, repo.Save(model.TargetBadge{TargetID: 1}))
+	})
+
+	t.Run("save replaces the token", func(t *testing.T) {
+		assert.NoError(t, repo.Save(model.TargetBadge{TargetID: 1, Token: "first"}))
+		assert.NoError(t, repo.Save(model.TargetBadge{TargetID: 1, Token: "second"}))
+
+		badge, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.Equal(t, "second", badge.Token)
+
+		_, err = repo.GetByToken("first")
+		assert.ErrorIs(t, err, ErrBadgeNotFound)
+
+		badge, err = repo.GetByToken("second")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, badge.TargetID)
+	})
+
+	t.Run("tokens are unique", func(t *testing.T) {
+		assert.Error(t, repo.Save(model.TargetBadge{TargetID: 2, Token: "second"}))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, repo.Delete(1))
+		badge, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.Nil(t, badge)
+	})
+}
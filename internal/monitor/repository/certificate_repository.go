@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+)
+
+type CertificateRepositoryInterface interface {
+	Save(model.TargetCertificate) error
+	GetByTargetID(targetID int) (*model.TargetCertificate, error)
+}
+
+var _ CertificateRepositoryInterface = (*CertificateRepository)(nil)
+
+// CertificateRepository keeps the latest TLS certificate inspected per target
+type CertificateRepository struct {
+	db *sql.DB
+}
+
+func NewCertificateRepository(db *sql.DB) *CertificateRepository {
+	return &CertificateRepository{db: db}
+}
+
+// Save stores the certificate, replacing any previous one for the target
+func (r *CertificateRepository) Save(cert model.TargetCertificate) error {
+	if cert.TargetID <= 0 {
+		return fmt.Errorf("invalid TargetID: %d", cert.TargetID)
+	}
+
+	dnsNames, err := json.Marshal(cert.DNSNames)
+	if err != nil {
+		return fmt.Errorf("failed to encode DNS names: %w", err)
+	}
+
+	query := `
+		INSERT INTO target_certificate (
+			target_id, subject, issuer, dns_names, not_before, not_after, version,
+			chain_trusted, hostname_valid, verify_error, checked_at, notified_days
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (target_id) DO UPDATE SET
+			subject = excluded.subject,
+			issuer = excluded.issuer,
+			dns_names = excluded.dns_names,
+			not_before = excluded.not_before,
+			not_after = excluded.not_after,
+			version = excluded.version,
+			chain_trusted = excluded.chain_trusted,
+			hostname_valid = excluded.hostname_valid,
+			verify_error = excluded.verify_error,
+			checked_at = excluded.checked_at,
+			notified_days = excluded.notified_days`
+
+	_, err = r.db.Exec(
+		query,
+		cert.TargetID,
+		cert.Subject,
+		cert.Issuer,
+		string(dnsNames),
+		formatTime(cert.NotBefore),
+		formatTime(cert.NotAfter),
+		cert.Version,
+		cert.ChainTrusted,
+		cert.HostnameValid,
+		cert.VerifyError,
+		formatTime(cert.CheckedAt),
+		cert.NotifiedDays,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save certificate: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTargetID returns the latest certificate for a target, or nil if none
+// has been inspected yet
+func (r *CertificateRepository) GetByTargetID(targetID int) (*model.TargetCertificate, error) {
+	query := `
+		SELECT target_id, subject, issuer, dns_names, not_before, not_after, version,
+			chain_trusted, hostname_valid, verify_error, checked_at, notified_days
+		FROM target_certificate
+		WHERE target_id = ?`
+
+	cert := &model.TargetCertificate{}
+	var dnsNames, notBeforeStr, notAfterStr, checkedAtStr string
+
+	err := r.db.QueryRow(query, targetID).Scan(
+		&cert.TargetID,
+		&cert.Subject,
+		&cert.Issuer,
+		&dnsNames,
+		&notBeforeStr,
+		&notAfterStr,
+		&cert.Version,
+		&cert.ChainTrusted,
+		&cert.HostnameValid,
+		&cert.VerifyError,
+		&checkedAtStr,
+		&cert.NotifiedDays,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(dnsNames), &cert.DNSNames); err != nil {
+		return nil, fmt.Errorf("failed to parse DNS names: %w", err)
+	}
+	if cert.NotBefore, err = parseTime(notBeforeStr); err != nil {
+		return nil, fmt.Errorf("failed to parse not_before: %w", err)
+	}
+	if cert.NotAfter, err = parseTime(notAfterStr); err != nil {
+		return nil, fmt.Errorf("failed to parse not_after: %w", err)
+	}
+	if cert.CheckedAt, err = parseTime(checkedAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse checked_at: %w", err)
+	}
+
+	return cert, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificateRepository(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewCertificateRepository(db)
+
+	checkedAt := time.Date(2025, 3, 30, 12, 0, 0, 0, time.UTC)
+	cert := model.TargetCertificate{
+		TargetID: 1,
+		TLSInfo: monitor.TLSInfo{
+			Subject:       "CN=example.com",
+			Issuer:        "CN=Example CA",
+			DNSNames:      []string{"example.com", "www.example.com"},
+			NotBefore:     checkedAt.AddDate(0, -2, 0),
+			NotAfter:      checkedAt.AddDate(0, 1, 0),
+			Version:       tls.VersionTLS13,
+			ChainTrusted:  true,
+			HostnameValid: true,
+			CheckedAt:     checkedAt,
+		},
+	}
+
+	t.Run("none inspected yet", func(t *testing.T) {
+		got, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("save and fetch", func(t *testing.T) {
+		assert.NoError(t, repo.Save(cert))
+
+		got, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.Equal(t, cert.Subject, got.Subject)
+		assert.Equal(t, cert.DNSNames, got.DNSNames)
+		assert.True(t, cert.NotAfter.Equal(got.NotAfter))
+		assert.Equal(t, uint16(tls.VersionTLS13), got.Version)
+		assert.True(t, got.ChainTrusted)
+	})
+
+	t.Run("save replaces the previous certificate", func(t *testing.T) {
+		renewed := cert
+		renewed.NotAfter = checkedAt.AddDate(0, 3, 0)
+		renewed.NotifiedDays = 7
+		renewed.HostnameValid = false
+		renewed.VerifyError = "certificate is valid for example.com, not example.org"
+		assert.NoError(t, repo.Save(renewed))
+
+		got, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.True(t, renewed.NotAfter.Equal(got.NotAfter))
+		assert.Equal(t, 7, got.NotifiedDays)
+		assert.False(t, got.HostnameValid)
+		assert.Equal(t, renewed.VerifyError, got.VerifyError)
+	})
+
+	t.Run("invalid target ID", func(t *testing.T) {
+		assert.Error(t, repo.Save(model.TargetCertificate{}))
+	})
+}
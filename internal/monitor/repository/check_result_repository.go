@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+)
+
+type CheckResultRepositoryInterface interface {
+	Create(monitor.CheckResult) (monitor.CheckResult, error)
+	GetByTargetID(targetID int, from, to time.Time) ([]monitor.CheckResult, error)
+	GetLatestBefore(targetID int, before time.Time) (*monitor.CheckResult, error)
+}
+
+var _ CheckResultRepositoryInterface = (*CheckResultRepository)(nil)
+
+// CheckResultRepository stores the outcome of every probe as a time series
+type CheckResultRepository struct {
+	db *sql.DB
+}
+
+func NewCheckResultRepository(db *sql.DB) *CheckResultRepository {
+	return &CheckResultRepository{db: db}
+}
+
+func (r *CheckResultRepository) Create(result monitor.CheckResult) (monitor.CheckResult, error) {
+	if result.TargetID <= 0 {
+		return monitor.CheckResult{}, fmt.Errorf("invalid TargetID: %d", result.TargetID)
+	}
+	if result.CheckedAt.IsZero() {
+		return monitor.CheckResult{}, fmt.Errorf("checked_at cannot be empty")
+	}
+
+	query := `
+		INSERT INTO check_result (target_id, status, checked_at, latency_ms, status_code, error_class, bytes_read, reason, maintenance)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	res, err := r.db.Exec(
+		query,
+		result.TargetID,
+		result.Status,
+		formatTime(result.CheckedAt),
+		result.Latency.Milliseconds(),
+		result.StatusCode,
+		result.ErrorClass,
+		result.BytesRead,
+		result.Reason,
+		result.Maintenance,
+	)
+	if err != nil {
+		return monitor.CheckResult{}, fmt.Errorf("failed to create check result: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return monitor.CheckResult{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	result.ID = int(id)
+	result.CheckedAt = result.CheckedAt.UTC()
+	return result, nil
+}
+
+const checkResultQuery = `
+		SELECT id, target_id, status, checked_at, latency_ms, status_code, error_class, bytes_read, reason, maintenance
+		FROM check_result`
+
+// GetByTargetID returns the results recorded for a target within [from, to),
+// oldest first
+func (r *CheckResultRepository) GetByTargetID(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+	query := checkResultQuery + `
+		WHERE target_id = ? AND checked_at >= ? AND checked_at < ?
+		ORDER BY checked_at ASC`
+
+	rows, err := r.db.Query(query, targetID, formatTime(from), formatTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []monitor.CheckResult
+	for rows.Next() {
+		result, err := scanCheckResult(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan check result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating check results: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetLatestBefore returns the last result recorded for a target before the
+// given time, or nil if there is none
+func (r *CheckResultRepository) GetLatestBefore(targetID int, before time.Time) (*monitor.CheckResult, error) {
+	query := checkResultQuery + `
+		WHERE target_id = ? AND checked_at < ?
+		ORDER BY checked_at DESC
+		LIMIT 1`
+
+	result, err := scanCheckResult(r.db.QueryRow(query, targetID, formatTime(before)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest check result: %w", err)
+	}
+	return &result, nil
+}
+
+func scanCheckResult(row rowScanner) (monitor.CheckResult, error) {
+	var result monitor.CheckResult
+	var checkedAtStr string
+	var latencyMs int64
+
+	err := row.Scan(
+		&result.ID,
+		&result.TargetID,
+		&result.Status,
+		&checkedAtStr,
+		&latencyMs,
+		&result.StatusCode,
+		&result.ErrorClass,
+		&result.BytesRead,
+		&result.Reason,
+		&result.Maintenance,
+	)
+	if err != nil {
+		return result, err
+	}
+
+	result.CheckedAt, err = parseTime(checkedAtStr)
+	if err != nil {
+		return result, fmt.Errorf("failed to parse checked_at: %w", err)
+	}
+	result.Latency = time.Duration(latencyMs) * time.Millisecond
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckResultRepository_Create(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewCheckResultRepository(db)
+
+	tests := []struct {
+		name    string
+		result  monitor.CheckResult
+		wantErr bool
+	}{
+		{
+			name: "valid result",
+			result: monitor.CheckResult{
+				TargetID:   1,
+				Status:     "up",
+				CheckedAt:  time.Now(),
+				Latency:    120 * time.Millisecond,
+				StatusCode: 200,
+				BytesRead:  512,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid target ID",
+			result: monitor.CheckResult{
+				TargetID:  0,
+				Status:    "up",
+				CheckedAt: time.Now(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing timestamp",
+			result: monitor.CheckResult{
+				TargetID: 1,
+				Status:   "up",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := repo.Create(tt.result)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotZero(t, created.ID)
+			assert.Equal(t, tt.result.TargetID, created.TargetID)
+		})
+	}
+}
+
+func TestCheckResultRepository_GetByTargetID(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewCheckResultRepository(db)
+
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	seed := []monitor.CheckResult{
+		{TargetID: 1, Status: "up", CheckedAt: base, Latency: 80 * time.Millisecond, StatusCode: 200, BytesRead: 10},
+		{TargetID: 1, Status: "down", CheckedAt: base.Add(500 * time.Millisecond), StatusCode: 503, ErrorClass: monitor.ErrorClassHTTP, Reason: "HTTP error: 503"},
+		{TargetID: 1, Status: "error", CheckedAt: base.Add(time.Minute), ErrorClass: monitor.ErrorClassTimeout, Maintenance: true},
+		{TargetID: 1, Status: "up", CheckedAt: base.Add(2 * time.Hour)},
+		{TargetID: 2, Status: "up", CheckedAt: base.Add(time.Minute)},
+	}
+	for _, result := range seed {
+		_, err := repo.Create(result)
+		assert.NoError(t, err)
+	}
+
+	t.Run("results within range are returned oldest first", func(t *testing.T) {
+		results, err := repo.GetByTargetID(1, base, base.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+
+		assert.Equal(t, "up", results[0].Status)
+		assert.Equal(t, "down", results[1].Status)
+		assert.Equal(t, "error", results[2].Status)
+
+		assert.True(t, base.Equal(results[0].CheckedAt))
+		assert.Equal(t, 80*time.Millisecond, results[0].Latency)
+		assert.Equal(t, 200, results[0].StatusCode)
+		assert.Equal(t, int64(10), results[0].BytesRead)
+		assert.Equal(t, "HTTP error: 503", results[1].Reason)
+		assert.Equal(t, monitor.ErrorClassTimeout, results[2].ErrorClass)
+		assert.False(t, results[1].Maintenance)
+		assert.True(t, results[2].Maintenance)
+	})
+
+	t.Run("range end is exclusive", func(t *testing.T) {
+		results, err := repo.GetByTargetID(1, base, base.Add(time.Minute))
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		results, err := repo.GetByTargetID(999, base, base.Add(24*time.Hour))
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("latest result before a time", func(t *testing.T) {
+		result, err := repo.GetLatestBefore(1, base.Add(time.Hour))
+		assert.NoError(t, err)
+		if assert.NotNil(t, result) {
+			assert.Equal(t, "error", result.Status)
+			assert.True(t, base.Add(time.Minute).Equal(result.CheckedAt))
+			assert.Equal(t, monitor.ErrorClassTimeout, result.ErrorClass)
+		}
+
+		result, err = repo.GetLatestBefore(1, base.Add(time.Minute))
+		assert.NoError(t, err)
+		if assert.NotNil(t, result) {
+			assert.Equal(t, "down", result.Status, "the time itself is exclusive")
+		}
+
+		result, err = repo.GetLatestBefore(1, base)
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+}
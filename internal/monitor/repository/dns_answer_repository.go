@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+)
+
+type DNSAnswerRepositoryInterface interface {
+	Save(model.TargetDNSAnswer) error
+	GetByTargetID(targetID int) (*model.TargetDNSAnswer, error)
+}
+
+var _ DNSAnswerRepositoryInterface = (*DNSAnswerRepository)(nil)
+
+// DNSAnswerRepository keeps the latest answer resolved per DNS target
+type DNSAnswerRepository struct {
+	db *sql.DB
+}
+
+func NewDNSAnswerRepository(db *sql.DB) *DNSAnswerRepository {
+	return &DNSAnswerRepository{db: db}
+}
+
+// Save stores the answer, replacing any previous one for the target
+func (r *DNSAnswerRepository) Save(answer model.TargetDNSAnswer) error {
+	if answer.TargetID <= 0 {
+		return fmt.Errorf("invalid TargetID: %d", answer.TargetID)
+	}
+
+	records, err := json.Marshal(answer.Records)
+	if err != nil {
+		return fmt.Errorf("failed to encode records: %w", err)
+	}
+
+	query := `
+		INSERT INTO target_dns_answer (target_id, record_type, records, resolved_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (target_id) DO UPDATE SET
+			record_type = excluded.record_type,
+			records = excluded.records,
+			resolved_at = excluded.resolved_at`
+
+	_, err = r.db.Exec(
+		query,
+		answer.TargetID,
+		answer.RecordType,
+		string(records),
+		formatTime(answer.ResolvedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save DNS answer: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTargetID returns the latest answer for a target, or nil if it has
+// not resolved yet
+func (r *DNSAnswerRepository) GetByTargetID(targetID int) (*model.TargetDNSAnswer, error) {
+	query := `
+		SELECT target_id, record_type, records, resolved_at
+		FROM target_dns_answer
+		WHERE target_id = ?`
+
+	answer := &model.TargetDNSAnswer{}
+	var records, resolvedAtStr string
+
+	err := r.db.QueryRow(query, targetID).Scan(
+		&answer.TargetID,
+		&answer.RecordType,
+		&records,
+		&resolvedAtStr,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DNS answer: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(records), &answer.Records); err != nil {
+		return nil, fmt.Errorf("failed to parse records: %w", err)
+	}
+	if answer.ResolvedAt, err = parseTime(resolvedAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse resolved_at: %w", err)
+	}
+
+	return answer, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSAnswerRepository(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewDNSAnswerRepository(db)
+
+	resolvedAt := time.Date(2025, 4, 13, 9, 0, 0, 0, time.UTC)
+	answer := model.TargetDNSAnswer{
+		TargetID:   1,
+		RecordType: monitor.RecordA,
+		DNSAnswer: monitor.DNSAnswer{
+			Records:    []string{"192.0.2.1", "192.0.2.2"},
+			ResolvedAt: resolvedAt,
+		},
+	}
+
+	t.Run("not resolved yet", func(t *testing.T) {
+		got, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("save and fetch", func(t *testing.T) {
+		assert.NoError(t, repo.Save(answer))
+
+		got, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.Equal(t, monitor.RecordA, got.RecordType)
+		assert.Equal(t, answer.Records, got.Records)
+		assert.True(t, resolvedAt.Equal(got.ResolvedAt))
+	})
+
+	t.Run("save replaces the previous answer", func(t *testing.T) {
+		changed := answer
+		changed.Records = []string{"198.51.100.7"}
+		changed.ResolvedAt = resolvedAt.Add(time.Minute)
+		assert.NoError(t, repo.Save(changed))
+
+		got, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"198.51.100.7"}, got.Records)
+		assert.True(t, changed.ResolvedAt.Equal(got.ResolvedAt))
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		assert.Error(t, repo.Save(model.TargetDNSAnswer{}))
+	})
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+)
+
+var ErrEscalationPolicyNotFound = errors.New("escalation policy not found")
+
+type EscalationRepositoryInterface interface {
+	Create(model.EscalationPolicy) (model.EscalationPolicy, error)
+	GetByID(id int) (*model.EscalationPolicy, error)
+	GetAllByUserID(userID int) ([]model.EscalationPolicy, error)
+	Update(model.EscalationPolicy) error
+	Delete(id int) error
+	StartEscalations(now time.Time) (int64, error)
+	GetDueEscalations(now time.Time) ([]model.Escalation, error)
+	SaveEscalation(model.Escalation) error
+}
+
+var _ EscalationRepositoryInterface = (*EscalationRepository)(nil)
+
+// EscalationRepository stores escalation policies, the targets they are
+// assigned to, and how far each policy has got with the incidents it is
+// escalating
+type EscalationRepository struct {
+	db *sql.DB
+}
+
+func NewEscalationRepository(db *sql.DB) *EscalationRepository {
+	return &EscalationRepository{db: db}
+}
+
+func (r *EscalationRepository) Create(policy model.EscalationPolicy) (model.EscalationPolicy, error) {
+	if policy.UserID <= 0 {
+		return model.EscalationPolicy{}, fmt.Errorf("invalid UserID: %d", policy.UserID)
+	}
+
+	steps, err := json.Marshal(policy.Steps)
+	if err != nil {
+		return model.EscalationPolicy{}, fmt.Errorf("failed to marshal steps: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.EscalationPolicy{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO escalation_policy (user_id, name, steps) VALUES (?, ?, ?)`, policy.UserID, policy.Name, string(steps))
+	if err != nil {
+		return model.EscalationPolicy{}, fmt.Errorf("failed to create escalation policy: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.EscalationPolicy{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	policy.ID = int(id)
+
+	if err := saveEscalationTargets(tx, policy); err != nil {
+		return model.EscalationPolicy{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.EscalationPolicy{}, fmt.Errorf("failed to commit escalation policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (r *EscalationRepository) GetByID(id int) (*model.EscalationPolicy, error) {
+	policy, err := scanEscalationPolicy(r.db.QueryRow(`SELECT id, user_id, name, steps FROM escalation_policy WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrEscalationPolicyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escalation policy: %w", err)
+	}
+
+	if policy.TargetIDs, err = r.getTargetIDs(policy.ID); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// GetAllByUserID returns the user's escalation policies ordered by name
+func (r *EscalationRepository) GetAllByUserID(userID int) ([]model.EscalationPolicy, error) {
+	rows, err := r.db.Query(`SELECT id, user_id, name, steps FROM escalation_policy WHERE user_id = ? ORDER BY name ASC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query escalation policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []model.EscalationPolicy
+	for rows.Next() {
+		policy, err := scanEscalationPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan escalation policy: %w", err)
+		}
+		policies = append(policies, *policy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalation policies: %w", err)
+	}
+
+	for i := range policies {
+		if policies[i].TargetIDs, err = r.getTargetIDs(policies[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return policies, nil
+}
+
+// Update saves the policy's name and steps and replaces the targets it is
+// assigned to. Targets assigned to another policy are moved to this one.
+func (r *EscalationRepository) Update(policy model.EscalationPolicy) error {
+	steps, err := json.Marshal(policy.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal steps: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE escalation_policy SET name = ?, steps = ? WHERE id = ?`, policy.Name, string(steps), policy.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update escalation policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrEscalationPolicyNotFound
+	}
+
+	if _, err := tx.Exec(`DELETE FROM escalation_policy_target WHERE policy_id = ?`, policy.ID); err != nil {
+		return fmt.Errorf("failed to clear escalation policy targets: %w", err)
+	}
+	if err := saveEscalationTargets(tx, policy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit escalation policy: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a policy, unassigns it from its targets and stops the
+// escalations it was running
+func (r *EscalationRepository) Delete(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM escalation_policy WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete escalation policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrEscalationPolicyNotFound
+	}
+
+	if _, err := tx.Exec(`DELETE FROM escalation_policy_target WHERE policy_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete escalation policy targets: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM incident_escalation WHERE policy_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete escalations: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit escalation policy: %w", err)
+	}
+
+	return nil
+}
+
+// StartEscalations starts escalating every open, unacknowledged incident
+// on a target that has a policy and is not being escalated yet, with the
+// first step due at now. It returns how many were started.
+func (r *EscalationRepository) StartEscalations(now time.Time) (int64, error) {
+	query := `
+		INSERT INTO incident_escalation (incident_id, policy_id, next_at)
+		SELECT i.id, pt.policy_id, ?
+		FROM incident i
+		JOIN escalation_policy_target pt ON pt.target_id = i.target_id
+		WHERE i.resolved_at = '' AND i.acknowledged_at = ''
+			AND NOT EXISTS (SELECT 1 FROM incident_escalation e WHERE e.incident_id = i.id)`
+
+	result, err := r.db.Exec(query, formatTime(now))
+	if err != nil {
+		return 0, fmt.Errorf("failed to start escalations: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// GetDueEscalations returns the running escalations whose next step is due,
+// or whose incident has since been acknowledged or resolved
+func (r *EscalationRepository) GetDueEscalations(now time.Time) ([]model.Escalation, error) {
+	query := `
+		SELECT e.incident_id, e.policy_id, e.step, e.next_at, e.notified, e.finished_at
+		FROM incident_escalation e
+		JOIN incident i ON i.id = e.incident_id
+		WHERE e.finished_at = ''
+			AND ((e.next_at != '' AND e.next_at <= ?) OR i.acknowledged_at != '' OR i.resolved_at != '')
+		ORDER BY e.incident_id ASC`
+
+	rows, err := r.db.Query(query, formatTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query escalations: %w", err)
+	}
+	defer rows.Close()
+
+	var escalations []model.Escalation
+	for rows.Next() {
+		var escalation model.Escalation
+		var nextAtStr, notified, finishedAtStr string
+		if err := rows.Scan(&escalation.IncidentID, &escalation.PolicyID, &escalation.Step, &nextAtStr, &notified, &finishedAtStr); err != nil {
+			return nil, fmt.Errorf("failed to scan escalation: %w", err)
+		}
+		if escalation.NextAt, err = parseTime(nextAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse next_at: %w", err)
+		}
+		if escalation.FinishedAt, err = parseTime(finishedAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse finished_at: %w", err)
+		}
+		if err := json.Unmarshal([]byte(notified), &escalation.Notified); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal notified: %w", err)
+		}
+		escalations = append(escalations, escalation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalations: %w", err)
+	}
+
+	return escalations, nil
+}
+
+// SaveEscalation records how far an escalation has got
+func (r *EscalationRepository) SaveEscalation(escalation model.Escalation) error {
+	notified, err := json.Marshal(escalation.Notified)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notified: %w", err)
+	}
+	if escalation.Notified == nil {
+		notified = []byte("[]")
+	}
+
+	_, err = r.db.Exec(`UPDATE incident_escalation SET step = ?, next_at = ?, notified = ?, finished_at = ? WHERE incident_id = ?`,
+		escalation.Step, formatTime(escalation.NextAt), string(notified), formatTime(escalation.FinishedAt), escalation.IncidentID)
+	if err != nil {
+		return fmt.Errorf("failed to save escalation: %w", err)
+	}
+	return nil
+}
+
+func scanEscalationPolicy(row rowScanner) (*model.EscalationPolicy, error) {
+	policy := &model.EscalationPolicy{}
+	var steps string
+	if err := row.Scan(&policy.ID, &policy.UserID, &policy.Name, &steps); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(steps), &policy.Steps); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal steps: %w", err)
+	}
+	return policy, nil
+}
+
+// getTargetIDs returns the targets a policy is assigned to. Targets deleted
+// since the policy was saved are left out.
+func (r *EscalationRepository) getTargetIDs(policyID int) ([]int, error) {
+	query := `
+		SELECT p.target_id
+		FROM escalation_policy_target p
+		JOIN target t ON t.id = p.target_id
+		WHERE p.policy_id = ?
+		ORDER BY p.target_id ASC`
+
+	rows, err := r.db.Query(query, policyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query escalation policy targets: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan escalation policy target: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalation policy targets: %w", err)
+	}
+
+	return ids, nil
+}
+
+func saveEscalationTargets(tx *sql.Tx, policy model.EscalationPolicy) error {
+	query := `INSERT OR REPLACE INTO escalation_policy_target (target_id, policy_id) VALUES (?, ?)`
+
+	for _, targetID := range policy.TargetIDs {
+		if _, err := tx.Exec(query, targetID, policy.ID); err != nil {
+			return fmt.Errorf("failed to save escalation policy target: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscalationRepository(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+
+	targets := NewTargetRepository(db)
+	var targetIDs []int
+	for _, url := range []string{"https://example.com", "https://api.example.com", "https://cdn.example.com"} {
+		created, err := targets.Create(model.UserTarget{
+			UserID: 1,
+			Target: &core.Target{URL: url, Status: "down", Interval: 30 * time.Second},
+		})
+		assert.NoError(t, err)
+		targetIDs = append(targetIDs, created.ID)
+	}
+
+	repo := NewEscalationRepository(db)
+	steps := []model.EscalationStep{
+		{NotifierIDs: []int64{1}, WaitMinutes: 10},
+		{NotifierIDs: []int64{2, 3}},
+	}
+	policy, err := repo.Create(model.EscalationPolicy{
+		UserID:    1,
+		Name:      "On-call",
+		Steps:     steps,
+		TargetIDs: []int{targetIDs[0], targetIDs[1]},
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, policy.ID)
+
+	t.Run("invalid user ID", func(t *testing.T) {
+		_, err := repo.Create(model.EscalationPolicy{Name: "Nobody's"})
+		assert.Error(t, err)
+	})
+
+	t.Run("get by ID", func(t *testing.T) {
+		got, err := repo.GetByID(policy.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "On-call", got.Name)
+		assert.Equal(t, steps, got.Steps)
+		assert.Equal(t, []int{targetIDs[0], targetIDs[1]}, got.TargetIDs)
+
+		_, err = repo.GetByID(999)
+		assert.ErrorIs(t, err, ErrEscalationPolicyNotFound)
+	})
+
+	t.Run("assigning a target moves it between policies", func(t *testing.T) {
+		other, err := repo.Create(model.EscalationPolicy{
+			UserID:    1,
+			Name:      "Backend",
+			Steps:     steps[1:],
+			TargetIDs: []int{targetIDs[1]},
+		})
+		assert.NoError(t, err)
+
+		policies, err := repo.GetAllByUserID(1)
+		assert.NoError(t, err)
+		if assert.Len(t, policies, 2) {
+			assert.Equal(t, "Backend", policies[0].Name)
+			assert.Equal(t, []int{targetIDs[1]}, policies[0].TargetIDs)
+			assert.Equal(t, []int{targetIDs[0]}, policies[1].TargetIDs)
+		}
+
+		assert.NoError(t, repo.Delete(other.ID))
+		assert.ErrorIs(t, repo.Delete(other.ID), ErrEscalationPolicyNotFound)
+	})
+
+	t.Run("update replaces targets", func(t *testing.T) {
+		policy.Name = "Primary on-call"
+		policy.TargetIDs = []int{targetIDs[2]}
+		assert.NoError(t, repo.Update(policy))
+
+		got, err := repo.GetByID(policy.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Primary on-call", got.Name)
+		assert.Equal(t, []int{targetIDs[2]}, got.TargetIDs)
+
+		assert.ErrorIs(t, repo.Update(model.EscalationPolicy{ID: 999}), ErrEscalationPolicyNotFound)
+	})
+
+	t.Run("escalations", func(t *testing.T) {
+		incidents := NewIncidentRepository(db)
+		now := time.Date(2025, 6, 22, 9, 0, 0, 0, time.UTC)
+
+		escalated, err := incidents.Create(model.Incident{TargetID: targetIDs[2], Cause: "timeout", StartedAt: now})
+		assert.NoError(t, err)
+		// no policy covers this target, so nothing escalates it
+		_, err = incidents.Create(model.Incident{TargetID: targetIDs[0], Cause: "timeout", StartedAt: now})
+		assert.NoError(t, err)
+
+		started, err := repo.StartEscalations(now)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), started)
+
+		started, err = repo.StartEscalations(now)
+		assert.NoError(t, err)
+		assert.Zero(t, started, "running escalations are not restarted")
+
+		due, err := repo.GetDueEscalations(now)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.Escalation{{
+			IncidentID: escalated.ID,
+			PolicyID:   policy.ID,
+			NextAt:     now,
+			Notified:   []int64{},
+		}}, due)
+
+		escalation := due[0]
+		escalation.Step = 1
+		escalation.NextAt = now.Add(10 * time.Minute)
+		escalation.Notified = []int64{1}
+		assert.NoError(t, repo.SaveEscalation(escalation))
+
+		due, err = repo.GetDueEscalations(now.Add(time.Minute))
+		assert.NoError(t, err)
+		assert.Empty(t, due, "next step is not due yet")
+
+		due, err = repo.GetDueEscalations(now.Add(10 * time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, []model.Escalation{escalation}, due)
+
+		assert.NoError(t, incidents.Acknowledge(escalated.ID, 1, now.Add(time.Minute)))
+		due, err = repo.GetDueEscalations(now.Add(time.Minute))
+		assert.NoError(t, err)
+		assert.Len(t, due, 1, "acknowledged incidents are due straight away")
+
+		escalation.FinishedAt = now.Add(time.Minute)
+		assert.NoError(t, repo.SaveEscalation(escalation))
+		due, err = repo.GetDueEscalations(now.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Empty(t, due)
+	})
+}
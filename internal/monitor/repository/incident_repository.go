@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+)
+
+var ErrIncidentNotFound = errors.New("incident not found")
+
+type IncidentRepositoryInterface interface {
+	Create(model.Incident) (model.Incident, error)
+	GetByID(id int) (*model.Incident, error)
+	GetOpenByTargetID(targetID int) (*model.Incident, error)
+	GetAllByUserID(userID int) ([]model.Incident, error)
+	Acknowledge(id, userID int, at time.Time) error
+	Resolve(id int, at time.Time) error
+	AddEvent(model.IncidentEvent) (model.IncidentEvent, error)
+	GetEvents(incidentID int) ([]model.IncidentEvent, error)
+}
+
+var _ IncidentRepositoryInterface = (*IncidentRepository)(nil)
+
+// IncidentRepository stores incidents and their timelines
+type IncidentRepository struct {
+	db *sql.DB
+}
+
+func NewIncidentRepository(db *sql.DB) *IncidentRepository {
+	return &IncidentRepository{db: db}
+}
+
+// incidentQuery selects the columns scanIncident expects, joined with the
+// target and the acknowledging user
+const incidentQuery = `
+	SELECT i.id, i.target_id, t.url, t.user_id, i.cause, i.started_at,
+		i.acknowledged_at, COALESCE(u.name, ''), i.resolved_at
+	FROM incident i
+	JOIN target t ON t.id = i.target_id
+	LEFT JOIN user u ON u.id = i.acknowledged_by`
+
+func scanIncident(row rowScanner) (*model.Incident, error) {
+	incident := &model.Incident{}
+	var startedAtStr, acknowledgedAtStr, resolvedAtStr string
+
+	err := row.Scan(
+		&incident.ID,
+		&incident.TargetID,
+		&incident.TargetURL,
+		&incident.UserID,
+		&incident.Cause,
+		&startedAtStr,
+		&acknowledgedAtStr,
+		&incident.AcknowledgedBy,
+		&resolvedAtStr,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if incident.StartedAt, err = parseTime(startedAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse started_at: %w", err)
+	}
+	if incident.AcknowledgedAt, err = parseTime(acknowledgedAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse acknowledged_at: %w", err)
+	}
+	if incident.ResolvedAt, err = parseTime(resolvedAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse resolved_at: %w", err)
+	}
+
+	return incident, nil
+}
+
+func (r *IncidentRepository) Create(incident model.Incident) (model.Incident, error) {
+	if incident.TargetID <= 0 {
+		return model.Incident{}, fmt.Errorf("invalid TargetID: %d", incident.TargetID)
+	}
+	if incident.StartedAt.IsZero() {
+		return model.Incident{}, fmt.Errorf("started_at cannot be empty")
+	}
+
+	query := `
+		INSERT INTO incident (target_id, cause, started_at)
+		VALUES (?, ?, ?)`
+
+	result, err := r.db.Exec(query, incident.TargetID, incident.Cause, formatTime(incident.StartedAt))
+	if err != nil {
+		return model.Incident{}, fmt.Errorf("failed to create incident: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.Incident{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	incident.ID = int(id)
+	incident.StartedAt = incident.StartedAt.UTC()
+	return incident, nil
+}
+
+func (r *IncidentRepository) GetByID(id int) (*model.Incident, error) {
+	incident, err := scanIncident(r.db.QueryRow(incidentQuery+` WHERE i.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrIncidentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incident: %w", err)
+	}
+
+	return incident, nil
+}
+
+// GetOpenByTargetID returns the target's unresolved incident, or nil if the
+// target is not failing
+func (r *IncidentRepository) GetOpenByTargetID(targetID int) (*model.Incident, error) {
+	query := incidentQuery + `
+		WHERE i.target_id = ? AND i.resolved_at = ''
+		ORDER BY i.started_at DESC
+		LIMIT 1`
+
+	incident, err := scanIncident(r.db.QueryRow(query, targetID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open incident: %w", err)
+	}
+
+	return incident, nil
+}
+
+// GetAllByUserID returns the incidents on the user's targets, newest first
+func (r *IncidentRepository) GetAllByUserID(userID int) ([]model.Incident, error) {
+	query := incidentQuery + `
+		WHERE t.user_id = ?
+		ORDER BY i.started_at DESC`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []model.Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident: %w", err)
+		}
+		incidents = append(incidents, *incident)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incidents: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// Acknowledge records who took the incident on. Only the first
+// acknowledgement counts.
+func (r *IncidentRepository) Acknowledge(id, userID int, at time.Time) error {
+	query := `
+		UPDATE incident
+		SET acknowledged_at = ?, acknowledged_by = ?
+		WHERE id = ? AND acknowledged_at = ''`
+
+	_, err := r.db.Exec(query, formatTime(at), userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge incident: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IncidentRepository) Resolve(id int, at time.Time) error {
+	query := `
+		UPDATE incident
+		SET resolved_at = ?
+		WHERE id = ?`
+
+	result, err := r.db.Exec(query, formatTime(at), id)
+	if err != nil {
+		return fmt.Errorf("failed to resolve incident: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrIncidentNotFound
+	}
+
+	return nil
+}
+
+func (r *IncidentRepository) AddEvent(event model.IncidentEvent) (model.IncidentEvent, error) {
+	if event.IncidentID <= 0 {
+		return model.IncidentEvent{}, fmt.Errorf("invalid IncidentID: %d", event.IncidentID)
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO incident_event (incident_id, kind, message, user_id, created_at)
+		VALUES (?, ?, ?, ?, ?)`
+
+	userID := sql.NullInt64{Int64: int64(event.UserID), Valid: event.UserID > 0}
+	result, err := r.db.Exec(query, event.IncidentID, event.Kind, event.Message, userID, formatTime(event.CreatedAt))
+	if err != nil {
+		return model.IncidentEvent{}, fmt.Errorf("failed to add incident event: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.IncidentEvent{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	event.ID = int(id)
+	event.CreatedAt = event.CreatedAt.UTC()
+	return event, nil
+}
+
+// GetEvents returns an incident's timeline, oldest first
+func (r *IncidentRepository) GetEvents(incidentID int) ([]model.IncidentEvent, error) {
+	query := `
+		SELECT e.id, e.incident_id, e.kind, e.message, COALESCE(e.user_id, 0), COALESCE(u.name, ''), e.created_at
+		FROM incident_event e
+		LEFT JOIN user u ON u.id = e.user_id
+		WHERE e.incident_id = ?
+		ORDER BY e.created_at ASC, e.id ASC`
+
+	rows, err := r.db.Query(query, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []model.IncidentEvent
+	for rows.Next() {
+		var event model.IncidentEvent
+		var createdAtStr string
+
+		err := rows.Scan(
+			&event.ID,
+			&event.IncidentID,
+			&event.Kind,
+			&event.Message,
+			&event.UserID,
+			&event.UserName,
+			&createdAtStr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident event: %w", err)
+		}
+
+		if event.CreatedAt, err = parseTime(createdAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incident events: %w", err)
+	}
+
+	return events, nil
+}
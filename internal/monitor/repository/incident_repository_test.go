@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncidentRepository(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+
+	_, err := db.Exec(`INSERT INTO user (id, name, email, password) VALUES (1, 'Alice', 'alice@example.com', 'x')`)
+	assert.NoError(t, err)
+
+	target, err := NewTargetRepository(db).Create(model.UserTarget{
+		UserID: 1,
+		Target: &core.Target{URL: "https://example.org", Status: "down", Interval: 30 * time.Second},
+	})
+	assert.NoError(t, err)
+
+	repo := NewIncidentRepository(db)
+	startedAt := time.Date(2025, 5, 4, 10, 0, 0, 0, time.UTC)
+
+	t.Run("no open incident", func(t *testing.T) {
+		got, err := repo.GetOpenByTargetID(target.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	created, err := repo.Create(model.Incident{TargetID: target.ID, Cause: "HTTP error: 503", StartedAt: startedAt})
+	assert.NoError(t, err)
+	assert.NotZero(t, created.ID)
+
+	t.Run("get open incident", func(t *testing.T) {
+		got, err := repo.GetOpenByTargetID(target.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, created.ID, got.ID)
+		assert.Equal(t, "https://example.org", got.TargetURL)
+		assert.Equal(t, 1, got.UserID)
+		assert.Equal(t, "HTTP error: 503", got.Cause)
+		assert.True(t, startedAt.Equal(got.StartedAt))
+		assert.False(t, got.IsAcknowledged())
+		assert.False(t, got.IsResolved())
+	})
+
+	t.Run("acknowledge", func(t *testing.T) {
+		assert.NoError(t, repo.Acknowledge(created.ID, 1, startedAt.Add(time.Minute)))
+		// A later acknowledgement does not replace the first
+		assert.NoError(t, repo.Acknowledge(created.ID, 1, startedAt.Add(time.Hour)))
+
+		got, err := repo.GetByID(created.ID)
+		assert.NoError(t, err)
+		assert.True(t, startedAt.Add(time.Minute).Equal(got.AcknowledgedAt))
+		assert.Equal(t, "Alice", got.AcknowledgedBy)
+	})
+
+	t.Run("timeline", func(t *testing.T) {
+		_, err := repo.AddEvent(model.IncidentEvent{IncidentID: created.ID, Kind: model.IncidentOpened, Message: "HTTP error: 503", CreatedAt: startedAt})
+		assert.NoError(t, err)
+		_, err = repo.AddEvent(model.IncidentEvent{IncidentID: created.ID, Kind: model.IncidentUpdate, Message: "Rolling back", UserID: 1, CreatedAt: startedAt.Add(2 * time.Minute)})
+		assert.NoError(t, err)
+
+		events, err := repo.GetEvents(created.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, model.IncidentOpened, events[0].Kind)
+			assert.Equal(t, 0, events[0].UserID)
+			assert.Equal(t, "Rolling back", events[1].Message)
+			assert.Equal(t, "Alice", events[1].UserName)
+		}
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		assert.NoError(t, repo.Resolve(created.ID, startedAt.Add(10*time.Minute)))
+
+		open, err := repo.GetOpenByTargetID(target.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, open)
+
+		incidents, err := repo.GetAllByUserID(1)
+		assert.NoError(t, err)
+		if assert.Len(t, incidents, 1) {
+			assert.Equal(t, 10*time.Minute, incidents[0].Duration(time.Now()))
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.GetByID(999)
+		assert.ErrorIs(t, err, ErrIncidentNotFound)
+		assert.ErrorIs(t, repo.Resolve(999, time.Now()), ErrIncidentNotFound)
+	})
+
+	t.Run("other users see nothing", func(t *testing.T) {
+		incidents, err := repo.GetAllByUserID(2)
+		assert.NoError(t, err)
+		assert.Empty(t, incidents)
+	})
+}
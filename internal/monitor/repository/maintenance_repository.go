@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+)
+
+var ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+
+type MaintenanceRepositoryInterface interface {
+	Create(model.MaintenanceWindow) (model.MaintenanceWindow, error)
+	GetByTargetID(targetID int) ([]model.MaintenanceWindow, error)
+	Delete(targetID, id int) error
+}
+
+var _ MaintenanceRepositoryInterface = (*MaintenanceRepository)(nil)
+
+// MaintenanceRepository stores the maintenance windows planned for targets
+type MaintenanceRepository struct {
+	db *sql.DB
+}
+
+func NewMaintenanceRepository(db *sql.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+func (r *MaintenanceRepository) Create(window model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+	if window.TargetID <= 0 {
+		return model.MaintenanceWindow{}, fmt.Errorf("invalid TargetID: %d", window.TargetID)
+	}
+
+	weekdays, err := json.Marshal(window.Weekdays)
+	if err != nil {
+		return model.MaintenanceWindow{}, fmt.Errorf("failed to marshal weekdays: %w", err)
+	}
+
+	query := `
+		INSERT INTO maintenance_window (target_id, description, kind, starts_at, ends_at, weekdays, start_time, duration_seconds, timezone)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.Exec(
+		query,
+		window.TargetID,
+		window.Description,
+		window.Kind,
+		formatTime(window.StartsAt),
+		formatTime(window.EndsAt),
+		string(weekdays),
+		window.StartTime,
+		int64(window.Duration.Seconds()),
+		window.Timezone,
+	)
+	if err != nil {
+		return model.MaintenanceWindow{}, fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.MaintenanceWindow{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	window.ID = int(id)
+	return window, nil
+}
+
+// GetByTargetID returns the target's maintenance windows in the order they
+// were planned
+func (r *MaintenanceRepository) GetByTargetID(targetID int) ([]model.MaintenanceWindow, error) {
+	query := `
+		SELECT id, target_id, description, kind, starts_at, ends_at, weekdays, start_time, duration_seconds, timezone
+		FROM maintenance_window
+		WHERE target_id = ?
+		ORDER BY id ASC`
+
+	rows, err := r.db.Query(query, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []model.MaintenanceWindow
+	for rows.Next() {
+		var window model.MaintenanceWindow
+		var startsAtStr, endsAtStr, weekdays string
+		var durationSeconds int64
+
+		err := rows.Scan(
+			&window.ID,
+			&window.TargetID,
+			&window.Description,
+			&window.Kind,
+			&startsAtStr,
+			&endsAtStr,
+			&weekdays,
+			&window.StartTime,
+			&durationSeconds,
+			&window.Timezone,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+
+		if window.StartsAt, err = parseTime(startsAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse starts_at: %w", err)
+		}
+		if window.EndsAt, err = parseTime(endsAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse ends_at: %w", err)
+		}
+		if err := json.Unmarshal([]byte(weekdays), &window.Weekdays); err != nil {
+			return nil, fmt.Errorf("failed to parse weekdays: %w", err)
+		}
+		window.Duration = time.Duration(durationSeconds) * time.Second
+
+		windows = append(windows, window)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating maintenance windows: %w", err)
+	}
+
+	return windows, nil
+}
+
+// Delete removes a window, provided it belongs to the target
+func (r *MaintenanceRepository) Delete(targetID, id int) error {
+	result, err := r.db.Exec(`DELETE FROM maintenance_window WHERE id = ? AND target_id = ?`, id, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrMaintenanceWindowNotFound
+	}
+
+	return nil
+}
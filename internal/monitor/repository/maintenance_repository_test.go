@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceRepository(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewMaintenanceRepository(db)
+
+	startsAt := time.Date(2025, 5, 13, 22, 0, 0, 0, time.UTC)
+	once, err := repo.Create(model.MaintenanceWindow{
+		TargetID:    1,
+		Description: "Database upgrade",
+		Kind:        model.MaintenanceOnce,
+		StartsAt:    startsAt,
+		EndsAt:      startsAt.Add(2 * time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, once.ID)
+
+	weekly, err := repo.Create(model.MaintenanceWindow{
+		TargetID:  1,
+		Kind:      model.MaintenanceWeekly,
+		Weekdays:  []time.Weekday{time.Tuesday, time.Thursday},
+		StartTime: "23:00",
+		Duration:  90 * time.Minute,
+		Timezone:  "Europe/Berlin",
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.Create(model.MaintenanceWindow{TargetID: 2, Kind: model.MaintenanceOnce, StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour)})
+	assert.NoError(t, err)
+
+	t.Run("invalid target ID", func(t *testing.T) {
+		_, err := repo.Create(model.MaintenanceWindow{Kind: model.MaintenanceOnce})
+		assert.Error(t, err)
+	})
+
+	t.Run("get by target", func(t *testing.T) {
+		windows, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		if !assert.Len(t, windows, 2) {
+			return
+		}
+
+		assert.Equal(t, "Database upgrade", windows[0].Description)
+		assert.True(t, startsAt.Equal(windows[0].StartsAt))
+		assert.True(t, startsAt.Add(2*time.Hour).Equal(windows[0].EndsAt))
+		assert.Empty(t, windows[0].Weekdays)
+
+		assert.Equal(t, weekly.ID, windows[1].ID)
+		assert.Equal(t, []time.Weekday{time.Tuesday, time.Thursday}, windows[1].Weekdays)
+		assert.Equal(t, "23:00", windows[1].StartTime)
+		assert.Equal(t, 90*time.Minute, windows[1].Duration)
+		assert.Equal(t, "Europe/Berlin", windows[1].Timezone)
+		assert.True(t, windows[1].StartsAt.IsZero())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Delete(2, once.ID), ErrMaintenanceWindowNotFound, "windows of other targets are left alone")
+		assert.NoError(t, repo.Delete(1, once.ID))
+		assert.ErrorIs(t, repo.Delete(1, once.ID), ErrMaintenanceWindowNotFound)
+
+		windows, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.Len(t, windows, 1)
+	})
+}
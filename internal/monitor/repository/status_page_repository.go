@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+)
+
+var ErrStatusPageNotFound = errors.New("status page not found")
+
+type StatusPageRepositoryInterface interface {
+	Create(model.StatusPage) (model.StatusPage, error)
+	GetByID(id int) (*model.StatusPage, error)
+	GetBySlug(slug string) (*model.StatusPage, error)
+	GetAllByUserID(userID int) ([]model.StatusPage, error)
+	Update(model.StatusPage) error
+	Delete(id int) error
+}
+
+var _ StatusPageRepositoryInterface = (*StatusPageRepository)(nil)
+
+// StatusPageRepository stores status pages along with the targets they show
+type StatusPageRepository struct {
+	db *sql.DB
+}
+
+func NewStatusPageRepository(db *sql.DB) *StatusPageRepository {
+	return &StatusPageRepository{db: db}
+}
+
+func (r *StatusPageRepository) Create(page model.StatusPage) (model.StatusPage, error) {
+	if page.UserID <= 0 {
+		return model.StatusPage{}, fmt.Errorf("invalid UserID: %d", page.UserID)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.StatusPage{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO status_page (user_id, title, slug) VALUES (?, ?, ?)`, page.UserID, page.Title, page.Slug)
+	if err != nil {
+		return model.StatusPage{}, fmt.Errorf("failed to create status page: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.StatusPage{}, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	page.ID = int(id)
+
+	if err := saveStatusPageTargets(tx, page); err != nil {
+		return model.StatusPage{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.StatusPage{}, fmt.Errorf("failed to commit status page: %w", err)
+	}
+
+	return page, nil
+}
+
+func (r *StatusPageRepository) GetByID(id int) (*model.StatusPage, error) {
+	return r.get(`SELECT id, user_id, title, slug FROM status_page WHERE id = ?`, id)
+}
+
+func (r *StatusPageRepository) GetBySlug(slug string) (*model.StatusPage, error) {
+	return r.get(`SELECT id, user_id, title, slug FROM status_page WHERE slug = ?`, slug)
+}
+
+func (r *StatusPageRepository) get(query string, arg any) (*model.StatusPage, error) {
+	page := &model.StatusPage{}
+	err := r.db.QueryRow(query, arg).Scan(&page.ID, &page.UserID, &page.Title, &page.Slug)
+	if err == sql.ErrNoRows {
+		return nil, ErrStatusPageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status page: %w", err)
+	}
+
+	if page.Targets, err = r.getTargets(page.ID); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// GetAllByUserID returns the user's status pages ordered by title
+func (r *StatusPageRepository) GetAllByUserID(userID int) ([]model.StatusPage, error) {
+	rows, err := r.db.Query(`SELECT id, user_id, title, slug FROM status_page WHERE user_id = ? ORDER BY title ASC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status pages: %w", err)
+	}
+	defer rows.Close()
+
+	var pages []model.StatusPage
+	for rows.Next() {
+		var page model.StatusPage
+		if err := rows.Scan(&page.ID, &page.UserID, &page.Title, &page.Slug); err != nil {
+			return nil, fmt.Errorf("failed to scan status page: %w", err)
+		}
+		pages = append(pages, page)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status pages: %w", err)
+	}
+
+	for i := range pages {
+		if pages[i].Targets, err = r.getTargets(pages[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return pages, nil
+}
+
+// Update saves the page's title and slug and replaces the targets it shows
+func (r *StatusPageRepository) Update(page model.StatusPage) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE status_page SET title = ?, slug = ? WHERE id = ?`, page.Title, page.Slug, page.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update status page: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrStatusPageNotFound
+	}
+
+	if _, err := tx.Exec(`DELETE FROM status_page_target WHERE status_page_id = ?`, page.ID); err != nil {
+		return fmt.Errorf("failed to clear status page targets: %w", err)
+	}
+	if err := saveStatusPageTargets(tx, page); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit status page: %w", err)
+	}
+
+	return nil
+}
+
+func (r *StatusPageRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM status_page WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete status page: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrStatusPageNotFound
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM status_page_target WHERE status_page_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete status page targets: %w", err)
+	}
+
+	return nil
+}
+
+// getTargets returns the targets shown on a page, in page order. Targets
+// deleted since the page was saved are left out.
+func (r *StatusPageRepository) getTargets(pageID int) ([]model.StatusPageTarget, error) {
+	query := `
+		SELECT s.target_id, s.name, s.component
+		FROM status_page_target s
+		JOIN target t ON t.id = s.target_id
+		WHERE s.status_page_id = ?
+		ORDER BY s.position ASC`
+
+	rows, err := r.db.Query(query, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status page targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []model.StatusPageTarget
+	for rows.Next() {
+		var target model.StatusPageTarget
+		if err := rows.Scan(&target.TargetID, &target.Name, &target.Component); err != nil {
+			return nil, fmt.Errorf("failed to scan status page target: %w", err)
+		}
+		targets = append(targets, target)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status page targets: %w", err)
+	}
+
+	return targets, nil
+}
+
+func saveStatusPageTargets(tx *sql.Tx, page model.StatusPage) error {
+	query := `
+		INSERT INTO status_page_target (status_page_id, target_id, name, component, position)
+		VALUES (?, ?, ?, ?, ?)`
+
+	for position, target := range page.Targets {
+		if _, err := tx.Exec(query, page.ID, target.TargetID, target.Name, target.Component, position); err != nil {
+			return fmt.Errorf("failed to save status page target: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	core "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusPageRepository(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+
+	targets := NewTargetRepository(db)
+	var targetIDs []int
+	for _, url := range []string{"https://example.com", "https://api.example.com", "https://cdn.example.com"} {
+		created, err := targets.Create(model.UserTarget{
+			UserID: 1,
+			Target: &core.Target{URL: url, Status: "up", Interval: 30 * time.Second},
+		})
+		assert.NoError(t, err)
+		targetIDs = append(targetIDs, created.ID)
+	}
+
+	repo := NewStatusPageRepository(db)
+	page, err := repo.Create(model.StatusPage{
+		UserID: 1,
+		Title:  "Example status",
+		Slug:   "example",
+		Targets: []model.StatusPageTarget{
+			{TargetID: targetIDs[1], Name: "API", Component: "Backend"},
+			{TargetID: targetIDs[0], Name: "Website", Component: "Frontend"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, page.ID)
+
+	t.Run("invalid user ID", func(t *testing.T) {
+		_, err := repo.Create(model.StatusPage{Title: "Nobody's", Slug: "nobody"})
+		assert.Error(t, err)
+	})
+
+	t.Run("slugs are unique", func(t *testing.T) {
+		_, err := repo.Create(model.StatusPage{UserID: 2, Title: "Copy", Slug: "example"})
+		assert.Error(t, err)
+	})
+
+	t.Run("get by slug keeps target order", func(t *testing.T) {
+		got, err := repo.GetBySlug("example")
+		assert.NoError(t, err)
+		assert.Equal(t, page.ID, got.ID)
+		assert.Equal(t, "Example status", got.Title)
+		assert.Equal(t, []model.StatusPageTarget{
+			{TargetID: targetIDs[1], Name: "API", Component: "Backend"},
+			{TargetID: targetIDs[0], Name: "Website", Component: "Frontend"},
+		}, got.Targets)
+	})
+
+	t.Run("update replaces targets", func(t *testing.T) {
+		page.Title = "Example Inc."
+		page.Targets = []model.StatusPageTarget{{TargetID: targetIDs[2], Name: "CDN"}}
+		assert.NoError(t, repo.Update(page))
+
+		got, err := repo.GetByID(page.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Example Inc.", got.Title)
+		assert.Equal(t, page.Targets, got.Targets)
+	})
+
+	t.Run("deleted targets are left out", func(t *testing.T) {
+		assert.NoError(t, targets.Delete(targetIDs[2]))
+
+		pages, err := repo.GetAllByUserID(1)
+		assert.NoError(t, err)
+		if assert.Len(t, pages, 1) {
+			assert.Empty(t, pages[0].Targets)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, repo.Delete(page.ID))
+		_, err := repo.GetBySlug("example")
+		assert.ErrorIs(t, err, ErrStatusPageNotFound)
+		assert.ErrorIs(t, repo.Delete(page.ID), ErrStatusPageNotFound)
+		assert.ErrorIs(t, repo.Update(page), ErrStatusPageNotFound)
+	})
+}
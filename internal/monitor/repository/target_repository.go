@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -20,6 +21,7 @@ type TargetRepositoryInterface interface {
 	GetByID(int) (*monitor.Target, error)
 	GetAll() ([]*monitor.Target, error)
 	GetAllByUserID(userID int) ([]*monitor.Target, error)
+	GetByHeartbeatToken(token string) (*monitor.Target, error)
 	Update(*monitor.Target) (*monitor.Target, error)
 	Delete(int) error
 	UpdateStatus(*monitor.Target, string) error
@@ -35,26 +37,142 @@ func NewTargetRepository(db *sql.DB) *TargetRepository {
 	return &TargetRepository{db: db}
 }
 
-func (r *TargetRepository) formatTime(t time.Time) string {
-	if t.IsZero() {
-		return ""
+// targetColumns lists the columns scanTarget expects, in order
+const targetColumns = `id, kind, url, status, enabled, interval, changed_at, request, assertions, latency_threshold_ms, tls, tcp, dns, heartbeat, heartbeat_token, confirm_after, recover_after`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTarget(row rowScanner) (*monitor.Target, error) {
+	target := &monitor.Target{}
+	var intervalSeconds float64
+	var statusChangedAtStr string
+	var request string
+	var assertions string
+	var latencyThresholdMs int64
+	var tlsSettings string
+	var tcp string
+	var dns string
+	var heartbeat string
+	var heartbeatToken sql.NullString
+
+	err := row.Scan(
+		&target.ID,
+		&target.Kind,
+		&target.URL,
+		&target.Status,
+		&target.Enabled,
+		&intervalSeconds,
+		&statusChangedAtStr,
+		&request,
+		&assertions,
+		&latencyThresholdMs,
+		&tlsSettings,
+		&tcp,
+		&dns,
+		&heartbeat,
+		&heartbeatToken,
+		&target.ConfirmAfter,
+		&target.RecoverAfter,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	target.Interval = time.Duration(intervalSeconds) * time.Second
+	target.LatencyThreshold = time.Duration(latencyThresholdMs) * time.Millisecond
+	target.StatusChangedAt, err = parseTime(statusChangedAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse changed_at: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(request), &target.Request); err != nil {
+		return nil, fmt.Errorf("failed to parse request: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(assertions), &target.Assertions); err != nil {
+		return nil, fmt.Errorf("failed to parse assertions: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(tlsSettings), &target.TLS); err != nil {
+		return nil, fmt.Errorf("failed to parse tls: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(tcp), &target.TCP); err != nil {
+		return nil, fmt.Errorf("failed to parse tcp: %w", err)
 	}
-	return t.UTC().Format(time.RFC3339Nano)
+
+	if err := json.Unmarshal([]byte(dns), &target.DNS); err != nil {
+		return nil, fmt.Errorf("failed to parse dns: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(heartbeat), &target.Heartbeat); err != nil {
+		return nil, fmt.Errorf("failed to parse heartbeat: %w", err)
+	}
+	target.Heartbeat.Token = heartbeatToken.String
+
+	return target, nil
+}
+
+// checkDefinition holds the JSON columns describing how a target is checked
+type checkDefinition struct {
+	request    string
+	assertions string
+	tls        string
+	tcp        string
+	dns        string
+	heartbeat  string
+	// heartbeatToken is NULL unless the target accepts pings, so the unique
+	// index only applies to heartbeat targets
+	heartbeatToken sql.NullString
 }
 
-func (r *TargetRepository) parseTime(s string) (time.Time, error) {
-	if s == "" {
-		return time.Time{}, nil
+func encodeCheckDefinition(target *monitor.Target) (checkDefinition, error) {
+	var def checkDefinition
+
+	requestJSON, err := json.Marshal(target.Request)
+	if err != nil {
+		return def, fmt.Errorf("failed to encode request: %w", err)
 	}
+	def.request = string(requestJSON)
 
-	// Try parsing RFC3339Nano format first
-	t, err := time.Parse(time.RFC3339Nano, s)
-	if err == nil {
-		return t, nil
+	if target.Assertions == nil {
+		target.Assertions = monitor.Assertions{}
 	}
+	assertionsJSON, err := json.Marshal(target.Assertions)
+	if err != nil {
+		return def, fmt.Errorf("failed to encode assertions: %w", err)
+	}
+	def.assertions = string(assertionsJSON)
 
-	// If that fails, try parsing the alternative format
-	return time.Parse("2006-01-02 15:04:05.999999999-07:00", s)
+	tlsJSON, err := json.Marshal(target.TLS)
+	if err != nil {
+		return def, fmt.Errorf("failed to encode tls: %w", err)
+	}
+	def.tls = string(tlsJSON)
+
+	tcpJSON, err := json.Marshal(target.TCP)
+	if err != nil {
+		return def, fmt.Errorf("failed to encode tcp: %w", err)
+	}
+	def.tcp = string(tcpJSON)
+
+	dnsJSON, err := json.Marshal(target.DNS)
+	if err != nil {
+		return def, fmt.Errorf("failed to encode dns: %w", err)
+	}
+	def.dns = string(dnsJSON)
+
+	heartbeatJSON, err := json.Marshal(target.Heartbeat)
+	if err != nil {
+		return def, fmt.Errorf("failed to encode heartbeat: %w", err)
+	}
+	def.heartbeat = string(heartbeatJSON)
+	def.heartbeatToken = sql.NullString{String: target.Heartbeat.Token, Valid: target.Heartbeat.Token != ""}
+
+	return def, nil
 }
 
 func (r *TargetRepository) Create(userTarget model.UserTarget) (model.UserTarget, error) {
@@ -68,19 +186,39 @@ func (r *TargetRepository) Create(userTarget model.UserTarget) (model.UserTarget
 	if userTarget.UserID <= 0 {
 		return model.UserTarget{}, fmt.Errorf("invalid UserID: %d", userTarget.UserID)
 	}
+	if err := userTarget.Validate(); err != nil {
+		return model.UserTarget{}, err
+	}
+	userTarget.Kind = userTarget.GetKind()
+
+	def, err := encodeCheckDefinition(userTarget.Target)
+	if err != nil {
+		return model.UserTarget{}, err
+	}
 
 	query := `
-		INSERT INTO target (url, user_id, status, enabled, interval, changed_at)
-		VALUES (?, ?, ?, ?, ?, ?)`
+		INSERT INTO target (kind, url, user_id, status, enabled, interval, changed_at, request, assertions, latency_threshold_ms, tls, tcp, dns, heartbeat, heartbeat_token, confirm_after, recover_after)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	result, err := r.db.Exec(
 		query,
+		userTarget.Kind,
 		userTarget.URL,
 		userTarget.UserID,
 		userTarget.Status,
 		userTarget.Enabled,
 		userTarget.Interval.Seconds(),
-		r.formatTime(userTarget.StatusChangedAt),
+		formatTime(userTarget.StatusChangedAt),
+		def.request,
+		def.assertions,
+		userTarget.LatencyThreshold.Milliseconds(),
+		def.tls,
+		def.tcp,
+		def.dns,
+		def.heartbeat,
+		def.heartbeatToken,
+		userTarget.ConfirmAfter,
+		userTarget.RecoverAfter,
 	)
 	if err != nil {
 		return model.UserTarget{}, fmt.Errorf("failed to create target: %w", err)
@@ -98,23 +236,11 @@ func (r *TargetRepository) Create(userTarget model.UserTarget) (model.UserTarget
 
 func (r *TargetRepository) GetByID(id int) (*monitor.Target, error) {
 	query := `
-		SELECT id, url, status, enabled, interval, changed_at
+		SELECT ` + targetColumns + `
 		FROM target
 		WHERE id = ?`
 
-	target := &monitor.Target{}
-	var intervalSeconds float64
-	var statusChangedAtStr string
-
-	err := r.db.QueryRow(query, id).Scan(
-		&target.ID,
-		&target.URL,
-		&target.Status,
-		&target.Enabled,
-		&intervalSeconds,
-		&statusChangedAtStr,
-	)
-
+	target, err := scanTarget(r.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, ErrTargetNotFound
 	}
@@ -122,19 +248,30 @@ func (r *TargetRepository) GetByID(id int) (*monitor.Target, error) {
 		return nil, fmt.Errorf("failed to get target: %w", err)
 	}
 
-	target.Interval = time.Duration(intervalSeconds) * time.Second
-	target.StatusChangedAt, err = r.parseTime(statusChangedAtStr)
+	return target, nil
+}
+
+// GetByHeartbeatToken returns the heartbeat target a ping URL belongs to
+func (r *TargetRepository) GetByHeartbeatToken(token string) (*monitor.Target, error) {
+	query := `
+		SELECT ` + targetColumns + `
+		FROM target
+		WHERE heartbeat_token = ?`
+
+	target, err := scanTarget(r.db.QueryRow(query, token))
+	if err == sql.ErrNoRows {
+		return nil, ErrTargetNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse changed_at: %w", err)
+		return nil, fmt.Errorf("failed to get target: %w", err)
 	}
 
 	return target, nil
 }
 
 func (r *TargetRepository) GetAll() ([]*monitor.Target, error) {
-
 	query := `
-		SELECT id, url, status, enabled, interval, changed_at
+		SELECT ` + targetColumns + `
 		FROM target`
 
 	rows, err := r.db.Query(query)
@@ -143,44 +280,13 @@ func (r *TargetRepository) GetAll() ([]*monitor.Target, error) {
 	}
 	defer rows.Close()
 
-	var targets []*monitor.Target
-	for rows.Next() {
-		target := &monitor.Target{}
-		var intervalSeconds float64
-		var statusChangedAtStr string
-
-		err := rows.Scan(
-			&target.ID,
-			&target.URL,
-			&target.Status,
-			&target.Enabled,
-			&intervalSeconds,
-			&statusChangedAtStr,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan target: %w", err)
-		}
-
-		target.StatusChangedAt, err = r.parseTime(statusChangedAtStr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse changed_at: %w", err)
-		}
-
-		target.Interval = time.Duration(intervalSeconds) * time.Second
-		targets = append(targets, target)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating targets: %w", err)
-	}
-
-	return targets, nil
+	return r.scanTargets(rows)
 }
 
 func (r *TargetRepository) GetAllByUserID(userID int) ([]*monitor.Target, error) {
 	query := `
-		SELECT id, url, status, enabled, interval, changed_at
-		FROM target 
+		SELECT ` + targetColumns + `
+		FROM target
 		WHERE user_id = ?`
 
 	rows, err := r.db.Query(query, userID)
@@ -189,34 +295,20 @@ func (r *TargetRepository) GetAllByUserID(userID int) ([]*monitor.Target, error)
 	}
 	defer rows.Close()
 
+	return r.scanTargets(rows)
+}
+
+func (r *TargetRepository) scanTargets(rows *sql.Rows) ([]*monitor.Target, error) {
 	var targets []*monitor.Target
 	for rows.Next() {
-		target := &monitor.Target{}
-		var intervalSeconds float64
-		var statusChangedAtStr string
-
-		err := rows.Scan(
-			&target.ID,
-			&target.URL,
-			&target.Status,
-			&target.Enabled,
-			&intervalSeconds,
-			&statusChangedAtStr,
-		)
+		target, err := scanTarget(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan target: %w", err)
 		}
-
-		target.StatusChangedAt, err = r.parseTime(statusChangedAtStr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse changed_at: %w", err)
-		}
-
-		target.Interval = time.Duration(intervalSeconds) * time.Second
 		targets = append(targets, target)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating targets: %w", err)
 	}
 
@@ -224,18 +316,39 @@ func (r *TargetRepository) GetAllByUserID(userID int) ([]*monitor.Target, error)
 }
 
 func (r *TargetRepository) Update(target *monitor.Target) (*monitor.Target, error) {
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	target.Kind = target.GetKind()
+
+	def, err := encodeCheckDefinition(target)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE target
-		SET url = ?, status = ?, enabled = ?, interval = ?, changed_at = ?
+		SET kind = ?, url = ?, status = ?, enabled = ?, interval = ?, changed_at = ?, request = ?, assertions = ?, latency_threshold_ms = ?, tls = ?, tcp = ?, dns = ?, heartbeat = ?, heartbeat_token = ?, confirm_after = ?, recover_after = ?
 		WHERE id = ?`
 
 	result, err := r.db.Exec(
 		query,
+		target.Kind,
 		target.URL,
 		target.Status,
 		target.Enabled,
 		target.Interval.Seconds(),
-		r.formatTime(target.StatusChangedAt),
+		formatTime(target.StatusChangedAt),
+		def.request,
+		def.assertions,
+		target.LatencyThreshold.Milliseconds(),
+		def.tls,
+		def.tcp,
+		def.dns,
+		def.heartbeat,
+		def.heartbeatToken,
+		target.ConfirmAfter,
+		target.RecoverAfter,
 		target.ID,
 	)
 	if err != nil {
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"crypto/tls"
 	"testing"
 	"time"
 
@@ -354,3 +355,318 @@ func TestTargetRepository_GetAllByUserID(t *testing.T) {
 		})
 	}
 }
+
+func TestTargetRepository_RequestSpec(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewTargetRepository(db)
+
+	request := core.RequestSpec{
+		Method:  "POST",
+		Headers: map[string]string{"X-Api-Key": "abc"},
+		Query:   map[string]string{"full": "1"},
+		Body:    `{"ping":true}`,
+		Auth:    core.RequestAuth{Type: core.AuthBasic, Username: "admin", Password: "secret"},
+	}
+
+	created, err := repo.Create(model.UserTarget{
+		UserID: 1,
+		Target: &core.Target{
+			URL:      "https://example.org/health",
+			Status:   "pending",
+			Interval: 30 * time.Second,
+			Request:  request,
+		},
+	})
+	assert.NoError(t, err)
+
+	fetched, err := repo.GetByID(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, request, fetched.Request)
+
+	t.Run("invalid spec is rejected", func(t *testing.T) {
+		_, err := repo.Create(model.UserTarget{
+			UserID: 1,
+			Target: &core.Target{
+				URL:      "https://example.org",
+				Interval: 30 * time.Second,
+				Request:  core.RequestSpec{Method: "FETCH"},
+			},
+		})
+		assert.Error(t, err)
+
+		fetched.Request.Auth = core.RequestAuth{Type: core.AuthBearer}
+		_, err = repo.Update(fetched)
+		assert.Error(t, err)
+	})
+}
+
+func TestTargetRepository_Assertions(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewTargetRepository(db)
+
+	assertions := core.Assertions{
+		{Type: core.AssertStatusCode, Value: "200-299"},
+		{Type: core.AssertJSONPath, Target: "status", Value: "ok"},
+	}
+
+	created, err := repo.Create(model.UserTarget{
+		UserID: 1,
+		Target: &core.Target{
+			URL:        "https://example.org/health",
+			Status:     "pending",
+			Interval:   30 * time.Second,
+			Assertions: assertions,
+		},
+	})
+	assert.NoError(t, err)
+
+	fetched, err := repo.GetByID(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, assertions, fetched.Assertions)
+
+	t.Run("cleared assertions", func(t *testing.T) {
+		fetched.Assertions = nil
+		_, err := repo.Update(fetched)
+		assert.NoError(t, err)
+
+		cleared, err := repo.GetByID(created.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, cleared.Assertions)
+	})
+
+	t.Run("invalid assertion is rejected", func(t *testing.T) {
+		fetched.Assertions = core.Assertions{{Type: core.AssertRegex, Value: "("}}
+		_, err := repo.Update(fetched)
+		assert.Error(t, err)
+	})
+}
+
+func TestTargetRepository_LatencyThreshold(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewTargetRepository(db)
+
+	created, err := repo.Create(model.UserTarget{
+		UserID: 1,
+		Target: &core.Target{
+			URL:              "https://example.org",
+			Status:           "pending",
+			Interval:         30 * time.Second,
+			LatencyThreshold: 2 * time.Second,
+		},
+	})
+	assert.NoError(t, err)
+
+	fetched, err := repo.GetByID(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Second, fetched.LatencyThreshold)
+
+	fetched.LatencyThreshold = 1500 * time.Millisecond
+	_, err = repo.Update(fetched)
+	assert.NoError(t, err)
+
+	updated, err := repo.GetByID(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, updated.LatencyThreshold)
+}
+
+func TestTargetRepository_Confirmation(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewTargetRepository(db)
+
+	created, err := repo.Create(model.UserTarget{
+		UserID: 1,
+		Target: &core.Target{
+			URL:          "https://example.org",
+			Status:       "pending",
+			Interval:     30 * time.Second,
+			ConfirmAfter: 3,
+			RecoverAfter: 2,
+		},
+	})
+	assert.NoError(t, err)
+
+	fetched, err := repo.GetByID(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, fetched.ConfirmAfter)
+	assert.Equal(t, 2, fetched.RecoverAfter)
+
+	fetched.ConfirmAfter = 0
+	_, err = repo.Update(fetched)
+	assert.NoError(t, err)
+
+	updated, err := repo.GetByID(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, updated.ConfirmAfter)
+	assert.Equal(t, 2, updated.RecoverAfter)
+
+	t.Run("negative threshold is rejected", func(t *testing.T) {
+		updated.RecoverAfter = -1
+		_, err := repo.Update(updated)
+		assert.Error(t, err)
+	})
+}
+
+func TestTargetRepository_TLSSettings(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewTargetRepository(db)
+
+	settings := core.TLSSettings{Enabled: true, MinVersion: tls.VersionTLS13, ExpiryDays: []int{21, 3}}
+	created, err := repo.Create(model.UserTarget{
+		UserID: 1,
+		Target: &core.Target{
+			URL:      "https://example.org",
+			Status:   "pending",
+			Interval: 30 * time.Second,
+			TLS:      settings,
+		},
+	})
+	assert.NoError(t, err)
+
+	fetched, err := repo.GetByID(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, settings, fetched.TLS)
+
+	t.Run("plain http is rejected", func(t *testing.T) {
+		fetched.URL = "http://example.org"
+		_, err := repo.Update(fetched)
+		assert.Error(t, err)
+	})
+}
+
+func TestTargetRepository_Kind(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewTargetRepository(db)
+
+	t.Run("defaults to http", func(t *testing.T) {
+		created, err := repo.Create(model.UserTarget{
+			UserID: 1,
+			Target: &core.Target{URL: "https://example.org", Status: "pending", Interval: 30 * time.Second},
+		})
+		assert.NoError(t, err)
+
+		fetched, err := repo.GetByID(created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, core.KindHTTP, fetched.Kind)
+	})
+
+	t.Run("tcp send/expect", func(t *testing.T) {
+		spec := core.TCPSpec{Send: "PING\r\n", Expect: "+PONG"}
+		created, err := repo.Create(model.UserTarget{
+			UserID: 1,
+			Target: &core.Target{
+				Kind:     core.KindTCPExpect,
+				URL:      "tcp://redis.internal:6379",
+				Status:   "pending",
+				Interval: 30 * time.Second,
+				TCP:      spec,
+			},
+		})
+		assert.NoError(t, err)
+
+		fetched, err := repo.GetByID(created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, core.KindTCPExpect, fetched.Kind)
+		assert.Equal(t, spec, fetched.TCP)
+
+		fetched.Kind = core.KindTCP
+		fetched.TCP = core.TCPSpec{}
+		_, err = repo.Update(fetched)
+		assert.NoError(t, err)
+
+		updated, err := repo.GetByID(created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, core.KindTCP, updated.Kind)
+	})
+
+	t.Run("tcp without port is rejected", func(t *testing.T) {
+		_, err := repo.Create(model.UserTarget{
+			UserID: 1,
+			Target: &core.Target{Kind: core.KindTCP, URL: "tcp://redis.internal", Interval: 30 * time.Second},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestTargetRepository_DNSSpec(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewTargetRepository(db)
+
+	spec := core.DNSSpec{
+		RecordType:   core.RecordMX,
+		Resolver:     "1.1.1.1:53",
+		Expected:     []string{"10 mail.example.org"},
+		MaxResolveMs: 500,
+	}
+	created, err := repo.Create(model.UserTarget{
+		UserID: 1,
+		Target: &core.Target{
+			Kind:     core.KindDNS,
+			URL:      "dns://example.org",
+			Status:   "pending",
+			Interval: 60 * time.Second,
+			DNS:      spec,
+		},
+	})
+	assert.NoError(t, err)
+
+	fetched, err := repo.GetByID(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, core.KindDNS, fetched.Kind)
+	assert.Equal(t, spec, fetched.DNS)
+
+	t.Run("unsupported record type is rejected", func(t *testing.T) {
+		fetched.DNS.RecordType = "SRV"
+		_, err := repo.Update(fetched)
+		assert.Error(t, err)
+	})
+}
+
+func TestTargetRepository_Heartbeat(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+	repo := NewTargetRepository(db)
+
+	spec := core.HeartbeatSpec{Token: "0b7e4c1a-ping", GraceSeconds: 300}
+	created, err := repo.Create(model.UserTarget{
+		UserID: 1,
+		Target: &core.Target{
+			Kind:      core.KindHeartbeat,
+			URL:       "heartbeat://nightly-backup",
+			Status:    "pending",
+			Interval:  time.Hour,
+			Heartbeat: spec,
+		},
+	})
+	assert.NoError(t, err)
+
+	fetched, err := repo.GetByID(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, spec, fetched.Heartbeat)
+
+	byToken, err := repo.GetByHeartbeatToken(spec.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, byToken.ID)
+
+	t.Run("unknown token", func(t *testing.T) {
+		_, err := repo.GetByHeartbeatToken("missing")
+		assert.ErrorIs(t, err, ErrTargetNotFound)
+	})
+
+	t.Run("targets without a token do not match", func(t *testing.T) {
+		_, err := repo.Create(model.UserTarget{
+			UserID: 1,
+			Target: &core.Target{URL: "https://example.org", Status: "pending", Interval: 30 * time.Second},
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.GetByHeartbeatToken("")
+		assert.ErrorIs(t, err, ErrTargetNotFound)
+	})
+}
@@ -0,0 +1,29 @@
+package repository
+
+import "time"
+
+// timeLayout is RFC3339 with a fixed-width fraction, so stored values sort
+// lexically in the same order as chronologically
+const timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(timeLayout)
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	// Try parsing RFC3339Nano format first
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err == nil {
+		return t, nil
+	}
+
+	// If that fails, try parsing the alternative format
+	return time.Parse("2006-01-02 15:04:05.999999999-07:00", s)
+}
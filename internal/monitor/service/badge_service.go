@@ -0,0 +1,246 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/pkg/badge"
+)
+
+// ErrUnknownBadge is returned when asked to render a badge that does not exist
+var ErrUnknownBadge = errors.New("unknown badge")
+
+// Badge names, as used in badge URLs
+const (
+	BadgeStatus  = "status"
+	BadgeLatency = "latency"
+	// BadgeUptime is followed by a window label, e.g. "uptime-30d"
+	BadgeUptime = "uptime-"
+)
+
+// BadgeLatencyWindow is the period the latency badge averages over
+const BadgeLatencyWindow = 24 * time.Hour
+
+// BadgeCacheTTL is how long an uptime or latency badge is reused. Badges are
+// public, and each of these reads up to 90 days of check results.
+var BadgeCacheTTL = time.Minute
+
+type BadgeServiceInterface interface {
+	GetByTargetID(targetID int) (*model.TargetBadge, error)
+	Enable(targetID int) (*model.TargetBadge, error)
+	Disable(targetID int) error
+	Render(token, name string) (badge.Badge, error)
+}
+
+var _ BadgeServiceInterface = (*BadgeService)(nil)
+
+// BadgeService renders the public badges of targets that have them enabled
+type BadgeService struct {
+	repo        repository.BadgeRepositoryInterface
+	targetRepo  repository.TargetRepositoryInterface
+	resultRepo  repository.CheckResultRepositoryInterface
+	maintenance MaintenanceServiceInterface
+	now         func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedBadge
+}
+
+// cachedBadge is a rendered badge and when it was rendered
+type cachedBadge struct {
+	badge badge.Badge
+	at    time.Time
+}
+
+func NewBadgeService(
+	repo repository.BadgeRepositoryInterface,
+	targetRepo repository.TargetRepositoryInterface,
+	resultRepo repository.CheckResultRepositoryInterface,
+	maintenance MaintenanceServiceInterface,
+) *BadgeService {
+	return &BadgeService{
+		repo:        repo,
+		targetRepo:  targetRepo,
+		resultRepo:  resultRepo,
+		maintenance: maintenance,
+		now:         time.Now,
+		cache:       make(map[string]cachedBadge),
+	}
+}
+
+// GetByTargetID returns the target's badge, or nil if badges are off
+func (s *BadgeService) GetByTargetID(targetID int) (*model.TargetBadge, error) {
+	return s.repo.GetByTargetID(targetID)
+}
+
+// Enable turns badges on for the target with a fresh token. Enabling them
+// again rotates the token, breaking every URL that embeds the old one.
+func (s *BadgeService) Enable(targetID int) (*model.TargetBadge, error) {
+	badge := model.TargetBadge{TargetID: targetID, Token: uuid.New().String()}
+	if err := s.repo.Save(badge); err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+func (s *BadgeService) Disable(targetID int) error {
+	return s.repo.Delete(targetID)
+}
+
+// Render builds the named badge for the target behind token. Uptime and
+// latency badges are cached for BadgeCacheTTL.
+func (s *BadgeService) Render(token, name string) (badge.Badge, error) {
+	targetBadge, err := s.repo.GetByToken(token)
+	if err != nil {
+		return badge.Badge{}, err
+	}
+
+	target, err := s.targetRepo.GetByID(targetBadge.TargetID)
+	if err != nil {
+		return badge.Badge{}, fmt.Errorf("failed to get target: %w", err)
+	}
+
+	now := s.now()
+	if name == BadgeStatus {
+		return s.statusBadge(target, now)
+	}
+
+	key := fmt.Sprintf("%d/%s", target.ID, name)
+	s.mu.Lock()
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && !now.Before(cached.at) && now.Sub(cached.at) < BadgeCacheTTL {
+		return cached.badge, nil
+	}
+
+	var b badge.Badge
+	switch {
+	case name == BadgeLatency:
+		b, err = s.latencyBadge(target, now)
+	case strings.HasPrefix(name, BadgeUptime):
+		b, err = s.uptimeBadge(target, strings.TrimPrefix(name, BadgeUptime), now)
+	default:
+		return badge.Badge{}, ErrUnknownBadge
+	}
+	if err != nil {
+		return badge.Badge{}, err
+	}
+
+	s.mu.Lock()
+	s.cache[key] = cachedBadge{badge: b, at: now}
+	s.mu.Unlock()
+
+	return b, nil
+}
+
+func (s *BadgeService) statusBadge(target *monitor.Target, now time.Time) (badge.Badge, error) {
+	inMaintenance, err := s.maintenance.IsActive(target.ID, now)
+	if err != nil {
+		return badge.Badge{}, fmt.Errorf("failed to check maintenance windows: %w", err)
+	}
+
+	b := badge.Badge{Label: "status", Message: target.Status, Color: badge.ColorLightGrey}
+	switch {
+	case !target.Enabled:
+		b.Message = "paused"
+	case inMaintenance:
+		b.Message, b.Color = "maintenance", badge.ColorBlue
+	case target.Status == "up":
+		b.Color = badge.ColorBrightGreen
+	case target.Status == "degraded":
+		b.Color = badge.ColorYellow
+	case monitor.IsFailing(target.Status):
+		b.Color = badge.ColorRed
+	}
+
+	return b, nil
+}
+
+func (s *BadgeService) uptimeBadge(target *monitor.Target, label string, now time.Time) (badge.Badge, error) {
+	for _, window := range StandardWindows(now) {
+		if window.Label != label {
+			continue
+		}
+
+		results, err := s.resultRepo.GetByTargetID(target.ID, window.From, window.To)
+		if err != nil {
+			return badge.Badge{}, fmt.Errorf("failed to get check results: %w", err)
+		}
+
+		b := badge.Badge{Label: "uptime " + label, Message: "no data", Color: badge.ColorLightGrey}
+		if report := CalculateSLA(results, window); report.HasData() {
+			b.Message = fmt.Sprintf("%.2f%%", report.Uptime)
+			b.Color = uptimeColor(report.Uptime)
+		}
+		return b, nil
+	}
+
+	return badge.Badge{}, ErrUnknownBadge
+}
+
+// uptimeColor grades availability the way uptime badges commonly do
+func uptimeColor(uptime float64) string {
+	switch {
+	case uptime >= 99.9:
+		return badge.ColorBrightGreen
+	case uptime >= 99:
+		return badge.ColorGreen
+	case uptime >= 95:
+		return badge.ColorYellow
+	case uptime >= 90:
+		return badge.ColorOrange
+	default:
+		return badge.ColorRed
+	}
+}
+
+// latencyBadge shows the average latency of the checks that found the target
+// available, leaving out failures which often end in a timeout
+func (s *BadgeService) latencyBadge(target *monitor.Target, now time.Time) (badge.Badge, error) {
+	results, err := s.resultRepo.GetByTargetID(target.ID, now.Add(-BadgeLatencyWindow), now)
+	if err != nil {
+		return badge.Badge{}, fmt.Errorf("failed to get check results: %w", err)
+	}
+
+	var total time.Duration
+	var count int
+	for _, result := range results {
+		available := result.IsUp() || result.Status == "degraded"
+		if !available || result.CheckedAt.Before(now.Add(-BadgeLatencyWindow)) {
+			continue
+		}
+		total += result.Latency
+		count++
+	}
+
+	b := badge.Badge{Label: "latency", Message: "no data", Color: badge.ColorLightGrey}
+	if count > 0 {
+		average := total / time.Duration(count)
+		b.Message = fmt.Sprintf("%dms", average.Milliseconds())
+		b.Color = latencyColor(average)
+	}
+
+	return b, nil
+}
+
+func latencyColor(latency time.Duration) string {
+	switch {
+	case latency < 200*time.Millisecond:
+		return badge.ColorBrightGreen
+	case latency < 500*time.Millisecond:
+		return badge.ColorGreen
+	case latency < time.Second:
+		return badge.ColorYellow
+	case latency < 2*time.Second:
+		return badge.ColorOrange
+	default:
+		return badge.ColorRed
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/shuvo-paul/uptimebot/pkg/badge"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockBadgeRepository keeps badges in memory
+type mockBadgeRepository struct {
+	badges map[int]model.TargetBadge
+}
+
+func (m *mockBadgeRepository) Save(b model.TargetBadge) error {
+	if m.badges == nil {
+		m.badges = make(map[int]model.TargetBadge)
+	}
+	m.badges[b.TargetID] = b
+	return nil
+}
+
+func (m *mockBadgeRepository) GetByTargetID(targetID int) (*model.TargetBadge, error) {
+	if b, ok := m.badges[targetID]; ok {
+		return &b, nil
+	}
+	return nil, nil
+}
+
+func (m *mockBadgeRepository) GetByToken(token string) (*model.TargetBadge, error) {
+	for _, b := range m.badges {
+		if b.Token == token {
+			return &b, nil
+		}
+	}
+	return nil, repository.ErrBadgeNotFound
+}
+
+func (m *mockBadgeRepository) Delete(targetID int) error {
+	delete(m.badges, targetID)
+	return nil
+}
+
+func TestBadgeService_Enable(t *testing.T) {
+	repo := &mockBadgeRepository{}
+	service := NewBadgeService(repo, &mockTargetRepository{}, &mockCheckResultRepository{}, NewMaintenanceService(&mockMaintenanceRepository{}))
+
+	first, err := service.Enable(1)
+	assert.NoError(t, err)
+	assert.Len(t, first.Token, 36)
+
+	second, err := service.Enable(1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Token, second.Token, "enabling again rotates the token")
+
+	_, err = repo.GetByToken(first.Token)
+	assert.ErrorIs(t, err, repository.ErrBadgeNotFound)
+
+	assert.NoError(t, service.Disable(1))
+	got, err := service.GetByTargetID(1)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestBadgeService_Render(t *testing.T) {
+	now := time.Date(2025, 5, 25, 12, 0, 0, 0, time.UTC)
+
+	targets := map[int]*monitor.Target{
+		1: {ID: 1, Status: "up", Enabled: true},
+		2: {ID: 2, Status: "down", Enabled: true},
+		3: {ID: 3, Status: "down"},
+		4: {ID: 4, Status: "down", Enabled: true},
+		5: {ID: 5, Status: "pending", Enabled: true},
+	}
+	targetRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (*monitor.Target, error) {
+			return targets[id], nil
+		},
+	}
+
+	resultRepo := &mockCheckResultRepository{
+		getByTargetIDFunc: func(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+			if targetID != 1 {
+				return nil, nil
+			}
+			// Up for 27 days, then down for the last 3
+			return []monitor.CheckResult{
+				{Status: "up", CheckedAt: now.AddDate(0, 0, -30), Latency: 400 * time.Millisecond},
+				{Status: "up", CheckedAt: now.Add(-6 * time.Hour), Latency: 100 * time.Millisecond},
+				{Status: "degraded", CheckedAt: now.Add(-5 * time.Hour), Latency: 200 * time.Millisecond},
+				{Status: "down", CheckedAt: now.AddDate(0, 0, -3), Latency: 10 * time.Second},
+			}, nil
+		},
+	}
+
+	maintenance := NewMaintenanceService(&mockMaintenanceRepository{})
+	_, err := maintenance.Create(model.MaintenanceWindow{TargetID: 4, Kind: model.MaintenanceOnce, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+	assert.NoError(t, err)
+
+	repo := &mockBadgeRepository{}
+	for id := range targets {
+		repo.Save(model.TargetBadge{TargetID: id, Token: string(rune('a' + id - 1))})
+	}
+
+	service := NewBadgeService(repo, targetRepo, resultRepo, maintenance)
+	service.now = func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		token   string
+		badge   string
+		want    badge.Badge
+		wantErr error
+	}{
+		{name: "up", token: "a", badge: "status", want: badge.Badge{Label: "status", Message: "up", Color: badge.ColorBrightGreen}},
+		{name: "down", token: "b", badge: "status", want: badge.Badge{Label: "status", Message: "down", Color: badge.ColorRed}},
+		{name: "paused", token: "c", badge: "status", want: badge.Badge{Label: "status", Message: "paused", Color: badge.ColorLightGrey}},
+		{name: "maintenance", token: "d", badge: "status", want: badge.Badge{Label: "status", Message: "maintenance", Color: badge.ColorBlue}},
+		{name: "pending", token: "e", badge: "status", want: badge.Badge{Label: "status", Message: "pending", Color: badge.ColorLightGrey}},
+		{name: "uptime", token: "a", badge: "uptime-30d", want: badge.Badge{Label: "uptime 30d", Message: "90.00%", Color: badge.ColorOrange}},
+		{name: "uptime without data", token: "b", badge: "uptime-7d", want: badge.Badge{Label: "uptime 7d", Message: "no data", Color: badge.ColorLightGrey}},
+		{name: "latency", token: "a", badge: "latency", want: badge.Badge{Label: "latency", Message: "150ms", Color: badge.ColorBrightGreen}},
+		{name: "latency without data", token: "b", badge: "latency", want: badge.Badge{Label: "latency", Message: "no data", Color: badge.ColorLightGrey}},
+		{name: "unknown window", token: "a", badge: "uptime-1y", wantErr: ErrUnknownBadge},
+		{name: "unknown badge", token: "a", badge: "response", wantErr: ErrUnknownBadge},
+		{name: "unknown token", token: "z", badge: "status", wantErr: repository.ErrBadgeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.Render(tt.token, tt.badge)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBadgeService_Render_Cache(t *testing.T) {
+	now := time.Date(2025, 5, 25, 12, 0, 0, 0, time.UTC)
+	target := &monitor.Target{ID: 1, Status: "up", Enabled: true}
+	targetRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (*monitor.Target, error) {
+			return target, nil
+		},
+	}
+	queries := 0
+	resultRepo := &mockCheckResultRepository{
+		getByTargetIDFunc: func(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+			queries++
+			return []monitor.CheckResult{{Status: "up", CheckedAt: now.Add(-time.Hour), Latency: 100 * time.Millisecond}}, nil
+		},
+	}
+	repo := &mockBadgeRepository{}
+	repo.Save(model.TargetBadge{TargetID: 1, Token: "a"})
+
+	service := NewBadgeService(repo, targetRepo, resultRepo, NewMaintenanceService(&mockMaintenanceRepository{}))
+	service.now = func() time.Time { return now }
+
+	for _, name := range []string{"latency", "uptime-30d", "latency", "uptime-30d"} {
+		_, err := service.Render("a", name)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, queries, "badges are reused within the cache TTL")
+
+	target.Status = "down"
+	status, err := service.Render("a", "status")
+	assert.NoError(t, err)
+	assert.Equal(t, "down", status.Message, "the status badge is never cached")
+
+	service.now = func() time.Time { return now.Add(BadgeCacheTTL) }
+	_, err = service.Render("a", "latency")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, queries, "stale badges are rendered again")
+}
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	notifCore "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	alertService "github.com/shuvo-paul/uptimebot/internal/notification/service"
+)
+
+// escalationInterval is how often open incidents are checked for steps due
+// to fire
+const escalationInterval = 30 * time.Second
+
+// NotifierOption is a notifier an escalation step can page, with the target
+// it was set up on
+type NotifierOption struct {
+	ID        int64
+	Type      string
+	TargetURL string
+}
+
+type EscalationServiceInterface interface {
+	Create(policy model.EscalationPolicy) (model.EscalationPolicy, error)
+	GetByID(id int) (*model.EscalationPolicy, error)
+	GetAllByUserID(userID int) ([]model.EscalationPolicy, error)
+	Update(policy model.EscalationPolicy) error
+	Delete(id int) error
+	NotifierOptions(userID int) ([]NotifierOption, error)
+}
+
+var _ EscalationServiceInterface = (*EscalationService)(nil)
+
+// EscalationService manages escalation policies and runs them: while an
+// incident on one of a policy's targets goes unacknowledged it fires the
+// policy's steps one after the other, and tells everyone it paged once the
+// incident is acknowledged or resolved
+type EscalationService struct {
+	repo            repository.EscalationRepositoryInterface
+	targetRepo      repository.TargetRepositoryInterface
+	incidentRepo    repository.IncidentRepositoryInterface
+	maintenance     MaintenanceServiceInterface
+	notifierService alertService.NotifierServiceInterface
+	now             func() time.Time
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewEscalationService(
+	repo repository.EscalationRepositoryInterface,
+	targetRepo repository.TargetRepositoryInterface,
+	incidentRepo repository.IncidentRepositoryInterface,
+	maintenance MaintenanceServiceInterface,
+	notifierService alertService.NotifierServiceInterface,
+) *EscalationService {
+	return &EscalationService{
+		repo:            repo,
+		targetRepo:      targetRepo,
+		incidentRepo:    incidentRepo,
+		maintenance:     maintenance,
+		notifierService: notifierService,
+		now:             time.Now,
+	}
+}
+
+func (s *EscalationService) Create(policy model.EscalationPolicy) (model.EscalationPolicy, error) {
+	if err := s.validate(&policy); err != nil {
+		return model.EscalationPolicy{}, err
+	}
+	return s.repo.Create(policy)
+}
+
+func (s *EscalationService) GetByID(id int) (*model.EscalationPolicy, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *EscalationService) GetAllByUserID(userID int) ([]model.EscalationPolicy, error) {
+	return s.repo.GetAllByUserID(userID)
+}
+
+func (s *EscalationService) Update(policy model.EscalationPolicy) error {
+	if err := s.validate(&policy); err != nil {
+		return err
+	}
+	return s.repo.Update(policy)
+}
+
+func (s *EscalationService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// validate checks the policy can page someone, and that it only covers
+// targets and pages notifiers owned by the policy's user
+func (s *EscalationService) validate(policy *model.EscalationPolicy) error {
+	policy.Name = strings.TrimSpace(policy.Name)
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	owned, err := s.targetRepo.GetAllByUserID(policy.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get targets: %w", err)
+	}
+	for _, id := range policy.TargetIDs {
+		if !ownsTarget(owned, id) {
+			return fmt.Errorf("target %d not found", id)
+		}
+	}
+
+	options, err := s.notifierOptions(owned)
+	if err != nil {
+		return err
+	}
+	for i, step := range policy.Steps {
+		for _, id := range step.NotifierIDs {
+			if !slices.ContainsFunc(options, func(option NotifierOption) bool { return option.ID == id }) {
+				return fmt.Errorf("step %d: notifier %d not found", i+1, id)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NotifierOptions lists the notifiers on all of the user's targets
+func (s *EscalationService) NotifierOptions(userID int) ([]NotifierOption, error) {
+	targets, err := s.targetRepo.GetAllByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get targets: %w", err)
+	}
+	return s.notifierOptions(targets)
+}
+
+func (s *EscalationService) notifierOptions(targets []*monitor.Target) ([]NotifierOption, error) {
+	var options []NotifierOption
+	for _, target := range targets {
+		notifiers, err := s.notifierService.GetByTargetID(target.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get notifiers: %w", err)
+		}
+		for _, notifier := range notifiers {
+			options = append(options, NotifierOption{
+				ID:        notifier.ID,
+				Type:      string(notifier.Type),
+				TargetURL: target.URL,
+			})
+		}
+	}
+	return options, nil
+}
+
+// Start checks for due escalation steps in the background until Stop is
+// called
+func (s *EscalationService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(escalationInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.EscalateDue(); err != nil {
+				slog.Error("Failed to escalate incidents", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the background checks and waits for the current one to finish
+func (s *EscalationService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// EscalateDue starts escalating new unacknowledged incidents, fires every
+// step that is due and wraps up escalations whose incident has been
+// acknowledged or resolved. It returns how many escalations it moved on.
+func (s *EscalationService) EscalateDue() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if _, err := s.repo.StartEscalations(now); err != nil {
+		return 0, err
+	}
+
+	escalations, err := s.repo.GetDueEscalations(now)
+	if err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	var errs []error
+	for _, escalation := range escalations {
+		moved, err := s.escalate(escalation, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("incident %d: %w", escalation.IncidentID, err))
+			continue
+		}
+		if moved {
+			handled++
+		}
+	}
+
+	if len(errs) > 0 {
+		return handled, fmt.Errorf("failed to escalate %d incidents: %v", len(errs), errs)
+	}
+	return handled, nil
+}
+
+// escalate moves one escalation on and reports whether it did. Steps due
+// while the target is under maintenance wait for the window to close.
+func (s *EscalationService) escalate(escalation model.Escalation, now time.Time) (bool, error) {
+	incident, err := s.incidentRepo.GetByID(escalation.IncidentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get incident: %w", err)
+	}
+	target, err := s.targetRepo.GetByID(incident.TargetID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get target: %w", err)
+	}
+
+	switch {
+	case incident.IsAcknowledged():
+		err = s.acknowledge(escalation, incident)
+	case incident.IsResolved() || !target.Enabled:
+		err = s.recover(escalation, incident, target)
+	default:
+		var fired bool
+		if fired, err = s.fireStep(&escalation, incident, target, now); err != nil || !fired {
+			return false, err
+		}
+		return true, s.repo.SaveEscalation(escalation)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	escalation.NextAt = time.Time{}
+	escalation.FinishedAt = now
+	return true, s.repo.SaveEscalation(escalation)
+}
+
+// fireStep pages the notifiers of the escalation's next step and schedules
+// the one after. The escalation finishes once the policy has run out of
+// steps or no longer covers the target.
+func (s *EscalationService) fireStep(escalation *model.Escalation, incident *model.Incident, target *monitor.Target, now time.Time) (bool, error) {
+	policy, err := s.repo.GetByID(escalation.PolicyID)
+	if err != nil && err != repository.ErrEscalationPolicyNotFound {
+		return false, err
+	}
+	if policy == nil || !policy.Includes(target.ID) || escalation.Step >= len(policy.Steps) {
+		escalation.NextAt = time.Time{}
+		escalation.FinishedAt = now
+		return true, nil
+	}
+
+	inMaintenance, err := s.maintenance.IsActive(target.ID, now)
+	if err != nil {
+		slog.Error("Failed to check maintenance windows", "Target", target.URL, "error", err)
+	}
+	if inMaintenance {
+		return false, nil
+	}
+
+	message := statusMessage(target, target.Status)
+	if escalation.Step > 0 {
+		message += fmt.Sprintf(" (unacknowledged for %s)", now.Sub(incident.StartedAt).Round(time.Minute))
+	}
+	state := notifCore.State{
+		Name:           target.URL,
+		Status:         target.Status,
+		Message:        message,
+		UpdatedAt:      now,
+		TargetID:       target.ID,
+		PreviousStatus: target.PreviousStatus,
+		Reason:         target.StatusReason,
+		IncidentID:     incident.ID,
+		OutageStart:    incident.StartedAt,
+	}
+
+	alerted, err := s.alertedNotifiers(target.ID, state, now)
+	if err != nil {
+		return false, err
+	}
+
+	// Those of the target's own notifiers that have already been told about
+	// the outage on their own terms are not paged again
+	step := policy.Steps[escalation.Step]
+	var paged []int64
+	for _, id := range step.NotifierIDs {
+		if !slices.Contains(alerted, id) && !slices.Contains(paged, id) {
+			paged = append(paged, id)
+		}
+	}
+
+	err = s.notifierService.Escalate(paged, state)
+	if err != nil {
+		return false, fmt.Errorf("failed to queue escalation: %w", err)
+	}
+
+	for _, id := range paged {
+		if !slices.Contains(escalation.Notified, id) {
+			escalation.Notified = append(escalation.Notified, id)
+		}
+	}
+	escalation.NextAt = time.Time{}
+	if escalation.Step < len(policy.Steps)-1 {
+		escalation.NextAt = now.Add(time.Duration(step.WaitMinutes) * time.Minute)
+	}
+	escalation.Step++
+
+	if len(paged) > 0 {
+		note := fmt.Sprintf("Escalated to step %d of %s", escalation.Step, policy.Name)
+		if err := s.recordEvent(incident, note, now); err != nil {
+			slog.Error("Failed to record escalation", "Target", target.URL, "error", err)
+		}
+	}
+	return true, nil
+}
+
+// acknowledge tells the paging services an escalation reached that someone
+// has taken the incident on
+func (s *EscalationService) acknowledge(escalation model.Escalation, incident *model.Incident) error {
+	if len(escalation.Notified) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("Incident #%d acknowledged", incident.ID)
+	if incident.AcknowledgedBy != "" {
+		message += " by " + incident.AcknowledgedBy
+	}
+
+	err := s.notifierService.EscalateAcknowledgement(escalation.Notified, notifCore.State{
+		Name:       incident.TargetURL,
+		Message:    message,
+		UpdatedAt:  incident.AcknowledgedAt,
+		TargetID:   incident.TargetID,
+		IncidentID: incident.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue acknowledgement: %w", err)
+	}
+	return nil
+}
+
+// recover tells everyone an escalation paged that the outage is over,
+// either because the target recovered or because it was paused
+func (s *EscalationService) recover(escalation model.Escalation, incident *model.Incident, target *monitor.Target) error {
+	if len(escalation.Notified) == 0 {
+		return nil
+	}
+
+	status := target.Status
+	if !target.Enabled {
+		status = StatusPaused
+	}
+	updatedAt := incident.ResolvedAt
+	if updatedAt.IsZero() {
+		updatedAt = s.now()
+	}
+
+	err := s.notifierService.Escalate(escalation.Notified, notifCore.State{
+		Name:           target.URL,
+		Status:         status,
+		Message:        fmt.Sprintf("Target %s is %s after %s", target.URL, status, incident.Duration(updatedAt).Round(time.Minute)),
+		UpdatedAt:      updatedAt,
+		TargetID:       target.ID,
+		PreviousStatus: target.PreviousStatus,
+		IncidentID:     incident.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue recovery: %w", err)
+	}
+	return nil
+}
+
+// alertedNotifiers lists the target's notifiers whose filters let the alert
+// about state through and have sent it by now
+func (s *EscalationService) alertedNotifiers(targetID int, state notifCore.State, now time.Time) ([]int64, error) {
+	notifiers, err := s.notifierService.GetByTargetID(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifiers: %w", err)
+	}
+	var ids []int64
+	for _, notifier := range notifiers {
+		if notifier.Accepts(state) && !notifier.Filter.DeliverAt(state, now).After(now) {
+			ids = append(ids, notifier.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *EscalationService) recordEvent(incident *model.Incident, message string, at time.Time) error {
+	_, err := s.incidentRepo.AddEvent(model.IncidentEvent{
+		IncidentID: incident.ID,
+		Kind:       model.IncidentNotification,
+		Message:    message,
+		CreatedAt:  at,
+	})
+	return err
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	alertModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockEscalationRepository keeps policies and escalations in memory. It
+// looks at the incidents of incidentRepo to tell which escalations are due.
+type mockEscalationRepository struct {
+	policies     []model.EscalationPolicy
+	escalations  []model.Escalation
+	incidentRepo *mockIncidentRepository
+}
+
+func (m *mockEscalationRepository) Create(policy model.EscalationPolicy) (model.EscalationPolicy, error) {
+	policy.ID = len(m.policies) + 1
+	m.policies = append(m.policies, policy)
+	return policy, nil
+}
+
+func (m *mockEscalationRepository) GetByID(id int) (*model.EscalationPolicy, error) {
+	for _, policy := range m.policies {
+		if policy.ID == id {
+			return &policy, nil
+		}
+	}
+	return nil, repository.ErrEscalationPolicyNotFound
+}
+
+func (m *mockEscalationRepository) GetAllByUserID(userID int) ([]model.EscalationPolicy, error) {
+	return m.policies, nil
+}
+
+func (m *mockEscalationRepository) Update(policy model.EscalationPolicy) error {
+	m.policies[policy.ID-1] = policy
+	return nil
+}
+
+func (m *mockEscalationRepository) Delete(id int) error {
+	return nil
+}
+
+func (m *mockEscalationRepository) StartEscalations(now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockEscalationRepository) GetDueEscalations(now time.Time) ([]model.Escalation, error) {
+	var due []model.Escalation
+	for _, escalation := range m.escalations {
+		if !escalation.FinishedAt.IsZero() {
+			continue
+		}
+		incident, _ := m.incidentRepo.GetByID(escalation.IncidentID)
+		stepDue := !escalation.NextAt.IsZero() && !escalation.NextAt.After(now)
+		if stepDue || incident.IsAcknowledged() || incident.IsResolved() {
+			due = append(due, escalation)
+		}
+	}
+	return due, nil
+}
+
+func (m *mockEscalationRepository) SaveEscalation(escalation model.Escalation) error {
+	for i := range m.escalations {
+		if m.escalations[i].IncidentID == escalation.IncidentID {
+			m.escalations[i] = escalation
+		}
+	}
+	return nil
+}
+
+// newEscalationTest sets up target 10, which has notifier 1 of its own, and
+// target 20, whose notifiers 2 and 3 a policy on target 10 escalates to
+func newEscalationTest(now time.Time) (*EscalationService, *mockEscalationRepository, *mockNotifierService) {
+	targets := map[int]*monitor.Target{
+		10: {ID: 10, URL: "https://example.com", Status: "down", PreviousStatus: "up", Enabled: true},
+		20: {ID: 20, URL: "https://api.example.com", Status: "up", Enabled: true},
+	}
+	targetRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (*monitor.Target, error) {
+			return targets[id], nil
+		},
+		getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
+			return []*monitor.Target{targets[10], targets[20]}, nil
+		},
+	}
+	notifiers := &mockNotifierService{
+		getByTargetIDFunc: func(targetID int) ([]*alertModel.Notifier, error) {
+			if targetID == 10 {
+				return []*alertModel.Notifier{{ID: 1, TargetId: 10, Type: alertModel.NotifierTypeEmail}}, nil
+			}
+			return []*alertModel.Notifier{
+				{ID: 2, TargetId: 20, Type: alertModel.NotifierTypeSlack},
+				{ID: 3, TargetId: 20, Type: alertModel.NotifierTypePagerDuty},
+			}, nil
+		},
+	}
+
+	incidentRepo := &mockIncidentRepository{}
+	incidentRepo.Create(model.Incident{TargetID: 10, TargetURL: "https://example.com", StartedAt: now.Add(-time.Minute)})
+
+	repo := &mockEscalationRepository{
+		policies: []model.EscalationPolicy{{
+			ID:     1,
+			UserID: 1,
+			Name:   "On-call",
+			Steps: []model.EscalationStep{
+				{NotifierIDs: []int64{1, 2}, WaitMinutes: 15},
+				{NotifierIDs: []int64{3}},
+			},
+			TargetIDs: []int{10},
+		}},
+		escalations:  []model.Escalation{{IncidentID: 1, PolicyID: 1, NextAt: now}},
+		incidentRepo: incidentRepo,
+	}
+
+	service := NewEscalationService(repo, targetRepo, incidentRepo, NewMaintenanceService(&mockMaintenanceRepository{}), notifiers)
+	return service, repo, notifiers
+}
+
+func TestEscalationService_EscalateDue(t *testing.T) {
+	now := time.Date(2025, 6, 22, 3, 0, 0, 0, time.UTC)
+
+	t.Run("steps fire until the incident is acknowledged", func(t *testing.T) {
+		service, repo, notifiers := newEscalationTest(now)
+		incidents := service.incidentRepo.(*mockIncidentRepository)
+
+		service.now = func() time.Time { return now }
+		handled, err := service.EscalateDue()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		assert.Equal(t, []int64{2}, notifiers.escalated["Target https://example.com is down"], "the target's own notifiers that sent the alert are skipped")
+		assert.Equal(t, now.Add(15*time.Minute), repo.escalations[0].NextAt)
+		if assert.Len(t, incidents.events, 1) {
+			assert.Equal(t, "Escalated to step 1 of On-call", incidents.events[0].Message)
+		}
+
+		service.now = func() time.Time { return now.Add(5 * time.Minute) }
+		handled, err = service.EscalateDue()
+		assert.NoError(t, err)
+		assert.Zero(t, handled, "the next step waits for its turn")
+
+		service.now = func() time.Time { return now.Add(15 * time.Minute) }
+		handled, err = service.EscalateDue()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		assert.Equal(t, []int64{3}, notifiers.escalated["Target https://example.com is down (unacknowledged for 16m0s)"])
+		assert.Equal(t, 2, repo.escalations[0].Step)
+		assert.True(t, repo.escalations[0].NextAt.IsZero(), "nothing is left to fire")
+		assert.Equal(t, []int64{2, 3}, repo.escalations[0].Notified)
+
+		incidents.incidents[0].AcknowledgedAt = now.Add(20 * time.Minute)
+		incidents.incidents[0].AcknowledgedBy = "Alice"
+		service.now = func() time.Time { return now.Add(20 * time.Minute) }
+		handled, err = service.EscalateDue()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		assert.Equal(t, []int64{2, 3}, notifiers.escalated["Incident #1 acknowledged by Alice"])
+		assert.Equal(t, now.Add(20*time.Minute), repo.escalations[0].FinishedAt)
+	})
+
+	t.Run("recovery is announced to everyone paged", func(t *testing.T) {
+		service, repo, notifiers := newEscalationTest(now)
+		incidents := service.incidentRepo.(*mockIncidentRepository)
+		service.now = func() time.Time { return now }
+		_, err := service.EscalateDue()
+		assert.NoError(t, err)
+
+		incidents.incidents[0].ResolvedAt = now.Add(4 * time.Minute)
+		target, _ := service.targetRepo.GetByID(10)
+		target.Status = "up"
+		service.now = func() time.Time { return now.Add(5 * time.Minute) }
+		handled, err := service.EscalateDue()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		assert.Equal(t, []int64{2}, notifiers.escalated["Target https://example.com is up after 5m0s"])
+		assert.False(t, repo.escalations[0].FinishedAt.IsZero())
+	})
+
+	t.Run("own notifiers still holding the alert are paged", func(t *testing.T) {
+		service, repo, notifiers := newEscalationTest(now)
+		repo.policies[0].Steps = []model.EscalationStep{{NotifierIDs: []int64{1, 4}}}
+		notifiers.getByTargetIDFunc = func(targetID int) ([]*alertModel.Notifier, error) {
+			return []*alertModel.Notifier{
+				{ID: 1, TargetId: 10, Type: alertModel.NotifierTypeEmail, Filter: alertModel.NotifierFilter{MinOutageMinutes: 30}},
+				{ID: 4, TargetId: 10, Type: alertModel.NotifierTypeSlack, Filter: alertModel.NotifierFilter{Statuses: []string{"up"}}},
+			}, nil
+		}
+		service.now = func() time.Time { return now }
+
+		handled, err := service.EscalateDue()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		assert.Equal(t, []int64{1, 4}, notifiers.escalated["Target https://example.com is down"])
+		assert.Equal(t, []int64{1, 4}, repo.escalations[0].Notified)
+	})
+
+	t.Run("policy no longer covering the target", func(t *testing.T) {
+		service, repo, notifiers := newEscalationTest(now)
+		repo.policies[0].TargetIDs = nil
+		service.now = func() time.Time { return now }
+
+		_, err := service.EscalateDue()
+		assert.NoError(t, err)
+		assert.Empty(t, notifiers.escalated)
+		assert.Equal(t, now, repo.escalations[0].FinishedAt)
+	})
+
+	t.Run("steps wait for maintenance to end", func(t *testing.T) {
+		service, repo, notifiers := newEscalationTest(now)
+		_, err := service.maintenance.Create(model.MaintenanceWindow{TargetID: 10, Kind: model.MaintenanceOnce, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+		assert.NoError(t, err)
+		service.now = func() time.Time { return now }
+
+		handled, err := service.EscalateDue()
+		assert.NoError(t, err)
+		assert.Zero(t, handled)
+		assert.Empty(t, notifiers.escalated)
+		assert.Equal(t, now, repo.escalations[0].NextAt)
+	})
+}
+
+func TestEscalationService_Create(t *testing.T) {
+	service, _, _ := newEscalationTest(time.Now())
+
+	tests := []struct {
+		name    string
+		policy  model.EscalationPolicy
+		wantErr bool
+	}{
+		{
+			name:   "valid policy",
+			policy: model.EscalationPolicy{UserID: 1, Name: " Nights ", Steps: []model.EscalationStep{{NotifierIDs: []int64{3}}}, TargetIDs: []int{20}},
+		},
+		{
+			name:    "no steps",
+			policy:  model.EscalationPolicy{UserID: 1, Name: "Empty"},
+			wantErr: true,
+		},
+		{
+			name:    "target of another user",
+			policy:  model.EscalationPolicy{UserID: 1, Name: "Nights", Steps: []model.EscalationStep{{NotifierIDs: []int64{3}}}, TargetIDs: []int{30}},
+			wantErr: true,
+		},
+		{
+			name:    "notifier of another user",
+			policy:  model.EscalationPolicy{UserID: 1, Name: "Nights", Steps: []model.EscalationStep{{NotifierIDs: []int64{9}}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := service.Create(tt.policy)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "Nights", policy.Name)
+		})
+	}
+
+	options, err := service.NotifierOptions(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []NotifierOption{
+		{ID: 1, Type: "email", TargetURL: "https://example.com"},
+		{ID: 2, Type: "slack", TargetURL: "https://api.example.com"},
+		{ID: 3, Type: "pagerduty", TargetURL: "https://api.example.com"},
+	}, options)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+)
+
+type IncidentServiceInterface interface {
+	HandleStatusChange(target *monitor.Target, status string) (*model.Incident, error)
+	RecordCheckFailure(target *monitor.Target, result monitor.CheckResult) error
+	RecordNotification(incident *model.Incident, message string) error
+	GetAllByUserID(userID int) ([]model.Incident, error)
+	GetByID(id int) (*model.Incident, error)
+	GetEvents(id int) ([]model.IncidentEvent, error)
+	Acknowledge(id, userID int) error
+	AddUpdate(id, userID int, message string) error
+}
+
+var _ IncidentServiceInterface = (*IncidentService)(nil)
+
+// IncidentService opens an incident when a target starts failing, closes it
+// when the target recovers, and keeps a timeline of what happened between
+type IncidentService struct {
+	repo repository.IncidentRepositoryInterface
+
+	// OnAcknowledge, when set, is called once an incident is acknowledged so
+	// paging services can be told someone is on it
+	OnAcknowledge func(incident *model.Incident) error
+}
+
+func NewIncidentService(repo repository.IncidentRepositoryInterface) *IncidentService {
+	return &IncidentService{repo: repo}
+}
+
+// HandleStatusChange opens, updates or resolves the target's incident for a
+// confirmed status change. It returns the incident the change belongs to,
+// or nil when the target was not failing before or after.
+func (s *IncidentService) HandleStatusChange(target *monitor.Target, status string) (*model.Incident, error) {
+	open, err := s.repo.GetOpenByTargetID(target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !monitor.IsFailing(status) {
+		if open == nil {
+			return nil, nil
+		}
+		if err := s.repo.Resolve(open.ID, now); err != nil {
+			return nil, err
+		}
+		open.ResolvedAt = now
+		return open, s.addEvent(open.ID, model.IncidentResolved,
+			fmt.Sprintf("Target is %s after %s", status, open.Duration(now)), 0)
+	}
+
+	if open != nil {
+		return open, s.addEvent(open.ID, model.IncidentStatus, "Target is "+status, 0)
+	}
+
+	created, err := s.repo.Create(model.Incident{
+		TargetID:  target.ID,
+		TargetURL: target.URL,
+		Cause:     target.StatusReason,
+		StartedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, s.addEvent(created.ID, model.IncidentOpened, "Target is "+status, 0)
+}
+
+// RecordCheckFailure adds a failed check to the target's open incident, if any
+func (s *IncidentService) RecordCheckFailure(target *monitor.Target, result monitor.CheckResult) error {
+	if !monitor.IsFailing(result.Status) {
+		return nil
+	}
+
+	open, err := s.repo.GetOpenByTargetID(target.ID)
+	if err != nil || open == nil {
+		return err
+	}
+
+	message := result.Reason
+	if result.ErrorClass != "" {
+		message = fmt.Sprintf("%s (%s)", message, result.ErrorClass)
+	}
+	_, err = s.repo.AddEvent(model.IncidentEvent{
+		IncidentID: open.ID,
+		Kind:       model.IncidentCheckFailed,
+		Message:    message,
+		CreatedAt:  result.CheckedAt,
+	})
+	return err
+}
+
+// RecordNotification adds a sent alert to the incident's timeline
+func (s *IncidentService) RecordNotification(incident *model.Incident, message string) error {
+	return s.addEvent(incident.ID, model.IncidentNotification, message, 0)
+}
+
+func (s *IncidentService) GetAllByUserID(userID int) ([]model.Incident, error) {
+	return s.repo.GetAllByUserID(userID)
+}
+
+func (s *IncidentService) GetByID(id int) (*model.Incident, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *IncidentService) GetEvents(id int) ([]model.IncidentEvent, error) {
+	return s.repo.GetEvents(id)
+}
+
+// Acknowledge records that the user is handling the incident. Status alerts
+// for the target stay quiet until it recovers.
+func (s *IncidentService) Acknowledge(id, userID int) error {
+	incident, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if incident.IsResolved() {
+		return fmt.Errorf("incident is already resolved")
+	}
+	if incident.IsAcknowledged() {
+		return nil
+	}
+
+	if err := s.repo.Acknowledge(id, userID, time.Now()); err != nil {
+		return err
+	}
+	if err := s.addEvent(id, model.IncidentAcknowledged, "", userID); err != nil {
+		return err
+	}
+
+	if s.OnAcknowledge != nil {
+		// The acknowledgement stands even if a paging service missed it
+		acknowledged, err := s.repo.GetByID(id)
+		if err == nil {
+			err = s.OnAcknowledge(acknowledged)
+		}
+		if err != nil {
+			slog.Error("Failed to pass on acknowledgement", "incident", id, "error", err)
+		}
+	}
+	return nil
+}
+
+// AddUpdate adds a free-text note from the user to the incident's timeline
+func (s *IncidentService) AddUpdate(id, userID int, message string) error {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return fmt.Errorf("update cannot be empty")
+	}
+
+	if _, err := s.repo.GetByID(id); err != nil {
+		return err
+	}
+	return s.addEvent(id, model.IncidentUpdate, message, userID)
+}
+
+func (s *IncidentService) addEvent(incidentID int, kind, message string, userID int) error {
+	_, err := s.repo.AddEvent(model.IncidentEvent{
+		IncidentID: incidentID,
+		Kind:       kind,
+		Message:    message,
+		UserID:     userID,
+		CreatedAt:  time.Now(),
+	})
+	return err
+}
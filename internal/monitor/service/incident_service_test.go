@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockIncidentRepository keeps incidents in memory
+type mockIncidentRepository struct {
+	incidents []*model.Incident
+	events    []model.IncidentEvent
+}
+
+func (m *mockIncidentRepository) Create(incident model.Incident) (model.Incident, error) {
+	incident.ID = len(m.incidents) + 1
+	m.incidents = append(m.incidents, &incident)
+	return incident, nil
+}
+
+func (m *mockIncidentRepository) GetByID(id int) (*model.Incident, error) {
+	for _, incident := range m.incidents {
+		if incident.ID == id {
+			copied := *incident
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrIncidentNotFound
+}
+
+func (m *mockIncidentRepository) GetOpenByTargetID(targetID int) (*model.Incident, error) {
+	for _, incident := range m.incidents {
+		if incident.TargetID == targetID && !incident.IsResolved() {
+			copied := *incident
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockIncidentRepository) GetAllByUserID(userID int) ([]model.Incident, error) {
+	var incidents []model.Incident
+	for _, incident := range m.incidents {
+		incidents = append(incidents, *incident)
+	}
+	return incidents, nil
+}
+
+func (m *mockIncidentRepository) Acknowledge(id, userID int, at time.Time) error {
+	m.incidents[id-1].AcknowledgedAt = at
+	return nil
+}
+
+func (m *mockIncidentRepository) Resolve(id int, at time.Time) error {
+	m.incidents[id-1].ResolvedAt = at
+	return nil
+}
+
+func (m *mockIncidentRepository) AddEvent(event model.IncidentEvent) (model.IncidentEvent, error) {
+	event.ID = len(m.events) + 1
+	m.events = append(m.events, event)
+	return event, nil
+}
+
+func (m *mockIncidentRepository) GetEvents(incidentID int) ([]model.IncidentEvent, error) {
+	var events []model.IncidentEvent
+	for _, event := range m.events {
+		if event.IncidentID == incidentID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func eventKinds(events []model.IncidentEvent) []string {
+	kinds := make([]string, len(events))
+	for i, event := range events {
+		kinds[i] = event.Kind
+	}
+	return kinds
+}
+
+func TestIncidentService_Lifecycle(t *testing.T) {
+	repo := &mockIncidentRepository{}
+	service := NewIncidentService(repo)
+	target := &monitor.Target{ID: 1, URL: "https://example.com", StatusReason: "HTTP error: 503"}
+
+	incident, err := service.HandleStatusChange(target, "up")
+	assert.NoError(t, err)
+	assert.Nil(t, incident, "a healthy target has no incident")
+
+	incident, err = service.HandleStatusChange(target, "down")
+	assert.NoError(t, err)
+	if !assert.NotNil(t, incident) {
+		return
+	}
+	assert.Equal(t, "HTTP error: 503", incident.Cause)
+
+	failed := monitor.CheckResult{TargetID: 1, Status: "down", CheckedAt: time.Now(), Reason: "HTTP error: 503", ErrorClass: "http"}
+	assert.NoError(t, service.RecordCheckFailure(target, failed))
+	assert.NoError(t, service.RecordCheckFailure(target, monitor.CheckResult{TargetID: 1, Status: "degraded"}))
+
+	again, err := service.HandleStatusChange(target, "error")
+	assert.NoError(t, err)
+	assert.Equal(t, incident.ID, again.ID, "a failing target keeps its incident")
+
+	assert.NoError(t, service.RecordNotification(again, "Target https://example.com is error"))
+	assert.NoError(t, service.Acknowledge(incident.ID, 7))
+	assert.NoError(t, service.Acknowledge(incident.ID, 8), "acknowledging twice is harmless")
+	assert.NoError(t, service.AddUpdate(incident.ID, 7, "  Restarting the database  "))
+	assert.Error(t, service.AddUpdate(incident.ID, 7, "   "))
+
+	resolved, err := service.HandleStatusChange(target, "degraded")
+	assert.NoError(t, err)
+	assert.True(t, resolved.IsResolved())
+	assert.Error(t, service.Acknowledge(incident.ID, 7), "resolved incidents cannot be acknowledged")
+
+	events, err := service.GetEvents(incident.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		model.IncidentOpened,
+		model.IncidentCheckFailed,
+		model.IncidentStatus,
+		model.IncidentNotification,
+		model.IncidentAcknowledged,
+		model.IncidentUpdate,
+		model.IncidentResolved,
+	}, eventKinds(events))
+	assert.Equal(t, "HTTP error: 503 (http)", events[1].Message)
+	assert.Equal(t, 7, events[4].UserID)
+	assert.Equal(t, "Restarting the database", events[5].Message)
+
+	t.Run("failures after recovery open a new incident", func(t *testing.T) {
+		next, err := service.HandleStatusChange(target, "down")
+		assert.NoError(t, err)
+		assert.NotEqual(t, incident.ID, next.ID)
+	})
+}
+
+func TestIncidentService_RecordCheckFailureWithoutIncident(t *testing.T) {
+	repo := &mockIncidentRepository{}
+	service := NewIncidentService(repo)
+
+	// Failures before ConfirmAfter is reached have no incident to join
+	err := service.RecordCheckFailure(&monitor.Target{ID: 1}, monitor.CheckResult{TargetID: 1, Status: "down"})
+	assert.NoError(t, err)
+	assert.Empty(t, repo.events)
+}
+
+func TestIncidentService_OnAcknowledge(t *testing.T) {
+	repo := &mockIncidentRepository{}
+	service := NewIncidentService(repo)
+	target := &monitor.Target{ID: 1, URL: "https://example.com"}
+
+	var passedOn []*model.Incident
+	service.OnAcknowledge = func(incident *model.Incident) error {
+		passedOn = append(passedOn, incident)
+		return fmt.Errorf("pagerduty returned non-2xx status code: 500")
+	}
+
+	incident, err := service.HandleStatusChange(target, "down")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, service.Acknowledge(incident.ID, 7), "failing to page does not undo the acknowledgement")
+	assert.NoError(t, service.Acknowledge(incident.ID, 7))
+
+	if assert.Len(t, passedOn, 1, "only the first acknowledgement is passed on") {
+		assert.Equal(t, incident.ID, passedOn[0].ID)
+		assert.True(t, passedOn[0].IsAcknowledged())
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+)
+
+type MaintenanceServiceInterface interface {
+	Create(window model.MaintenanceWindow) (model.MaintenanceWindow, error)
+	GetByTargetID(targetID int) ([]model.MaintenanceWindow, error)
+	Delete(targetID, id int) error
+	IsActive(targetID int, at time.Time) (bool, error)
+}
+
+var _ MaintenanceServiceInterface = (*MaintenanceService)(nil)
+
+// MaintenanceService manages the planned maintenance windows during which
+// a target's status changes are not alerted on
+type MaintenanceService struct {
+	repo repository.MaintenanceRepositoryInterface
+}
+
+func NewMaintenanceService(repo repository.MaintenanceRepositoryInterface) *MaintenanceService {
+	return &MaintenanceService{repo: repo}
+}
+
+func (s *MaintenanceService) Create(window model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+	if err := window.Validate(); err != nil {
+		return model.MaintenanceWindow{}, err
+	}
+	return s.repo.Create(window)
+}
+
+func (s *MaintenanceService) GetByTargetID(targetID int) ([]model.MaintenanceWindow, error) {
+	return s.repo.GetByTargetID(targetID)
+}
+
+func (s *MaintenanceService) Delete(targetID, id int) error {
+	return s.repo.Delete(targetID, id)
+}
+
+// IsActive reports whether any of the target's windows is open at the given time
+func (s *MaintenanceService) IsActive(targetID int, at time.Time) (bool, error) {
+	windows, err := s.repo.GetByTargetID(targetID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, window := range windows {
+		if window.IsActive(at) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
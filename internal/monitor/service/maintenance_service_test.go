@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockMaintenanceRepository keeps windows in memory
+type mockMaintenanceRepository struct {
+	windows []model.MaintenanceWindow
+}
+
+func (m *mockMaintenanceRepository) Create(window model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+	window.ID = len(m.windows) + 1
+	m.windows = append(m.windows, window)
+	return window, nil
+}
+
+func (m *mockMaintenanceRepository) GetByTargetID(targetID int) ([]model.MaintenanceWindow, error) {
+	var windows []model.MaintenanceWindow
+	for _, window := range m.windows {
+		if window.TargetID == targetID {
+			windows = append(windows, window)
+		}
+	}
+	return windows, nil
+}
+
+func (m *mockMaintenanceRepository) Delete(targetID, id int) error {
+	for i, window := range m.windows {
+		if window.ID == id && window.TargetID == targetID {
+			m.windows = append(m.windows[:i], m.windows[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrMaintenanceWindowNotFound
+}
+
+func TestMaintenanceService(t *testing.T) {
+	repo := &mockMaintenanceRepository{}
+	service := NewMaintenanceService(repo)
+	start := time.Date(2025, 5, 13, 22, 0, 0, 0, time.UTC)
+
+	_, err := service.Create(model.MaintenanceWindow{TargetID: 1, Kind: model.MaintenanceOnce, StartsAt: start})
+	assert.Error(t, err, "invalid windows are rejected")
+	assert.Empty(t, repo.windows)
+
+	window, err := service.Create(model.MaintenanceWindow{TargetID: 1, Kind: model.MaintenanceOnce, StartsAt: start, EndsAt: start.Add(time.Hour)})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		targetID int
+		at       time.Time
+		want     bool
+	}{
+		{name: "during window", targetID: 1, at: start.Add(time.Minute), want: true},
+		{name: "after window", targetID: 1, at: start.Add(2 * time.Hour), want: false},
+		{name: "other target", targetID: 2, at: start.Add(time.Minute), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, err := service.IsActive(tt.targetID, tt.at)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, active)
+		})
+	}
+
+	assert.NoError(t, service.Delete(1, window.ID))
+	active, err := service.IsActive(1, start.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, active)
+}
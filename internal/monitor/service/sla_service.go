@@ -0,0 +1,254 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+)
+
+// SLAWindow is the period a report covers, [From, To)
+type SLAWindow struct {
+	Label string
+	From  time.Time
+	To    time.Time
+}
+
+// StandardWindows returns the rolling 24h, 7d, 30d and 90d windows ending at now
+func StandardWindows(now time.Time) []SLAWindow {
+	return []SLAWindow{
+		{Label: "24h", From: now.Add(-24 * time.Hour), To: now},
+		{Label: "7d", From: now.AddDate(0, 0, -7), To: now},
+		{Label: "30d", From: now.AddDate(0, 0, -30), To: now},
+		{Label: "90d", From: now.AddDate(0, 0, -90), To: now},
+	}
+}
+
+// CalendarMonth returns the window covering a whole month in UTC
+func CalendarMonth(year int, month time.Month) SLAWindow {
+	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return SLAWindow{
+		Label: from.Format("2006-01"),
+		From:  from,
+		To:    from.AddDate(0, 1, 0),
+	}
+}
+
+// SLAReport summarises availability over a window
+type SLAReport struct {
+	Window    SLAWindow
+	Checks    int
+	Uptime    float64 // percentage of monitored time the target was available
+	Monitored time.Duration
+	Downtime  time.Duration
+	Incidents int
+	MTTR      time.Duration // mean time to recovery
+	MTBF      time.Duration // mean time between failures
+}
+
+// HasData reports whether any check fell inside the window
+func (r SLAReport) HasData() bool {
+	return r.Checks > 0
+}
+
+// CalculateSLA computes availability from check results ordered oldest first.
+// Each result is taken to hold until the next one, and the last until the end
+// of the window. Degraded checks count as available. The latest result before
+// the window carries its state up to the first check inside it; without one,
+// that time is not counted as monitored. Results recorded during maintenance
+// are left out of the report altogether.
+func CalculateSLA(results []monitor.CheckResult, window SLAWindow) SLAReport {
+	report := SLAReport{Window: window, Uptime: 100}
+
+	var inWindow []monitor.CheckResult
+	var carried *monitor.CheckResult
+	for _, result := range results {
+		if result.CheckedAt.Before(window.From) {
+			carried = &result
+			continue
+		}
+		if !result.CheckedAt.Before(window.To) {
+			continue
+		}
+		inWindow = append(inWindow, result)
+	}
+
+	report.Checks = len(inWindow)
+	if report.Checks == 0 {
+		return report
+	}
+
+	if carried != nil && inWindow[0].CheckedAt.After(window.From) {
+		leading := *carried
+		leading.CheckedAt = window.From
+		inWindow = append([]monitor.CheckResult{leading}, inWindow...)
+	}
+
+	wasUp := true
+	for i, result := range inWindow {
+		end := window.To
+		if i+1 < len(inWindow) {
+			end = inWindow[i+1].CheckedAt
+		}
+		if result.Maintenance {
+			continue
+		}
+		span := end.Sub(result.CheckedAt)
+		report.Monitored += span
+
+		if result.IsAvailable() {
+			wasUp = true
+			continue
+		}
+
+		report.Downtime += span
+		if wasUp {
+			report.Incidents++
+		}
+		wasUp = false
+	}
+
+	if report.Monitored > 0 {
+		report.Uptime = float64(report.Monitored-report.Downtime) / float64(report.Monitored) * 100
+	}
+	if report.Incidents > 0 {
+		report.MTTR = report.Downtime / time.Duration(report.Incidents)
+		report.MTBF = (report.Monitored - report.Downtime) / time.Duration(report.Incidents)
+	}
+
+	return report
+}
+
+// TargetSLA pairs a target with its report for a window
+type TargetSLA struct {
+	Target *monitor.Target
+	Report SLAReport
+}
+
+type SLAServiceInterface interface {
+	GetReport(targetID int, window SLAWindow) (SLAReport, error)
+	GetStandardReports(targetID int, now time.Time) ([]SLAReport, error)
+	GetMonthlyReport(userID int, year int, month time.Month) ([]TargetSLA, error)
+}
+
+var _ SLAServiceInterface = (*SLAService)(nil)
+
+// SLAReportCacheTTL is how long a target's standard reports are reused. The
+// target list shows them for every target, and each reads up to 90 days of
+// check results.
+var SLAReportCacheTTL = 5 * time.Minute
+
+type SLAService struct {
+	targetRepo repository.TargetRepositoryInterface
+	resultRepo repository.CheckResultRepositoryInterface
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[int]cachedReports
+}
+
+// cachedReports are a target's standard reports and when they were computed
+type cachedReports struct {
+	reports []SLAReport
+	at      time.Time
+}
+
+func NewSLAService(
+	targetRepo repository.TargetRepositoryInterface,
+	resultRepo repository.CheckResultRepositoryInterface,
+) *SLAService {
+	return &SLAService{
+		targetRepo: targetRepo,
+		resultRepo: resultRepo,
+		now:        time.Now,
+		cache:      make(map[int]cachedReports),
+	}
+}
+
+// GetReport computes the report for a single window. A window reaching into
+// the future is cut off at the current time.
+func (s *SLAService) GetReport(targetID int, window SLAWindow) (SLAReport, error) {
+	if now := s.now(); window.To.After(now) {
+		window.To = now
+	}
+	if !window.From.Before(window.To) {
+		return SLAReport{Window: window, Uptime: 100}, nil
+	}
+
+	results, err := s.results(targetID, window)
+	if err != nil {
+		return SLAReport{}, err
+	}
+
+	return CalculateSLA(results, window), nil
+}
+
+// GetStandardReports computes the 24h, 7d, 30d and 90d reports from a single
+// query over the longest window. Reports are cached for SLAReportCacheTTL.
+func (s *SLAService) GetStandardReports(targetID int, now time.Time) ([]SLAReport, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[targetID]
+	s.mu.Unlock()
+	if ok && !now.Before(cached.at) && now.Sub(cached.at) < SLAReportCacheTTL {
+		return cached.reports, nil
+	}
+
+	windows := StandardWindows(now)
+	longest := windows[len(windows)-1]
+
+	results, err := s.results(targetID, longest)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]SLAReport, len(windows))
+	for i, window := range windows {
+		reports[i] = CalculateSLA(results, window)
+	}
+
+	s.mu.Lock()
+	s.cache[targetID] = cachedReports{reports: reports, at: now}
+	s.mu.Unlock()
+
+	return reports, nil
+}
+
+// results loads the check results within the window, led by the latest one
+// before it so the state the window opens in is known
+func (s *SLAService) results(targetID int, window SLAWindow) ([]monitor.CheckResult, error) {
+	results, err := s.resultRepo.GetByTargetID(targetID, window.From, window.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check results: %w", err)
+	}
+
+	previous, err := s.resultRepo.GetLatestBefore(targetID, window.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check results: %w", err)
+	}
+	if previous != nil {
+		results = append([]monitor.CheckResult{*previous}, results...)
+	}
+	return results, nil
+}
+
+// GetMonthlyReport computes the calendar month report for every target a user owns
+func (s *SLAService) GetMonthlyReport(userID int, year int, month time.Month) ([]TargetSLA, error) {
+	targets, err := s.targetRepo.GetAllByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get targets: %w", err)
+	}
+
+	window := CalendarMonth(year, month)
+	reports := make([]TargetSLA, 0, len(targets))
+	for _, target := range targets {
+		report, err := s.GetReport(target.ID, window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute report for %s: %w", target.URL, err)
+		}
+		reports = append(reports, TargetSLA{Target: target, Report: report})
+	}
+
+	return reports, nil
+}
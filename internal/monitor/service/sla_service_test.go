@@ -0,0 +1,289 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateSLA(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	window := SLAWindow{Label: "test", From: start, To: start.Add(10 * time.Hour)}
+
+	at := func(hours int) time.Time {
+		return start.Add(time.Duration(hours) * time.Hour)
+	}
+
+	tests := []struct {
+		name          string
+		results       []monitor.CheckResult
+		wantChecks    int
+		wantUptime    float64
+		wantDowntime  time.Duration
+		wantIncidents int
+		wantMTTR      time.Duration
+		wantMTBF      time.Duration
+	}{
+		{
+			name:       "no results",
+			results:    nil,
+			wantChecks: 0,
+			wantUptime: 100,
+		},
+		{
+			name: "always up",
+			results: []monitor.CheckResult{
+				{Status: "up", CheckedAt: at(0)},
+				{Status: "up", CheckedAt: at(5)},
+			},
+			wantChecks: 2,
+			wantUptime: 100,
+		},
+		{
+			name: "two outages",
+			results: []monitor.CheckResult{
+				{Status: "up", CheckedAt: at(0)},
+				{Status: "down", CheckedAt: at(2)},
+				{Status: "up", CheckedAt: at(3)},
+				{Status: "error", CheckedAt: at(6)},
+				{Status: "down", CheckedAt: at(7)},
+				{Status: "up", CheckedAt: at(8)},
+			},
+			wantChecks:    6,
+			wantUptime:    70,
+			wantDowntime:  3 * time.Hour,
+			wantIncidents: 2,
+			wantMTTR:      90 * time.Minute,
+			wantMTBF:      210 * time.Minute,
+		},
+		{
+			name: "results outside the window are ignored",
+			results: []monitor.CheckResult{
+				{Status: "down", CheckedAt: start.Add(-time.Hour)},
+				{Status: "up", CheckedAt: at(0)},
+				{Status: "down", CheckedAt: at(9)},
+				{Status: "down", CheckedAt: at(10)},
+			},
+			wantChecks:    2,
+			wantUptime:    90,
+			wantDowntime:  time.Hour,
+			wantIncidents: 1,
+			wantMTTR:      time.Hour,
+			wantMTBF:      9 * time.Hour,
+		},
+		{
+			name: "degraded counts as available",
+			results: []monitor.CheckResult{
+				{Status: "up", CheckedAt: at(0)},
+				{Status: "degraded", CheckedAt: at(4)},
+				{Status: "up", CheckedAt: at(6)},
+			},
+			wantChecks: 3,
+			wantUptime: 100,
+		},
+		{
+			name: "maintenance is neither monitored nor downtime",
+			results: []monitor.CheckResult{
+				{Status: "up", CheckedAt: at(0)},
+				{Status: "down", CheckedAt: at(2), Maintenance: true},
+				{Status: "down", CheckedAt: at(3), Maintenance: true},
+				{Status: "up", CheckedAt: at(4)},
+				{Status: "down", CheckedAt: at(8)},
+			},
+			wantChecks:    5,
+			wantUptime:    75,
+			wantDowntime:  2 * time.Hour,
+			wantIncidents: 1,
+			wantMTTR:      2 * time.Hour,
+			wantMTBF:      6 * time.Hour,
+		},
+		{
+			name: "state before the window carries into it",
+			results: []monitor.CheckResult{
+				{Status: "down", CheckedAt: start.Add(-time.Hour)},
+				{Status: "up", CheckedAt: at(1)},
+			},
+			wantChecks:    1,
+			wantUptime:    90,
+			wantDowntime:  time.Hour,
+			wantIncidents: 1,
+			wantMTTR:      time.Hour,
+			wantMTBF:      9 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CalculateSLA(tt.results, window)
+			assert.Equal(t, tt.wantChecks, report.Checks)
+			assert.InDelta(t, tt.wantUptime, report.Uptime, 0.0001)
+			assert.Equal(t, tt.wantDowntime, report.Downtime)
+			assert.Equal(t, tt.wantIncidents, report.Incidents)
+			assert.Equal(t, tt.wantMTTR, report.MTTR)
+			assert.Equal(t, tt.wantMTBF, report.MTBF)
+		})
+	}
+}
+
+func TestCalendarMonth(t *testing.T) {
+	window := CalendarMonth(2024, time.February)
+	assert.Equal(t, "2024-02", window.Label)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), window.From)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), window.To)
+}
+
+func TestSLAService_GetStandardReports(t *testing.T) {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	queries := 0
+	resultRepo := &mockCheckResultRepository{
+		getByTargetIDFunc: func(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+			queries++
+			assert.Equal(t, now.AddDate(0, 0, -90), from)
+			assert.Equal(t, now, to)
+			return []monitor.CheckResult{
+				{Status: "up", CheckedAt: now.AddDate(0, 0, -60)},
+				{Status: "down", CheckedAt: now.Add(-2 * time.Hour)},
+				{Status: "up", CheckedAt: now.Add(-time.Hour)},
+			}, nil
+		},
+	}
+	service := NewSLAService(&mockTargetRepository{}, resultRepo)
+
+	reports, err := service.GetStandardReports(1, now)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 4)
+
+	labels := []string{"24h", "7d", "30d", "90d"}
+	for i, report := range reports {
+		assert.Equal(t, labels[i], report.Window.Label)
+		assert.Equal(t, time.Hour, report.Downtime)
+	}
+	assert.Equal(t, 2, reports[0].Checks)
+	assert.Equal(t, 3, reports[3].Checks)
+	assert.InDelta(t, 100*23.0/24.0, reports[0].Uptime, 0.0001)
+
+	cached, err := service.GetStandardReports(1, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, reports, cached)
+	assert.Equal(t, 1, queries, "reports are reused within the cache TTL")
+
+	now = now.Add(SLAReportCacheTTL)
+	_, err = service.GetStandardReports(1, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, queries, "stale reports are computed again")
+}
+
+func TestSLAService_GetMonthlyReport(t *testing.T) {
+	targetRepo := &mockTargetRepository{
+		getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
+			return []*monitor.Target{
+				{ID: 1, URL: "https://one.example.com"},
+				{ID: 2, URL: "https://two.example.com"},
+			}, nil
+		},
+	}
+	month := CalendarMonth(2025, time.January)
+	resultRepo := &mockCheckResultRepository{
+		getByTargetIDFunc: func(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+			assert.Equal(t, month.From, from)
+			assert.Equal(t, month.To, to)
+			if targetID == 2 {
+				return []monitor.CheckResult{
+					{Status: "up", CheckedAt: month.From},
+					{Status: "down", CheckedAt: month.To.Add(-31 * time.Hour)},
+				}, nil
+			}
+			return []monitor.CheckResult{{Status: "up", CheckedAt: month.From}}, nil
+		},
+	}
+
+	service := NewSLAService(targetRepo, resultRepo)
+	service.now = func() time.Time { return time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC) }
+
+	t.Run("reports every target", func(t *testing.T) {
+		reports, err := service.GetMonthlyReport(1, 2025, time.January)
+		assert.NoError(t, err)
+		assert.Len(t, reports, 2)
+
+		assert.Equal(t, 1, reports[0].Target.ID)
+		assert.InDelta(t, 100, reports[0].Report.Uptime, 0.0001)
+
+		assert.Equal(t, 31*time.Hour, reports[1].Report.Downtime)
+		assert.Equal(t, 1, reports[1].Report.Incidents)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		resultRepo.getByTargetIDFunc = func(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+			return nil, fmt.Errorf("database error")
+		}
+		_, err := service.GetMonthlyReport(1, 2025, time.January)
+		assert.Error(t, err)
+	})
+}
+
+func TestSLAService_GetReport_ClampsFutureWindow(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	resultRepo := &mockCheckResultRepository{
+		getByTargetIDFunc: func(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+			assert.Equal(t, now, to)
+			return []monitor.CheckResult{{Status: "down", CheckedAt: now.Add(-24 * time.Hour)}}, nil
+		},
+	}
+	service := NewSLAService(&mockTargetRepository{}, resultRepo)
+	service.now = func() time.Time { return now }
+
+	report, err := service.GetReport(1, CalendarMonth(2025, time.March))
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, report.Downtime)
+}
+
+func TestSLAService_GetReport_StartsMidOutage(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	stored := []monitor.CheckResult{
+		{Status: "down", CheckedAt: now.Add(-30 * time.Hour)},
+		{Status: "up", CheckedAt: now.Add(-22 * time.Hour)},
+	}
+	resultRepo := &mockCheckResultRepository{
+		getByTargetIDFunc: func(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+			var results []monitor.CheckResult
+			for _, result := range stored {
+				if !result.CheckedAt.Before(from) && result.CheckedAt.Before(to) {
+					results = append(results, result)
+				}
+			}
+			return results, nil
+		},
+		getLatestBeforeFunc: func(targetID int, before time.Time) (*monitor.CheckResult, error) {
+			var latest *monitor.CheckResult
+			for _, result := range stored {
+				if result.CheckedAt.Before(before) {
+					latest = &result
+				}
+			}
+			return latest, nil
+		},
+	}
+	service := NewSLAService(&mockTargetRepository{}, resultRepo)
+	service.now = func() time.Time { return now }
+
+	window := StandardWindows(now)[0]
+	report, err := service.GetReport(1, window)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Checks)
+	assert.Equal(t, 2*time.Hour, report.Downtime, "the outage under way when the window opens counts")
+	assert.Equal(t, 24*time.Hour, report.Monitored)
+	assert.Equal(t, 1, report.Incidents)
+
+	standard, err := service.GetStandardReports(1, now)
+	assert.NoError(t, err)
+	assert.Equal(t, standard[0], report, "both paths agree on the same window")
+
+	resultRepo.getLatestBeforeFunc = func(targetID int, before time.Time) (*monitor.CheckResult, error) {
+		return nil, fmt.Errorf("database error")
+	}
+	_, err = service.GetReport(1, window)
+	assert.Error(t, err)
+}
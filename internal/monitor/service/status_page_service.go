@@ -0,0 +1,351 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+)
+
+// Statuses shown on public status pages, from best to worst
+const (
+	PublicStatusUnknown     = "unknown"
+	PublicStatusOperational = "operational"
+	PublicStatusMaintenance = "maintenance"
+	PublicStatusDegraded    = "degraded"
+	PublicStatusOutage      = "outage"
+)
+
+var publicStatusRank = map[string]int{
+	PublicStatusUnknown:     0,
+	PublicStatusOperational: 1,
+	PublicStatusMaintenance: 2,
+	PublicStatusDegraded:    3,
+	PublicStatusOutage:      4,
+}
+
+// StatusPageDays is how many days of uptime history a status page shows
+const StatusPageDays = 90
+
+// StatusPageCacheTTL is how long a rendered status page is reused. Status
+// pages are public, so this bounds the load a busy page puts on the database.
+var StatusPageCacheTTL = time.Minute
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// PublicStatusPage is what visitors of a status page see
+type PublicStatusPage struct {
+	Title      string
+	Status     string // worst status of any component
+	Components []PublicComponent
+	Incidents  []PublicIncident
+	UpdatedAt  time.Time
+}
+
+// PublicComponent groups the targets a page shows under one name
+type PublicComponent struct {
+	Name    string
+	Status  string // worst status of any target in the component
+	Targets []PublicTarget
+}
+
+// PublicTarget is a target as shown on a status page
+type PublicTarget struct {
+	Name   string
+	Status string
+	Uptime SLAReport   // over the whole history shown
+	Days   []SLAReport // one per day, oldest first
+}
+
+// PublicIncident is an ongoing incident on one of a page's targets, with the
+// updates posted to it
+type PublicIncident struct {
+	Name      string
+	StartedAt time.Time
+	Updates   []model.IncidentEvent
+}
+
+type StatusPageServiceInterface interface {
+	Create(page model.StatusPage) (model.StatusPage, error)
+	GetByID(id int) (*model.StatusPage, error)
+	GetAllByUserID(userID int) ([]model.StatusPage, error)
+	Update(page model.StatusPage) error
+	Delete(id int) error
+	GetPublic(slug string) (*PublicStatusPage, error)
+}
+
+var _ StatusPageServiceInterface = (*StatusPageService)(nil)
+
+type StatusPageService struct {
+	repo         repository.StatusPageRepositoryInterface
+	targetRepo   repository.TargetRepositoryInterface
+	resultRepo   repository.CheckResultRepositoryInterface
+	incidentRepo repository.IncidentRepositoryInterface
+	maintenance  MaintenanceServiceInterface
+	now          func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*PublicStatusPage
+}
+
+func NewStatusPageService(
+	repo repository.StatusPageRepositoryInterface,
+	targetRepo repository.TargetRepositoryInterface,
+	resultRepo repository.CheckResultRepositoryInterface,
+	incidentRepo repository.IncidentRepositoryInterface,
+	maintenance MaintenanceServiceInterface,
+) *StatusPageService {
+	return &StatusPageService{
+		repo:         repo,
+		targetRepo:   targetRepo,
+		resultRepo:   resultRepo,
+		incidentRepo: incidentRepo,
+		maintenance:  maintenance,
+		now:          time.Now,
+		cache:        make(map[string]*PublicStatusPage),
+	}
+}
+
+func (s *StatusPageService) Create(page model.StatusPage) (model.StatusPage, error) {
+	if err := s.validate(&page); err != nil {
+		return model.StatusPage{}, err
+	}
+	return s.repo.Create(page)
+}
+
+func (s *StatusPageService) GetByID(id int) (*model.StatusPage, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *StatusPageService) GetAllByUserID(userID int) ([]model.StatusPage, error) {
+	return s.repo.GetAllByUserID(userID)
+}
+
+func (s *StatusPageService) Update(page model.StatusPage) error {
+	if err := s.validate(&page); err != nil {
+		return err
+	}
+	if err := s.repo.Update(page); err != nil {
+		return err
+	}
+	s.clearCache()
+	return nil
+}
+
+func (s *StatusPageService) Delete(id int) error {
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.clearCache()
+	return nil
+}
+
+// validate checks the page can be published, and that it only shows targets
+// owned by the page's user
+func (s *StatusPageService) validate(page *model.StatusPage) error {
+	page.Title = strings.TrimSpace(page.Title)
+	page.Slug = strings.ToLower(strings.TrimSpace(page.Slug))
+
+	if page.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(page.Slug) > 64 || !slugPattern.MatchString(page.Slug) {
+		return fmt.Errorf("slug may only contain lowercase letters, digits and dashes")
+	}
+
+	existing, err := s.repo.GetBySlug(page.Slug)
+	if err != nil && err != repository.ErrStatusPageNotFound {
+		return err
+	}
+	if existing != nil && existing.ID != page.ID {
+		return fmt.Errorf("slug %q is already taken", page.Slug)
+	}
+
+	owned, err := s.targetRepo.GetAllByUserID(page.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get targets: %w", err)
+	}
+	for i, target := range page.Targets {
+		if !ownsTarget(owned, target.TargetID) {
+			return fmt.Errorf("target %d not found", target.TargetID)
+		}
+		page.Targets[i].Name = strings.TrimSpace(target.Name)
+		page.Targets[i].Component = strings.TrimSpace(target.Component)
+	}
+
+	return nil
+}
+
+func ownsTarget(targets []*monitor.Target, id int) bool {
+	for _, target := range targets {
+		if target.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPublic builds the public view of the page with the given slug. Views
+// are cached for StatusPageCacheTTL.
+func (s *StatusPageService) GetPublic(slug string) (*PublicStatusPage, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[slug]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.UpdatedAt) < StatusPageCacheTTL {
+		return cached, nil
+	}
+
+	page, err := s.repo.GetBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := s.buildPublic(page, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[slug] = view
+	s.mu.Unlock()
+
+	return view, nil
+}
+
+func (s *StatusPageService) buildPublic(page *model.StatusPage, now time.Time) (*PublicStatusPage, error) {
+	view := &PublicStatusPage{
+		Title:     page.Title,
+		Status:    PublicStatusUnknown,
+		UpdatedAt: now,
+	}
+
+	days := dailyWindows(now, StatusPageDays)
+	history := SLAWindow{Label: fmt.Sprintf("%dd", StatusPageDays), From: days[0].From, To: now}
+
+	for _, entry := range page.Targets {
+		target, err := s.targetRepo.GetByID(entry.TargetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get target %d: %w", entry.TargetID, err)
+		}
+
+		results, err := s.resultRepo.GetByTargetID(target.ID, history.From, history.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get check results: %w", err)
+		}
+
+		inMaintenance, err := s.maintenance.IsActive(target.ID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check maintenance windows: %w", err)
+		}
+
+		name := entry.Name
+		if name == "" {
+			name = target.URL
+		}
+
+		shown := PublicTarget{
+			Name:   name,
+			Status: publicStatus(target, inMaintenance),
+			Uptime: CalculateSLA(results, history),
+			Days:   make([]SLAReport, len(days)),
+		}
+		for i, day := range days {
+			shown.Days[i] = CalculateSLA(results, day)
+		}
+
+		component := view.component(entry.Component)
+		component.Targets = append(component.Targets, shown)
+		component.Status = worsePublicStatus(component.Status, shown.Status)
+		view.Status = worsePublicStatus(view.Status, shown.Status)
+
+		incident, err := s.incidentRepo.GetOpenByTargetID(target.ID)
+		if err != nil {
+			return nil, err
+		}
+		if incident == nil {
+			continue
+		}
+
+		events, err := s.incidentRepo.GetEvents(incident.ID)
+		if err != nil {
+			return nil, err
+		}
+		public := PublicIncident{Name: name, StartedAt: incident.StartedAt}
+		for _, event := range events {
+			// Only notes written by people are meant for visitors
+			if event.Kind == model.IncidentUpdate {
+				public.Updates = append(public.Updates, event)
+			}
+		}
+		view.Incidents = append(view.Incidents, public)
+	}
+
+	return view, nil
+}
+
+// component returns the named component, adding it after the existing ones
+// the first time it is seen
+func (p *PublicStatusPage) component(name string) *PublicComponent {
+	for i := range p.Components {
+		if p.Components[i].Name == name {
+			return &p.Components[i]
+		}
+	}
+	p.Components = append(p.Components, PublicComponent{Name: name, Status: PublicStatusUnknown})
+	return &p.Components[len(p.Components)-1]
+}
+
+// publicStatus maps a target's status to the one visitors see. Maintenance
+// hides whatever the checks report.
+func publicStatus(target *monitor.Target, inMaintenance bool) string {
+	switch {
+	case !target.Enabled:
+		return PublicStatusUnknown
+	case inMaintenance:
+		return PublicStatusMaintenance
+	case target.Status == "up":
+		return PublicStatusOperational
+	case target.Status == "degraded":
+		return PublicStatusDegraded
+	case monitor.IsFailing(target.Status):
+		return PublicStatusOutage
+	default:
+		return PublicStatusUnknown
+	}
+}
+
+func worsePublicStatus(a, b string) string {
+	if publicStatusRank[b] > publicStatusRank[a] {
+		return b
+	}
+	return a
+}
+
+// dailyWindows returns one window per UTC day for the last n days, oldest
+// first. Today's window ends at now.
+func dailyWindows(now time.Time, n int) []SLAWindow {
+	today := now.UTC().Truncate(24 * time.Hour)
+	windows := make([]SLAWindow, n)
+	for i := range windows {
+		from := today.AddDate(0, 0, i-n+1)
+		to := from.AddDate(0, 0, 1)
+		if to.After(now) {
+			to = now
+		}
+		windows[i] = SLAWindow{Label: from.Format(time.DateOnly), From: from, To: to}
+	}
+	return windows
+}
+
+func (s *StatusPageService) clearCache() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = make(map[string]*PublicStatusPage)
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockStatusPageRepository keeps pages in memory and counts slug lookups
+type mockStatusPageRepository struct {
+	pages   []model.StatusPage
+	lookups int
+}
+
+func (m *mockStatusPageRepository) Create(page model.StatusPage) (model.StatusPage, error) {
+	page.ID = len(m.pages) + 1
+	m.pages = append(m.pages, page)
+	return page, nil
+}
+
+func (m *mockStatusPageRepository) GetByID(id int) (*model.StatusPage, error) {
+	for _, page := range m.pages {
+		if page.ID == id {
+			return &page, nil
+		}
+	}
+	return nil, repository.ErrStatusPageNotFound
+}
+
+func (m *mockStatusPageRepository) GetBySlug(slug string) (*model.StatusPage, error) {
+	m.lookups++
+	for _, page := range m.pages {
+		if page.Slug == slug {
+			return &page, nil
+		}
+	}
+	return nil, repository.ErrStatusPageNotFound
+}
+
+func (m *mockStatusPageRepository) GetAllByUserID(userID int) ([]model.StatusPage, error) {
+	return m.pages, nil
+}
+
+func (m *mockStatusPageRepository) Update(page model.StatusPage) error {
+	m.pages[page.ID-1] = page
+	return nil
+}
+
+func (m *mockStatusPageRepository) Delete(id int) error {
+	return nil
+}
+
+func TestStatusPageService_Create(t *testing.T) {
+	repo := &mockStatusPageRepository{pages: []model.StatusPage{{ID: 1, UserID: 2, Title: "Taken", Slug: "taken"}}}
+	targetRepo := &mockTargetRepository{
+		getAllByUserIDFunc: func(userID int) ([]*monitor.Target, error) {
+			return []*monitor.Target{{ID: 10}, {ID: 11}}, nil
+		},
+	}
+	service := NewStatusPageService(repo, targetRepo, &mockCheckResultRepository{}, &mockIncidentRepository{}, NewMaintenanceService(&mockMaintenanceRepository{}))
+
+	tests := []struct {
+		name    string
+		page    model.StatusPage
+		wantErr bool
+	}{
+		{
+			name: "valid page",
+			page: model.StatusPage{UserID: 1, Title: " Example ", Slug: "Example-Status", Targets: []model.StatusPageTarget{{TargetID: 10, Name: " API "}}},
+		},
+		{
+			name:    "missing title",
+			page:    model.StatusPage{UserID: 1, Slug: "untitled"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid slug",
+			page:    model.StatusPage{UserID: 1, Title: "Example", Slug: "example/status"},
+			wantErr: true,
+		},
+		{
+			name:    "slug taken",
+			page:    model.StatusPage{UserID: 1, Title: "Example", Slug: "taken"},
+			wantErr: true,
+		},
+		{
+			name:    "target of another user",
+			page:    model.StatusPage{UserID: 1, Title: "Example", Slug: "other", Targets: []model.StatusPageTarget{{TargetID: 99}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := service.Create(tt.page)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "Example", created.Title)
+			assert.Equal(t, "example-status", created.Slug)
+			assert.Equal(t, "API", created.Targets[0].Name)
+		})
+	}
+}
+
+func TestStatusPageService_GetPublic(t *testing.T) {
+	now := time.Date(2025, 5, 18, 12, 0, 0, 0, time.UTC)
+
+	repo := &mockStatusPageRepository{pages: []model.StatusPage{{
+		ID:    1,
+		Title: "Example status",
+		Slug:  "example",
+		Targets: []model.StatusPageTarget{
+			{TargetID: 1, Component: "Backend", Name: "API"},
+			{TargetID: 2, Component: "Frontend"},
+			{TargetID: 3, Component: "Backend", Name: "Workers"},
+		},
+	}}}
+
+	targets := map[int]*monitor.Target{
+		1: {ID: 1, URL: "https://api.example.com", Status: "down", Enabled: true},
+		2: {ID: 2, URL: "https://www.example.com", Status: "up", Enabled: true},
+		3: {ID: 3, URL: "https://jobs.example.com", Status: "down", Enabled: true},
+	}
+	targetRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (*monitor.Target, error) {
+			return targets[id], nil
+		},
+	}
+
+	resultRepo := &mockCheckResultRepository{
+		getByTargetIDFunc: func(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+			assert.Equal(t, now.AddDate(0, 0, -StatusPageDays+1).Truncate(24*time.Hour), from)
+			if targetID != 1 {
+				return []monitor.CheckResult{{Status: "up", CheckedAt: from}}, nil
+			}
+			// Down since 06:00 today
+			return []monitor.CheckResult{
+				{Status: "up", CheckedAt: from},
+				{Status: "down", CheckedAt: now.Add(-6 * time.Hour)},
+			}, nil
+		},
+	}
+
+	incidentRepo := &mockIncidentRepository{}
+	incident, _ := incidentRepo.Create(model.Incident{TargetID: 1, StartedAt: now.Add(-6 * time.Hour)})
+	incidentRepo.AddEvent(model.IncidentEvent{IncidentID: incident.ID, Kind: model.IncidentOpened, Message: "HTTP error: 502"})
+	incidentRepo.AddEvent(model.IncidentEvent{IncidentID: incident.ID, Kind: model.IncidentUpdate, Message: "We are rolling back the deploy"})
+
+	maintenanceRepo := &mockMaintenanceRepository{}
+	maintenance := NewMaintenanceService(maintenanceRepo)
+	_, err := maintenance.Create(model.MaintenanceWindow{TargetID: 3, Kind: model.MaintenanceOnce, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+	assert.NoError(t, err)
+
+	service := NewStatusPageService(repo, targetRepo, resultRepo, incidentRepo, maintenance)
+	service.now = func() time.Time { return now }
+
+	page, err := service.GetPublic("example")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "Example status", page.Title)
+	assert.Equal(t, PublicStatusOutage, page.Status)
+	if assert.Len(t, page.Components, 2) {
+		backend := page.Components[0]
+		assert.Equal(t, "Backend", backend.Name)
+		assert.Equal(t, PublicStatusOutage, backend.Status)
+		assert.Equal(t, []string{"API", "Workers"}, []string{backend.Targets[0].Name, backend.Targets[1].Name})
+		assert.Equal(t, PublicStatusMaintenance, backend.Targets[1].Status)
+
+		frontend := page.Components[1]
+		assert.Equal(t, PublicStatusOperational, frontend.Status)
+		assert.Equal(t, "https://www.example.com", frontend.Targets[0].Name, "the URL is shown when the target has no name")
+	}
+
+	api := page.Components[0].Targets[0]
+	if assert.Len(t, api.Days, StatusPageDays) {
+		assert.Equal(t, "2025-05-18", api.Days[StatusPageDays-1].Window.Label)
+		assert.InDelta(t, 50, api.Days[StatusPageDays-1].Uptime, 0.0001)
+		assert.InDelta(t, 100, api.Days[0].Uptime, 0.0001)
+	}
+
+	if assert.Len(t, page.Incidents, 1) {
+		assert.Equal(t, "API", page.Incidents[0].Name)
+		if assert.Len(t, page.Incidents[0].Updates, 1) {
+			assert.Equal(t, "We are rolling back the deploy", page.Incidents[0].Updates[0].Message)
+		}
+	}
+
+	t.Run("views are cached", func(t *testing.T) {
+		lookups := repo.lookups
+		_, err := service.GetPublic("example")
+		assert.NoError(t, err)
+		assert.Equal(t, lookups, repo.lookups)
+
+		service.now = func() time.Time { return now.Add(StatusPageCacheTTL) }
+		_, err = service.GetPublic("example")
+		assert.NoError(t, err)
+		assert.Equal(t, lookups+1, repo.lookups)
+	})
+
+	t.Run("unknown slug", func(t *testing.T) {
+		_, err := service.GetPublic("missing")
+		assert.ErrorIs(t, err, repository.ErrStatusPageNotFound)
+	})
+}
+
+func TestPublicStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		target        *monitor.Target
+		inMaintenance bool
+		want          string
+	}{
+		{name: "up", target: &monitor.Target{Status: "up", Enabled: true}, want: PublicStatusOperational},
+		{name: "degraded", target: &monitor.Target{Status: "degraded", Enabled: true}, want: PublicStatusDegraded},
+		{name: "error", target: &monitor.Target{Status: "error", Enabled: true}, want: PublicStatusOutage},
+		{name: "pending", target: &monitor.Target{Status: "pending", Enabled: true}, want: PublicStatusUnknown},
+		{name: "paused", target: &monitor.Target{Status: "down"}, want: PublicStatusUnknown},
+		{name: "maintenance", target: &monitor.Target{Status: "down", Enabled: true}, inMaintenance: true, want: PublicStatusMaintenance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, publicStatus(tt.target, tt.inMaintenance))
+		})
+	}
+}
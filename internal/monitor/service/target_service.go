@@ -2,8 +2,11 @@ package service
 
 import (
 	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
 	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
 	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
@@ -12,28 +15,56 @@ import (
 )
 
 type TargetServiceInterface interface {
-	Create(userID int, url string, interval time.Duration) (*monitor.Target, error)
+	Create(userID int, target *monitor.Target) (*monitor.Target, error)
 	GetByID(id int) (*monitor.Target, error)
 	GetAll() ([]*monitor.Target, error)
 	GetAllByUserID(userID int) ([]*monitor.Target, error)
 	Update(*monitor.Target) (*monitor.Target, error)
 	Delete(id int) error
+	SetEnabled(id int, enabled bool) (*monitor.Target, error)
 	InitializeMonitoring() error
+	GetCheckResults(targetID int, from, to time.Time) ([]monitor.CheckResult, error)
+	GetCertificate(targetID int) (*model.TargetCertificate, error)
+	Ping(token, event string, exitCode int) error
 }
 
 var _ TargetServiceInterface = (*TargetService)(nil)
 
 type TargetService struct {
 	repo            repository.TargetRepositoryInterface
+	resultRepo      repository.CheckResultRepositoryInterface
+	certRepo        repository.CertificateRepositoryInterface
+	answerRepo      repository.DNSAnswerRepositoryInterface
+	incidents       IncidentServiceInterface
+	maintenance     MaintenanceServiceInterface
 	manager         *monitor.Manager
 	notifierService alertService.NotifierServiceInterface
+
+	mu sync.Mutex
+	// deferred holds the last status change each target went through during
+	// a maintenance window, announced once the window has closed
+	deferred map[int]string
 }
 
-func NewTargetService(repo repository.TargetRepositoryInterface, notifierService alertService.NotifierServiceInterface) *TargetService {
+func NewTargetService(
+	repo repository.TargetRepositoryInterface,
+	resultRepo repository.CheckResultRepositoryInterface,
+	certRepo repository.CertificateRepositoryInterface,
+	answerRepo repository.DNSAnswerRepositoryInterface,
+	incidents IncidentServiceInterface,
+	maintenance MaintenanceServiceInterface,
+	notifierService alertService.NotifierServiceInterface,
+) *TargetService {
 	return &TargetService{
 		repo:            repo,
+		resultRepo:      resultRepo,
+		certRepo:        certRepo,
+		answerRepo:      answerRepo,
+		incidents:       incidents,
+		maintenance:     maintenance,
 		manager:         monitor.NewManager(),
 		notifierService: notifierService,
+		deferred:        make(map[int]string),
 	}
 }
 
@@ -42,34 +73,282 @@ func (s *TargetService) handleStatusUpdate(target *monitor.Target, status string
 		return err
 	}
 
-	if err := s.notifierService.ConfigureObservers(target.ID); err != nil {
-		return fmt.Errorf("failed to configure observers: %w", err)
+	if s.inMaintenance(target, time.Now()) {
+		s.mu.Lock()
+		s.deferred[target.ID] = status
+		s.mu.Unlock()
+		return nil
+	}
+
+	s.mu.Lock()
+	delete(s.deferred, target.ID)
+	s.mu.Unlock()
+
+	return s.announceStatus(target, status, false)
+}
+
+// announceStatus records a status change on the target's incident and
+// notifies about it. A change deferred by maintenance that ended healthy
+// without an incident to resolve is not worth announcing.
+func (s *TargetService) announceStatus(target *monitor.Target, status string, deferred bool) error {
+	incident, err := s.incidents.HandleStatusChange(target, status)
+	if err != nil {
+		slog.Error("Failed to track incident", "Target", target.URL, "error", err)
+	}
+	if deferred && incident == nil && !monitor.IsFailing(status) {
+		return nil
+	}
+	if incident != nil && incident.IsAcknowledged() && !incident.IsResolved() {
+		// Someone is already on it; the recovery is still announced
+		return nil
 	}
 
+	message := statusMessage(target, status)
 	state := notifCore.State{
-		Name:      target.URL,
-		Status:    status,
-		UpdatedAt: time.Now(),
-		Message:   fmt.Sprintf("Target %s is %s", target.URL, status),
+		Name:           target.URL,
+		Status:         status,
+		UpdatedAt:      time.Now(),
+		Message:        message,
+		PreviousStatus: target.PreviousStatus,
+		Reason:         target.StatusReason,
+		Latency:        target.LastLatency,
+	}
+	if incident != nil {
+		state.IncidentID = incident.ID
+		if monitor.IsFailing(status) {
+			state.OutageStart = incident.StartedAt
+		}
+	}
+	if err := s.notify(target, state); err != nil {
+		return err
 	}
 
-	s.notifierService.GetSubject().Notify(state)
+	if incident != nil {
+		if err := s.incidents.RecordNotification(incident, message); err != nil {
+			slog.Error("Failed to record notification", "Target", target.URL, "error", err)
+		}
+	}
+	return nil
+}
+
+// HandleIncidentAcknowledged tells the notifiers of the incident's target
+// that track incidents, such as paging services, that someone is handling it
+func (s *TargetService) HandleIncidentAcknowledged(incident *model.Incident) error {
+	message := fmt.Sprintf("Incident #%d acknowledged", incident.ID)
+	if incident.AcknowledgedBy != "" {
+		message += " by " + incident.AcknowledgedBy
+	}
 
+	err := s.notifierService.EnqueueAcknowledgement(incident.TargetID, notifCore.State{
+		Name:       incident.TargetURL,
+		Message:    message,
+		UpdatedAt:  incident.AcknowledgedAt,
+		TargetID:   incident.TargetID,
+		IncidentID: incident.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue acknowledgement: %w", err)
+	}
 	return nil
 }
 
-func (s *TargetService) Create(userID int, url string, interval time.Duration) (*monitor.Target, error) {
+// notify queues state for delivery to the target's notifiers
+func (s *TargetService) notify(target *monitor.Target, state notifCore.State) error {
+	state.TargetID = target.ID
+	if err := s.notifierService.Enqueue(target.ID, state); err != nil {
+		return fmt.Errorf("failed to queue notification: %w", err)
+	}
+	return nil
+}
+
+// statusMessage describes a status change, including why the check failed
+// when the probe reported a reason
+func statusMessage(target *monitor.Target, status string) string {
+	message := fmt.Sprintf("Target %s is %s", target.URL, status)
+	if target.StatusReason != "" {
+		message += ": " + target.StatusReason
+	}
+	return message
+}
+
+// inMaintenance reports whether one of the target's maintenance windows is
+// open. Alerts go out as usual if the windows cannot be loaded.
+func (s *TargetService) inMaintenance(target *monitor.Target, at time.Time) bool {
+	active, err := s.maintenance.IsActive(target.ID, at)
+	if err != nil {
+		slog.Error("Failed to check maintenance windows", "Target", target.URL, "error", err)
+		return false
+	}
+	return active
+}
+
+// announceDeferred sends the status change a target went through during a
+// maintenance window that has since closed
+func (s *TargetService) announceDeferred(target *monitor.Target) {
+	s.mu.Lock()
+	status, ok := s.deferred[target.ID]
+	delete(s.deferred, target.ID)
+	s.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	if err := s.announceStatus(target, status, true); err != nil {
+		slog.Error("Failed to announce status after maintenance", "Target", target.URL, "error", err)
+	}
+}
+
+func (s *TargetService) handleCheckResult(target *monitor.Target, result monitor.CheckResult) {
+	result.Maintenance = s.inMaintenance(target, result.CheckedAt)
+	if _, err := s.resultRepo.Create(result); err != nil {
+		slog.Error("Failed to persist check result", "Target", target.URL, "error", err)
+	}
+
+	if !result.Maintenance {
+		s.announceDeferred(target)
+	}
+
+	if err := s.incidents.RecordCheckFailure(target, result); err != nil {
+		slog.Error("Failed to record check failure", "Target", target.URL, "error", err)
+	}
+
+	if result.TLS != nil {
+		if err := s.handleCertificate(target, *result.TLS); err != nil {
+			slog.Error("Failed to process certificate", "Target", target.URL, "error", err)
+		}
+	}
+
+	if result.DNS != nil {
+		if err := s.handleDNSAnswer(target, *result.DNS); err != nil {
+			slog.Error("Failed to process DNS answer", "Target", target.URL, "error", err)
+		}
+	}
+}
+
+// StatusDNSChanged is the notification status sent when a DNS target
+// resolves to a different answer than before
+const StatusDNSChanged = "dns_changed"
+
+// handleDNSAnswer stores the resolved answer and notifies when it differs
+// from the previous answer for the same record type. A change during
+// maintenance keeps the answer from before the window, so the first answer
+// after it is compared with that one.
+func (s *TargetService) handleDNSAnswer(target *monitor.Target, answer monitor.DNSAnswer) error {
+	previous, err := s.answerRepo.GetByTargetID(target.ID)
+	if err != nil {
+		return err
+	}
+
+	current := model.TargetDNSAnswer{
+		TargetID:   target.ID,
+		RecordType: target.DNS.GetRecordType(),
+		DNSAnswer:  answer,
+	}
+	changed := previous != nil && previous.RecordType == current.RecordType && !previous.Equal(answer)
+	if changed && s.inMaintenance(target, answer.ResolvedAt) {
+		return nil
+	}
+
+	if err := s.answerRepo.Save(current); err != nil {
+		return err
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return s.notify(target, notifCore.State{
+		Name:      target.URL,
+		Status:    StatusDNSChanged,
+		UpdatedAt: answer.ResolvedAt,
+		Message: fmt.Sprintf("%s records for %s changed from %s to %s",
+			current.RecordType, target.URL, previous.DNSAnswer, answer),
+	})
+}
+
+// handleCertificate stores the inspected certificate and sends a warning the
+// first time its remaining lifetime falls within each expiry threshold.
+// Warnings due during maintenance are sent after the window closes.
+func (s *TargetService) handleCertificate(target *monitor.Target, info monitor.TLSInfo) error {
+	previous, err := s.certRepo.GetByTargetID(target.ID)
+	if err != nil {
+		return err
+	}
+
+	cert := model.TargetCertificate{TargetID: target.ID, TLSInfo: info}
+	if previous != nil {
+		cert.NotifiedDays = previous.NotifiedDays
+	}
+
+	days := info.DaysRemaining(info.CheckedAt)
+	threshold := expiryThreshold(days, target.TLS.GetExpiryDays())
+	shouldNotify := threshold > 0 && (cert.NotifiedDays == 0 || threshold < cert.NotifiedDays)
+	if shouldNotify && s.inMaintenance(target, info.CheckedAt) {
+		// Left at the last threshold warned about, so the first check
+		// after the window sends the warning
+		shouldNotify = false
+	} else {
+		// Falls back to zero once the certificate is renewed past every threshold
+		cert.NotifiedDays = threshold
+	}
+
+	if err := s.certRepo.Save(cert); err != nil {
+		return err
+	}
+
+	if !shouldNotify {
+		return nil
+	}
+
+	return s.notify(target, notifCore.State{
+		Name:      target.URL,
+		Status:    StatusCertificateExpiring,
+		UpdatedAt: info.CheckedAt,
+		Message:   expiryMessage(target, info, days),
+	})
+}
+
+// StatusCertificateExpiring is the notification status sent when a
+// certificate reaches an expiry threshold
+const StatusCertificateExpiring = "certificate_expiring"
+
+// expiryThreshold returns the smallest threshold days falls within, or zero
+func expiryThreshold(days int, thresholds []int) int {
+	matched := 0
+	for _, threshold := range thresholds {
+		if days <= threshold && (matched == 0 || threshold < matched) {
+			matched = threshold
+		}
+	}
+	return matched
+}
+
+func expiryMessage(target *monitor.Target, info monitor.TLSInfo, days int) string {
+	expires := info.NotAfter.UTC().Format(time.DateOnly)
+	switch {
+	case days < 0:
+		return fmt.Sprintf("TLS certificate for %s expired on %s", target.URL, expires)
+	case days == 1:
+		return fmt.Sprintf("TLS certificate for %s expires in 1 day on %s", target.URL, expires)
+	default:
+		return fmt.Sprintf("TLS certificate for %s expires in %d days on %s", target.URL, days, expires)
+	}
+}
+
+// Create stores a new target for the user and starts monitoring it. New
+// targets are always enabled and start out pending.
+func (s *TargetService) Create(userID int, target *monitor.Target) (*monitor.Target, error) {
+	target.Enabled = true
+	target.Status = "pending"
+	assignHeartbeatToken(target)
+
 	userTarget := model.UserTarget{
 		UserID: userID,
-		Target: &monitor.Target{
-			URL:      url,
-			Interval: interval,
-			Enabled:  true,
-			Status:   "pending",
-		},
+		Target: target,
 	}
 
 	userTarget.Target.OnStatusUpdate = s.handleStatusUpdate
+	userTarget.Target.OnCheckResult = s.handleCheckResult
 
 	newUserTarget, err := s.repo.Create(userTarget)
 	if err != nil {
@@ -97,7 +376,9 @@ func (s *TargetService) GetAllByUserID(userID int) ([]*monitor.Target, error) {
 }
 
 func (s *TargetService) Update(target *monitor.Target) (*monitor.Target, error) {
+	assignHeartbeatToken(target)
 	target.OnStatusUpdate = s.handleStatusUpdate
+	target.OnCheckResult = s.handleCheckResult
 
 	// First update the target in the database
 	updatedTarget, err := s.repo.Update(target)
@@ -105,7 +386,7 @@ func (s *TargetService) Update(target *monitor.Target) (*monitor.Target, error)
 		return nil, fmt.Errorf("failed to update target: %w", err)
 	}
 
-	if existingTarget, exists := s.manager.Targets[target.ID]; exists {
+	if existingTarget, exists := s.manager.Get(target.ID); exists {
 		existingTarget.Update(updatedTarget)
 	} else {
 		// Register new monitor if it doesn't exist
@@ -122,6 +403,39 @@ func (s *TargetService) Delete(id int) error {
 	return s.repo.Delete(id)
 }
 
+// StatusPaused is the notification status sent when a target is paused
+const StatusPaused = "paused"
+
+// SetEnabled pauses or resumes checking the target. A paused target keeps
+// its history and configuration, and its notifiers are told it was paused.
+func (s *TargetService) SetEnabled(id int, enabled bool) (*monitor.Target, error) {
+	target, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	pausing := target.Enabled && !enabled
+	target.Enabled = enabled
+	updated, err := s.Update(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if pausing {
+		err := s.notify(updated, notifCore.State{
+			Name:           updated.URL,
+			Status:         StatusPaused,
+			UpdatedAt:      time.Now(),
+			Message:        fmt.Sprintf("Target %s is paused", updated.URL),
+			PreviousStatus: updated.Status,
+		})
+		if err != nil {
+			slog.Error("Failed to announce pause", "Target", updated.URL, "error", err)
+		}
+	}
+	return updated, nil
+}
+
 func (s *TargetService) InitializeMonitoring() error {
 	targets, err := s.repo.GetAll()
 	if err != nil {
@@ -130,6 +444,7 @@ func (s *TargetService) InitializeMonitoring() error {
 
 	for _, target := range targets {
 		target.OnStatusUpdate = s.handleStatusUpdate
+		target.OnCheckResult = s.handleCheckResult
 
 		if err := s.manager.RegisterTarget(target); err != nil {
 			return fmt.Errorf("failed to register target %s: %w", target.URL, err)
@@ -138,3 +453,47 @@ func (s *TargetService) InitializeMonitoring() error {
 
 	return nil
 }
+
+// GetCheckResults returns the probes recorded for a target within [from, to)
+func (s *TargetService) GetCheckResults(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid time range: %s is not before %s", from, to)
+	}
+	return s.resultRepo.GetByTargetID(targetID, from, to)
+}
+
+// GetCertificate returns the latest certificate inspected on a target, or nil
+func (s *TargetService) GetCertificate(targetID int) (*model.TargetCertificate, error) {
+	return s.certRepo.GetByTargetID(targetID)
+}
+
+// assignHeartbeatToken gives a heartbeat target its secret ping token, and
+// drops it when the target stops being one
+func assignHeartbeatToken(target *monitor.Target) {
+	if target.GetKind() != monitor.KindHeartbeat {
+		target.Heartbeat.Token = ""
+		return
+	}
+	if target.Heartbeat.Token == "" {
+		target.Heartbeat.Token = uuid.New().String()
+	}
+}
+
+// Ping passes a ping to the monitored heartbeat target the token belongs to.
+// Paused targets ignore pings.
+func (s *TargetService) Ping(token, event string, exitCode int) error {
+	stored, err := s.repo.GetByHeartbeatToken(token)
+	if err != nil {
+		return err
+	}
+
+	target, ok := s.manager.Get(stored.ID)
+	if !ok {
+		return repository.ErrTargetNotFound
+	}
+	if !target.Enabled {
+		return nil
+	}
+
+	return target.Ping(event, exitCode)
+}
@@ -3,13 +3,18 @@ package service
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
 	"github.com/shuvo-paul/uptimebot/internal/monitor/model"
+	"github.com/shuvo-paul/uptimebot/internal/monitor/repository"
 	notifCore "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	alertModel "github.com/shuvo-paul/uptimebot/internal/notification/model"
+	alertRepository "github.com/shuvo-paul/uptimebot/internal/notification/repository"
+	alertService "github.com/shuvo-paul/uptimebot/internal/notification/service"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,6 +27,7 @@ type mockTargetRepository struct {
 	deleteFunc         func(id int) error
 	updateStatusFunc   func(target *monitor.Target, status string) error
 	getAllByUserIDFunc func(userID int) ([]*monitor.Target, error)
+	getByTokenFunc     func(token string) (*monitor.Target, error)
 }
 
 func (m *mockTargetRepository) Create(userTarget model.UserTarget) (model.UserTarget, error) {
@@ -52,12 +58,157 @@ func (m *mockTargetRepository) GetAllByUserID(userID int) ([]*monitor.Target, er
 	return m.getAllByUserIDFunc(userID)
 }
 
+func (m *mockTargetRepository) GetByHeartbeatToken(token string) (*monitor.Target, error) {
+	return m.getByTokenFunc(token)
+}
+
+// mockCheckResultRepository is a mock implementation of CheckResultRepositoryInterface
+type mockCheckResultRepository struct {
+	createFunc          func(result monitor.CheckResult) (monitor.CheckResult, error)
+	getByTargetIDFunc   func(targetID int, from, to time.Time) ([]monitor.CheckResult, error)
+	getLatestBeforeFunc func(targetID int, before time.Time) (*monitor.CheckResult, error)
+}
+
+func (m *mockCheckResultRepository) Create(result monitor.CheckResult) (monitor.CheckResult, error) {
+	return m.createFunc(result)
+}
+
+func (m *mockCheckResultRepository) GetByTargetID(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+	return m.getByTargetIDFunc(targetID, from, to)
+}
+
+func (m *mockCheckResultRepository) GetLatestBefore(targetID int, before time.Time) (*monitor.CheckResult, error) {
+	if m.getLatestBeforeFunc == nil {
+		return nil, nil
+	}
+	return m.getLatestBeforeFunc(targetID, before)
+}
+
+// mockCertificateRepository is a mock implementation of CertificateRepositoryInterface
+type mockCertificateRepository struct {
+	saveFunc          func(cert model.TargetCertificate) error
+	getByTargetIDFunc func(targetID int) (*model.TargetCertificate, error)
+}
+
+func (m *mockCertificateRepository) Save(cert model.TargetCertificate) error {
+	return m.saveFunc(cert)
+}
+
+func (m *mockCertificateRepository) GetByTargetID(targetID int) (*model.TargetCertificate, error) {
+	return m.getByTargetIDFunc(targetID)
+}
+
+// mockDNSAnswerRepository is a mock implementation of DNSAnswerRepositoryInterface
+type mockDNSAnswerRepository struct {
+	saveFunc          func(answer model.TargetDNSAnswer) error
+	getByTargetIDFunc func(targetID int) (*model.TargetDNSAnswer, error)
+}
+
+func (m *mockDNSAnswerRepository) Save(answer model.TargetDNSAnswer) error {
+	return m.saveFunc(answer)
+}
+
+func (m *mockDNSAnswerRepository) GetByTargetID(targetID int) (*model.TargetDNSAnswer, error) {
+	return m.getByTargetIDFunc(targetID)
+}
+
+// mockIncidentService is a mock implementation of IncidentServiceInterface.
+// Unset funcs behave as if the target had no incident.
+type mockIncidentService struct {
+	handleStatusChangeFunc func(target *monitor.Target, status string) (*model.Incident, error)
+	recordNotificationFunc func(incident *model.Incident, message string) error
+}
+
+func (m *mockIncidentService) HandleStatusChange(target *monitor.Target, status string) (*model.Incident, error) {
+	if m.handleStatusChangeFunc == nil {
+		return nil, nil
+	}
+	return m.handleStatusChangeFunc(target, status)
+}
+
+func (m *mockIncidentService) RecordCheckFailure(target *monitor.Target, result monitor.CheckResult) error {
+	return nil
+}
+
+func (m *mockIncidentService) RecordNotification(incident *model.Incident, message string) error {
+	if m.recordNotificationFunc == nil {
+		return nil
+	}
+	return m.recordNotificationFunc(incident, message)
+}
+
+func (m *mockIncidentService) GetAllByUserID(userID int) ([]model.Incident, error) {
+	return nil, nil
+}
+
+func (m *mockIncidentService) GetByID(id int) (*model.Incident, error) {
+	return nil, repository.ErrIncidentNotFound
+}
+
+func (m *mockIncidentService) GetEvents(id int) ([]model.IncidentEvent, error) {
+	return nil, nil
+}
+
+func (m *mockIncidentService) Acknowledge(id, userID int) error {
+	return nil
+}
+
+func (m *mockIncidentService) AddUpdate(id, userID int, message string) error {
+	return nil
+}
+
+// mockNotifierService records the notifications queued through it
 type mockNotifierService struct {
-	configureObserversFunc func(targetID int) error
+	mu           sync.Mutex
+	queued       []notifCore.State
+	acknowledged []notifCore.State
+	targetIDs    []int
+	// escalated records the notifiers each escalated state was queued for
+	escalated         map[string][]int64
+	getByTargetIDFunc func(targetID int) ([]*alertModel.Notifier, error)
+}
+
+func (m *mockNotifierService) Enqueue(targetID int, state notifCore.State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.targetIDs = append(m.targetIDs, targetID)
+	m.queued = append(m.queued, state)
+	return nil
 }
 
-func (m *mockNotifierService) ConfigureObservers(targetID int) error {
-	return m.configureObserversFunc(targetID)
+func (m *mockNotifierService) EnqueueAcknowledgement(targetID int, state notifCore.State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.targetIDs = append(m.targetIDs, targetID)
+	m.acknowledged = append(m.acknowledged, state)
+	return nil
+}
+
+func (m *mockNotifierService) Escalate(notifierIDs []int64, state notifCore.State) error {
+	m.escalate(notifierIDs, state.Message)
+	return nil
+}
+
+func (m *mockNotifierService) EscalateAcknowledgement(notifierIDs []int64, state notifCore.State) error {
+	m.escalate(notifierIDs, state.Message)
+	return nil
+}
+
+func (m *mockNotifierService) escalate(notifierIDs []int64, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.escalated == nil {
+		m.escalated = make(map[string][]int64)
+	}
+	m.escalated[message] = append(m.escalated[message], notifierIDs...)
+}
+
+func (m *mockNotifierService) GetDeliveries(notifierID int64, limit int) ([]*alertModel.DeliveryAttempt, error) {
+	return nil, nil
+}
+
+func (m *mockNotifierService) SendTest(notifier *alertModel.Notifier) error {
+	return nil
 }
 
 func (m *mockNotifierService) Create(notifier *alertModel.Notifier) error {
@@ -76,7 +227,14 @@ func (m *mockNotifierService) Delete(id int64) error {
 	return nil
 }
 
-func (m *mockNotifierService) GetSubject() *notifCore.Subject {
+func (m *mockNotifierService) GetByTargetID(targetID int) ([]*alertModel.Notifier, error) {
+	if m.getByTargetIDFunc == nil {
+		return nil, nil
+	}
+	return m.getByTargetIDFunc(targetID)
+}
+
+func (m *mockNotifierService) UpdateFilter(id int64, filter alertModel.NotifierFilter) error {
 	return nil
 }
 
@@ -97,19 +255,24 @@ func TestTargetService_Create(t *testing.T) {
 	}
 	mockNotifierService := &mockNotifierService{}
 
-	service := NewTargetService(mockRepo, mockNotifierService)
+	service := NewTargetService(mockRepo, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), mockNotifierService)
 
 	t.Run("Target created successfully", func(t *testing.T) {
 		url := "https://example.com"
 		interval := time.Second * 30
 
-		target, err := service.Create(1, url, interval)
+		target, err := service.Create(1, &monitor.Target{
+			URL:      url,
+			Interval: interval,
+			Request:  monitor.RequestSpec{Method: "POST"},
+		})
 		assert.NoError(t, err)
 		assert.Equal(t, 1, target.ID)
 		assert.Equal(t, url, target.URL)
 		assert.Equal(t, interval, target.Interval)
 		assert.True(t, target.Enabled)
 		assert.Equal(t, "pending", target.Status)
+		assert.Equal(t, "POST", target.Request.Method)
 
 		// Verify the target was registered with the monitor manager
 		assert.Contains(t, service.manager.Targets, target.ID)
@@ -120,7 +283,7 @@ func TestTargetService_Create(t *testing.T) {
 			return model.UserTarget{}, fmt.Errorf("database error")
 		}
 
-		_, err := service.Create(1, "https://example.com", time.Second*30)
+		_, err := service.Create(1, &monitor.Target{URL: "https://example.com", Interval: time.Second * 30})
 		assert.Error(t, err)
 	})
 }
@@ -131,7 +294,7 @@ func TestTargetService_Update(t *testing.T) {
 			return target, nil
 		},
 	}
-	service := NewTargetService(mockRepo, &mockNotifierService{})
+	service := NewTargetService(mockRepo, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), &mockNotifierService{})
 
 	t.Run("Update existing target", func(t *testing.T) {
 		// Create and register initial target
@@ -174,13 +337,54 @@ func TestTargetService_Update(t *testing.T) {
 	})
 }
 
+func TestTargetService_SetEnabled(t *testing.T) {
+	stored := &monitor.Target{ID: 1, URL: "https://example.com", Interval: time.Second * 30, Enabled: true, Status: "up"}
+	mockRepo := &mockTargetRepository{
+		getByIDFunc: func(id int) (*monitor.Target, error) {
+			if id != stored.ID {
+				return nil, repository.ErrTargetNotFound
+			}
+			return stored, nil
+		},
+		updateFunc: func(target *monitor.Target) (*monitor.Target, error) {
+			return target, nil
+		},
+	}
+	notifierService := &mockNotifierService{}
+	service := NewTargetService(mockRepo, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), notifierService)
+
+	paused, err := service.SetEnabled(1, false)
+	assert.NoError(t, err)
+	assert.False(t, paused.Enabled)
+	assert.Equal(t, "up", paused.Status, "pausing keeps the last status")
+	assert.False(t, service.manager.Targets[1].Enabled)
+	if assert.Len(t, notifierService.queued, 1) {
+		assert.Equal(t, StatusPaused, notifierService.queued[0].Status)
+		assert.Equal(t, "up", notifierService.queued[0].PreviousStatus)
+		assert.Equal(t, 1, notifierService.queued[0].TargetID)
+	}
+
+	_, err = service.SetEnabled(1, false)
+	assert.NoError(t, err)
+	assert.Len(t, notifierService.queued, 1, "a target already paused is not announced again")
+
+	resumed, err := service.SetEnabled(1, true)
+	assert.NoError(t, err)
+	assert.True(t, resumed.Enabled)
+	assert.True(t, service.manager.Targets[1].Enabled)
+	assert.Len(t, notifierService.queued, 1, "resuming is announced by the next check")
+
+	_, err = service.SetEnabled(2, true)
+	assert.ErrorIs(t, err, repository.ErrTargetNotFound)
+}
+
 func TestTargetService_Delete(t *testing.T) {
 	mockRepo := &mockTargetRepository{
 		deleteFunc: func(id int) error {
 			return nil
 		},
 	}
-	service := NewTargetService(mockRepo, &mockNotifierService{})
+	service := NewTargetService(mockRepo, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), &mockNotifierService{})
 
 	t.Run("Delete existing target", func(t *testing.T) {
 		// Register a target first
@@ -224,7 +428,7 @@ func TestTargetService_GetAllByUserID(t *testing.T) {
 			},
 		}
 
-		service := NewTargetService(mockRepo, &mockNotifierService{})
+		service := NewTargetService(mockRepo, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), &mockNotifierService{})
 		targets, err := service.GetAllByUserID(1)
 
 		assert.NoError(t, err)
@@ -238,7 +442,7 @@ func TestTargetService_GetAllByUserID(t *testing.T) {
 			},
 		}
 
-		service := NewTargetService(mockRepo, &mockNotifierService{})
+		service := NewTargetService(mockRepo, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), &mockNotifierService{})
 		targets, err := service.GetAllByUserID(999)
 
 		assert.NoError(t, err)
@@ -252,10 +456,558 @@ func TestTargetService_GetAllByUserID(t *testing.T) {
 			},
 		}
 
-		service := NewTargetService(mockRepo, &mockNotifierService{})
+		service := NewTargetService(mockRepo, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), &mockNotifierService{})
 		targets, err := service.GetAllByUserID(1)
 
 		assert.Error(t, err)
 		assert.Nil(t, targets)
 	})
 }
+
+func TestTargetService_handleCheckResult(t *testing.T) {
+	var persisted []monitor.CheckResult
+	resultRepo := &mockCheckResultRepository{
+		createFunc: func(result monitor.CheckResult) (monitor.CheckResult, error) {
+			persisted = append(persisted, result)
+			return result, nil
+		},
+	}
+	service := NewTargetService(&mockTargetRepository{}, resultRepo, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), &mockNotifierService{})
+
+	target := &monitor.Target{ID: 1, URL: "https://example.com"}
+	result := monitor.CheckResult{TargetID: 1, Status: "up", CheckedAt: time.Now(), StatusCode: 200}
+
+	service.handleCheckResult(target, result)
+	service.handleCheckResult(target, result)
+
+	assert.Len(t, persisted, 2)
+	assert.Equal(t, result, persisted[0])
+}
+
+func TestTargetService_handleStatusUpdate(t *testing.T) {
+	startedAt := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name         string
+		status       string
+		incident     *model.Incident
+		wantNotified bool
+	}{
+		{
+			name:         "no incident",
+			status:       "up",
+			wantNotified: true,
+		},
+		{
+			name:         "new incident",
+			status:       "down",
+			incident:     &model.Incident{ID: 1, StartedAt: startedAt},
+			wantNotified: true,
+		},
+		{
+			name:     "acknowledged incident",
+			status:   "error",
+			incident: &model.Incident{ID: 1, StartedAt: startedAt, AcknowledgedAt: startedAt.Add(time.Minute)},
+		},
+		{
+			name:         "resolved acknowledged incident",
+			status:       "up",
+			incident:     &model.Incident{ID: 1, StartedAt: startedAt, AcknowledgedAt: startedAt.Add(time.Minute), ResolvedAt: time.Now()},
+			wantNotified: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifierService := &mockNotifierService{}
+
+			var recorded []string
+			incidents := &mockIncidentService{
+				handleStatusChangeFunc: func(target *monitor.Target, status string) (*model.Incident, error) {
+					return tt.incident, nil
+				},
+				recordNotificationFunc: func(incident *model.Incident, message string) error {
+					recorded = append(recorded, message)
+					return nil
+				},
+			}
+			repo := &mockTargetRepository{
+				updateStatusFunc: func(target *monitor.Target, status string) error { return nil },
+			}
+
+			service := NewTargetService(repo, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, incidents, NewMaintenanceService(&mockMaintenanceRepository{}), notifierService)
+			target := &monitor.Target{ID: 1, URL: "https://example.com", PreviousStatus: "pending", LastLatency: 80 * time.Millisecond}
+
+			assert.NoError(t, service.handleStatusUpdate(target, tt.status))
+
+			if !tt.wantNotified {
+				assert.Empty(t, notifierService.queued)
+				assert.Empty(t, recorded)
+				return
+			}
+			if !assert.Len(t, notifierService.queued, 1) {
+				return
+			}
+			state := notifierService.queued[0]
+			assert.Equal(t, 1, state.TargetID)
+			assert.Equal(t, "pending", state.PreviousStatus)
+			assert.Equal(t, 80*time.Millisecond, state.Latency)
+			if tt.incident != nil {
+				assert.Equal(t, tt.incident.ID, state.IncidentID)
+				assert.Equal(t, []string{state.Message}, recorded)
+			}
+			if monitor.IsFailing(tt.status) {
+				assert.True(t, state.OutageStart.Equal(startedAt), "the outage began with the incident")
+			} else {
+				assert.True(t, state.OutageStart.IsZero())
+			}
+		})
+	}
+}
+
+// TestTargetService_ConcurrentStatusUpdates flips many targets at once and
+// checks each status change is queued for its own target. Run it with -race.
+func TestTargetService_ConcurrentStatusUpdates(t *testing.T) {
+	const targets = 50
+	const flips = 4
+
+	notifierService := &mockNotifierService{}
+	repo := &mockTargetRepository{
+		updateStatusFunc: func(target *monitor.Target, status string) error { return nil },
+	}
+	service := NewTargetService(repo, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), notifierService)
+
+	statuses := []string{"down", "up"}
+	var wg sync.WaitGroup
+	for id := 1; id <= targets; id++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			target := &monitor.Target{ID: id, URL: fmt.Sprintf("https://%d.example.com", id)}
+			for i := range flips {
+				assert.NoError(t, service.handleStatusUpdate(target, statuses[i%2]))
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, notifierService.queued, targets*flips)
+	byTarget := make(map[int][]string)
+	for i, state := range notifierService.queued {
+		assert.Equal(t, notifierService.targetIDs[i], state.TargetID)
+		assert.Equal(t, fmt.Sprintf("https://%d.example.com", state.TargetID), state.Name)
+		byTarget[state.TargetID] = append(byTarget[state.TargetID], state.Status)
+	}
+	for id := 1; id <= targets; id++ {
+		assert.Equal(t, []string{"down", "up", "down", "up"}, byTarget[id], "target %d", id)
+	}
+}
+
+// TestTargetService_ConcurrentNotifications flips many targets at once
+// through the real notifier service while their notifiers are edited, so
+// notifier lookups are cached and invalidated side by side. Run it with
+// -race.
+func TestTargetService_ConcurrentNotifications(t *testing.T) {
+	const targets = 20
+	const flips = 4
+
+	db := testutil.NewInMemoryDB()
+	// Every connection to :memory: opens a database of its own
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	notifierRepo := alertRepository.NewNotifierRepository(db)
+	notifierIDs := make(map[int]int64, targets)
+	for id := 1; id <= targets; id++ {
+		notifier, err := notifierRepo.Create(&alertModel.Notifier{
+			TargetId: id,
+			Type:     alertModel.NotifierTypeSlack,
+			Config:   json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+		notifierIDs[id] = notifier.ID
+	}
+	notifierService := alertService.NewNotifierService(notifierRepo, alertRepository.NewOutboxRepository(db), nil, "")
+
+	repo := &mockTargetRepository{
+		updateStatusFunc: func(target *monitor.Target, status string) error { return nil },
+	}
+	service := NewTargetService(repo, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), notifierService)
+
+	statuses := []string{"down", "up"}
+	var wg sync.WaitGroup
+	for id := 1; id <= targets; id++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			target := &monitor.Target{ID: id, URL: fmt.Sprintf("https://%d.example.com", id)}
+			for i := range flips {
+				assert.NoError(t, service.handleStatusUpdate(target, statuses[i%2]))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range flips {
+				assert.NoError(t, notifierService.UpdateFilter(notifierIDs[id], alertModel.NotifierFilter{}))
+			}
+		}()
+	}
+	wg.Wait()
+
+	rows, err := db.Query(`SELECT notifier_id, state FROM notification_outbox ORDER BY id`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer rows.Close()
+
+	byNotifier := make(map[int64][]string)
+	for rows.Next() {
+		var notifierID int64
+		var raw string
+		var state notifCore.State
+		assert.NoError(t, rows.Scan(&notifierID, &raw))
+		assert.NoError(t, json.Unmarshal([]byte(raw), &state))
+		assert.Equal(t, notifierIDs[state.TargetID], notifierID, "%s was queued for another target's notifier", state.Name)
+		byNotifier[notifierID] = append(byNotifier[notifierID], state.Status)
+	}
+	for id := 1; id <= targets; id++ {
+		assert.Equal(t, []string{"down", "up", "down", "up"}, byNotifier[notifierIDs[id]], "target %d", id)
+	}
+}
+
+func TestTargetService_Maintenance(t *testing.T) {
+	now := time.Now()
+	window := model.MaintenanceWindow{TargetID: 1, Kind: model.MaintenanceOnce, StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)}
+
+	tests := []struct {
+		name       string
+		statuses   []string
+		wantStates []string
+	}{
+		{
+			name:       "still failing after the window",
+			statuses:   []string{"down"},
+			wantStates: []string{"down"},
+		},
+		{
+			name:     "recovered within the window",
+			statuses: []string{"down", "up"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifierService := &mockNotifierService{}
+
+			var results []monitor.CheckResult
+			resultRepo := &mockCheckResultRepository{
+				createFunc: func(result monitor.CheckResult) (monitor.CheckResult, error) {
+					results = append(results, result)
+					return result, nil
+				},
+			}
+			repo := &mockTargetRepository{
+				updateStatusFunc: func(target *monitor.Target, status string) error { return nil },
+			}
+			maintenance := NewMaintenanceService(&mockMaintenanceRepository{})
+			planned, err := maintenance.Create(window)
+			assert.NoError(t, err)
+
+			service := NewTargetService(repo, resultRepo, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, maintenance, notifierService)
+			target := &monitor.Target{ID: 1, URL: "https://example.com"}
+
+			for _, status := range tt.statuses {
+				assert.NoError(t, service.handleStatusUpdate(target, status))
+				service.handleCheckResult(target, monitor.CheckResult{TargetID: 1, Status: status, CheckedAt: time.Now()})
+			}
+			assert.Empty(t, notifierService.queued, "no alerts during maintenance")
+
+			assert.NoError(t, maintenance.Delete(1, planned.ID))
+			last := tt.statuses[len(tt.statuses)-1]
+			service.handleCheckResult(target, monitor.CheckResult{TargetID: 1, Status: last, CheckedAt: time.Now()})
+			service.handleCheckResult(target, monitor.CheckResult{TargetID: 1, Status: last, CheckedAt: time.Now()})
+
+			var states []string
+			for _, state := range notifierService.queued {
+				states = append(states, state.Status)
+			}
+			assert.Equal(t, tt.wantStates, states)
+
+			if assert.Len(t, results, len(tt.statuses)+2) {
+				assert.True(t, results[0].Maintenance)
+				assert.False(t, results[len(results)-1].Maintenance)
+			}
+		})
+	}
+}
+
+func TestStatusMessage(t *testing.T) {
+	target := &monitor.Target{URL: "https://example.com"}
+	assert.Equal(t, "Target https://example.com is up", statusMessage(target, "up"))
+
+	target.StatusReason = `assertion failed: body contains "Database connection failed"`
+	assert.Equal(t,
+		`Target https://example.com is down: assertion failed: body contains "Database connection failed"`,
+		statusMessage(target, "down"),
+	)
+}
+
+func TestTargetService_HandleIncidentAcknowledged(t *testing.T) {
+	notifierService := &mockNotifierService{}
+	service := NewTargetService(&mockTargetRepository{}, &mockCheckResultRepository{}, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), notifierService)
+
+	acknowledgedAt := time.Now()
+	err := service.HandleIncidentAcknowledged(&model.Incident{
+		ID:             3,
+		TargetID:       7,
+		TargetURL:      "https://example.com",
+		AcknowledgedAt: acknowledgedAt,
+		AcknowledgedBy: "Alice",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{7}, notifierService.targetIDs)
+	assert.Empty(t, notifierService.queued)
+	if assert.Len(t, notifierService.acknowledged, 1) {
+		state := notifierService.acknowledged[0]
+		assert.Equal(t, 7, state.TargetID)
+		assert.Equal(t, 3, state.IncidentID)
+		assert.Equal(t, "Incident #3 acknowledged by Alice", state.Message)
+		assert.True(t, state.UpdatedAt.Equal(acknowledgedAt))
+	}
+}
+
+func TestExpiryThreshold(t *testing.T) {
+	thresholds := []int{30, 14, 7, 1}
+	tests := []struct {
+		days int
+		want int
+	}{
+		{days: 45, want: 0},
+		{days: 30, want: 30},
+		{days: 15, want: 30},
+		{days: 14, want: 14},
+		{days: 2, want: 7},
+		{days: 0, want: 1},
+		{days: -3, want: 1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, expiryThreshold(tt.days, thresholds), "days=%d", tt.days)
+	}
+}
+
+func TestTargetService_handleCertificate(t *testing.T) {
+	var stored *model.TargetCertificate
+	certRepo := &mockCertificateRepository{
+		getByTargetIDFunc: func(targetID int) (*model.TargetCertificate, error) {
+			return stored, nil
+		},
+		saveFunc: func(cert model.TargetCertificate) error {
+			stored = &cert
+			return nil
+		},
+	}
+
+	notifierService := &mockNotifierService{}
+
+	service := NewTargetService(&mockTargetRepository{}, &mockCheckResultRepository{}, certRepo, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), notifierService)
+	target := &monitor.Target{ID: 1, URL: "https://example.com", TLS: monitor.TLSSettings{Enabled: true}}
+
+	now := time.Date(2025, 3, 30, 12, 0, 0, 0, time.UTC)
+	inspect := func(daysLeft int) {
+		info := monitor.TLSInfo{
+			Subject:   "CN=example.com",
+			NotAfter:  now.Add(time.Duration(daysLeft)*24*time.Hour + time.Hour),
+			CheckedAt: now,
+		}
+		assert.NoError(t, service.handleCertificate(target, info))
+	}
+
+	inspect(45)
+	assert.Empty(t, notifierService.queued)
+	assert.Equal(t, 0, stored.NotifiedDays)
+
+	inspect(29)
+	inspect(20)
+	assert.Len(t, notifierService.queued, 1)
+	assert.Equal(t, StatusCertificateExpiring, notifierService.queued[0].Status)
+	assert.Equal(t, "TLS certificate for https://example.com expires in 29 days on 2025-04-28", notifierService.queued[0].Message)
+
+	inspect(13)
+	inspect(6)
+	inspect(1)
+	assert.Len(t, notifierService.queued, 4)
+	assert.Contains(t, notifierService.queued[3].Message, "expires in 1 day on")
+	assert.Equal(t, 1, stored.NotifiedDays)
+
+	// Renewal resets the thresholds so the next cycle warns again
+	inspect(89)
+	assert.Equal(t, 0, stored.NotifiedDays)
+	inspect(30)
+	assert.Len(t, notifierService.queued, 5)
+
+	t.Run("maintenance", func(t *testing.T) {
+		planned, err := service.maintenance.Create(model.MaintenanceWindow{TargetID: 1, Kind: model.MaintenanceOnce, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+		assert.NoError(t, err)
+
+		inspect(13)
+		assert.Len(t, notifierService.queued, 5, "no warnings during maintenance")
+		assert.Equal(t, 30, stored.NotifiedDays)
+
+		assert.NoError(t, service.maintenance.Delete(1, planned.ID))
+		inspect(13)
+		assert.Len(t, notifierService.queued, 6, "the warning is sent once the window closes")
+		assert.Contains(t, notifierService.queued[5].Message, "expires in 13 days")
+		assert.Equal(t, 14, stored.NotifiedDays)
+	})
+}
+
+func TestTargetService_handleDNSAnswer(t *testing.T) {
+	var stored *model.TargetDNSAnswer
+	answerRepo := &mockDNSAnswerRepository{
+		getByTargetIDFunc: func(targetID int) (*model.TargetDNSAnswer, error) {
+			return stored, nil
+		},
+		saveFunc: func(answer model.TargetDNSAnswer) error {
+			stored = &answer
+			return nil
+		},
+	}
+
+	notifierService := &mockNotifierService{}
+
+	service := NewTargetService(&mockTargetRepository{}, &mockCheckResultRepository{}, &mockCertificateRepository{}, answerRepo, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), notifierService)
+	target := &monitor.Target{ID: 1, Kind: monitor.KindDNS, URL: "dns://example.com"}
+
+	resolve := func(records ...string) {
+		answer := monitor.DNSAnswer{Records: records, ResolvedAt: time.Now()}
+		assert.NoError(t, service.handleDNSAnswer(target, answer))
+	}
+
+	// The first answer is the baseline
+	resolve("192.0.2.1")
+	resolve("192.0.2.1")
+	assert.Empty(t, notifierService.queued)
+	assert.Equal(t, monitor.RecordA, stored.RecordType)
+
+	resolve("192.0.2.1", "198.51.100.7")
+	assert.Len(t, notifierService.queued, 1)
+	assert.Equal(t, StatusDNSChanged, notifierService.queued[0].Status)
+	assert.Equal(t, "A records for dns://example.com changed from 192.0.2.1 to 192.0.2.1, 198.51.100.7", notifierService.queued[0].Message)
+
+	// Switching the record type starts a new baseline
+	target.DNS.RecordType = monitor.RecordMX
+	resolve("10 mail.example.com")
+	assert.Len(t, notifierService.queued, 1)
+	assert.Equal(t, monitor.RecordMX, stored.RecordType)
+
+	t.Run("maintenance", func(t *testing.T) {
+		now := time.Now()
+		planned, err := service.maintenance.Create(model.MaintenanceWindow{TargetID: 1, Kind: model.MaintenanceOnce, StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)})
+		assert.NoError(t, err)
+
+		resolve("20 backup.example.com")
+		resolve("30 other.example.com")
+		assert.Len(t, notifierService.queued, 1, "no alerts during maintenance")
+		assert.Equal(t, []string{"10 mail.example.com"}, stored.Records, "the answer from before the window is kept")
+
+		assert.NoError(t, service.maintenance.Delete(1, planned.ID))
+		resolve("30 other.example.com")
+		if assert.Len(t, notifierService.queued, 2, "the change is announced once the window closes") {
+			assert.Equal(t, "MX records for dns://example.com changed from 10 mail.example.com to 30 other.example.com", notifierService.queued[1].Message)
+		}
+	})
+}
+
+func TestTargetService_Ping(t *testing.T) {
+	var stored *monitor.Target
+	mockRepo := &mockTargetRepository{
+		createFunc: func(userTarget model.UserTarget) (model.UserTarget, error) {
+			userTarget.ID = 1
+			stored = userTarget.Target
+			return userTarget, nil
+		},
+		getByTokenFunc: func(token string) (*monitor.Target, error) {
+			if stored == nil || token != stored.Heartbeat.Token {
+				return nil, repository.ErrTargetNotFound
+			}
+			return stored, nil
+		},
+		updateStatusFunc: func(target *monitor.Target, status string) error { return nil },
+	}
+	notifierService := &mockNotifierService{}
+	resultRepo := &mockCheckResultRepository{
+		createFunc: func(result monitor.CheckResult) (monitor.CheckResult, error) { return result, nil },
+	}
+
+	service := NewTargetService(mockRepo, resultRepo, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), notifierService)
+
+	target, err := service.Create(1, &monitor.Target{
+		Kind:     monitor.KindHeartbeat,
+		URL:      "heartbeat://nightly-backup",
+		Interval: time.Hour,
+	})
+	assert.NoError(t, err)
+	defer service.manager.RevokeTarget(target.ID)
+	assert.NotEmpty(t, target.Heartbeat.Token)
+
+	t.Run("ping reaches the monitored target", func(t *testing.T) {
+		assert.NoError(t, service.Ping(target.Heartbeat.Token, monitor.PingSuccess, 0))
+		assert.Equal(t, "up", target.Status)
+	})
+
+	t.Run("paused targets ignore pings", func(t *testing.T) {
+		target.Enabled = false
+		defer func() { target.Enabled = true }()
+
+		assert.NoError(t, service.Ping(target.Heartbeat.Token, monitor.PingFail, 0))
+		assert.Equal(t, "up", target.Status)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		err := service.Ping("not-a-token", monitor.PingSuccess, 0)
+		assert.ErrorIs(t, err, repository.ErrTargetNotFound)
+	})
+}
+
+func TestAssignHeartbeatToken(t *testing.T) {
+	target := &monitor.Target{Kind: monitor.KindHeartbeat}
+	assignHeartbeatToken(target)
+	token := target.Heartbeat.Token
+	assert.NotEmpty(t, token)
+
+	assignHeartbeatToken(target)
+	assert.Equal(t, token, target.Heartbeat.Token, "existing token must be kept")
+
+	target.Kind = monitor.KindHTTP
+	assignHeartbeatToken(target)
+	assert.Empty(t, target.Heartbeat.Token)
+}
+
+func TestTargetService_GetCheckResults(t *testing.T) {
+	now := time.Now()
+	expected := []monitor.CheckResult{
+		{ID: 1, TargetID: 1, Status: "up", CheckedAt: now.Add(-time.Hour)},
+		{ID: 2, TargetID: 1, Status: "down", CheckedAt: now.Add(-time.Minute)},
+	}
+	resultRepo := &mockCheckResultRepository{
+		getByTargetIDFunc: func(targetID int, from, to time.Time) ([]monitor.CheckResult, error) {
+			assert.Equal(t, 1, targetID)
+			return expected, nil
+		},
+	}
+	service := NewTargetService(&mockTargetRepository{}, resultRepo, &mockCertificateRepository{}, &mockDNSAnswerRepository{}, &mockIncidentService{}, NewMaintenanceService(&mockMaintenanceRepository{}), &mockNotifierService{})
+
+	t.Run("valid range", func(t *testing.T) {
+		results, err := service.GetCheckResults(1, now.Add(-24*time.Hour), now)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, results)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := service.GetCheckResults(1, now, now.Add(-time.Hour))
+		assert.Error(t, err)
+	})
+}
@@ -8,6 +8,14 @@ type State struct {
 	Status    string    // Current status
 	Message   string    // Additional details
 	UpdatedAt time.Time // When the state was last updated
+
+	TargetID       int           // Target the state is about
+	PreviousStatus string        // Status before the change, empty if unknown
+	Reason         string        // Why the check failed, empty when up
+	Latency        time.Duration // Latency of the check behind the change
+	IncidentID     int           // Incident tracking the outage, zero if none
+	OutageStart    time.Time     // When the outage began, zero if not failing
+	Test           bool          // Sample sent to check a notifier works
 }
 
 // Observer defines the interface for objects that should be notified of state changes
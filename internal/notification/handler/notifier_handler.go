@@ -1,28 +1,533 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
 	"github.com/shuvo-paul/uptimebot/internal/notification/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/pkg/flash"
 )
 
+// TargetLister lists the targets a user owns, so notifiers are only managed
+// by the owner of their target
+type TargetLister interface {
+	GetAllByUserID(userID int) ([]*monitor.Target, error)
+}
+
 type NotifierHandler struct {
-	notifierService service.NotifierServiceInterface
+	notifierService  service.NotifierServiceInterface
+	recipientService service.EmailRecipientServiceInterface
+	targets          TargetLister
+	flash            flash.FlashStoreInterface
+	Template         struct {
+		List     *renderer.Template
+		Verified *renderer.Template
+	}
 }
 
-func NewNotifierHandler(notifierService service.NotifierServiceInterface) *NotifierHandler {
+func NewNotifierHandler(
+	notifierService service.NotifierServiceInterface,
+	recipientService service.EmailRecipientServiceInterface,
+	targets TargetLister,
+	flash flash.FlashStoreInterface,
+) *NotifierHandler {
 	return &NotifierHandler{
-		notifierService: notifierService,
+		notifierService:  notifierService,
+		recipientService: recipientService,
+		targets:          targets,
+		flash:            flash,
 	}
 }
 
-func (nh *NotifierHandler) AuthSlack(w http.ResponseWriter, r *http.Request) {
-	targetId, err := strconv.Atoi(r.PathValue("targetId"))
+// recentDeliveries is how many delivery attempts are listed per notifier
+const recentDeliveries = 10
+
+// List shows the notifiers attached to a target, the statuses each is sent
+// and how the latest attempts at delivering them went
+func (nh *NotifierHandler) List(w http.ResponseWriter, r *http.Request) {
+	targetId, ok := nh.userTargetID(w, r)
+	if !ok {
+		return
+	}
+
+	notifiers, err := nh.notifierService.GetByTargetID(targetId)
 	if err != nil {
-		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		http.Error(w, "Failed to fetch notifiers", http.StatusInternalServerError)
+		return
+	}
+
+	// Email recipients with whether each confirmed, by notifier
+	recipients := make(map[int64][]*model.EmailRecipient)
+	for _, notifier := range notifiers {
+		if notifier.Type != model.NotifierTypeEmail {
+			continue
+		}
+		recipients[notifier.ID], err = nh.recipientService.GetByNotifierID(notifier.ID)
+		if err != nil {
+			http.Error(w, "Failed to fetch email recipients", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Latest delivery attempts, by notifier
+	deliveries := make(map[int64][]*model.DeliveryAttempt)
+	for _, notifier := range notifiers {
+		deliveries[notifier.ID], err = nh.notifierService.GetDeliveries(notifier.ID, recentDeliveries)
+		if err != nil {
+			http.Error(w, "Failed to fetch deliveries", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	data := map[string]any{
+		"title":      "notifications",
+		"targetID":   targetId,
+		"notifiers":  notifiers,
+		"recipients": recipients,
+		"deliveries": deliveries,
+		"statuses":   model.FilterStatuses,
+		"success":    nh.flash.GetFlash(flashId, "success"),
+		"error":      nh.flash.GetFlash(flashId, "error"),
+	}
+
+	nh.Template.List.Render(w, r, data)
+}
+
+// UpdateFilter saves which statuses a notifier is sent and when. Selecting
+// every status, or none, sends them all; leaving the quiet hours blank
+// turns them off.
+func (nh *NotifierHandler) UpdateFilter(w http.ResponseWriter, r *http.Request) {
+	targetId, ok := nh.userTargetID(w, r)
+	if !ok {
+		return
+	}
+
+	notifierId, err := strconv.ParseInt(r.PathValue("notifierId"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid notifier ID", http.StatusBadRequest)
+		return
+	}
+
+	notifier, err := nh.notifierService.Get(notifierId)
+	if err != nil || notifier == nil || notifier.TargetId != targetId {
+		http.Error(w, "Notifier not found", http.StatusNotFound)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	var filter model.NotifierFilter
+	for _, status := range r.Form["statuses"] {
+		if !slices.Contains(model.FilterStatuses, status) {
+			http.Error(w, "Invalid status: "+status, http.StatusBadRequest)
+			return
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	if len(filter.Statuses) == len(model.FilterStatuses) {
+		filter.Statuses = nil
+	}
+
+	redirect := fmt.Sprintf("/targets/%d/notifiers", targetId)
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	err = parseFilterTiming(r, &filter)
+	if err == nil {
+		err = filter.Validate()
+	}
+	if err != nil {
+		nh.flash.SetFlash(flashId, "error", "Invalid notifications: "+err.Error())
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	if err := nh.notifierService.UpdateFilter(notifierId, filter); err != nil {
+		nh.flash.SetFlash(flashId, "error", "Failed to update notifications")
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	nh.flash.SetFlash(flashId, "success", "Notifications updated")
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// parseFilterTiming reads the minimum outage, whether recoveries are sent
+// and the quiet hours from the filter form
+func parseFilterTiming(r *http.Request, filter *model.NotifierFilter) error {
+	if minutes := strings.TrimSpace(r.FormValue("min_outage_minutes")); minutes != "" {
+		n, err := strconv.Atoi(minutes)
+		if err != nil {
+			return fmt.Errorf("minimum outage must be a number of minutes")
+		}
+		filter.MinOutageMinutes = n
+	}
+
+	filter.ExcludeRecoveries = r.FormValue("include_recoveries") == ""
+
+	start := strings.TrimSpace(r.FormValue("quiet_start"))
+	end := strings.TrimSpace(r.FormValue("quiet_end"))
+	if start == "" && end == "" {
+		return nil
+	}
+	if start == "" || end == "" {
+		return fmt.Errorf("quiet hours need both a start and an end")
+	}
+	filter.QuietHours = &model.QuietHours{
+		Start:    start,
+		End:      end,
+		Timezone: strings.TrimSpace(r.FormValue("quiet_timezone")),
+	}
+	return nil
+}
+
+// CreateWebhook attaches a webhook to a target. Headers are entered one per
+// line as "Name: value"; a secret is generated when none is given. The
+// secret is only shown once, right after the webhook is added.
+func (nh *NotifierHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	targetId, ok := nh.userTargetID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	redirect := fmt.Sprintf("/targets/%d/notifiers", targetId)
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	notifier, err := parseWebhookForm(r, targetId)
+	if err == nil {
+		err = notifier.Validate()
+	}
+	if err != nil {
+		nh.flash.SetFlash(flashId, "error", "Invalid webhook: "+err.Error())
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	if err := nh.notifierService.Create(notifier); err != nil {
+		nh.flash.SetFlash(flashId, "error", "Failed to add webhook")
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	message := "Webhook added"
+	if config, err := notifier.GetWebhookConfig(); err == nil && config != nil {
+		message += ". Its signing secret is " + config.Secret + ", copy it now as it will not be shown again"
+	}
+	nh.flash.SetFlash(flashId, "success", message)
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+func parseWebhookForm(r *http.Request, targetId int) (*model.Notifier, error) {
+	config := model.WebhookConfig{
+		URL:    strings.TrimSpace(r.FormValue("url")),
+		Secret: strings.TrimSpace(r.FormValue("secret")),
+	}
+
+	if timeout := strings.TrimSpace(r.FormValue("timeout")); timeout != "" {
+		seconds, err := strconv.Atoi(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("timeout must be a number of seconds")
+		}
+		config.TimeoutSeconds = seconds
+	}
+
+	for _, line := range strings.Split(r.FormValue("headers"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("header %q must look like \"Name: value\"", line)
+		}
+		if config.Headers == nil {
+			config.Headers = make(map[string]string)
+		}
+		config.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Notifier{
+		TargetId: targetId,
+		Type:     model.NotifierTypeWebhook,
+		Config:   raw,
+	}, nil
+}
+
+// CreateDiscord attaches a Discord channel webhook to a target
+func (nh *NotifierHandler) CreateDiscord(w http.ResponseWriter, r *http.Request) {
+	nh.createNotifier(w, r, model.NotifierTypeDiscord, "Discord", webhookURLConfig)
+}
+
+// CreateMSTeams attaches a Microsoft Teams webhook to a target
+func (nh *NotifierHandler) CreateMSTeams(w http.ResponseWriter, r *http.Request) {
+	nh.createNotifier(w, r, model.NotifierTypeMSTeams, "Microsoft Teams", webhookURLConfig)
+}
+
+// CreateTelegram attaches a Telegram bot to a target, sending to one chat
+func (nh *NotifierHandler) CreateTelegram(w http.ResponseWriter, r *http.Request) {
+	nh.createNotifier(w, r, model.NotifierTypeTelegram, "Telegram", func(r *http.Request) any {
+		return model.TelegramConfig{
+			BotToken:         strings.TrimSpace(r.FormValue("bot_token")),
+			ChatID:           strings.TrimSpace(r.FormValue("chat_id")),
+			SilentRecoveries: r.FormValue("silent_recoveries") != "",
+		}
+	})
+}
+
+// CreatePagerDuty attaches a PagerDuty service to a target, which then
+// pages whoever is on call while the target is failing
+func (nh *NotifierHandler) CreatePagerDuty(w http.ResponseWriter, r *http.Request) {
+	nh.createNotifier(w, r, model.NotifierTypePagerDuty, "PagerDuty", func(r *http.Request) any {
+		return model.PagerDutyConfig{RoutingKey: strings.TrimSpace(r.FormValue("routing_key"))}
+	})
+}
+
+// CreateOpsgenie attaches an Opsgenie API integration to a target, which
+// then raises an alert while the target is failing
+func (nh *NotifierHandler) CreateOpsgenie(w http.ResponseWriter, r *http.Request) {
+	nh.createNotifier(w, r, model.NotifierTypeOpsgenie, "Opsgenie", func(r *http.Request) any {
+		return model.OpsgenieConfig{APIKey: strings.TrimSpace(r.FormValue("api_key"))}
+	})
+}
+
+// webhookURLConfig reads the configuration of notifiers set up with nothing
+// but a webhook URL
+func webhookURLConfig(r *http.Request) any {
+	return map[string]string{"webhook_url": strings.TrimSpace(r.FormValue("webhook_url"))}
+}
+
+// createNotifier creates a notifier of the given type from the configuration
+// config reads off the form
+func (nh *NotifierHandler) createNotifier(w http.ResponseWriter, r *http.Request, notifierType model.NotifierType, label string, config func(r *http.Request) any) {
+	targetId, ok := nh.userTargetID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	redirect := fmt.Sprintf("/targets/%d/notifiers", targetId)
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	raw, err := json.Marshal(config(r))
+	if err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	notifier := &model.Notifier{
+		TargetId: targetId,
+		Type:     notifierType,
+		Config:   raw,
+	}
+
+	if err := notifier.Validate(); err != nil {
+		nh.flash.SetFlash(flashId, "error", fmt.Sprintf("Invalid %s settings: %s", label, err))
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	if err := nh.notifierService.Create(notifier); err != nil {
+		nh.flash.SetFlash(flashId, "error", "Failed to add "+label)
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	nh.flash.SetFlash(flashId, "success", label+" added")
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// SendTest sends a notifier a sample message regardless of its filter, so
+// its configuration can be checked without waiting for an outage
+func (nh *NotifierHandler) SendTest(w http.ResponseWriter, r *http.Request) {
+	targetId, ok := nh.userTargetID(w, r)
+	if !ok {
+		return
+	}
+
+	notifierId, err := strconv.ParseInt(r.PathValue("notifierId"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid notifier ID", http.StatusBadRequest)
+		return
+	}
+
+	notifier, err := nh.notifierService.Get(notifierId)
+	if err != nil || notifier == nil || notifier.TargetId != targetId {
+		http.Error(w, "Notifier not found", http.StatusNotFound)
+		return
+	}
+
+	redirect := fmt.Sprintf("/targets/%d/notifiers", targetId)
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	if err := nh.notifierService.SendTest(notifier); err != nil {
+		nh.flash.SetFlash(flashId, "error", "Test notification failed: "+err.Error())
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	nh.flash.SetFlash(flashId, "success", "Test notification sent")
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// AddEmailRecipient adds an address to the target's email notifier, creating
+// the notifier for the first address. The address is mailed a confirmation
+// link and receives nothing else until it is followed.
+func (nh *NotifierHandler) AddEmailRecipient(w http.ResponseWriter, r *http.Request) {
+	targetId, ok := nh.userTargetID(w, r)
+	if !ok {
+		return
+	}
+
+	notifiers, err := nh.notifierService.GetByTargetID(targetId)
+	if err != nil {
+		http.Error(w, "Failed to fetch notifiers", http.StatusInternalServerError)
+		return
+	}
+
+	redirect := fmt.Sprintf("/targets/%d/notifiers", targetId)
+	flashId := flash.GetFlashIDFromContext(r.Context())
+	address := strings.TrimSpace(r.FormValue("email"))
+
+	notifier := &model.Notifier{TargetId: targetId, Type: model.NotifierTypeEmail}
+	config := &model.EmailConfig{}
+	for _, existing := range notifiers {
+		if existing.Type == model.NotifierTypeEmail {
+			notifier = existing
+			if config, err = existing.GetEmailConfig(); err != nil {
+				http.Error(w, "Invalid email notifier", http.StatusInternalServerError)
+				return
+			}
+			break
+		}
+	}
+
+	config.Recipients = append(config.Recipients, address)
+	notifier.Config, err = json.Marshal(config)
+	if err == nil {
+		err = notifier.Validate()
+	}
+	if err != nil {
+		nh.flash.SetFlash(flashId, "error", "Invalid recipient: "+err.Error())
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	if notifier.ID == 0 {
+		err = nh.notifierService.Create(notifier)
+	} else {
+		_, err = nh.notifierService.Update(int(notifier.ID), notifier.Config)
+	}
+	if err != nil {
+		nh.flash.SetFlash(flashId, "error", "Failed to add recipient")
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	nh.flash.SetFlash(flashId, "success", "A confirmation link was sent to "+address)
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// RemoveEmailRecipient removes an address from an email notifier, deleting
+// the notifier along with its last address
+func (nh *NotifierHandler) RemoveEmailRecipient(w http.ResponseWriter, r *http.Request) {
+	targetId, ok := nh.userTargetID(w, r)
+	if !ok {
+		return
+	}
+
+	notifierId, err := strconv.ParseInt(r.PathValue("notifierId"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid notifier ID", http.StatusBadRequest)
+		return
+	}
+
+	notifier, err := nh.notifierService.Get(notifierId)
+	if err != nil || notifier == nil || notifier.TargetId != targetId || notifier.Type != model.NotifierTypeEmail {
+		http.Error(w, "Notifier not found", http.StatusNotFound)
+		return
+	}
+
+	config, err := notifier.GetEmailConfig()
+	if err != nil {
+		http.Error(w, "Invalid email notifier", http.StatusInternalServerError)
+		return
+	}
+
+	address := r.FormValue("email")
+	config.Recipients = slices.DeleteFunc(config.Recipients, func(recipient string) bool {
+		return strings.EqualFold(recipient, address)
+	})
+
+	redirect := fmt.Sprintf("/targets/%d/notifiers", targetId)
+	flashId := flash.GetFlashIDFromContext(r.Context())
+
+	if len(config.Recipients) == 0 {
+		err = nh.notifierService.Delete(notifierId)
+	} else if notifier.Config, err = json.Marshal(config); err == nil {
+		_, err = nh.notifierService.Update(int(notifierId), notifier.Config)
+	}
+	if err != nil {
+		nh.flash.SetFlash(flashId, "error", "Failed to remove recipient")
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	nh.flash.SetFlash(flashId, "success", "Removed "+address)
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// VerifyEmail confirms an email recipient from the link they were mailed.
+// Recipients need not have an account, so this page is public.
+func (nh *NotifierHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	recipient, err := nh.recipientService.Verify(r.URL.Query().Get("token"))
+	if err != nil && err != service.ErrInvalidRecipientToken {
+		http.Error(w, "Failed to confirm email address", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{
+		"title": "Confirm alerts",
+		"email": "",
+	}
+	if recipient != nil {
+		data["email"] = recipient.Email
+	} else {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	nh.Template.Verified.Render(w, r, data)
+}
+
+func (nh *NotifierHandler) AuthSlack(w http.ResponseWriter, r *http.Request) {
+	targetId, ok := nh.userTargetID(w, r)
+	if !ok {
 		return
 	}
 
@@ -47,6 +552,9 @@ func (nh *NotifierHandler) AuthSlackCallback(w http.ResponseWriter, r *http.Requ
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if !nh.ownsTarget(w, r, targetId) {
+		return
+	}
 
 	notifier, err := nh.notifierService.HandleSlackCallback(code, targetId)
 	if err != nil {
@@ -62,3 +570,36 @@ func (nh *NotifierHandler) AuthSlackCallback(w http.ResponseWriter, r *http.Requ
 
 	http.Redirect(w, r, fmt.Sprintf("/targets/%d", targetId), http.StatusSeeOther)
 }
+
+// userTargetID reads the target named in the path, answering 404 unless it
+// belongs to the current user
+func (nh *NotifierHandler) userTargetID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	targetId, err := strconv.Atoi(r.PathValue("targetId"))
+	if err != nil {
+		http.Error(w, "Invalid target ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	return targetId, nh.ownsTarget(w, r, targetId)
+}
+
+// ownsTarget answers 404 unless the target belongs to the current user
+func (nh *NotifierHandler) ownsTarget(w http.ResponseWriter, r *http.Request, targetId int) bool {
+	user, ok := authService.GetUser(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusInternalServerError)
+		return false
+	}
+
+	targets, err := nh.targets.GetAllByUserID(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to fetch targets", http.StatusInternalServerError)
+		return false
+	}
+
+	if !slices.ContainsFunc(targets, func(target *monitor.Target) bool { return target.ID == targetId }) {
+		http.Error(w, "Target not found", http.StatusNotFound)
+		return false
+	}
+	return true
+}
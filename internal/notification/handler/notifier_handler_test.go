@@ -2,14 +2,25 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
+	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
+	monitor "github.com/shuvo-paul/uptimebot/internal/monitor/engine"
 	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/service"
+	"github.com/shuvo-paul/uptimebot/internal/renderer"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,9 +29,12 @@ type MockNotifierService struct {
 	getFunc                 func(id int64) (*model.Notifier, error)
 	updateFunc              func(id int, config json.RawMessage) (*model.Notifier, error)
 	deleteFunc              func(id int64) error
-	configureObserversFunc  func(targetID int) error
 	handleSlackCallbackFunc func(code string, targetId int) (*model.Notifier, error)
 	parseOAuthStateFunc     func(state string) (int, error)
+	getByTargetIDFunc       func(targetID int) ([]*model.Notifier, error)
+	updateFilterFunc        func(id int64, filter model.NotifierFilter) error
+	sendTestFunc            func(notifier *model.Notifier) error
+	getDeliveriesFunc       func(notifierID int64, limit int) ([]*model.DeliveryAttempt, error)
 }
 
 func (m *MockNotifierService) Create(notifier *model.Notifier) error {
@@ -39,8 +53,39 @@ func (m *MockNotifierService) Delete(id int64) error {
 	return m.deleteFunc(id)
 }
 
-func (m *MockNotifierService) ConfigureObservers(targetID int) error {
-	return m.configureObserversFunc(targetID)
+func (m *MockNotifierService) GetByTargetID(targetID int) ([]*model.Notifier, error) {
+	return m.getByTargetIDFunc(targetID)
+}
+
+func (m *MockNotifierService) UpdateFilter(id int64, filter model.NotifierFilter) error {
+	return m.updateFilterFunc(id, filter)
+}
+
+func (m *MockNotifierService) Enqueue(targetID int, state notification.State) error {
+	return nil
+}
+
+func (m *MockNotifierService) EnqueueAcknowledgement(targetID int, state notification.State) error {
+	return nil
+}
+
+func (m *MockNotifierService) Escalate(notifierIDs []int64, state notification.State) error {
+	return nil
+}
+
+func (m *MockNotifierService) EscalateAcknowledgement(notifierIDs []int64, state notification.State) error {
+	return nil
+}
+
+func (m *MockNotifierService) GetDeliveries(notifierID int64, limit int) ([]*model.DeliveryAttempt, error) {
+	if m.getDeliveriesFunc == nil {
+		return nil, nil
+	}
+	return m.getDeliveriesFunc(notifierID, limit)
+}
+
+func (m *MockNotifierService) SendTest(notifier *model.Notifier) error {
+	return m.sendTestFunc(notifier)
 }
 
 func (m *MockNotifierService) HandleSlackCallback(code string, targetId int) (*model.Notifier, error) {
@@ -51,13 +96,61 @@ func (m *MockNotifierService) ParseOAuthState(state string) (int, error) {
 	return m.parseOAuthStateFunc(state)
 }
 
-func (m *MockNotifierService) GetSubject() *notification.Subject {
-	return nil
+type mockEmailRecipientService struct {
+	syncFunc            func(notifier *model.Notifier) error
+	getByNotifierIDFunc func(notifierID int64) ([]*model.EmailRecipient, error)
+	verifyFunc          func(token string) (*model.EmailRecipient, error)
+}
+
+func (m *mockEmailRecipientService) Sync(notifier *model.Notifier) error {
+	return m.syncFunc(notifier)
+}
+
+func (m *mockEmailRecipientService) GetByNotifierID(notifierID int64) ([]*model.EmailRecipient, error) {
+	return m.getByNotifierIDFunc(notifierID)
+}
+
+func (m *mockEmailRecipientService) Verify(token string) (*model.EmailRecipient, error) {
+	return m.verifyFunc(token)
+}
+
+func (m *mockEmailRecipientService) NewObserver(notifier *model.Notifier) (notification.Observer, error) {
+	return nil, nil
+}
+
+// mockTargetLister gives user 1 targets 1 and 2; nobody else owns any
+type mockTargetLister struct{}
+
+func (m *mockTargetLister) GetAllByUserID(userID int) ([]*monitor.Target, error) {
+	if userID != 1 {
+		return nil, nil
+	}
+	return []*monitor.Target{{ID: 1}, {ID: 2}}, nil
+}
+
+// recordingFlashStore keeps the last message set for each key
+type recordingFlashStore struct {
+	messages map[string]any
+}
+
+func (m *recordingFlashStore) SetFlash(flashID, key string, value any) {
+	if m.messages == nil {
+		m.messages = make(map[string]any)
+	}
+	m.messages[key] = value
+}
+
+func (m *recordingFlashStore) GetFlash(flashID, key string) any {
+	return m.messages[key]
+}
+
+func withUser(req *http.Request, id int) *http.Request {
+	return req.WithContext(authService.WithUser(req.Context(), &authModel.User{ID: id, Name: "Alice"}))
 }
 
 func TestNotifierHandler_AuthSlack(t *testing.T) {
 	mockService := new(MockNotifierService)
-	handler := NewNotifierHandler(mockService)
+	handler := NewNotifierHandler(mockService, nil, &mockTargetLister{}, &testutil.MockFlashStore{})
 
 	t.Run("successful redirect", func(t *testing.T) {
 		os.Setenv("SLACK_REDIRECT_URI", "http://example.com/callback")
@@ -67,7 +160,7 @@ func TestNotifierHandler_AuthSlack(t *testing.T) {
 			os.Unsetenv("SLACK_CLIENT_ID")
 		}()
 
-		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/", nil)
+		req := withUser(httptest.NewRequest(http.MethodGet, "/oauth/slack/", nil), 1)
 		req.SetPathValue("targetId", "1")
 		w := httptest.NewRecorder()
 
@@ -92,7 +185,7 @@ func TestNotifierHandler_AuthSlack(t *testing.T) {
 		os.Unsetenv("SLACK_REDIRECT_URI")
 		os.Unsetenv("SLACK_CLIENT_ID")
 
-		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/", nil)
+		req := withUser(httptest.NewRequest(http.MethodGet, "/oauth/slack/", nil), 1)
 		req.SetPathValue("targetId", "1")
 		w := httptest.NewRecorder()
 
@@ -101,6 +194,16 @@ func TestNotifierHandler_AuthSlack(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "Missing environment variables")
 	})
+
+	t.Run("target of another user", func(t *testing.T) {
+		req := withUser(httptest.NewRequest(http.MethodGet, "/oauth/slack/", nil), 2)
+		req.SetPathValue("targetId", "1")
+		w := httptest.NewRecorder()
+
+		handler.AuthSlack(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
 }
 
 func TestNotifierController_AuthSlackCallback(t *testing.T) {
@@ -114,10 +217,10 @@ func TestNotifierController_AuthSlackCallback(t *testing.T) {
 	mockService.createFunc = func(notifier *model.Notifier) error {
 		return nil
 	}
-	controller := NewNotifierHandler(mockService)
+	controller := NewNotifierHandler(mockService, nil, &mockTargetLister{}, &testutil.MockFlashStore{})
 
 	t.Run("successful callback", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=test_code&state=target_id=1", nil)
+		req := withUser(httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=test_code&state=target_id=1", nil), 1)
 		w := httptest.NewRecorder()
 
 		controller.AuthSlackCallback(w, req)
@@ -126,11 +229,20 @@ func TestNotifierController_AuthSlackCallback(t *testing.T) {
 		assert.Equal(t, "/targets/1", w.Header().Get("Location"))
 	})
 
+	t.Run("target of another user", func(t *testing.T) {
+		req := withUser(httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=test_code&state=target_id=1", nil), 2)
+		w := httptest.NewRecorder()
+
+		controller.AuthSlackCallback(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
 	t.Run("invalid code", func(t *testing.T) {
 		mockService.handleSlackCallbackFunc = func(code string, targetId int) (*model.Notifier, error) {
 			return nil, fmt.Errorf("invalid code")
 		}
-		req := httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=&state=target_id=1", nil)
+		req := withUser(httptest.NewRequest(http.MethodGet, "/oauth/slack/callback?code=&state=target_id=1", nil), 1)
 		w := httptest.NewRecorder()
 
 		controller.AuthSlackCallback(w, req)
@@ -152,3 +264,722 @@ func TestNotifierController_AuthSlackCallback(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "invalid state")
 	})
 }
+
+func TestNotifierHandler_List(t *testing.T) {
+	mockService := &MockNotifierService{
+		getByTargetIDFunc: func(targetID int) ([]*model.Notifier, error) {
+			assert.Equal(t, 1, targetID)
+			return []*model.Notifier{
+				{ID: 5, TargetId: 1, Type: model.NotifierTypeSlack, Filter: model.NotifierFilter{
+					Statuses:         []string{"degraded"},
+					MinOutageMinutes: 5,
+					QuietHours:       &model.QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/Berlin"},
+				}},
+				{ID: 6, TargetId: 1, Type: model.NotifierTypeWebhook, Config: json.RawMessage(`{"url": "https://example.com/hook", "secret": "s3cret"}`), Filter: model.NotifierFilter{Statuses: []string{"up"}}},
+				{ID: 7, TargetId: 1, Type: model.NotifierTypeEmail, Config: json.RawMessage(`{"recipients": ["ops@example.com", "cto@example.com"]}`), Filter: model.NotifierFilter{Statuses: []string{"up"}}},
+			}, nil
+		},
+		getDeliveriesFunc: func(notifierID int64, limit int) ([]*model.DeliveryAttempt, error) {
+			if notifierID != 5 {
+				return nil, nil
+			}
+			return []*model.DeliveryAttempt{
+				{Attempt: 2, AttemptedAt: time.Now(), StatusCode: 200, Latency: 85 * time.Millisecond, Summary: "https://example.com is down"},
+				{Attempt: 1, AttemptedAt: time.Now(), StatusCode: 500, Error: "slack API returned non-200 status code: 500", Latency: 120 * time.Millisecond, Summary: "https://example.com is down"},
+			}, nil
+		},
+	}
+	recipientService := &mockEmailRecipientService{
+		getByNotifierIDFunc: func(notifierID int64) ([]*model.EmailRecipient, error) {
+			assert.Equal(t, int64(7), notifierID)
+			return []*model.EmailRecipient{
+				{ID: 1, NotifierID: 7, Email: "ops@example.com", VerifiedAt: time.Now()},
+				{ID: 2, NotifierID: 7, Email: "cto@example.com"},
+			}, nil
+		},
+	}
+	handler := NewNotifierHandler(mockService, recipientService, &mockTargetLister{}, &testutil.MockFlashStore{})
+	handler.Template.List = renderer.New(templates.TemplateFS).GetTemplate("pages:targets/notifiers")
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/targets/1/notifiers", nil), 1)
+	req.SetPathValue("targetId", "1")
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `action="/targets/1/notifiers/5/filter"`)
+	assert.Contains(t, w.Body.String(), `value="degraded" class="mr-2" checked`)
+	assert.NotContains(t, w.Body.String(), `value="down" class="mr-2" checked`)
+	assert.Contains(t, w.Body.String(), `name="min_outage_minutes" min="0" max="1440" value="5"`)
+	assert.Contains(t, w.Body.String(), `name="quiet_start" value="22:00"`)
+	assert.Contains(t, w.Body.String(), `name="quiet_timezone" value="Europe/Berlin"`)
+	assert.Equal(t, 3, strings.Count(w.Body.String(), `name="include_recoveries" value="on" class="mr-2" checked`))
+	assert.NotContains(t, w.Body.String(), "s3cret", "webhook secrets are only shown once")
+	assert.Contains(t, w.Body.String(), `action="/targets/1/notifiers/webhook"`)
+	assert.Contains(t, w.Body.String(), `action="/targets/1/notifiers/email"`)
+	assert.Contains(t, w.Body.String(), `action="/targets/1/notifiers/7/recipients/delete"`)
+	assert.Equal(t, 1, strings.Count(w.Body.String(), "waiting for confirmation"))
+	assert.Equal(t, 1, strings.Count(w.Body.String(), "Recent deliveries"))
+	assert.Contains(t, w.Body.String(), "https://example.com is down (attempt 2)")
+	assert.Contains(t, w.Body.String(), "slack API returned non-200 status code: 500")
+	assert.Contains(t, w.Body.String(), "HTTP 500 · 120 ms")
+
+	t.Run("target of another user", func(t *testing.T) {
+		req := withUser(httptest.NewRequest(http.MethodGet, "/targets/1/notifiers", nil), 2)
+		req.SetPathValue("targetId", "1")
+		w := httptest.NewRecorder()
+
+		handler.List(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestNotifierHandler_UpdateFilter(t *testing.T) {
+	var saved *model.NotifierFilter
+	mockService := &MockNotifierService{
+		getFunc: func(id int64) (*model.Notifier, error) {
+			return &model.Notifier{ID: id, TargetId: 1, Type: model.NotifierTypeSlack}, nil
+		},
+		updateFilterFunc: func(id int64, filter model.NotifierFilter) error {
+			saved = &filter
+			return nil
+		},
+	}
+	handler := NewNotifierHandler(mockService, nil, &mockTargetLister{}, &testutil.MockFlashStore{})
+
+	newRequest := func(targetID string, form url.Values) *http.Request {
+		req := withUser(httptest.NewRequest(http.MethodPost, "/targets/"+targetID+"/notifiers/5/filter", strings.NewReader(form.Encode())), 1)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("targetId", targetID)
+		req.SetPathValue("notifierId", "5")
+		return req
+	}
+
+	t.Run("success", func(t *testing.T) {
+		saved = nil
+		w := httptest.NewRecorder()
+		handler.UpdateFilter(w, newRequest("1", url.Values{"statuses": {"down", "degraded"}, "include_recoveries": {"on"}}))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/targets/1/notifiers", w.Header().Get("Location"))
+		assert.Equal(t, model.NotifierFilter{Statuses: []string{"down", "degraded"}}, *saved)
+	})
+
+	t.Run("every status clears the filter", func(t *testing.T) {
+		saved = nil
+		w := httptest.NewRecorder()
+		handler.UpdateFilter(w, newRequest("1", url.Values{"statuses": model.FilterStatuses, "include_recoveries": {"on"}}))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Nil(t, saved.Statuses)
+	})
+
+	t.Run("timing", func(t *testing.T) {
+		saved = nil
+		w := httptest.NewRecorder()
+		handler.UpdateFilter(w, newRequest("1", url.Values{
+			"min_outage_minutes": {"5"},
+			"quiet_start":        {"22:00"},
+			"quiet_end":          {"07:00"},
+			"quiet_timezone":     {"Europe/Berlin"},
+		}))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, model.NotifierFilter{
+			MinOutageMinutes:  5,
+			ExcludeRecoveries: true,
+			QuietHours:        &model.QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/Berlin"},
+		}, *saved)
+	})
+
+	invalid := []struct {
+		name string
+		form url.Values
+	}{
+		{name: "minimum outage not a number", form: url.Values{"min_outage_minutes": {"five"}}},
+		{name: "negative minimum outage", form: url.Values{"min_outage_minutes": {"-1"}}},
+		{name: "quiet hours without an end", form: url.Values{"quiet_start": {"22:00"}}},
+		{name: "unknown time zone", form: url.Values{"quiet_start": {"22:00"}, "quiet_end": {"07:00"}, "quiet_timezone": {"Mars/Olympus"}}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			saved = nil
+			w := httptest.NewRecorder()
+			handler.UpdateFilter(w, newRequest("1", tt.form))
+
+			assert.Equal(t, http.StatusSeeOther, w.Code)
+			assert.Nil(t, saved, "nothing is saved")
+		})
+	}
+
+	t.Run("unknown status", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.UpdateFilter(w, newRequest("1", url.Values{"statuses": {"sideways"}}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("notifier of another target", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.UpdateFilter(w, newRequest("2", url.Values{"statuses": {"down"}}))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("target of another user", func(t *testing.T) {
+		saved = nil
+		req := newRequest("1", url.Values{"statuses": {"down"}})
+		w := httptest.NewRecorder()
+		handler.UpdateFilter(w, withUser(req, 2))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Nil(t, saved)
+	})
+}
+
+func TestNotifierHandler_CreateWebhook(t *testing.T) {
+	var created *model.Notifier
+	mockService := &MockNotifierService{
+		createFunc: func(notifier *model.Notifier) error {
+			created = notifier
+			return nil
+		},
+	}
+	flashStore := &recordingFlashStore{}
+	handler := NewNotifierHandler(mockService, nil, &mockTargetLister{}, flashStore)
+
+	tests := []struct {
+		name        string
+		form        url.Values
+		wantCreated bool
+		wantConfig  model.WebhookConfig
+	}{
+		{
+			name: "valid webhook",
+			form: url.Values{
+				"url":     {"https://example.com/hook"},
+				"headers": {"Authorization: Bearer abc\r\n\r\nX-Team: ops"},
+				"timeout": {"5"},
+			},
+			wantCreated: true,
+			wantConfig: model.WebhookConfig{
+				URL:            "https://example.com/hook",
+				Headers:        map[string]string{"Authorization": "Bearer abc", "X-Team": "ops"},
+				TimeoutSeconds: 5,
+			},
+		},
+		{name: "invalid URL", form: url.Values{"url": {"ftp://example.com"}}},
+		{name: "malformed header", form: url.Values{"url": {"https://example.com/hook"}, "headers": {"Authorization"}}},
+		{name: "timeout not a number", form: url.Values{"url": {"https://example.com/hook"}, "timeout": {"soon"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = nil
+			req := httptest.NewRequest(http.MethodPost, "/targets/1/notifiers/webhook", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetPathValue("targetId", "1")
+			req = withUser(req, 1)
+			w := httptest.NewRecorder()
+
+			handler.CreateWebhook(w, req)
+
+			assert.Equal(t, http.StatusSeeOther, w.Code)
+			assert.Equal(t, "/targets/1/notifiers", w.Header().Get("Location"))
+			if !tt.wantCreated {
+				assert.Nil(t, created)
+				return
+			}
+
+			if assert.NotNil(t, created) {
+				assert.Equal(t, 1, created.TargetId)
+				assert.Equal(t, model.NotifierTypeWebhook, created.Type)
+				config, err := created.GetWebhookConfig()
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantConfig, *config)
+			}
+		})
+	}
+
+	t.Run("secret shown once", func(t *testing.T) {
+		form := url.Values{"url": {"https://example.com/hook"}, "secret": {"s3cret"}}
+		req := httptest.NewRequest(http.MethodPost, "/targets/1/notifiers/webhook", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("targetId", "1")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.CreateWebhook(w, req)
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Contains(t, flashStore.messages["success"], "s3cret")
+	})
+
+	t.Run("target of another user", func(t *testing.T) {
+		created = nil
+		form := url.Values{"url": {"https://example.com/hook"}}
+		req := httptest.NewRequest(http.MethodPost, "/targets/3/notifiers/webhook", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("targetId", "3")
+		req = withUser(req, 1)
+		w := httptest.NewRecorder()
+
+		handler.CreateWebhook(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Nil(t, created)
+	})
+}
+
+func TestNotifierHandler_CreateChatWebhook(t *testing.T) {
+	var created *model.Notifier
+	mockService := &MockNotifierService{
+		createFunc: func(notifier *model.Notifier) error {
+			created = notifier
+			return nil
+		},
+	}
+	handler := NewNotifierHandler(mockService, nil, &mockTargetLister{}, &testutil.MockFlashStore{})
+
+	tests := []struct {
+		name        string
+		create      http.HandlerFunc
+		url         string
+		wantType    model.NotifierType
+		wantCreated bool
+	}{
+		{name: "discord", create: handler.CreateDiscord, url: "https://discord.com/api/webhooks/1/abc", wantType: model.NotifierTypeDiscord, wantCreated: true},
+		{name: "teams", create: handler.CreateMSTeams, url: "https://example.webhook.office.com/hook", wantType: model.NotifierTypeMSTeams, wantCreated: true},
+		{name: "discord without https", create: handler.CreateDiscord, url: "http://discord.com/api/webhooks/1/abc"},
+		{name: "teams without URL", create: handler.CreateMSTeams, url: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = nil
+			form := url.Values{"webhook_url": {tt.url}}
+			req := httptest.NewRequest(http.MethodPost, "/targets/1/notifiers/x", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetPathValue("targetId", "1")
+			req = withUser(req, 1)
+			w := httptest.NewRecorder()
+
+			tt.create(w, req)
+
+			assert.Equal(t, http.StatusSeeOther, w.Code)
+			assert.Equal(t, "/targets/1/notifiers", w.Header().Get("Location"))
+			if !tt.wantCreated {
+				assert.Nil(t, created)
+				return
+			}
+
+			if assert.NotNil(t, created) {
+				assert.Equal(t, 1, created.TargetId)
+				assert.Equal(t, tt.wantType, created.Type)
+				assert.JSONEq(t, `{"webhook_url": "`+tt.url+`"}`, string(created.Config))
+			}
+		})
+	}
+
+	t.Run("target of another user", func(t *testing.T) {
+		for _, create := range []http.HandlerFunc{handler.CreateDiscord, handler.CreateMSTeams} {
+			created = nil
+			form := url.Values{"webhook_url": {"https://discord.com/api/webhooks/1/abc"}}
+			req := httptest.NewRequest(http.MethodPost, "/targets/3/notifiers/x", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetPathValue("targetId", "3")
+			req = withUser(req, 1)
+			w := httptest.NewRecorder()
+
+			create(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Nil(t, created)
+		}
+	})
+}
+
+func TestNotifierHandler_CreateTelegram(t *testing.T) {
+	var created *model.Notifier
+	mockService := &MockNotifierService{
+		createFunc: func(notifier *model.Notifier) error {
+			created = notifier
+			return nil
+		},
+	}
+	handler := NewNotifierHandler(mockService, nil, &mockTargetLister{}, &testutil.MockFlashStore{})
+
+	tests := []struct {
+		name       string
+		form       url.Values
+		wantConfig *model.TelegramConfig
+	}{
+		{
+			name:       "silent recoveries",
+			form:       url.Values{"bot_token": {" 123456:ABC-DEF "}, "chat_id": {"-10042"}, "silent_recoveries": {"on"}},
+			wantConfig: &model.TelegramConfig{BotToken: "123456:ABC-DEF", ChatID: "-10042", SilentRecoveries: true},
+		},
+		{
+			name:       "recoveries with sound",
+			form:       url.Values{"bot_token": {"123456:ABC-DEF"}, "chat_id": {"@uptime_alerts"}},
+			wantConfig: &model.TelegramConfig{BotToken: "123456:ABC-DEF", ChatID: "@uptime_alerts"},
+		},
+		{name: "malformed token", form: url.Values{"bot_token": {"ABC-DEF"}, "chat_id": {"42"}}},
+		{name: "missing chat ID", form: url.Values{"bot_token": {"123456:ABC-DEF"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = nil
+			req := httptest.NewRequest(http.MethodPost, "/targets/1/notifiers/telegram", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetPathValue("targetId", "1")
+			req = withUser(req, 1)
+			w := httptest.NewRecorder()
+
+			handler.CreateTelegram(w, req)
+
+			assert.Equal(t, http.StatusSeeOther, w.Code)
+			assert.Equal(t, "/targets/1/notifiers", w.Header().Get("Location"))
+			if tt.wantConfig == nil {
+				assert.Nil(t, created)
+				return
+			}
+
+			if assert.NotNil(t, created) {
+				assert.Equal(t, model.NotifierTypeTelegram, created.Type)
+				config, err := created.GetTelegramConfig()
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantConfig, config)
+			}
+		})
+	}
+
+	t.Run("target of another user", func(t *testing.T) {
+		created = nil
+		form := url.Values{"bot_token": {"123456:ABC-DEF"}, "chat_id": {"-10042"}}
+		req := httptest.NewRequest(http.MethodPost, "/targets/1/notifiers/telegram", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("targetId", "1")
+		req = withUser(req, 2)
+		w := httptest.NewRecorder()
+
+		handler.CreateTelegram(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Nil(t, created)
+	})
+}
+
+func TestNotifierHandler_CreatePaging(t *testing.T) {
+	var created *model.Notifier
+	mockService := &MockNotifierService{
+		createFunc: func(notifier *model.Notifier) error {
+			created = notifier
+			return nil
+		},
+	}
+	handler := NewNotifierHandler(mockService, nil, &mockTargetLister{}, &testutil.MockFlashStore{})
+
+	tests := []struct {
+		name       string
+		create     http.HandlerFunc
+		form       url.Values
+		wantType   model.NotifierType
+		wantConfig string
+	}{
+		{
+			name:       "pagerduty",
+			create:     handler.CreatePagerDuty,
+			form:       url.Values{"routing_key": {" R0123456789ABCDEF0123456789abcde "}},
+			wantType:   model.NotifierTypePagerDuty,
+			wantConfig: `{"routing_key": "R0123456789ABCDEF0123456789abcde"}`,
+		},
+		{
+			name:       "opsgenie",
+			create:     handler.CreateOpsgenie,
+			form:       url.Values{"api_key": {"eb243592-faa2-4ba2-a551-1afdf565c889"}},
+			wantType:   model.NotifierTypeOpsgenie,
+			wantConfig: `{"api_key": "eb243592-faa2-4ba2-a551-1afdf565c889"}`,
+		},
+		{name: "pagerduty with a short key", create: handler.CreatePagerDuty, form: url.Values{"routing_key": {"R0123"}}},
+		{name: "opsgenie without API key", create: handler.CreateOpsgenie, form: url.Values{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = nil
+			req := httptest.NewRequest(http.MethodPost, "/targets/1/notifiers/x", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetPathValue("targetId", "1")
+			req = withUser(req, 1)
+			w := httptest.NewRecorder()
+
+			tt.create(w, req)
+
+			assert.Equal(t, http.StatusSeeOther, w.Code)
+			assert.Equal(t, "/targets/1/notifiers", w.Header().Get("Location"))
+			if tt.wantConfig == "" {
+				assert.Nil(t, created)
+				return
+			}
+
+			if assert.NotNil(t, created) {
+				assert.Equal(t, tt.wantType, created.Type)
+				assert.JSONEq(t, tt.wantConfig, string(created.Config))
+			}
+		})
+	}
+
+	t.Run("target of another user", func(t *testing.T) {
+		for _, create := range []http.HandlerFunc{handler.CreatePagerDuty, handler.CreateOpsgenie} {
+			created = nil
+			form := url.Values{"routing_key": {"R0123456789ABCDEF0123456789abcde"}, "api_key": {"eb243592-faa2-4ba2-a551-1afdf565c889"}}
+			req := httptest.NewRequest(http.MethodPost, "/targets/1/notifiers/x", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetPathValue("targetId", "1")
+			req = withUser(req, 2)
+			w := httptest.NewRecorder()
+
+			create(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Nil(t, created)
+		}
+	})
+}
+
+func TestNotifierHandler_SendTest(t *testing.T) {
+	var sent []*model.Notifier
+	var sendErr error
+	mockService := &MockNotifierService{
+		getFunc: func(id int64) (*model.Notifier, error) {
+			return &model.Notifier{ID: id, TargetId: 1, Type: model.NotifierTypeDiscord}, nil
+		},
+		sendTestFunc: func(notifier *model.Notifier) error {
+			sent = append(sent, notifier)
+			return sendErr
+		},
+	}
+	handler := NewNotifierHandler(mockService, nil, &mockTargetLister{}, &testutil.MockFlashStore{})
+
+	newRequest := func(targetID string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/targets/"+targetID+"/notifiers/5/test", nil)
+		req.SetPathValue("targetId", targetID)
+		req.SetPathValue("notifierId", "5")
+		return withUser(req, 1)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.SendTest(w, newRequest("1"))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/targets/1/notifiers", w.Header().Get("Location"))
+		if assert.Len(t, sent, 1) {
+			assert.Equal(t, int64(5), sent[0].ID)
+		}
+	})
+
+	t.Run("failure redirects back", func(t *testing.T) {
+		sendErr = errors.New("discord returned non-2xx status code: 404")
+		w := httptest.NewRecorder()
+		handler.SendTest(w, newRequest("1"))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/targets/1/notifiers", w.Header().Get("Location"))
+		assert.Len(t, sent, 2)
+	})
+
+	t.Run("notifier of another target", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.SendTest(w, newRequest("2"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Len(t, sent, 2)
+	})
+
+	t.Run("target of another user", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.SendTest(w, withUser(newRequest("1"), 2))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Len(t, sent, 2)
+	})
+}
+
+func TestNotifierHandler_AddEmailRecipient(t *testing.T) {
+	existing := &model.Notifier{ID: 7, TargetId: 2, Type: model.NotifierTypeEmail, Config: json.RawMessage(`{"recipients": ["ops@example.com"]}`)}
+
+	var created *model.Notifier
+	var updated json.RawMessage
+	mockService := &MockNotifierService{
+		getByTargetIDFunc: func(targetID int) ([]*model.Notifier, error) {
+			if targetID == 2 {
+				copied := *existing
+				return []*model.Notifier{{ID: 1, TargetId: 2, Type: model.NotifierTypeSlack}, &copied}, nil
+			}
+			return nil, nil
+		},
+		createFunc: func(notifier *model.Notifier) error {
+			created = notifier
+			return nil
+		},
+		updateFunc: func(id int, config json.RawMessage) (*model.Notifier, error) {
+			assert.Equal(t, 7, id)
+			updated = config
+			return existing, nil
+		},
+	}
+	handler := NewNotifierHandler(mockService, &mockEmailRecipientService{}, &mockTargetLister{}, &testutil.MockFlashStore{})
+
+	newRequest := func(targetID, email string) *http.Request {
+		form := url.Values{"email": {email}}
+		req := httptest.NewRequest(http.MethodPost, "/targets/"+targetID+"/notifiers/email", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("targetId", targetID)
+		return withUser(req, 1)
+	}
+
+	t.Run("first recipient creates the notifier", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.AddEmailRecipient(w, newRequest("1", " ops@example.com "))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/targets/1/notifiers", w.Header().Get("Location"))
+		if assert.NotNil(t, created) {
+			assert.Equal(t, model.NotifierTypeEmail, created.Type)
+			assert.Equal(t, 1, created.TargetId)
+			assert.JSONEq(t, `{"recipients": ["ops@example.com"]}`, string(created.Config))
+		}
+	})
+
+	t.Run("later recipients join the notifier", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.AddEmailRecipient(w, newRequest("2", "cto@example.com"))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.JSONEq(t, `{"recipients": ["ops@example.com", "cto@example.com"]}`, string(updated))
+	})
+
+	t.Run("invalid or repeated address", func(t *testing.T) {
+		updated = nil
+		for _, email := range []string{"not an address", "OPS@example.com"} {
+			w := httptest.NewRecorder()
+			handler.AddEmailRecipient(w, newRequest("2", email))
+
+			assert.Equal(t, http.StatusSeeOther, w.Code)
+			assert.Nil(t, updated)
+		}
+	})
+
+	t.Run("target of another user", func(t *testing.T) {
+		created, updated = nil, nil
+		w := httptest.NewRecorder()
+		handler.AddEmailRecipient(w, withUser(newRequest("2", "cto@example.com"), 2))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Nil(t, created)
+		assert.Nil(t, updated)
+	})
+}
+
+func TestNotifierHandler_RemoveEmailRecipient(t *testing.T) {
+	var updated json.RawMessage
+	var deleted int64
+	mockService := &MockNotifierService{
+		getFunc: func(id int64) (*model.Notifier, error) {
+			switch id {
+			case 7:
+				return &model.Notifier{ID: 7, TargetId: 1, Type: model.NotifierTypeEmail, Config: json.RawMessage(`{"recipients": ["ops@example.com", "cto@example.com"]}`)}, nil
+			case 8:
+				return &model.Notifier{ID: 8, TargetId: 1, Type: model.NotifierTypeEmail, Config: json.RawMessage(`{"recipients": ["ops@example.com"]}`)}, nil
+			}
+			return nil, nil
+		},
+		updateFunc: func(id int, config json.RawMessage) (*model.Notifier, error) {
+			updated = config
+			return &model.Notifier{}, nil
+		},
+		deleteFunc: func(id int64) error {
+			deleted = id
+			return nil
+		},
+	}
+	handler := NewNotifierHandler(mockService, &mockEmailRecipientService{}, &mockTargetLister{}, &testutil.MockFlashStore{})
+
+	newRequest := func(targetID, notifierID string) *http.Request {
+		form := url.Values{"email": {"ops@example.com"}}
+		req := httptest.NewRequest(http.MethodPost, "/targets/"+targetID+"/notifiers/"+notifierID+"/recipients/delete", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("targetId", targetID)
+		req.SetPathValue("notifierId", notifierID)
+		return withUser(req, 1)
+	}
+
+	t.Run("remove one of several", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.RemoveEmailRecipient(w, newRequest("1", "7"))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.JSONEq(t, `{"recipients": ["cto@example.com"]}`, string(updated))
+		assert.Zero(t, deleted)
+	})
+
+	t.Run("last recipient deletes the notifier", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.RemoveEmailRecipient(w, newRequest("1", "8"))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, int64(8), deleted)
+	})
+
+	t.Run("notifier of another target", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.RemoveEmailRecipient(w, newRequest("2", "7"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("target of another user", func(t *testing.T) {
+		updated, deleted = nil, 0
+		w := httptest.NewRecorder()
+		handler.RemoveEmailRecipient(w, withUser(newRequest("1", "7"), 2))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Nil(t, updated)
+		assert.Zero(t, deleted)
+	})
+}
+
+func TestNotifierHandler_VerifyEmail(t *testing.T) {
+	recipientService := &mockEmailRecipientService{
+		verifyFunc: func(token string) (*model.EmailRecipient, error) {
+			if token != "token-1" {
+				return nil, service.ErrInvalidRecipientToken
+			}
+			return &model.EmailRecipient{Email: "ops@example.com", VerifiedAt: time.Now()}, nil
+		},
+	}
+	handler := NewNotifierHandler(&MockNotifierService{}, recipientService, &mockTargetLister{}, &testutil.MockFlashStore{})
+	handler.Template.Verified = renderer.New(templates.TemplateFS).GetTemplate("pages:notifiers/verified")
+
+	t.Run("valid link", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.VerifyEmail(w, httptest.NewRequest(http.MethodGet, "/notifiers/verify-email?token=token-1", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "ops@example.com")
+		assert.NotContains(t, w.Body.String(), "Logout", "recipients need not be signed in")
+	})
+
+	t.Run("unknown link", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.VerifyEmail(w, httptest.NewRequest(http.MethodGet, "/notifiers/verify-email?token=other", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Link not valid")
+	})
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// EmailRecipient records whether an address of an email notifier has
+// confirmed it wants the notifier's mail. Nothing is sent to an address
+// until it has.
+type EmailRecipient struct {
+	ID         int64
+	NotifierID int64
+	Email      string
+	Token      string // sent in the confirmation link
+	CreatedAt  time.Time
+	VerifiedAt time.Time // zero until confirmed
+}
+
+// IsVerified reports whether the address has been confirmed
+func (r *EmailRecipient) IsVerified() bool {
+	return !r.VerifiedAt.IsZero()
+}
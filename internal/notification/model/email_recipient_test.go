@@ -0,0 +1,16 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailRecipient_IsVerified(t *testing.T) {
+	recipient := EmailRecipient{Email: "ops@example.com"}
+	assert.False(t, recipient.IsVerified())
+
+	recipient.VerifiedAt = time.Now()
+	assert.True(t, recipient.IsVerified())
+}
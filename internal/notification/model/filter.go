@@ -0,0 +1,181 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+)
+
+// NotifierFilter restricts which status changes a notifier is sent, and
+// when. The zero value sends everything as soon as it happens.
+type NotifierFilter struct {
+	Statuses []string `json:"statuses,omitempty"`
+	// MinOutageMinutes holds back alerts about an outage until it has
+	// lasted this long. Outages that end sooner are never announced, and
+	// neither is the recovery from them.
+	MinOutageMinutes int `json:"min_outage_minutes,omitempty"`
+	// ExcludeRecoveries drops the changes that end an outage
+	ExcludeRecoveries bool `json:"exclude_recoveries,omitempty"`
+	// QuietHours holds back everything that happens during a daily window
+	// until the window closes
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+}
+
+// FilterStatuses are the statuses a notifier filter can select
+var FilterStatuses = []string{"up", "degraded", "down", "error", "paused", "certificate_expiring", "dns_changed"}
+
+// MaxMinOutageMinutes is the longest an outage alert can be held back
+const MaxMinOutageMinutes = 24 * 60
+
+// OutageStatuses are the statuses of a failing target
+var OutageStatuses = []string{"down", "error"}
+
+// IsOutage reports whether status means the target is failing
+func IsOutage(status string) bool {
+	return slices.Contains(OutageStatuses, status)
+}
+
+// IsRecovery reports whether the state ends an outage
+func IsRecovery(state notification.State) bool {
+	return (state.Status == "up" || state.Status == "degraded") && IsOutage(state.PreviousStatus)
+}
+
+// Includes reports whether changes to status pass the filter
+func (f NotifierFilter) Includes(status string) bool {
+	return len(f.Statuses) == 0 || slices.Contains(f.Statuses, status)
+}
+
+// Accepts reports whether the notifier should be sent the state
+func (f NotifierFilter) Accepts(state notification.State) bool {
+	if f.ExcludeRecoveries && IsRecovery(state) {
+		return false
+	}
+	return f.Includes(state.Status)
+}
+
+// DeliverAt returns when a message about state, accepted at now, may be
+// sent: once the outage has lasted long enough and outside quiet hours
+func (f NotifierFilter) DeliverAt(state notification.State, now time.Time) time.Time {
+	at := now
+	if f.MinOutageMinutes > 0 && IsOutage(state.Status) {
+		if start := OutageStart(state); !start.IsZero() {
+			at = latest(at, start.Add(f.MinOutage()))
+		}
+	}
+	if f.QuietHours != nil {
+		if end, ok := f.QuietHours.EndOf(at); ok {
+			at = end
+		}
+	}
+	return at
+}
+
+// MinOutage returns how long an outage must last before it is announced
+func (f NotifierFilter) MinOutage() time.Duration {
+	return time.Duration(f.MinOutageMinutes) * time.Minute
+}
+
+// OutageStart returns when the outage state is about began, or when the
+// state was recorded if that is unknown
+func OutageStart(state notification.State) time.Time {
+	if state.OutageStart.IsZero() {
+		return state.UpdatedAt
+	}
+	return state.OutageStart
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// Validate checks the filter only selects known statuses and its times make
+// sense
+func (f NotifierFilter) Validate() error {
+	for _, status := range f.Statuses {
+		if !slices.Contains(FilterStatuses, status) {
+			return fmt.Errorf("invalid filter status: %s", status)
+		}
+	}
+	if f.MinOutageMinutes < 0 || f.MinOutageMinutes > MaxMinOutageMinutes {
+		return fmt.Errorf("minimum outage must be between 0 and %d minutes", MaxMinOutageMinutes)
+	}
+	if f.QuietHours != nil {
+		if err := f.QuietHours.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// QuietHours is a daily window, in a time zone, during which a notifier is
+// not disturbed. Start and End are "15:04" times; a window ending before it
+// starts runs past midnight.
+type QuietHours struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone,omitempty"` // IANA name, UTC if empty
+}
+
+// Validate checks the times parse, differ, and the time zone is known
+func (q *QuietHours) Validate() error {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return fmt.Errorf("invalid quiet hours start: %w", err)
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return fmt.Errorf("invalid quiet hours end: %w", err)
+	}
+	if start == end {
+		return fmt.Errorf("quiet hours must start and end at different times")
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("unknown time zone: %s", q.Timezone)
+	}
+	return nil
+}
+
+// EndOf reports whether t falls within the quiet hours, and if so when they
+// end. Unusable quiet hours never apply.
+func (q *QuietHours) EndOf(t time.Time) (time.Time, bool) {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := parseClock(q.End)
+	if err != nil || start == end {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := t.In(loc)
+	now := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	day := local
+	switch {
+	case start < end && now >= start && now < end:
+	case start > end && now >= start:
+		// The window runs past midnight and ends tomorrow
+		day = local.AddDate(0, 0, 1)
+	case start > end && now < end:
+	default:
+		return time.Time{}, false
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), int(end/time.Hour), int(end%time.Hour/time.Minute), 0, 0, loc), true
+}
+
+// parseClock parses a "15:04" time of day into the time since midnight
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time like 22:30", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifierFilter_Accepts(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter NotifierFilter
+		state  notification.State
+		want   bool
+	}{
+		{name: "empty filter accepts all", filter: NotifierFilter{}, state: notification.State{Status: "degraded"}, want: true},
+		{name: "listed status", filter: NotifierFilter{Statuses: []string{"down", "degraded"}}, state: notification.State{Status: "degraded"}, want: true},
+		{name: "unlisted status", filter: NotifierFilter{Statuses: []string{"down"}}, state: notification.State{Status: "degraded"}, want: false},
+		{name: "recovery", filter: NotifierFilter{}, state: notification.State{Status: "up", PreviousStatus: "down"}, want: true},
+		{name: "recovery excluded", filter: NotifierFilter{ExcludeRecoveries: true}, state: notification.State{Status: "up", PreviousStatus: "error"}, want: false},
+		{name: "degraded after outage excluded", filter: NotifierFilter{ExcludeRecoveries: true}, state: notification.State{Status: "degraded", PreviousStatus: "down"}, want: false},
+		{name: "up without outage kept", filter: NotifierFilter{ExcludeRecoveries: true}, state: notification.State{Status: "up", PreviousStatus: "pending"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Accepts(tt.state))
+		})
+	}
+}
+
+func TestNotifierFilter_DeliverAt(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	// A weekday afternoon in Berlin
+	now := time.Date(2025, 6, 10, 14, 0, 0, 0, berlin)
+	night := &QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/Berlin"}
+
+	tests := []struct {
+		name   string
+		filter NotifierFilter
+		state  notification.State
+		now    time.Time
+		want   time.Time
+	}{
+		{name: "no filter", state: notification.State{Status: "down"}, now: now, want: now},
+		{
+			name:   "outage held",
+			filter: NotifierFilter{MinOutageMinutes: 5},
+			state:  notification.State{Status: "down", UpdatedAt: now},
+			now:    now,
+			want:   now.Add(5 * time.Minute),
+		},
+		{
+			name:   "outage counted from its start",
+			filter: NotifierFilter{MinOutageMinutes: 5},
+			state:  notification.State{Status: "error", UpdatedAt: now, OutageStart: now.Add(-3 * time.Minute)},
+			now:    now,
+			want:   now.Add(2 * time.Minute),
+		},
+		{
+			name:   "outage already long enough",
+			filter: NotifierFilter{MinOutageMinutes: 5},
+			state:  notification.State{Status: "down", UpdatedAt: now, OutageStart: now.Add(-time.Hour)},
+			now:    now,
+			want:   now,
+		},
+		{
+			name:   "recovery not held",
+			filter: NotifierFilter{MinOutageMinutes: 5},
+			state:  notification.State{Status: "up", UpdatedAt: now},
+			now:    now,
+			want:   now,
+		},
+		{
+			name:   "outside quiet hours",
+			filter: NotifierFilter{QuietHours: night},
+			state:  notification.State{Status: "down"},
+			now:    now,
+			want:   now,
+		},
+		{
+			name:   "quiet hours before midnight",
+			filter: NotifierFilter{QuietHours: night},
+			state:  notification.State{Status: "up"},
+			now:    time.Date(2025, 6, 10, 23, 30, 0, 0, berlin),
+			want:   time.Date(2025, 6, 11, 7, 0, 0, 0, berlin),
+		},
+		{
+			name:   "quiet hours after midnight",
+			filter: NotifierFilter{QuietHours: night},
+			state:  notification.State{Status: "down"},
+			now:    time.Date(2025, 6, 11, 3, 0, 0, 0, berlin),
+			want:   time.Date(2025, 6, 11, 7, 0, 0, 0, berlin),
+		},
+		{
+			name:   "held outage ends in quiet hours",
+			filter: NotifierFilter{MinOutageMinutes: 10, QuietHours: night},
+			state:  notification.State{Status: "down", UpdatedAt: time.Date(2025, 6, 10, 21, 55, 0, 0, berlin)},
+			now:    time.Date(2025, 6, 10, 21, 55, 0, 0, berlin),
+			want:   time.Date(2025, 6, 11, 7, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.DeliverAt(tt.state, tt.now)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestQuietHours_EndOf(t *testing.T) {
+	day := &QuietHours{Start: "09:00", End: "17:00"}
+
+	end, ok := day.EndOf(time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 6, 10, 17, 0, 0, 0, time.UTC), end)
+
+	_, ok = day.EndOf(time.Date(2025, 6, 10, 17, 0, 0, 0, time.UTC))
+	assert.False(t, ok, "the end is outside the window")
+
+	_, ok = day.EndOf(time.Date(2025, 6, 10, 8, 59, 0, 0, time.UTC))
+	assert.False(t, ok)
+
+	// 22:00 UTC is already tomorrow in Tokyo
+	tokyo := &QuietHours{Start: "06:00", End: "08:00", Timezone: "Asia/Tokyo"}
+	end, ok = tokyo.EndOf(time.Date(2025, 6, 10, 22, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 6, 10, 23, 0, 0, 0, time.UTC), end.UTC())
+
+	_, ok = (&QuietHours{Start: "late", End: "07:00"}).EndOf(time.Now())
+	assert.False(t, ok, "unusable quiet hours never apply")
+}
+
+func TestNotifierFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  NotifierFilter
+		wantErr bool
+	}{
+		{name: "empty", filter: NotifierFilter{}},
+		{name: "everything", filter: NotifierFilter{
+			Statuses:          []string{"down", "paused"},
+			MinOutageMinutes:  5,
+			ExcludeRecoveries: true,
+			QuietHours:        &QuietHours{Start: "22:00", End: "07:00", Timezone: "America/New_York"},
+		}},
+		{name: "unknown status", filter: NotifierFilter{Statuses: []string{"sideways"}}, wantErr: true},
+		{name: "negative minimum outage", filter: NotifierFilter{MinOutageMinutes: -1}, wantErr: true},
+		{name: "minimum outage too long", filter: NotifierFilter{MinOutageMinutes: MaxMinOutageMinutes + 1}, wantErr: true},
+		{name: "bad quiet hours start", filter: NotifierFilter{QuietHours: &QuietHours{Start: "25:00", End: "07:00"}}, wantErr: true},
+		{name: "bad quiet hours end", filter: NotifierFilter{QuietHours: &QuietHours{Start: "22:00", End: "7am"}}, wantErr: true},
+		{name: "empty quiet hours", filter: NotifierFilter{QuietHours: &QuietHours{Start: "22:00", End: "22:00"}}, wantErr: true},
+		{name: "unknown time zone", filter: NotifierFilter{QuietHours: &QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
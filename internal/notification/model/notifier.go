@@ -1,23 +1,133 @@
 package model
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
 )
 
 // NotifierType represents the type of notifier
 type NotifierType string
 
 const (
-	NotifierTypeSlack NotifierType = "slack"
-	NotifierTypeEmail NotifierType = "email"
+	NotifierTypeSlack     NotifierType = "slack"
+	NotifierTypeEmail     NotifierType = "email"
+	NotifierTypeWebhook   NotifierType = "webhook"
+	NotifierTypeDiscord   NotifierType = "discord"
+	NotifierTypeMSTeams   NotifierType = "msteams"
+	NotifierTypeTelegram  NotifierType = "telegram"
+	NotifierTypePagerDuty NotifierType = "pagerduty"
+	NotifierTypeOpsgenie  NotifierType = "opsgenie"
 )
 
+// NotifierTypes are the notifier types that can be configured
+var NotifierTypes = []NotifierType{NotifierTypeSlack, NotifierTypeEmail, NotifierTypeWebhook, NotifierTypeDiscord, NotifierTypeMSTeams, NotifierTypeTelegram, NotifierTypePagerDuty, NotifierTypeOpsgenie}
+
+// IsPaging reports whether notifiers of the type open incidents on a paging
+// service, which must hear about recoveries to resolve them
+func (t NotifierType) IsPaging() bool {
+	return t == NotifierTypePagerDuty || t == NotifierTypeOpsgenie
+}
+
 // Notifier represents a notification channel configuration
 type Notifier struct {
 	ID       int64           `db:"id"`
 	TargetId int             `db:"target_id"`
 	Type     NotifierType    `db:"type"`
 	Config   json.RawMessage `db:"config"`
+	Filter   NotifierFilter  `db:"filter"`
+}
+
+// Accepts reports whether the notifier should be sent the state. Paging
+// notifiers are sent every change that resolves an incident whatever their
+// filter, so the incidents they opened get resolved too.
+func (n *Notifier) Accepts(state notification.State) bool {
+	if n.Type.IsPaging() && (state.Status == "up" || state.Status == "degraded") {
+		return true
+	}
+	return n.Filter.Accepts(state)
+}
+
+// Validate checks the notifier has a known type, a usable configuration for
+// it, and a usable filter
+func (n *Notifier) Validate() error {
+	switch n.Type {
+	case NotifierTypeSlack:
+		config, err := n.GetSlackConfig()
+		if err != nil {
+			return fmt.Errorf("invalid slack config: %w", err)
+		}
+		if config.WebhookURL == "" {
+			return fmt.Errorf("webhook URL is required for slack notifier")
+		}
+	case NotifierTypeEmail:
+		config, err := n.GetEmailConfig()
+		if err != nil {
+			return fmt.Errorf("invalid email config: %w", err)
+		}
+		if err := config.Validate(); err != nil {
+			return err
+		}
+	case NotifierTypeDiscord:
+		config, err := n.GetDiscordConfig()
+		if err != nil {
+			return fmt.Errorf("invalid discord config: %w", err)
+		}
+		if !isHTTPSURL(config.WebhookURL) {
+			return fmt.Errorf("discord webhook URL must be an https URL")
+		}
+	case NotifierTypeMSTeams:
+		config, err := n.GetMSTeamsConfig()
+		if err != nil {
+			return fmt.Errorf("invalid teams config: %w", err)
+		}
+		if !isHTTPSURL(config.WebhookURL) {
+			return fmt.Errorf("teams webhook URL must be an https URL")
+		}
+	case NotifierTypeTelegram:
+		config, err := n.GetTelegramConfig()
+		if err != nil {
+			return fmt.Errorf("invalid telegram config: %w", err)
+		}
+		if err := config.Validate(); err != nil {
+			return err
+		}
+	case NotifierTypePagerDuty:
+		config, err := n.GetPagerDutyConfig()
+		if err != nil {
+			return fmt.Errorf("invalid pagerduty config: %w", err)
+		}
+		if !pagerDutyRoutingKeyPattern.MatchString(config.RoutingKey) {
+			return fmt.Errorf("pagerduty integration key must be 32 letters and digits")
+		}
+	case NotifierTypeOpsgenie:
+		config, err := n.GetOpsgenieConfig()
+		if err != nil {
+			return fmt.Errorf("invalid opsgenie config: %w", err)
+		}
+		if strings.TrimSpace(config.APIKey) == "" || strings.ContainsAny(config.APIKey, " \r\n") {
+			return fmt.Errorf("opsgenie API key is required")
+		}
+	case NotifierTypeWebhook:
+		config, err := n.GetWebhookConfig()
+		if err != nil {
+			return fmt.Errorf("invalid webhook config: %w", err)
+		}
+		if err := config.Validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported notifier type: %s", n.Type)
+	}
+
+	return n.Filter.Validate()
 }
 
 // SlackConfig represents Slack notifier configuration
@@ -25,11 +135,132 @@ type SlackConfig struct {
 	WebhookURL string `json:"webhook_url"`
 }
 
-// EmailConfig represents email notifier configuration
+// DiscordConfig represents Discord notifier configuration
+type DiscordConfig struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+// MSTeamsConfig represents Microsoft Teams notifier configuration. The URL
+// is that of an incoming webhook or a workflow accepting Adaptive Cards.
+type MSTeamsConfig struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+// TelegramConfig represents Telegram notifier configuration. ChatID is the
+// numeric ID of a chat, group or channel, or a channel's @username.
+type TelegramConfig struct {
+	BotToken string `json:"bot_token"`
+	ChatID   string `json:"chat_id"`
+	// SilentRecoveries delivers messages about a target coming back up
+	// without a sound
+	SilentRecoveries bool `json:"silent_recoveries,omitempty"`
+}
+
+var (
+	telegramTokenPattern  = regexp.MustCompile(`^[0-9]+:[A-Za-z0-9_-]+$`)
+	telegramChatIDPattern = regexp.MustCompile(`^(-?[0-9]+|@[A-Za-z][A-Za-z0-9_]{4,31})$`)
+)
+
+// Validate checks the token and chat ID look like ones Telegram issues
+func (c *TelegramConfig) Validate() error {
+	if !telegramTokenPattern.MatchString(c.BotToken) {
+		return fmt.Errorf("telegram bot token must look like \"123456:ABC-DEF...\"")
+	}
+	if !telegramChatIDPattern.MatchString(c.ChatID) {
+		return fmt.Errorf("telegram chat ID must be a number or a @channel username")
+	}
+	return nil
+}
+
+// PagerDutyConfig represents PagerDuty notifier configuration. The routing
+// key is the integration key of an Events API v2 integration on a service.
+type PagerDutyConfig struct {
+	RoutingKey string `json:"routing_key"`
+}
+
+var pagerDutyRoutingKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]{32}$`)
+
+// OpsgenieConfig represents Opsgenie notifier configuration. The API key is
+// that of an API integration allowed to create and update alerts.
+type OpsgenieConfig struct {
+	APIKey string `json:"api_key"`
+}
+
+func isHTTPSURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
+// EmailConfig represents email notifier configuration. Recipients are only
+// mailed once they have confirmed their address.
 type EmailConfig struct {
 	Recipients []string `json:"recipients"`
 }
 
+// MaxEmailRecipients bounds how many addresses one email notifier mails
+const MaxEmailRecipients = 20
+
+// Validate checks every recipient is a plain email address, listed once
+func (c *EmailConfig) Validate() error {
+	if len(c.Recipients) == 0 {
+		return fmt.Errorf("at least one recipient is required for email notifier")
+	}
+	if len(c.Recipients) > MaxEmailRecipients {
+		return fmt.Errorf("an email notifier can have at most %d recipients", MaxEmailRecipients)
+	}
+	seen := make(map[string]bool)
+	for _, recipient := range c.Recipients {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil || address.Address != recipient {
+			return fmt.Errorf("invalid email address: %q", recipient)
+		}
+		if seen[strings.ToLower(recipient)] {
+			return fmt.Errorf("%s is listed more than once", recipient)
+		}
+		seen[strings.ToLower(recipient)] = true
+	}
+	return nil
+}
+
+// MaxWebhookTimeout bounds how long a webhook may take to answer
+const MaxWebhookTimeout = 30
+
+var headerNamePattern = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+
+// Headers set on every webhook request, which custom headers cannot replace
+var reservedWebhookHeaders = []string{"Content-Type", "User-Agent", "X-Uptimebot-Signature", "X-Uptimebot-Timestamp"}
+
+// WebhookConfig represents generic webhook notifier configuration. The secret
+// signs every request so receivers can check it came from us.
+type WebhookConfig struct {
+	URL            string            `json:"url"`
+	Secret         string            `json:"secret"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"` // zero uses the default
+}
+
+// Validate checks the webhook can be called
+func (c *WebhookConfig) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook URL must be an http or https URL")
+	}
+	if c.TimeoutSeconds < 0 || c.TimeoutSeconds > MaxWebhookTimeout {
+		return fmt.Errorf("webhook timeout must be between 1 and %d seconds", MaxWebhookTimeout)
+	}
+	for name, value := range c.Headers {
+		if !headerNamePattern.MatchString(name) || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid header: %q", name)
+		}
+		for _, reserved := range reservedWebhookHeaders {
+			if strings.EqualFold(name, reserved) {
+				return fmt.Errorf("header %s cannot be set", reserved)
+			}
+		}
+	}
+	return nil
+}
+
 // GetSlackConfig parses and returns Slack configuration
 func (n *Notifier) GetSlackConfig() (*SlackConfig, error) {
 	if n.Type != NotifierTypeSlack {
@@ -42,6 +273,66 @@ func (n *Notifier) GetSlackConfig() (*SlackConfig, error) {
 	return &config, nil
 }
 
+// GetDiscordConfig parses and returns Discord configuration
+func (n *Notifier) GetDiscordConfig() (*DiscordConfig, error) {
+	if n.Type != NotifierTypeDiscord {
+		return nil, nil
+	}
+	var config DiscordConfig
+	if err := json.Unmarshal(n.Config, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetMSTeamsConfig parses and returns Microsoft Teams configuration
+func (n *Notifier) GetMSTeamsConfig() (*MSTeamsConfig, error) {
+	if n.Type != NotifierTypeMSTeams {
+		return nil, nil
+	}
+	var config MSTeamsConfig
+	if err := json.Unmarshal(n.Config, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetTelegramConfig parses and returns Telegram configuration
+func (n *Notifier) GetTelegramConfig() (*TelegramConfig, error) {
+	if n.Type != NotifierTypeTelegram {
+		return nil, nil
+	}
+	var config TelegramConfig
+	if err := json.Unmarshal(n.Config, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetPagerDutyConfig parses and returns PagerDuty configuration
+func (n *Notifier) GetPagerDutyConfig() (*PagerDutyConfig, error) {
+	if n.Type != NotifierTypePagerDuty {
+		return nil, nil
+	}
+	var config PagerDutyConfig
+	if err := json.Unmarshal(n.Config, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetOpsgenieConfig parses and returns Opsgenie configuration
+func (n *Notifier) GetOpsgenieConfig() (*OpsgenieConfig, error) {
+	if n.Type != NotifierTypeOpsgenie {
+		return nil, nil
+	}
+	var config OpsgenieConfig
+	if err := json.Unmarshal(n.Config, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
 // GetEmailConfig parses and returns email configuration
 func (n *Notifier) GetEmailConfig() (*EmailConfig, error) {
 	if n.Type != NotifierTypeEmail {
@@ -53,3 +344,93 @@ func (n *Notifier) GetEmailConfig() (*EmailConfig, error) {
 	}
 	return &config, nil
 }
+
+// GetWebhookConfig parses and returns webhook configuration
+func (n *Notifier) GetWebhookConfig() (*WebhookConfig, error) {
+	if n.Type != NotifierTypeWebhook {
+		return nil, nil
+	}
+	var config WebhookConfig
+	if err := json.Unmarshal(n.Config, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// EnsureWebhookSecret gives a webhook notifier a random signing secret when
+// its configuration has none
+func (n *Notifier) EnsureWebhookSecret() error {
+	config, err := n.GetWebhookConfig()
+	if err != nil || config == nil || config.Secret != "" {
+		return err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	config.Secret = hex.EncodeToString(secret)
+
+	return n.setConfig(config)
+}
+
+// secretFields names the configuration field holding each type's
+// credential. They are never shown back once stored.
+var secretFields = map[NotifierType]string{
+	NotifierTypeWebhook:   "secret",
+	NotifierTypeTelegram:  "bot_token",
+	NotifierTypePagerDuty: "routing_key",
+	NotifierTypeOpsgenie:  "api_key",
+}
+
+// RedactedConfig returns the configuration without its credential
+func (n *Notifier) RedactedConfig() (json.RawMessage, error) {
+	field, ok := secretFields[n.Type]
+	if !ok {
+		return n.Config, nil
+	}
+
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(n.Config, &config); err != nil {
+		return nil, err
+	}
+	delete(config, field)
+
+	return json.Marshal(config)
+}
+
+// KeepSecrets carries the credential of the previous configuration over
+// when the new one leaves it out, so updates need not repeat it
+func (n *Notifier) KeepSecrets(previous json.RawMessage) error {
+	field, ok := secretFields[n.Type]
+	if !ok {
+		return nil
+	}
+
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(n.Config, &config); err != nil {
+		return err
+	}
+	if value, ok := config[field]; ok && string(value) != `""` {
+		return nil
+	}
+
+	var old map[string]json.RawMessage
+	if err := json.Unmarshal(previous, &old); err != nil {
+		return err
+	}
+	if value, ok := old[field]; ok {
+		config[field] = value
+	}
+
+	return n.setConfig(config)
+}
+
+func (n *Notifier) setConfig(config any) error {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	n.Config = raw
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -115,3 +116,275 @@ func TestNotifier_GetEmailConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestNotifier_Accepts(t *testing.T) {
+	filter := NotifierFilter{Statuses: []string{"down"}}
+
+	tests := []struct {
+		name     string
+		notifier Notifier
+		status   string
+		want     bool
+	}{
+		{name: "listed status", notifier: Notifier{Type: NotifierTypeSlack, Filter: filter}, status: "down", want: true},
+		{name: "recovery filtered out", notifier: Notifier{Type: NotifierTypeSlack, Filter: filter}, status: "up", want: false},
+		{name: "pagerduty recovery", notifier: Notifier{Type: NotifierTypePagerDuty, Filter: filter}, status: "up", want: true},
+		{name: "opsgenie recovery", notifier: Notifier{Type: NotifierTypeOpsgenie, Filter: filter}, status: "up", want: true},
+		{name: "pagerduty degraded", notifier: Notifier{Type: NotifierTypePagerDuty, Filter: filter}, status: "degraded", want: true},
+		{name: "pagerduty unlisted status", notifier: Notifier{Type: NotifierTypePagerDuty, Filter: filter}, status: "certificate_expiring", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.notifier.Accepts(notification.State{Status: tt.status}))
+		})
+	}
+}
+
+func TestNotifier_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier Notifier
+		wantErr  bool
+	}{
+		{
+			name:     "slack",
+			notifier: Notifier{Type: NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`)},
+		},
+		{
+			name:     "slack without webhook URL",
+			notifier: Notifier{Type: NotifierTypeSlack, Config: json.RawMessage(`{}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "email",
+			notifier: Notifier{Type: NotifierTypeEmail, Config: json.RawMessage(`{"recipients": ["ops@example.com"]}`)},
+		},
+		{
+			name:     "email without recipients",
+			notifier: Notifier{Type: NotifierTypeEmail, Config: json.RawMessage(`{"recipients": []}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "email with an invalid address",
+			notifier: Notifier{Type: NotifierTypeEmail, Config: json.RawMessage(`{"recipients": ["ops@example.com", "not an address"]}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "email with a display name",
+			notifier: Notifier{Type: NotifierTypeEmail, Config: json.RawMessage(`{"recipients": ["Ops <ops@example.com>"]}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "email with a repeated address",
+			notifier: Notifier{Type: NotifierTypeEmail, Config: json.RawMessage(`{"recipients": ["ops@example.com", "OPS@example.com"]}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "discord",
+			notifier: Notifier{Type: NotifierTypeDiscord, Config: json.RawMessage(`{"webhook_url": "https://discord.com/api/webhooks/1/abc"}`)},
+		},
+		{
+			name:     "discord over http",
+			notifier: Notifier{Type: NotifierTypeDiscord, Config: json.RawMessage(`{"webhook_url": "http://discord.com/api/webhooks/1/abc"}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "teams",
+			notifier: Notifier{Type: NotifierTypeMSTeams, Config: json.RawMessage(`{"webhook_url": "https://example.webhook.office.com/webhookb2/abc"}`)},
+		},
+		{
+			name:     "teams without webhook URL",
+			notifier: Notifier{Type: NotifierTypeMSTeams, Config: json.RawMessage(`{}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "telegram",
+			notifier: Notifier{Type: NotifierTypeTelegram, Config: json.RawMessage(`{"bot_token": "123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11", "chat_id": "-1001234567890", "silent_recoveries": true}`)},
+		},
+		{
+			name:     "telegram channel username",
+			notifier: Notifier{Type: NotifierTypeTelegram, Config: json.RawMessage(`{"bot_token": "123456:ABC-DEF1234ghIkl", "chat_id": "@uptime_alerts"}`)},
+		},
+		{
+			name:     "telegram without bot token",
+			notifier: Notifier{Type: NotifierTypeTelegram, Config: json.RawMessage(`{"chat_id": "42"}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "telegram with a malformed chat ID",
+			notifier: Notifier{Type: NotifierTypeTelegram, Config: json.RawMessage(`{"bot_token": "123456:ABC-DEF1234ghIkl", "chat_id": "ops team"}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "pagerduty",
+			notifier: Notifier{Type: NotifierTypePagerDuty, Config: json.RawMessage(`{"routing_key": "R0123456789ABCDEF0123456789abcde"}`)},
+		},
+		{
+			name:     "pagerduty with a short key",
+			notifier: Notifier{Type: NotifierTypePagerDuty, Config: json.RawMessage(`{"routing_key": "R0123"}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "opsgenie",
+			notifier: Notifier{Type: NotifierTypeOpsgenie, Config: json.RawMessage(`{"api_key": "eb243592-faa2-4ba2-a551-1afdf565c889"}`)},
+		},
+		{
+			name:     "opsgenie without API key",
+			notifier: Notifier{Type: NotifierTypeOpsgenie, Config: json.RawMessage(`{"api_key": " "}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "webhook",
+			notifier: Notifier{Type: NotifierTypeWebhook, Config: json.RawMessage(`{"url": "https://tools.example.com/hooks/uptime", "headers": {"X-Api-Key": "abc"}, "timeout_seconds": 5}`)},
+		},
+		{
+			name:     "webhook without URL",
+			notifier: Notifier{Type: NotifierTypeWebhook, Config: json.RawMessage(`{"secret": "s3cret"}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "webhook with another scheme",
+			notifier: Notifier{Type: NotifierTypeWebhook, Config: json.RawMessage(`{"url": "ftp://tools.example.com/hooks"}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "webhook with too long a timeout",
+			notifier: Notifier{Type: NotifierTypeWebhook, Config: json.RawMessage(`{"url": "https://tools.example.com/hooks", "timeout_seconds": 300}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "webhook replacing the signature header",
+			notifier: Notifier{Type: NotifierTypeWebhook, Config: json.RawMessage(`{"url": "https://tools.example.com/hooks", "headers": {"x-uptimebot-signature": "forged"}}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "webhook with an invalid header",
+			notifier: Notifier{Type: NotifierTypeWebhook, Config: json.RawMessage(`{"url": "https://tools.example.com/hooks", "headers": {"X-Team": "ops\r\nX-Injected: 1"}}`)},
+			wantErr:  true,
+		},
+		{
+			name:     "malformed config",
+			notifier: Notifier{Type: NotifierTypeSlack, Config: json.RawMessage(`[]`)},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown type",
+			notifier: Notifier{Type: "pigeon", Config: json.RawMessage(`{}`)},
+			wantErr:  true,
+		},
+		{
+			name: "unknown filter status",
+			notifier: Notifier{
+				Type:   NotifierTypeSlack,
+				Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+				Filter: NotifierFilter{Statuses: []string{"sideways"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.notifier.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNotifier_EnsureWebhookSecret(t *testing.T) {
+	notifier := &Notifier{Type: NotifierTypeWebhook, Config: json.RawMessage(`{"url": "https://tools.example.com/hooks"}`)}
+
+	assert.NoError(t, notifier.EnsureWebhookSecret())
+	config, err := notifier.GetWebhookConfig()
+	assert.NoError(t, err)
+	assert.Len(t, config.Secret, 64)
+	assert.Equal(t, "https://tools.example.com/hooks", config.URL)
+
+	// An existing secret is kept
+	secret := config.Secret
+	assert.NoError(t, notifier.EnsureWebhookSecret())
+	config, _ = notifier.GetWebhookConfig()
+	assert.Equal(t, secret, config.Secret)
+
+	// Other notifier types are left alone
+	slack := &Notifier{Type: NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`)}
+	assert.NoError(t, slack.EnsureWebhookSecret())
+	assert.JSONEq(t, `{"webhook_url": "https://hooks.slack.com/test"}`, string(slack.Config))
+}
+
+func TestNotifier_KeepSecrets(t *testing.T) {
+	tests := []struct {
+		name         string
+		notifierType NotifierType
+		previous     string
+		config       string
+		want         string
+	}{
+		{
+			name:         "webhook secret left out",
+			notifierType: NotifierTypeWebhook,
+			previous:     `{"url": "https://tools.example.com/old", "secret": "s3cret"}`,
+			config:       `{"url": "https://tools.example.com/new"}`,
+			want:         `{"url": "https://tools.example.com/new", "secret": "s3cret"}`,
+		},
+		{
+			name:         "webhook secret replaced",
+			notifierType: NotifierTypeWebhook,
+			previous:     `{"url": "https://tools.example.com/old", "secret": "s3cret"}`,
+			config:       `{"url": "https://tools.example.com/new", "secret": "rotated"}`,
+			want:         `{"url": "https://tools.example.com/new", "secret": "rotated"}`,
+		},
+		{
+			name:         "blank telegram bot token",
+			notifierType: NotifierTypeTelegram,
+			previous:     `{"bot_token": "123456:ABC-def", "chat_id": "-100123"}`,
+			config:       `{"bot_token": "", "chat_id": "@uptime_alerts"}`,
+			want:         `{"bot_token": "123456:ABC-def", "chat_id": "@uptime_alerts"}`,
+		},
+		{
+			name:         "pagerduty routing key left out",
+			notifierType: NotifierTypePagerDuty,
+			previous:     `{"routing_key": "0123456789abcdef0123456789abcdef"}`,
+			config:       `{}`,
+			want:         `{"routing_key": "0123456789abcdef0123456789abcdef"}`,
+		},
+		{
+			name:         "opsgenie api key left out",
+			notifierType: NotifierTypeOpsgenie,
+			previous:     `{"api_key": "opsgenie-key"}`,
+			config:       `{}`,
+			want:         `{"api_key": "opsgenie-key"}`,
+		},
+		{
+			name:         "types without a credential are left alone",
+			notifierType: NotifierTypeSlack,
+			previous:     `{"webhook_url": "https://hooks.slack.com/old"}`,
+			config:       `{"webhook_url": "https://hooks.slack.com/new"}`,
+			want:         `{"webhook_url": "https://hooks.slack.com/new"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &Notifier{Type: tt.notifierType, Config: json.RawMessage(tt.config)}
+
+			assert.NoError(t, notifier.KeepSecrets(json.RawMessage(tt.previous)))
+			assert.JSONEq(t, tt.want, string(notifier.Config))
+		})
+	}
+}
+
+func TestNotifier_RedactedConfig(t *testing.T) {
+	notifier := &Notifier{Type: NotifierTypeTelegram, Config: json.RawMessage(`{"bot_token": "123456:ABC-def", "chat_id": "-100123"}`)}
+
+	config, err := notifier.RedactedConfig()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"chat_id": "-100123"}`, string(config))
+	assert.Contains(t, string(notifier.Config), "123456:ABC-def", "the notifier itself keeps its token")
+}
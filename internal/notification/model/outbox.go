@@ -0,0 +1,71 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+)
+
+// Kinds of outbox message
+const (
+	OutboxNotify      = "notify"      // a status change
+	OutboxAcknowledge = "acknowledge" // someone took on the target's incident
+)
+
+// Delivery statuses of an outbox message
+const (
+	OutboxPending   = "pending"
+	OutboxDelivered = "delivered"
+	OutboxFailed    = "failed"    // gave up after too many or hopeless attempts
+	OutboxCancelled = "cancelled" // held back, then made pointless before it was due
+)
+
+// OutboxMessage is a notification waiting to reach one notifier, kept until
+// it has been delivered or given up on
+type OutboxMessage struct {
+	ID            int64
+	NotifierID    int64
+	Kind          string
+	State         notification.State
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	FinishedAt    time.Time // zero while pending
+	// DeliveredTo lists the recipients earlier attempts reached, for
+	// notifiers that send each recipient their own copy. Retries skip them.
+	DeliveredTo []string
+}
+
+// Summary describes the message in a few words
+func (m *OutboxMessage) Summary() string {
+	if m.Kind == OutboxAcknowledge {
+		return m.State.Message
+	}
+	if m.State.Test {
+		return "Test notification"
+	}
+	return fmt.Sprintf("%s is %s", m.State.Name, m.State.Status)
+}
+
+// DeliveryAttempt records one try at delivering an outbox message
+type DeliveryAttempt struct {
+	ID          int64
+	OutboxID    int64
+	NotifierID  int64
+	Attempt     int // 1 for the first try
+	AttemptedAt time.Time
+	StatusCode  int    // zero when the notifier does not speak HTTP or never got an answer
+	Error       string // empty on success
+	Latency     time.Duration
+
+	// Summary describes the message tried, filled in when listing attempts
+	Summary string
+}
+
+// Succeeded reports whether the attempt delivered the message
+func (a *DeliveryAttempt) Succeeded() bool {
+	return a.Error == ""
+}
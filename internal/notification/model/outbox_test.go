@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxMessage_Summary(t *testing.T) {
+	tests := []struct {
+		name    string
+		message OutboxMessage
+		want    string
+	}{
+		{
+			name:    "status change",
+			message: OutboxMessage{Kind: OutboxNotify, State: notification.State{Name: "https://example.com", Status: "down"}},
+			want:    "https://example.com is down",
+		},
+		{
+			name:    "test notification",
+			message: OutboxMessage{Kind: OutboxNotify, State: notification.State{Name: "Test notification", Status: "up", Test: true}},
+			want:    "Test notification",
+		},
+		{
+			name:    "acknowledgement",
+			message: OutboxMessage{Kind: OutboxAcknowledge, State: notification.State{Name: "https://example.com", Message: "Incident #3 acknowledged by Alice"}},
+			want:    "Incident #3 acknowledged by Alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.message.Summary())
+		})
+	}
+}
+
+func TestDeliveryAttempt_Succeeded(t *testing.T) {
+	attempt := DeliveryAttempt{StatusCode: 200}
+	assert.True(t, attempt.Succeeded())
+
+	attempt = DeliveryAttempt{StatusCode: 500, Error: "slack API returned non-200 status code: 500"}
+	assert.False(t, attempt.Succeeded())
+}
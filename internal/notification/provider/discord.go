@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+)
+
+// DiscordObserver implements the Observer interface by posting an embed to
+// a Discord channel webhook
+type DiscordObserver struct {
+	webhookURL string
+	baseURL    string
+	client     HTTPClient
+}
+
+// NewDiscordObserver creates a new Discord observer. Links back to the
+// target start with baseURL.
+func NewDiscordObserver(webhookURL, baseURL string, client HTTPClient) *DiscordObserver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &DiscordObserver{
+		webhookURL: webhookURL,
+		baseURL:    baseURL,
+		client:     client,
+	}
+}
+
+type discordMessage struct {
+	Username string         `json:"username"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	URL         string         `json:"url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// discordColors are the embed colours of each status, as RGB integers
+var discordColors = map[string]int{
+	"up":                   0x2eb67d,
+	"degraded":             0xf2c744,
+	"down":                 0xe01e5a,
+	"error":                0xe01e5a,
+	"certificate_expiring": 0xf2994a,
+	"dns_changed":          0x36c5f0,
+}
+
+// Notify implements the Observer interface
+func (d *DiscordObserver) Notify(state notification.State) error {
+	embed := discordEmbed{
+		Title:       fmt.Sprintf("%s is %s", state.Name, state.Status),
+		URL:         targetLink(d.baseURL, state.TargetID),
+		Description: state.Message,
+		Color:       discordColors[state.Status],
+		Fields: []discordField{
+			{Name: "Status", Value: state.Status, Inline: true},
+		},
+	}
+	if state.PreviousStatus != "" {
+		embed.Fields = append(embed.Fields, discordField{Name: "Previous status", Value: state.PreviousStatus, Inline: true})
+	}
+	if state.Latency > 0 {
+		embed.Fields = append(embed.Fields, discordField{Name: "Response time", Value: state.Latency.String(), Inline: true})
+	}
+	if state.Reason != "" {
+		embed.Fields = append(embed.Fields, discordField{Name: "Reason", Value: state.Reason})
+	}
+	if link := incidentLink(d.baseURL, state.IncidentID); link != "" {
+		embed.Fields = append(embed.Fields, discordField{Name: "Incident", Value: fmt.Sprintf("[#%d](%s)", state.IncidentID, link)})
+	}
+	if !state.UpdatedAt.IsZero() {
+		embed.Timestamp = state.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+
+	payload, err := json.Marshal(discordMessage{Username: "UptimeBot", Embeds: []discordEmbed{embed}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal discord message: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, d.webhookURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send discord message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Discord answers 204 No Content unless asked to wait for the message
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("discord returned non-2xx status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscordObserver_Notify(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      notification.State
+		statusCode int
+		err        error
+		wantErr    bool
+		wantColor  int
+		wantFields []string
+	}{
+		{
+			name: "down",
+			state: notification.State{
+				Name:           "https://example.com",
+				Status:         "down",
+				Message:        "Target https://example.com is down: status code 503",
+				UpdatedAt:      time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC),
+				TargetID:       7,
+				PreviousStatus: "up",
+				Reason:         "status code 503",
+				Latency:        1500 * time.Millisecond,
+				IncidentID:     3,
+			},
+			statusCode: http.StatusNoContent,
+			wantColor:  0xe01e5a,
+			wantFields: []string{"Status", "Previous status", "Response time", "Reason", "Incident"},
+		},
+		{
+			name:       "up",
+			state:      notification.State{Name: "https://example.com", Status: "up", TargetID: 7},
+			statusCode: http.StatusOK,
+			wantColor:  0x2eb67d,
+			wantFields: []string{"Status"},
+		},
+		{
+			name:       "rate limited",
+			state:      notification.State{Name: "https://example.com", Status: "up"},
+			statusCode: http.StatusTooManyRequests,
+			wantErr:    true,
+		},
+		{
+			name:    "network error",
+			state:   notification.State{Name: "https://example.com", Status: "up"},
+			err:     errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := NewMockHTTPClient(tt.statusCode, tt.err)
+			observer := NewDiscordObserver("https://discord.com/api/webhooks/1/abc", "https://uptime.example.com", mockClient)
+
+			err := observer.Notify(tt.state)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if !assert.Len(t, mockClient.requests, 1) {
+				return
+			}
+
+			req := mockClient.requests[0]
+			assert.Equal(t, "https://discord.com/api/webhooks/1/abc", req.URL.String())
+			assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+			var msg discordMessage
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&msg))
+			if !assert.Len(t, msg.Embeds, 1) {
+				return
+			}
+			embed := msg.Embeds[0]
+			assert.Equal(t, tt.state.Name+" is "+tt.state.Status, embed.Title)
+			assert.Equal(t, "https://uptime.example.com/targets/7/edit", embed.URL)
+			assert.Equal(t, tt.wantColor, embed.Color)
+
+			var names []string
+			for _, field := range embed.Fields {
+				names = append(names, field.Name)
+			}
+			assert.Equal(t, tt.wantFields, names)
+			if tt.state.IncidentID > 0 {
+				assert.Equal(t, "[#3](https://uptime.example.com/incidents/3)", embed.Fields[len(embed.Fields)-1].Value)
+				assert.Equal(t, "2025-06-15T12:00:00Z", embed.Timestamp)
+			}
+		})
+	}
+}
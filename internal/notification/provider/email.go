@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/shuvo-paul/uptimebot/internal/email"
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+)
+
+// Templates every email is rendered from. Each is defined twice under the
+// same name, in emails/*.html and emails/*.txt.
+const (
+	EmailTemplateStatusChange    = "status_change"
+	EmailTemplateVerifyRecipient = "verify_recipient"
+)
+
+// EmailSender renders emails with an HTML and a plain-text body and sends them
+type EmailSender struct {
+	newMailer func() email.Mailer
+	html      *htmltemplate.Template
+	text      *texttemplate.Template
+}
+
+// NewEmailSender parses the email templates in fsys. newMailer is called
+// for every email sent.
+func NewEmailSender(newMailer func() email.Mailer, fsys fs.FS) (*EmailSender, error) {
+	html, err := htmltemplate.ParseFS(fsys, "emails/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML email templates: %w", err)
+	}
+	text, err := texttemplate.ParseFS(fsys, "emails/*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text email templates: %w", err)
+	}
+	return &EmailSender{
+		newMailer: newMailer,
+		html:      html,
+		text:      text,
+	}, nil
+}
+
+// Send renders the named template with data and mails it to a single address
+func (s *EmailSender) Send(to, subject, name string, data any) error {
+	var html, text bytes.Buffer
+	if err := s.html.ExecuteTemplate(&html, name, data); err != nil {
+		return fmt.Errorf("failed to execute email template: %w", err)
+	}
+	if err := s.text.ExecuteTemplate(&text, name, data); err != nil {
+		return fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	mailer := s.newMailer()
+	if err := mailer.SetTo(to); err != nil {
+		return fmt.Errorf("failed to set email recipient: %w", err)
+	}
+	if err := mailer.SetSubject(subject); err != nil {
+		return fmt.Errorf("failed to set email subject: %w", err)
+	}
+	if err := mailer.SetBody(strings.TrimSpace(html.String())); err != nil {
+		return fmt.Errorf("failed to set email body: %w", err)
+	}
+	if err := mailer.SetTextBody(strings.TrimSpace(text.String())); err != nil {
+		return fmt.Errorf("failed to set email body: %w", err)
+	}
+	if err := mailer.SendEmail(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// EmailObserver implements the Observer interface by mailing every recipient
+type EmailObserver struct {
+	recipients []string
+	sender     *EmailSender
+	baseURL    string
+}
+
+// NewEmailObserver creates a new email observer. Recipients must already
+// have confirmed they want the mail. Links in the mail start with baseURL.
+func NewEmailObserver(recipients []string, sender *EmailSender, baseURL string) *EmailObserver {
+	return &EmailObserver{
+		recipients: recipients,
+		sender:     sender,
+		baseURL:    baseURL,
+	}
+}
+
+// StatusChangeEmail is the data the status change templates are given
+type StatusChangeEmail struct {
+	Subject     string
+	State       notification.State
+	IncidentURL string
+	SettingsURL string
+}
+
+// RecipientNotifier is implemented by observers that send every recipient
+// their own copy, so a failed delivery can be retried for just the
+// recipients it missed
+type RecipientNotifier interface {
+	// NotifyRecipients sends state to every recipient not in skip and
+	// returns those it reached, along with the first failure
+	NotifyRecipients(state notification.State, skip []string) ([]string, error)
+}
+
+var _ RecipientNotifier = (*EmailObserver)(nil)
+
+// Notify implements the Observer interface. Every recipient is sent their
+// own copy so addresses are not shared; the first failure is returned after
+// trying them all.
+func (e *EmailObserver) Notify(state notification.State) error {
+	_, err := e.NotifyRecipients(state, nil)
+	return err
+}
+
+// NotifyRecipients implements the RecipientNotifier interface
+func (e *EmailObserver) NotifyRecipients(state notification.State, skip []string) ([]string, error) {
+	data := StatusChangeEmail{
+		Subject:     fmt.Sprintf("%s is %s", state.Name, state.Status),
+		State:       state,
+		IncidentURL: incidentLink(e.baseURL, state.IncidentID),
+	}
+	if e.baseURL != "" && state.TargetID > 0 {
+		data.SettingsURL = fmt.Sprintf("%s/targets/%d/notifiers", e.baseURL, state.TargetID)
+	}
+
+	var reached []string
+	var firstErr error
+	for _, recipient := range e.recipients {
+		if slices.Contains(skip, recipient) {
+			continue
+		}
+		if err := e.sender.Send(recipient, data.Subject, EmailTemplateStatusChange, data); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to email %s: %w", recipient, err)
+			}
+			continue
+		}
+		reached = append(reached, recipient)
+	}
+	return reached, firstErr
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/email"
+	"github.com/shuvo-paul/uptimebot/internal/email/mock"
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestEmailSender returns a sender using the real templates, and the
+// mailers it has handed out so far
+func newTestEmailSender(t *testing.T, sendErr error) (*EmailSender, *[]*mock.MailServiceMock) {
+	var mailers []*mock.MailServiceMock
+	sender, err := NewEmailSender(func() email.Mailer {
+		mailer := &mock.MailServiceMock{SendEmailFunc: func() error { return sendErr }}
+		mailers = append(mailers, mailer)
+		return mailer
+	}, templates.TemplateFS)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return sender, &mailers
+}
+
+func TestEmailObserver_Notify(t *testing.T) {
+	state := notification.State{
+		Name:           "https://example.com",
+		Status:         "down",
+		Message:        "Target https://example.com is down: status code 503",
+		UpdatedAt:      time.Date(2025, 6, 8, 12, 0, 0, 0, time.UTC),
+		TargetID:       7,
+		PreviousStatus: "up",
+		Reason:         "status code 503 & more",
+		Latency:        1500 * time.Millisecond,
+		IncidentID:     3,
+	}
+
+	t.Run("every recipient gets their own mail", func(t *testing.T) {
+		sender, mailers := newTestEmailSender(t, nil)
+		observer := NewEmailObserver([]string{"ops@example.com", "cto@example.com"}, sender, "https://uptime.example.com")
+
+		assert.NoError(t, observer.Notify(state))
+
+		if !assert.Len(t, *mailers, 2) {
+			return
+		}
+		for i, recipient := range []string{"ops@example.com", "cto@example.com"} {
+			mailer := (*mailers)[i]
+			assert.Equal(t, []string{recipient}, mailer.GetSetToCalls())
+			assert.Equal(t, []string{"https://example.com is down"}, mailer.GetSetSubjectCalls())
+			assert.Equal(t, 1, mailer.GetSendEmailCallCount())
+		}
+
+		html := (*mailers)[0].GetSetBodyCalls()[0]
+		assert.Contains(t, html, `href="https://uptime.example.com/incidents/3"`)
+		assert.Contains(t, html, "status code 503 &amp; more")
+		assert.Contains(t, html, "1.5s")
+
+		text := (*mailers)[0].GetSetTextBodyCalls()[0]
+		assert.Contains(t, text, "https://example.com is down (was up)")
+		assert.Contains(t, text, "Reason: status code 503 & more")
+		assert.Contains(t, text, "View the incident: https://uptime.example.com/incidents/3")
+		assert.Contains(t, text, "https://uptime.example.com/targets/7/notifiers")
+		assert.NotContains(t, text, "<")
+	})
+
+	t.Run("failed sends are reported", func(t *testing.T) {
+		sender, mailers := newTestEmailSender(t, errors.New("connection refused"))
+		observer := NewEmailObserver([]string{"ops@example.com", "cto@example.com"}, sender, "")
+
+		err := observer.Notify(state)
+
+		assert.ErrorContains(t, err, "ops@example.com")
+		assert.Len(t, *mailers, 2, "later recipients are still tried")
+	})
+
+	t.Run("recipients already reached are skipped", func(t *testing.T) {
+		sender, mailers := newTestEmailSender(t, nil)
+		observer := NewEmailObserver([]string{"ops@example.com", "cto@example.com"}, sender, "")
+
+		reached, err := observer.NotifyRecipients(state, []string{"ops@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cto@example.com"}, reached)
+		if assert.Len(t, *mailers, 1) {
+			assert.Equal(t, []string{"cto@example.com"}, (*mailers)[0].GetSetToCalls())
+		}
+	})
+
+	t.Run("failed recipients are not reported as reached", func(t *testing.T) {
+		sender, _ := newTestEmailSender(t, errors.New("connection refused"))
+		observer := NewEmailObserver([]string{"ops@example.com"}, sender, "")
+
+		reached, err := observer.NotifyRecipients(state, nil)
+
+		assert.Error(t, err)
+		assert.Empty(t, reached)
+	})
+}
+
+func TestEmailSender_Send(t *testing.T) {
+	sender, mailers := newTestEmailSender(t, nil)
+
+	err := sender.Send("ops@example.com", "Confirm UptimeBot alerts", EmailTemplateVerifyRecipient, map[string]string{
+		"VerifyLink": "https://uptime.example.com/notifiers/verify-email?token=abc",
+	})
+
+	assert.NoError(t, err)
+	if assert.Len(t, *mailers, 1) {
+		assert.Contains(t, (*mailers)[0].GetSetBodyCalls()[0], `href="https://uptime.example.com/notifiers/verify-email?token=abc"`)
+		assert.Contains(t, (*mailers)[0].GetSetTextBodyCalls()[0], "https://uptime.example.com/notifiers/verify-email?token=abc")
+	}
+
+	err = sender.Send("ops@example.com", "Missing", "no_such_template", nil)
+	assert.Error(t, err)
+}
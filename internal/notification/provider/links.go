@@ -0,0 +1,21 @@
+package provider
+
+import "fmt"
+
+// targetLink returns the address of the target's page in the app, or "" if
+// the app's address or the target is not known
+func targetLink(baseURL string, targetID int) string {
+	if baseURL == "" || targetID <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/targets/%d/edit", baseURL, targetID)
+}
+
+// incidentLink returns the address of an incident's page in the app, or ""
+// if the app's address or the incident is not known
+func incidentLink(baseURL string, incidentID int) string {
+	if baseURL == "" || incidentID <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/incidents/%d", baseURL, incidentID)
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+)
+
+// MSTeamsObserver implements the Observer interface by posting an Adaptive
+// Card to a Microsoft Teams incoming webhook or workflow
+type MSTeamsObserver struct {
+	webhookURL string
+	baseURL    string
+	client     HTTPClient
+}
+
+// NewMSTeamsObserver creates a new Teams observer. Links back to the target
+// start with baseURL.
+func NewMSTeamsObserver(webhookURL, baseURL string, client HTTPClient) *MSTeamsObserver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &MSTeamsObserver{
+		webhookURL: webhookURL,
+		baseURL:    baseURL,
+		client:     client,
+	}
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string           `json:"$schema"`
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []map[string]any `json:"body"`
+	Actions []teamsAction    `json:"actions,omitempty"`
+}
+
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type teamsAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// teamsColors are the Adaptive Card text colours of each status
+var teamsColors = map[string]string{
+	"up":                   "Good",
+	"degraded":             "Warning",
+	"down":                 "Attention",
+	"error":                "Attention",
+	"certificate_expiring": "Warning",
+	"dns_changed":          "Accent",
+}
+
+// Notify implements the Observer interface
+func (m *MSTeamsObserver) Notify(state notification.State) error {
+	color, ok := teamsColors[state.Status]
+	if !ok {
+		color = "Default"
+	}
+
+	facts := []teamsFact{{Title: "Status", Value: state.Status}}
+	if state.PreviousStatus != "" {
+		facts = append(facts, teamsFact{Title: "Previous status", Value: state.PreviousStatus})
+	}
+	if state.Reason != "" {
+		facts = append(facts, teamsFact{Title: "Reason", Value: state.Reason})
+	}
+	if state.Latency > 0 {
+		facts = append(facts, teamsFact{Title: "Response time", Value: state.Latency.String()})
+	}
+	if !state.UpdatedAt.IsZero() {
+		facts = append(facts, teamsFact{Title: "Time", Value: state.UpdatedAt.UTC().Format(time.RFC1123)})
+	}
+
+	card := teamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []map[string]any{
+			{"type": "TextBlock", "text": fmt.Sprintf("%s is %s", state.Name, state.Status), "weight": "Bolder", "size": "Medium", "color": color, "wrap": true},
+			{"type": "TextBlock", "text": state.Message, "wrap": true},
+			{"type": "FactSet", "facts": facts},
+		},
+	}
+	if link := targetLink(m.baseURL, state.TargetID); link != "" {
+		card.Actions = append(card.Actions, teamsAction{Type: "Action.OpenUrl", Title: "View target", URL: link})
+	}
+	if link := incidentLink(m.baseURL, state.IncidentID); link != "" {
+		card.Actions = append(card.Actions, teamsAction{Type: "Action.OpenUrl", Title: "View incident", URL: link})
+	}
+
+	payload, err := json.Marshal(teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{ContentType: "application/vnd.microsoft.card.adaptive", Content: card},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal teams message: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, m.webhookURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send teams message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Workflows answer 202 Accepted, older incoming webhooks 200 OK
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("teams returned non-2xx status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
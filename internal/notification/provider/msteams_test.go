@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMSTeamsObserver_Notify(t *testing.T) {
+	tests := []struct {
+		name        string
+		state       notification.State
+		statusCode  int
+		err         error
+		wantErr     bool
+		wantColor   string
+		wantActions []string
+	}{
+		{
+			name: "down",
+			state: notification.State{
+				Name:           "https://example.com",
+				Status:         "down",
+				Message:        "Target https://example.com is down: status code 503",
+				UpdatedAt:      time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC),
+				TargetID:       7,
+				PreviousStatus: "up",
+				Reason:         "status code 503",
+				IncidentID:     3,
+			},
+			statusCode:  http.StatusAccepted,
+			wantColor:   "Attention",
+			wantActions: []string{"https://uptime.example.com/targets/7/edit", "https://uptime.example.com/incidents/3"},
+		},
+		{
+			name:        "degraded",
+			state:       notification.State{Name: "https://example.com", Status: "degraded", TargetID: 7},
+			statusCode:  http.StatusOK,
+			wantColor:   "Warning",
+			wantActions: []string{"https://uptime.example.com/targets/7/edit"},
+		},
+		{
+			name:       "unknown status",
+			state:      notification.State{Name: "https://example.com", Status: "pending"},
+			statusCode: http.StatusOK,
+			wantColor:  "Default",
+		},
+		{
+			name:       "webhook removed",
+			state:      notification.State{Name: "https://example.com", Status: "up"},
+			statusCode: http.StatusNotFound,
+			wantErr:    true,
+		},
+		{
+			name:    "network error",
+			state:   notification.State{Name: "https://example.com", Status: "up"},
+			err:     errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := NewMockHTTPClient(tt.statusCode, tt.err)
+			observer := NewMSTeamsObserver("https://example.webhook.office.com/webhookb2/abc", "https://uptime.example.com", mockClient)
+
+			err := observer.Notify(tt.state)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if !assert.Len(t, mockClient.requests, 1) {
+				return
+			}
+
+			var msg teamsMessage
+			assert.NoError(t, json.NewDecoder(mockClient.requests[0].Body).Decode(&msg))
+			assert.Equal(t, "message", msg.Type)
+			if !assert.Len(t, msg.Attachments, 1) {
+				return
+			}
+			assert.Equal(t, "application/vnd.microsoft.card.adaptive", msg.Attachments[0].ContentType)
+
+			card := msg.Attachments[0].Content
+			assert.Equal(t, "AdaptiveCard", card.Type)
+			assert.Equal(t, tt.state.Name+" is "+tt.state.Status, card.Body[0]["text"])
+			assert.Equal(t, tt.wantColor, card.Body[0]["color"])
+			assert.Equal(t, "FactSet", card.Body[2]["type"])
+
+			var urls []string
+			for _, action := range card.Actions {
+				assert.Equal(t, "Action.OpenUrl", action.Type)
+				urls = append(urls, action.URL)
+			}
+			assert.Equal(t, tt.wantActions, urls)
+		})
+	}
+}
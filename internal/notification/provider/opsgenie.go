@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+)
+
+// DefaultOpsgenieAPIURL is the address of the Opsgenie API. Accounts in the
+// EU instance use https://api.eu.opsgenie.com instead.
+const DefaultOpsgenieAPIURL = "https://api.opsgenie.com"
+
+// Opsgenie cuts longer alert messages short
+const maxOpsgenieMessage = 130
+
+// opsgeniePriorities are the alert priorities of the statuses that open one
+var opsgeniePriorities = map[string]string{
+	"critical": "P1",
+	"error":    "P2",
+}
+
+// OpsgenieObserver implements the Observer interface by creating,
+// acknowledging and closing an Opsgenie alert per target
+type OpsgenieObserver struct {
+	apiURL  string
+	apiKey  string
+	baseURL string
+	client  HTTPClient
+}
+
+var _ Acknowledger = (*OpsgenieObserver)(nil)
+
+// NewOpsgenieObserver creates a new Opsgenie observer authenticating with
+// the API key of an API integration. apiURL is the API's address,
+// DefaultOpsgenieAPIURL when empty. Links back to the target start with
+// baseURL.
+func NewOpsgenieObserver(apiURL, apiKey, baseURL string, client HTTPClient) *OpsgenieObserver {
+	if apiURL == "" {
+		apiURL = DefaultOpsgenieAPIURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &OpsgenieObserver{
+		apiURL:  strings.TrimRight(apiURL, "/"),
+		apiKey:  apiKey,
+		baseURL: baseURL,
+		client:  client,
+	}
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Entity      string            `json:"entity,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+type opsgenieAction struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Notify implements the Observer interface. Failing statuses create the
+// target's alert, which Opsgenie deduplicates by alias, and recoveries close
+// it; test notifications open a separate alert and close it straight away.
+func (o *OpsgenieObserver) Notify(state notification.State) error {
+	if state.Test {
+		if err := o.create(state, "P5"); err != nil {
+			return err
+		}
+		return o.act("close", state)
+	}
+
+	if pagingResolves(state.Status) {
+		return o.act("close", state)
+	}
+	severity, ok := pagingSeverities[state.Status]
+	if !ok {
+		return nil
+	}
+	return o.create(state, opsgeniePriorities[severity])
+}
+
+// Acknowledge implements the Acknowledger interface
+func (o *OpsgenieObserver) Acknowledge(state notification.State) error {
+	return o.act("acknowledge", state)
+}
+
+func (o *OpsgenieObserver) create(state notification.State, priority string) error {
+	message := fmt.Sprintf("%s is %s", state.Name, state.Status)
+	if len(message) > maxOpsgenieMessage {
+		message = message[:maxOpsgenieMessage-3] + "..."
+	}
+
+	details := map[string]string{"status": state.Status}
+	if state.PreviousStatus != "" {
+		details["previous_status"] = state.PreviousStatus
+	}
+	if state.Reason != "" {
+		details["reason"] = state.Reason
+	}
+	if state.Latency > 0 {
+		details["response_time"] = state.Latency.String()
+	}
+	if !state.UpdatedAt.IsZero() {
+		details["time"] = state.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	if link := targetLink(o.baseURL, state.TargetID); link != "" {
+		details["target_url"] = link
+	}
+	if link := incidentLink(o.baseURL, state.IncidentID); link != "" {
+		details["incident_url"] = link
+	}
+
+	return o.post("/v2/alerts", opsgenieAlert{
+		Message:     message,
+		Alias:       alertKey(state),
+		Description: state.Message,
+		Details:     details,
+		Entity:      state.Name,
+		Source:      "UptimeBot",
+		Priority:    priority,
+		Tags:        []string{"uptimebot"},
+	})
+}
+
+// act acknowledges or closes the target's alert
+func (o *OpsgenieObserver) act(action string, state notification.State) error {
+	path := fmt.Sprintf("/v2/alerts/%s/%s?identifierType=alias", url.PathEscape(alertKey(state)), action)
+	return o.post(path, opsgenieAction{Source: "UptimeBot", Note: state.Message})
+}
+
+func (o *OpsgenieObserver) post(path string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal opsgenie request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, o.apiURL+path, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+o.apiKey)
+
+	// Opsgenie processes requests asynchronously and answers 202 Accepted
+	return sendPagingRequest(o.client, req, "opsgenie", "message")
+}
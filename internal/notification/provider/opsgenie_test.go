@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// opsgenieFake stands in for the Alerts API and records the requests it is sent
+type opsgenieFake struct {
+	*httptest.Server
+	requests []string
+	bodies   []map[string]any
+}
+
+func newOpsgenieFake(t *testing.T, statusCode int, body string) *opsgenieFake {
+	f := &opsgenieFake{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GenieKey api-key", r.Header.Get("Authorization"))
+		var decoded map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&decoded))
+		f.requests = append(f.requests, r.URL.RequestURI())
+		f.bodies = append(f.bodies, decoded)
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func TestOpsgenieObserver_Notify(t *testing.T) {
+	down := notification.State{
+		Name:       "https://example.com",
+		Status:     "down",
+		Message:    "Target https://example.com is down: status code 503",
+		TargetID:   7,
+		Reason:     "status code 503",
+		IncidentID: 3,
+	}
+
+	t.Run("outage and recovery", func(t *testing.T) {
+		fake := newOpsgenieFake(t, http.StatusAccepted, `{"result": "Request will be processed"}`)
+		observer := NewOpsgenieObserver(fake.URL+"/", "api-key", "https://uptime.example.com", nil)
+
+		assert.NoError(t, observer.Notify(down))
+		assert.NoError(t, observer.Acknowledge(down))
+		assert.NoError(t, observer.Notify(notification.State{Name: "https://example.com", Status: "up", TargetID: 7}))
+
+		assert.Equal(t, []string{
+			"/v2/alerts",
+			"/v2/alerts/uptimebot-target-7/acknowledge?identifierType=alias",
+			"/v2/alerts/uptimebot-target-7/close?identifierType=alias",
+		}, fake.requests)
+
+		alert := fake.bodies[0]
+		assert.Equal(t, "https://example.com is down", alert["message"])
+		assert.Equal(t, "uptimebot-target-7", alert["alias"])
+		assert.Equal(t, "P1", alert["priority"])
+		assert.Equal(t, "UptimeBot", alert["source"])
+		details, _ := alert["details"].(map[string]any)
+		assert.Equal(t, "https://uptime.example.com/incidents/3", details["incident_url"])
+		assert.Equal(t, "UptimeBot", fake.bodies[2]["source"])
+	})
+
+	t.Run("long message", func(t *testing.T) {
+		fake := newOpsgenieFake(t, http.StatusAccepted, `{}`)
+		observer := NewOpsgenieObserver(fake.URL, "api-key", "", nil)
+
+		assert.NoError(t, observer.Notify(notification.State{Name: "https://example.com/" + strings.Repeat("a", 200), Status: "error", TargetID: 7}))
+
+		if assert.Len(t, fake.bodies, 1) {
+			message, _ := fake.bodies[0]["message"].(string)
+			assert.Len(t, message, maxOpsgenieMessage)
+			assert.Equal(t, "P2", fake.bodies[0]["priority"])
+		}
+	})
+
+	t.Run("test notification", func(t *testing.T) {
+		fake := newOpsgenieFake(t, http.StatusAccepted, `{}`)
+		observer := NewOpsgenieObserver(fake.URL, "api-key", "", nil)
+
+		assert.NoError(t, observer.Notify(notification.State{Name: "Test notification", Status: "up", TargetID: 7, Test: true}))
+
+		assert.Equal(t, []string{"/v2/alerts", "/v2/alerts/uptimebot-test-7/close?identifierType=alias"}, fake.requests)
+		assert.Equal(t, "P5", fake.bodies[0]["priority"])
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		fake := newOpsgenieFake(t, http.StatusUnauthorized, `{"message": "Could not authenticate", "took": 0.001}`)
+		observer := NewOpsgenieObserver(fake.URL, "api-key", "", nil)
+
+		err := observer.Notify(down)
+
+		assert.EqualError(t, err, "opsgenie returned status code 401: Could not authenticate")
+	})
+
+	t.Run("network error hides the URL", func(t *testing.T) {
+		mockClient := NewMockHTTPClient(0, &url.Error{Op: "Post", URL: "https://api.opsgenie.com/v2/alerts?key=secret", Err: errors.New("connection refused")})
+		observer := NewOpsgenieObserver("", "api-key", "", mockClient)
+
+		err := observer.Notify(down)
+
+		assert.EqualError(t, err, "failed to reach opsgenie: connection refused")
+	})
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+)
+
+// DefaultPagerDutyEventsURL is the address of the PagerDuty Events API v2
+const DefaultPagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyObserver implements the Observer interface by triggering,
+// acknowledging and resolving a PagerDuty incident per target
+type PagerDutyObserver struct {
+	eventsURL  string
+	routingKey string
+	baseURL    string
+	client     HTTPClient
+}
+
+var _ Acknowledger = (*PagerDutyObserver)(nil)
+
+// NewPagerDutyObserver creates a new PagerDuty observer sending events to
+// the service integration routingKey belongs to. eventsURL is the Events
+// API's address, DefaultPagerDutyEventsURL when empty. Links back to the
+// target start with baseURL.
+func NewPagerDutyObserver(eventsURL, routingKey, baseURL string, client HTTPClient) *PagerDutyObserver {
+	if eventsURL == "" {
+		eventsURL = DefaultPagerDutyEventsURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &PagerDutyObserver{
+		eventsURL:  eventsURL,
+		routingKey: routingKey,
+		baseURL:    baseURL,
+		client:     client,
+	}
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Client      string            `json:"client,omitempty"`
+	ClientURL   string            `json:"client_url,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// Notify implements the Observer interface. Failing statuses trigger the
+// target's incident and recoveries resolve it; test notifications open a
+// separate incident and resolve it straight away.
+func (p *PagerDutyObserver) Notify(state notification.State) error {
+	if state.Test {
+		if err := p.send(p.triggerEvent(state, "info")); err != nil {
+			return err
+		}
+		return p.send(p.event("resolve", state))
+	}
+
+	if pagingResolves(state.Status) {
+		return p.send(p.event("resolve", state))
+	}
+	severity, ok := pagingSeverities[state.Status]
+	if !ok {
+		return nil
+	}
+	return p.send(p.triggerEvent(state, severity))
+}
+
+// Acknowledge implements the Acknowledger interface
+func (p *PagerDutyObserver) Acknowledge(state notification.State) error {
+	return p.send(p.event("acknowledge", state))
+}
+
+func (p *PagerDutyObserver) event(action string, state notification.State) pagerDutyEvent {
+	return pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: action,
+		DedupKey:    alertKey(state),
+	}
+}
+
+func (p *PagerDutyObserver) triggerEvent(state notification.State, severity string) pagerDutyEvent {
+	event := p.event("trigger", state)
+	event.Client = "UptimeBot"
+	event.ClientURL = p.baseURL
+
+	details := map[string]string{"status": state.Status}
+	if state.Message != "" {
+		details["message"] = state.Message
+	}
+	if state.PreviousStatus != "" {
+		details["previous_status"] = state.PreviousStatus
+	}
+	if state.Reason != "" {
+		details["reason"] = state.Reason
+	}
+	if state.Latency > 0 {
+		details["response_time"] = state.Latency.String()
+	}
+
+	event.Payload = &pagerDutyPayload{
+		Summary:       fmt.Sprintf("%s is %s", state.Name, state.Status),
+		Source:        state.Name,
+		Severity:      severity,
+		CustomDetails: details,
+	}
+	if !state.UpdatedAt.IsZero() {
+		event.Payload.Timestamp = state.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+
+	if link := targetLink(p.baseURL, state.TargetID); link != "" {
+		event.Links = append(event.Links, pagerDutyLink{Href: link, Text: "View target"})
+	}
+	if link := incidentLink(p.baseURL, state.IncidentID); link != "" {
+		event.Links = append(event.Links, pagerDutyLink{Href: link, Text: "View incident"})
+	}
+
+	return event
+}
+
+func (p *PagerDutyObserver) send(event pagerDutyEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pagerduty event: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.eventsURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// PagerDuty answers 202 Accepted
+	return sendPagingRequest(p.client, req, "pagerduty", "message")
+}
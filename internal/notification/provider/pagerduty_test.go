@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// pagerDutyFake stands in for the Events API and records the events it is sent
+type pagerDutyFake struct {
+	*httptest.Server
+	events []pagerDutyEvent
+}
+
+func newPagerDutyFake(t *testing.T, statusCode int, body string) *pagerDutyFake {
+	f := &pagerDutyFake{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/enqueue", r.URL.Path)
+		var event pagerDutyEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		f.events = append(f.events, event)
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *pagerDutyFake) actions() []string {
+	var actions []string
+	for _, event := range f.events {
+		actions = append(actions, event.EventAction+" "+event.DedupKey)
+	}
+	return actions
+}
+
+func TestPagerDutyObserver_Notify(t *testing.T) {
+	down := notification.State{
+		Name:           "https://example.com",
+		Status:         "down",
+		Message:        "Target https://example.com is down: status code 503",
+		UpdatedAt:      time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC),
+		TargetID:       7,
+		PreviousStatus: "up",
+		Reason:         "status code 503",
+		IncidentID:     3,
+	}
+
+	t.Run("outage and recovery", func(t *testing.T) {
+		fake := newPagerDutyFake(t, http.StatusAccepted, `{"status": "success"}`)
+		observer := NewPagerDutyObserver(fake.URL+"/v2/enqueue", "routing-key", "https://uptime.example.com", nil)
+
+		assert.NoError(t, observer.Notify(down))
+		assert.NoError(t, observer.Acknowledge(down))
+		assert.NoError(t, observer.Notify(notification.State{Name: "https://example.com", Status: "up", TargetID: 7}))
+
+		assert.Equal(t, []string{
+			"trigger uptimebot-target-7",
+			"acknowledge uptimebot-target-7",
+			"resolve uptimebot-target-7",
+		}, fake.actions())
+
+		trigger := fake.events[0]
+		assert.Equal(t, "routing-key", trigger.RoutingKey)
+		if assert.NotNil(t, trigger.Payload) {
+			assert.Equal(t, "https://example.com is down", trigger.Payload.Summary)
+			assert.Equal(t, "critical", trigger.Payload.Severity)
+			assert.Equal(t, "2025-06-15T12:00:00Z", trigger.Payload.Timestamp)
+			assert.Equal(t, "status code 503", trigger.Payload.CustomDetails["reason"])
+		}
+		assert.Equal(t, []pagerDutyLink{
+			{Href: "https://uptime.example.com/targets/7/edit", Text: "View target"},
+			{Href: "https://uptime.example.com/incidents/3", Text: "View incident"},
+		}, trigger.Links)
+		assert.Nil(t, fake.events[2].Payload)
+	})
+
+	t.Run("degraded resolves like the app's incident", func(t *testing.T) {
+		fake := newPagerDutyFake(t, http.StatusAccepted, `{"status": "success"}`)
+		observer := NewPagerDutyObserver(fake.URL+"/v2/enqueue", "routing-key", "", nil)
+
+		assert.NoError(t, observer.Notify(notification.State{Name: "api", Status: "error", TargetID: 7}))
+		assert.NoError(t, observer.Notify(notification.State{Name: "api", Status: "degraded", TargetID: 7}))
+
+		assert.Equal(t, []string{"trigger uptimebot-target-7", "resolve uptimebot-target-7"}, fake.actions())
+		assert.Equal(t, "error", fake.events[0].Payload.Severity)
+	})
+
+	t.Run("notices do not page", func(t *testing.T) {
+		fake := newPagerDutyFake(t, http.StatusAccepted, `{"status": "success"}`)
+		observer := NewPagerDutyObserver(fake.URL+"/v2/enqueue", "routing-key", "", nil)
+
+		assert.NoError(t, observer.Notify(notification.State{Name: "api", Status: "certificate_expiring", TargetID: 7}))
+
+		assert.Empty(t, fake.events)
+	})
+
+	t.Run("test notification", func(t *testing.T) {
+		fake := newPagerDutyFake(t, http.StatusAccepted, `{"status": "success"}`)
+		observer := NewPagerDutyObserver(fake.URL+"/v2/enqueue", "routing-key", "", nil)
+
+		assert.NoError(t, observer.Notify(notification.State{Name: "Test notification", Status: "up", TargetID: 7, Test: true}))
+
+		assert.Equal(t, []string{"trigger uptimebot-test-7", "resolve uptimebot-test-7"}, fake.actions())
+		assert.Equal(t, "info", fake.events[0].Payload.Severity)
+	})
+
+	t.Run("invalid event", func(t *testing.T) {
+		fake := newPagerDutyFake(t, http.StatusBadRequest, `{"status": "invalid event", "message": "Event object is invalid", "errors": ["Length of 'routing_key' is incorrect"]}`)
+		observer := NewPagerDutyObserver(fake.URL+"/v2/enqueue", "short", "", nil)
+
+		err := observer.Notify(down)
+
+		assert.EqualError(t, err, "pagerduty returned status code 400: Event object is invalid")
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		fake := newPagerDutyFake(t, http.StatusTooManyRequests, ``)
+		observer := NewPagerDutyObserver(fake.URL+"/v2/enqueue", "routing-key", "", nil)
+
+		err := observer.Notify(down)
+
+		assert.EqualError(t, err, "pagerduty returned non-2xx status code: 429")
+	})
+}
+
+func TestPagerDutyObserver_DefaultURL(t *testing.T) {
+	mockClient := NewMockHTTPClient(http.StatusAccepted, nil)
+	observer := NewPagerDutyObserver("", "routing-key", "", mockClient)
+
+	assert.NoError(t, observer.Notify(notification.State{Name: "api", Status: "up", TargetID: 1}))
+
+	if assert.Len(t, mockClient.requests, 1) {
+		assert.Equal(t, DefaultPagerDutyEventsURL, mockClient.requests[0].URL.String())
+	}
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+)
+
+// Acknowledger is implemented by observers that track incidents on their
+// side, such as paging services, so they can be told someone is handling one
+type Acknowledger interface {
+	Acknowledge(notification.State) error
+}
+
+// pagingSeverities are the statuses paging services open an alert for, and
+// how urgent it is. They match the statuses that open an incident in the
+// app, so the alert lives as long as the incident does.
+var pagingSeverities = map[string]string{
+	"down":  "critical",
+	"error": "error",
+}
+
+// pagingResolves reports whether status closes the target's alert. Notices
+// that are not target statuses, such as certificate expiry, neither open
+// nor close one.
+func pagingResolves(status string) bool {
+	return status == "up" || status == "degraded"
+}
+
+// alertKey identifies a target's alert on paging services, so every status
+// change of the target updates or resolves the same one. Test notifications
+// use their own key so they never touch a real alert.
+func alertKey(state notification.State) string {
+	if state.Test {
+		return fmt.Sprintf("uptimebot-test-%d", state.TargetID)
+	}
+	return fmt.Sprintf("uptimebot-target-%d", state.TargetID)
+}
+
+// sendPagingRequest sends req and turns anything but a 2xx answer into an
+// error. messageField names the field of the JSON error body explaining
+// what went wrong. Transport errors are unwrapped from the URL they quote,
+// which may carry credentials.
+func sendPagingRequest(client HTTPClient, req *http.Request, service, messageField string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to reach %s: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+
+	var body map[string]any
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10)); err == nil && json.Unmarshal(data, &body) == nil {
+		if message, ok := body[messageField].(string); ok && message != "" {
+			return fmt.Errorf("%s returned status code %d: %s", service, resp.StatusCode, message)
+		}
+	}
+	return fmt.Errorf("%s returned non-2xx status code: %d", service, resp.StatusCode)
+}
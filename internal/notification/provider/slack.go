@@ -50,10 +50,13 @@ type field struct {
 // Notify implements the Observer interface
 func (s *SlackObserver) Notify(state notification.State) error {
 	color := "warning"
-	if state.Status == "up" {
+	switch state.Status {
+	case "up":
 		color = "good"
-	} else if state.Status == "down" {
+	case "down":
 		color = "danger"
+	case "degraded":
+		color = "#f2c744"
 	}
 
 	msg := slackMessage{
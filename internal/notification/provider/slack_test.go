@@ -70,6 +70,19 @@ func TestSlackObserver_Notify(t *testing.T) {
 			wantErr:     false,
 			checkFields: true,
 		},
+		{
+			name: "degraded status",
+			state: notification.State{
+				Name:      "test-system",
+				Status:    "degraded",
+				Message:   "Response time 2500ms exceeded threshold of 2000ms",
+				UpdatedAt: time.Now(),
+			},
+			statusCode:  http.StatusOK,
+			err:         nil,
+			wantErr:     false,
+			checkFields: true,
+		},
 		{
 			name: "api error",
 			state: notification.State{
@@ -113,10 +126,13 @@ func TestSlackObserver_Notify(t *testing.T) {
 
 				attachment := msg.Attachments[0]
 				expectedColor := "warning"
-				if tt.state.Status == "up" {
+				switch tt.state.Status {
+				case "up":
 					expectedColor = "good"
-				} else if tt.state.Status == "down" {
+				case "down":
 					expectedColor = "danger"
+				case "degraded":
+					expectedColor = "#f2c744"
 				}
 				assert.Equal(t, expectedColor, attachment.Color)
 
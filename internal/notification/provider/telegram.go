@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+)
+
+// DefaultTelegramAPIURL is the address of the Telegram Bot API
+const DefaultTelegramAPIURL = "https://api.telegram.org"
+
+// TelegramObserver implements the Observer interface by sending a message
+// through the Telegram Bot API
+type TelegramObserver struct {
+	apiURL           string
+	botToken         string
+	chatID           string
+	silentRecoveries bool
+	baseURL          string
+	client           HTTPClient
+}
+
+// NewTelegramObserver creates a new Telegram observer. apiURL is the Bot
+// API's address, DefaultTelegramAPIURL when empty. Recoveries are delivered
+// without a sound when silentRecoveries is set. Links back to the target
+// start with baseURL.
+func NewTelegramObserver(apiURL, botToken, chatID string, silentRecoveries bool, baseURL string, client HTTPClient) *TelegramObserver {
+	if apiURL == "" {
+		apiURL = DefaultTelegramAPIURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &TelegramObserver{
+		apiURL:           strings.TrimRight(apiURL, "/"),
+		botToken:         botToken,
+		chatID:           chatID,
+		silentRecoveries: silentRecoveries,
+		baseURL:          baseURL,
+		client:           client,
+	}
+}
+
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableNotification   bool   `json:"disable_notification,omitempty"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+// telegramIcons lead the message of each status
+var telegramIcons = map[string]string{
+	"up":                   "✅",
+	"degraded":             "⚠️",
+	"down":                 "🔴",
+	"error":                "🔴",
+	"certificate_expiring": "🔒",
+	"dns_changed":          "🔀",
+}
+
+// Notify implements the Observer interface
+func (t *TelegramObserver) Notify(state notification.State) error {
+	payload, err := json.Marshal(telegramMessage{
+		ChatID:                t.chatID,
+		Text:                  t.format(state),
+		ParseMode:             "MarkdownV2",
+		DisableNotification:   t.silentRecoveries && state.Status == "up",
+		DisableWebPagePreview: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram message: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/bot%s/sendMessage", t.apiURL, t.botToken), bytes.NewBuffer(payload))
+	if err != nil {
+		// The error would quote the URL and with it the bot token
+		return fmt.Errorf("failed to create request: invalid telegram API URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send telegram message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var result telegramResponse
+		if json.NewDecoder(resp.Body).Decode(&result) == nil && result.Description != "" {
+			return fmt.Errorf("telegram returned status code %d: %s", resp.StatusCode, result.Description)
+		}
+		return fmt.Errorf("telegram returned non-2xx status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// format renders the state as MarkdownV2
+func (t *TelegramObserver) format(state notification.State) string {
+	var b strings.Builder
+
+	if icon, ok := telegramIcons[state.Status]; ok {
+		b.WriteString(icon + " ")
+	}
+	fmt.Fprintf(&b, "*%s is %s*\n", escapeTelegramMarkdown(state.Name), escapeTelegramMarkdown(state.Status))
+	if state.Message != "" {
+		b.WriteString(escapeTelegramMarkdown(state.Message) + "\n")
+	}
+
+	b.WriteString("\n")
+	if state.PreviousStatus != "" {
+		fmt.Fprintf(&b, "*Previous status:* %s\n", escapeTelegramMarkdown(state.PreviousStatus))
+	}
+	if state.Reason != "" {
+		fmt.Fprintf(&b, "*Reason:* %s\n", escapeTelegramMarkdown(state.Reason))
+	}
+	if state.Latency > 0 {
+		fmt.Fprintf(&b, "*Response time:* %s\n", escapeTelegramMarkdown(state.Latency.String()))
+	}
+	if !state.UpdatedAt.IsZero() {
+		fmt.Fprintf(&b, "*Time:* %s\n", escapeTelegramMarkdown(state.UpdatedAt.UTC().Format(time.RFC1123)))
+	}
+
+	var links []string
+	if link := targetLink(t.baseURL, state.TargetID); link != "" {
+		links = append(links, fmt.Sprintf("[View target](%s)", escapeTelegramLink(link)))
+	}
+	if link := incidentLink(t.baseURL, state.IncidentID); link != "" {
+		links = append(links, fmt.Sprintf("[Incident \\#%d](%s)", state.IncidentID, escapeTelegramLink(link)))
+	}
+	if len(links) > 0 {
+		b.WriteString("\n" + strings.Join(links, " · ") + "\n")
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// telegramMarkdownEscaper escapes the characters MarkdownV2 reserves outside
+// of entities
+var telegramMarkdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+	"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+func escapeTelegramMarkdown(s string) string {
+	return telegramMarkdownEscaper.Replace(s)
+}
+
+// Inside the (...) of a link only ")" and "\" must be escaped
+var telegramLinkEscaper = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+
+func escapeTelegramLink(s string) string {
+	return telegramLinkEscaper.Replace(s)
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// telegramServer stands in for the Bot API and records what it is sent
+type telegramServer struct {
+	*httptest.Server
+	paths    []string
+	messages []telegramMessage
+}
+
+func newTelegramServer(t *testing.T, statusCode int, body string) *telegramServer {
+	s := &telegramServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg telegramMessage
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		s.paths = append(s.paths, r.URL.Path)
+		s.messages = append(s.messages, msg)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestTelegramObserver_Notify(t *testing.T) {
+	down := notification.State{
+		Name:           "https://example.com",
+		Status:         "down",
+		Message:        "Target https://example.com is down: status code 503",
+		UpdatedAt:      time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC),
+		TargetID:       7,
+		PreviousStatus: "up",
+		Reason:         "status code 503",
+		Latency:        1500 * time.Millisecond,
+		IncidentID:     3,
+	}
+
+	t.Run("down", func(t *testing.T) {
+		server := newTelegramServer(t, http.StatusOK, `{"ok": true}`)
+		observer := NewTelegramObserver(server.URL, "123:abc", "-10042", true, "https://uptime.example.com", nil)
+
+		assert.NoError(t, observer.Notify(down))
+
+		if !assert.Len(t, server.messages, 1) {
+			return
+		}
+		assert.Equal(t, []string{"/bot123:abc/sendMessage"}, server.paths)
+		msg := server.messages[0]
+		assert.Equal(t, "-10042", msg.ChatID)
+		assert.Equal(t, "MarkdownV2", msg.ParseMode)
+		assert.False(t, msg.DisableNotification, "outages always make a sound")
+		assert.Equal(t, strings.Join([]string{
+			`🔴 *https://example\.com is down*`,
+			`Target https://example\.com is down: status code 503`,
+			``,
+			`*Previous status:* up`,
+			`*Reason:* status code 503`,
+			`*Response time:* 1\.5s`,
+			`*Time:* Sun, 15 Jun 2025 12:00:00 UTC`,
+			``,
+			`[View target](https://uptime.example.com/targets/7/edit) · [Incident \#3](https://uptime.example.com/incidents/3)`,
+		}, "\n"), msg.Text)
+	})
+
+	t.Run("silent recovery", func(t *testing.T) {
+		server := newTelegramServer(t, http.StatusOK, `{"ok": true}`)
+		observer := NewTelegramObserver(server.URL, "123:abc", "42", true, "", nil)
+
+		assert.NoError(t, observer.Notify(notification.State{Name: "api", Status: "up"}))
+
+		if assert.Len(t, server.messages, 1) {
+			assert.True(t, server.messages[0].DisableNotification)
+			assert.Equal(t, "✅ *api is up*", server.messages[0].Text)
+		}
+	})
+
+	t.Run("recovery with sound", func(t *testing.T) {
+		server := newTelegramServer(t, http.StatusOK, `{"ok": true}`)
+		observer := NewTelegramObserver(server.URL, "123:abc", "42", false, "", nil)
+
+		assert.NoError(t, observer.Notify(notification.State{Name: "api", Status: "up"}))
+
+		if assert.Len(t, server.messages, 1) {
+			assert.False(t, server.messages[0].DisableNotification)
+		}
+	})
+
+	t.Run("error description", func(t *testing.T) {
+		server := newTelegramServer(t, http.StatusBadRequest, `{"ok": false, "error_code": 400, "description": "Bad Request: chat not found"}`)
+		observer := NewTelegramObserver(server.URL, "123:abc", "42", false, "", nil)
+
+		err := observer.Notify(down)
+
+		assert.EqualError(t, err, "telegram returned status code 400: Bad Request: chat not found")
+	})
+
+	t.Run("network error hides the token", func(t *testing.T) {
+		server := newTelegramServer(t, http.StatusOK, `{"ok": true}`)
+		server.Close()
+		observer := NewTelegramObserver(server.URL, "123:secret-token", "42", false, "", nil)
+
+		err := observer.Notify(down)
+
+		if assert.Error(t, err) {
+			assert.NotContains(t, err.Error(), "secret-token")
+		}
+	})
+}
+
+func TestEscapeTelegramMarkdown(t *testing.T) {
+	assert.Equal(t, `a\_b\*c\[d\]\(e\)\~\`+"`"+`\>\#\+\-\=\|\{\}\.\!\\`, escapeTelegramMarkdown("a_b*c[d](e)~`>#+-=|{}.!\\"))
+	assert.Equal(t, `https://example.com/a\)b\\c`, escapeTelegramLink(`https://example.com/a)b\c`))
+}
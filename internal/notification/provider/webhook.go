@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+)
+
+// Headers sent with every webhook request
+const (
+	WebhookSignatureHeader = "X-Uptimebot-Signature"
+	WebhookTimestampHeader = "X-Uptimebot-Timestamp"
+)
+
+// DefaultWebhookTimeout is how long a webhook may take to answer unless its
+// configuration says otherwise
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookObserver implements the Observer interface by POSTing a JSON
+// description of the state to any URL
+type WebhookObserver struct {
+	url     string
+	secret  string
+	headers map[string]string
+	timeout time.Duration
+	baseURL string
+	client  HTTPClient
+}
+
+// NewWebhookObserver creates a new webhook observer. Links to incidents in
+// the payload start with baseURL.
+func NewWebhookObserver(url, secret string, headers map[string]string, timeout time.Duration, baseURL string, client HTTPClient) *WebhookObserver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	return &WebhookObserver{
+		url:     url,
+		secret:  secret,
+		headers: headers,
+		timeout: timeout,
+		baseURL: baseURL,
+		client:  client,
+	}
+}
+
+// WebhookPayload is the body of every webhook request
+type WebhookPayload struct {
+	Target         WebhookTarget `json:"target"`
+	Status         string        `json:"status"`
+	PreviousStatus string        `json:"previous_status,omitempty"`
+	Message        string        `json:"message"`
+	Reason         string        `json:"reason,omitempty"`
+	LatencyMs      int64         `json:"latency_ms,omitempty"`
+	IncidentID     int           `json:"incident_id,omitempty"`
+	IncidentURL    string        `json:"incident_url,omitempty"`
+	Timestamp      time.Time     `json:"timestamp"`
+}
+
+// WebhookTarget identifies the target a webhook is about
+type WebhookTarget struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Notify implements the Observer interface
+func (w *WebhookObserver) Notify(state notification.State) error {
+	payload := WebhookPayload{
+		Target:         WebhookTarget{ID: state.TargetID, Name: state.Name},
+		Status:         state.Status,
+		PreviousStatus: state.PreviousStatus,
+		Message:        state.Message,
+		Reason:         state.Reason,
+		LatencyMs:      state.Latency.Milliseconds(),
+		IncidentID:     state.IncidentID,
+		IncidentURL:    incidentLink(w.baseURL, state.IncidentID),
+		Timestamp:      state.UpdatedAt.UTC(),
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range w.headers {
+		req.Header.Set(name, value)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Uptimebot-Webhook/1.0")
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(w.secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned non-2xx status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SignWebhook returns the signature header value for a webhook body sent at
+// timestamp. Receivers recompute it with their copy of the secret; signing
+// the timestamp too stops old requests from being replayed.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookObserver_Notify(t *testing.T) {
+	updatedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	state := notification.State{
+		Name:           "https://example.com",
+		Status:         "down",
+		Message:        "Target https://example.com is down: status code 503",
+		UpdatedAt:      updatedAt,
+		TargetID:       7,
+		PreviousStatus: "up",
+		Reason:         "status code 503",
+		Latency:        1500 * time.Millisecond,
+		IncidentID:     3,
+	}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+		wantErr    bool
+	}{
+		{name: "accepted", statusCode: http.StatusAccepted},
+		{name: "receiver error", statusCode: http.StatusInternalServerError, wantErr: true},
+		{name: "network error", err: errors.New("connection refused"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := NewMockHTTPClient(tt.statusCode, tt.err)
+			headers := map[string]string{"X-Api-Key": "abc"}
+			observer := NewWebhookObserver("https://tools.example.com/hooks", "s3cret", headers, 0, "https://uptime.example.com", mockClient)
+
+			err := observer.Notify(state)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if !assert.Len(t, mockClient.requests, 1) {
+				return
+			}
+
+			req := mockClient.requests[0]
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "https://tools.example.com/hooks", req.URL.String())
+			assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+			assert.Equal(t, "abc", req.Header.Get("X-Api-Key"))
+
+			deadline, ok := req.Context().Deadline()
+			assert.True(t, ok, "requests time out")
+			assert.WithinDuration(t, time.Now().Add(DefaultWebhookTimeout), deadline, time.Second)
+
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			timestamp := req.Header.Get(WebhookTimestampHeader)
+			assert.NotEmpty(t, timestamp)
+			assert.Equal(t, SignWebhook("s3cret", timestamp, body), req.Header.Get(WebhookSignatureHeader))
+
+			var payload WebhookPayload
+			assert.NoError(t, json.Unmarshal(body, &payload))
+			assert.Equal(t, WebhookPayload{
+				Target:         WebhookTarget{ID: 7, Name: "https://example.com"},
+				Status:         "down",
+				PreviousStatus: "up",
+				Message:        "Target https://example.com is down: status code 503",
+				Reason:         "status code 503",
+				LatencyMs:      1500,
+				IncidentID:     3,
+				IncidentURL:    "https://uptime.example.com/incidents/3",
+				Timestamp:      updatedAt,
+			}, payload)
+		})
+	}
+}
+
+func TestWebhookObserver_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	observer := NewWebhookObserver(server.URL, "s3cret", nil, 50*time.Millisecond, "", nil)
+
+	start := time.Now()
+	err := observer.Notify(notification.State{Name: "https://example.com", Status: "down"})
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestSignWebhook(t *testing.T) {
+	signature := SignWebhook("s3cret", "1748779200", []byte(`{"status":"down"}`))
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.NotEqual(t, signature, SignWebhook("other", "1748779200", []byte(`{"status":"down"}`)))
+	assert.NotEqual(t, signature, SignWebhook("s3cret", "1748779260", []byte(`{"status":"down"}`)))
+}
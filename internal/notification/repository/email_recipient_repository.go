@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+)
+
+type EmailRecipientRepositoryInterface interface {
+	Create(recipient *model.EmailRecipient) (*model.EmailRecipient, error)
+	GetByNotifierID(notifierID int64) ([]*model.EmailRecipient, error)
+	GetByToken(token string) (*model.EmailRecipient, error)
+	MarkVerified(id int64, at time.Time) error
+	Delete(id int64) error
+}
+
+var _ EmailRecipientRepositoryInterface = (*EmailRecipientRepository)(nil)
+
+// EmailRecipientRepository handles database operations for the addresses
+// of email notifiers
+type EmailRecipientRepository struct {
+	db *sql.DB
+}
+
+// NewEmailRecipientRepository creates a new email recipient repository
+func NewEmailRecipientRepository(db *sql.DB) *EmailRecipientRepository {
+	return &EmailRecipientRepository{db: db}
+}
+
+const emailRecipientQuery = `SELECT id, notifier_id, email, token, created_at, verified_at
+			  FROM email_recipient`
+
+// Create inserts a new, unverified recipient
+func (r *EmailRecipientRepository) Create(recipient *model.EmailRecipient) (*model.EmailRecipient, error) {
+	query := `INSERT INTO email_recipient (notifier_id, email, token, created_at)
+			  VALUES (?, ?, ?, ?)`
+	result, err := r.db.Exec(query, recipient.NotifierID, recipient.Email, recipient.Token, recipient.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email recipient: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	recipient.ID = id
+	return recipient, nil
+}
+
+// GetByNotifierID lists the recipients of a notifier
+func (r *EmailRecipientRepository) GetByNotifierID(notifierID int64) ([]*model.EmailRecipient, error) {
+	rows, err := r.db.Query(emailRecipientQuery+` WHERE notifier_id = ? ORDER BY id`, notifierID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []*model.EmailRecipient
+	for rows.Next() {
+		recipient, err := scanEmailRecipient(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan email recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating email recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+// GetByToken returns the recipient a confirmation link was sent to, or nil if
+// there is none
+func (r *EmailRecipientRepository) GetByToken(token string) (*model.EmailRecipient, error) {
+	recipient, err := scanEmailRecipient(r.db.QueryRow(emailRecipientQuery+` WHERE token = ?`, token))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email recipient: %w", err)
+	}
+	return recipient, nil
+}
+
+// MarkVerified records that the recipient confirmed their address
+func (r *EmailRecipientRepository) MarkVerified(id int64, at time.Time) error {
+	if _, err := r.db.Exec(`UPDATE email_recipient SET verified_at = ? WHERE id = ?`, at, id); err != nil {
+		return fmt.Errorf("failed to verify email recipient: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a recipient
+func (r *EmailRecipientRepository) Delete(id int64) error {
+	if _, err := r.db.Exec(`DELETE FROM email_recipient WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete email recipient: %w", err)
+	}
+	return nil
+}
+
+func scanEmailRecipient(row rowScanner) (*model.EmailRecipient, error) {
+	var recipient model.EmailRecipient
+	var verifiedAt sql.NullTime
+
+	err := row.Scan(
+		&recipient.ID,
+		&recipient.NotifierID,
+		&recipient.Email,
+		&recipient.Token,
+		&recipient.CreatedAt,
+		&verifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient.VerifiedAt = verifiedAt.Time
+	return &recipient, nil
+}
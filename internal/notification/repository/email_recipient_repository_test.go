@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailRecipientRepository(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+
+	repo := NewEmailRecipientRepository(db)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("Create", func(t *testing.T) {
+		recipient, err := repo.Create(&model.EmailRecipient{NotifierID: 1, Email: "ops@example.com", Token: "token-1", CreatedAt: now})
+		assert.NoError(t, err)
+		assert.NotZero(t, recipient.ID)
+
+		_, err = repo.Create(&model.EmailRecipient{NotifierID: 1, Email: "cto@example.com", Token: "token-2", CreatedAt: now})
+		assert.NoError(t, err)
+
+		_, err = repo.Create(&model.EmailRecipient{NotifierID: 2, Email: "ops@example.com", Token: "token-3", CreatedAt: now})
+		assert.NoError(t, err, "an address can receive mail from several notifiers")
+
+		_, err = repo.Create(&model.EmailRecipient{NotifierID: 1, Email: "ops@example.com", Token: "token-4", CreatedAt: now})
+		assert.Error(t, err, "an address is listed once per notifier")
+	})
+
+	t.Run("GetByToken", func(t *testing.T) {
+		recipient, err := repo.GetByToken("token-1")
+		if assert.NoError(t, err) && assert.NotNil(t, recipient) {
+			assert.Equal(t, "ops@example.com", recipient.Email)
+			assert.Equal(t, int64(1), recipient.NotifierID)
+			assert.True(t, recipient.CreatedAt.Equal(now))
+			assert.False(t, recipient.IsVerified())
+		}
+
+		recipient, err = repo.GetByToken("missing")
+		assert.NoError(t, err)
+		assert.Nil(t, recipient)
+	})
+
+	t.Run("MarkVerified", func(t *testing.T) {
+		recipient, _ := repo.GetByToken("token-1")
+		assert.NoError(t, repo.MarkVerified(recipient.ID, now))
+
+		recipient, err := repo.GetByToken("token-1")
+		if assert.NoError(t, err) {
+			assert.True(t, recipient.VerifiedAt.Equal(now))
+		}
+	})
+
+	t.Run("GetByNotifierID and Delete", func(t *testing.T) {
+		recipients, err := repo.GetByNotifierID(1)
+		assert.NoError(t, err)
+		if assert.Len(t, recipients, 2) {
+			assert.Equal(t, "ops@example.com", recipients[0].Email)
+			assert.True(t, recipients[0].IsVerified())
+			assert.False(t, recipients[1].IsVerified())
+		}
+
+		assert.NoError(t, repo.Delete(recipients[1].ID))
+		recipients, err = repo.GetByNotifierID(1)
+		assert.NoError(t, err)
+		assert.Len(t, recipients, 1)
+	})
+}
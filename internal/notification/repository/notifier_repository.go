@@ -14,6 +14,7 @@ type NotifierRepositoryInterface interface {
 	Update(int, json.RawMessage) (*model.Notifier, error)
 	Delete(int64) error
 	GetByTargetID(int) ([]*model.Notifier, error)
+	UpdateFilter(int64, model.NotifierFilter) error
 }
 
 var _ NotifierRepositoryInterface = (*NotifierRepository)(nil)
@@ -28,6 +29,36 @@ func NewNotifierRepository(db *sql.DB) *NotifierRepository {
 	return &NotifierRepository{db: db}
 }
 
+// notifierColumns lists the columns scanNotifier expects, in order
+const notifierColumns = `id, target_id, type, config, filter`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNotifier(row rowScanner) (*model.Notifier, error) {
+	notifier := &model.Notifier{}
+	var filter string
+
+	err := row.Scan(
+		&notifier.ID,
+		&notifier.TargetId,
+		&notifier.Type,
+		&notifier.Config,
+		&filter,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(filter), &notifier.Filter); err != nil {
+		return nil, fmt.Errorf("failed to parse filter: %w", err)
+	}
+
+	return notifier, nil
+}
+
 // Create inserts a new notifier into the database
 func (r *NotifierRepository) Create(notifier *model.Notifier) (*model.Notifier, error) {
 	// Validate config based on notifier type
@@ -49,19 +80,17 @@ func (r *NotifierRepository) Create(notifier *model.Notifier) (*model.Notifier,
 		}
 	}
 
+	filter, err := json.Marshal(notifier.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode filter: %w", err)
+	}
+
 	query := `
-		INSERT INTO notifier (target_id, type, config)
-		VALUES (?, ?, ?)
-		RETURNING *
-	`
+		INSERT INTO notifier (target_id, type, config, filter)
+		VALUES (?, ?, ?, ?)
+		RETURNING ` + notifierColumns
 
-	newNotifier := &model.Notifier{}
-	err := r.db.QueryRow(query, notifier.TargetId, notifier.Type, notifier.Config).Scan(
-		&newNotifier.ID,
-		&newNotifier.TargetId,
-		&newNotifier.Type,
-		&newNotifier.Config,
-	)
+	newNotifier, err := scanNotifier(r.db.QueryRow(query, notifier.TargetId, notifier.Type, notifier.Config, string(filter)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create notifier: %w", err)
 	}
@@ -72,18 +101,12 @@ func (r *NotifierRepository) Create(notifier *model.Notifier) (*model.Notifier,
 // Get retrieves a notifier by ID
 func (r *NotifierRepository) Get(id int64) (*model.Notifier, error) {
 	query := `
-		SELECT id, target_id, type, config
+		SELECT ` + notifierColumns + `
 		FROM notifier
 		WHERE id = ?
 	`
 
-	notifier := &model.Notifier{}
-	err := r.db.QueryRow(query, id).Scan(
-		&notifier.ID,
-		&notifier.TargetId,
-		&notifier.Type,
-		&notifier.Config,
-	)
+	notifier, err := scanNotifier(r.db.QueryRow(query, id))
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -101,16 +124,9 @@ func (r *NotifierRepository) Update(id int, config json.RawMessage) (*model.Noti
 		UPDATE notifier
 		SET config = ?
 		WHERE id = ?
-		RETURNING id, target_id, type, config
-	`
+		RETURNING ` + notifierColumns
 
-	notifier := &model.Notifier{}
-	err := r.db.QueryRow(query, config, id).Scan(
-		&notifier.ID,
-		&notifier.TargetId,
-		&notifier.Type,
-		&notifier.Config,
-	)
+	notifier, err := scanNotifier(r.db.QueryRow(query, config, id))
 	if err != nil {
 		return nil, fmt.Errorf("failed to update: %w", err)
 	}
@@ -141,7 +157,7 @@ func (r *NotifierRepository) Delete(id int64) error {
 // GetByTargetID retrieves all notifiers for a specific target
 func (r *NotifierRepository) GetByTargetID(targetID int) ([]*model.Notifier, error) {
 	query := `
-		SELECT id, target_id, type, config
+		SELECT ` + notifierColumns + `
 		FROM notifier
 		WHERE target_id = ?
 	`
@@ -154,13 +170,7 @@ func (r *NotifierRepository) GetByTargetID(targetID int) ([]*model.Notifier, err
 
 	var notifiers []*model.Notifier
 	for rows.Next() {
-		notifier := &model.Notifier{}
-		err := rows.Scan(
-			&notifier.ID,
-			&notifier.TargetId,
-			&notifier.Type,
-			&notifier.Config,
-		)
+		notifier, err := scanNotifier(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan notifier: %w", err)
 		}
@@ -174,3 +184,27 @@ func (r *NotifierRepository) GetByTargetID(targetID int) ([]*model.Notifier, err
 
 	return notifiers, nil
 }
+
+// UpdateFilter replaces the filter deciding which status changes a notifier is sent
+func (r *NotifierRepository) UpdateFilter(id int64, filter model.NotifierFilter) error {
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		return fmt.Errorf("failed to encode filter: %w", err)
+	}
+
+	result, err := r.db.Exec(`UPDATE notifier SET filter = ? WHERE id = ?`, string(encoded), id)
+	if err != nil {
+		return fmt.Errorf("failed to update filter: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("notifier %d not found", id)
+	}
+
+	return nil
+}
@@ -253,3 +253,37 @@ func TestNotifierRepository_GetByTargetID(t *testing.T) {
 		assert.Equal(t, targetID+1, otherNotifiers[0].TargetId)
 	})
 }
+
+func TestNotifierRepository_UpdateFilter(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+
+	repo := NewNotifierRepository(db)
+
+	t.Run("NotFound", func(t *testing.T) {
+		err := repo.UpdateFilter(99, model.NotifierFilter{Statuses: []string{"down"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		created, err := repo.Create(&model.Notifier{
+			TargetId: 1,
+			Type:     model.NotifierTypeSlack,
+			Config:   json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, created.Filter.Statuses)
+
+		filter := model.NotifierFilter{Statuses: []string{"degraded"}}
+		err = repo.UpdateFilter(created.ID, filter)
+		assert.NoError(t, err)
+
+		saved, err := repo.Get(created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, filter, saved.Filter)
+
+		notifiers, err := repo.GetByTargetID(1)
+		assert.NoError(t, err)
+		assert.Equal(t, filter, notifiers[0].Filter)
+	})
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+)
+
+type OutboxRepositoryInterface interface {
+	Enqueue(message *model.OutboxMessage) (*model.OutboxMessage, error)
+	GetDue(now time.Time, limit int) ([]*model.OutboxMessage, error)
+	RecordAttempt(message *model.OutboxMessage, attempt *model.DeliveryAttempt) error
+	GetAttemptsByNotifierID(notifierID int64, limit int) ([]*model.DeliveryAttempt, error)
+	CancelHeld(notifierID int64, statuses []string, since, now time.Time) (int64, error)
+	DeleteFinishedBefore(before time.Time) (int64, error)
+}
+
+var _ OutboxRepositoryInterface = (*OutboxRepository)(nil)
+
+// OutboxRepository handles database operations for notifications waiting to
+// be delivered and the attempts made at delivering them
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+const outboxQuery = `SELECT id, notifier_id, kind, state, status, attempts, next_attempt_at, last_error, created_at, finished_at, delivered_to
+			  FROM notification_outbox`
+
+// Enqueue stores a new pending message and sets its ID
+func (r *OutboxRepository) Enqueue(message *model.OutboxMessage) (*model.OutboxMessage, error) {
+	state, err := json.Marshal(message.State)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification state: %w", err)
+	}
+
+	query := `INSERT INTO notification_outbox (notifier_id, kind, state, status, next_attempt_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := r.db.Exec(query, message.NotifierID, message.Kind, string(state), model.OutboxPending,
+		message.NextAttemptAt.UTC(), message.CreatedAt.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	message.ID = id
+	message.Status = model.OutboxPending
+	return message, nil
+}
+
+// GetDue lists pending messages whose next attempt is due, oldest first.
+// Only the oldest pending message of each notifier is returned, so a message
+// waiting to be retried holds back the ones queued after it and every
+// notifier hears about changes in the order they happened. Messages a filter
+// holds back and that were never attempted hold back nothing.
+func (r *OutboxRepository) GetDue(now time.Time, limit int) ([]*model.OutboxMessage, error) {
+	query := outboxQuery + ` o
+			  WHERE o.status = ? AND o.next_attempt_at <= ?
+			    AND NOT EXISTS (
+			        SELECT 1 FROM notification_outbox p
+			        WHERE p.notifier_id = o.notifier_id AND p.status = ? AND p.id < o.id
+			          AND NOT (p.attempts = 0 AND p.next_attempt_at > ?)
+			    )
+			  ORDER BY o.id
+			  LIMIT ?`
+	rows, err := r.db.Query(query, model.OutboxPending, now.UTC(), model.OutboxPending, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*model.OutboxMessage
+	for rows.Next() {
+		message, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+	return messages, nil
+}
+
+// RecordAttempt logs an attempt at delivering the message and saves where
+// that left the message, both at once
+func (r *OutboxRepository) RecordAttempt(message *model.OutboxMessage, attempt *model.DeliveryAttempt) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var finishedAt sql.NullTime
+	if !message.FinishedAt.IsZero() {
+		finishedAt = sql.NullTime{Time: message.FinishedAt.UTC(), Valid: true}
+	}
+	deliveredTo, err := json.Marshal(message.DeliveredTo)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivered recipients: %w", err)
+	}
+	_, err = tx.Exec(`UPDATE notification_outbox
+			  SET status = ?, attempts = ?, next_attempt_at = ?, last_error = ?, finished_at = ?, delivered_to = ?
+			  WHERE id = ?`,
+		message.Status, message.Attempts, message.NextAttemptAt.UTC(), message.LastError, finishedAt, string(deliveredTo), message.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update notification: %w", err)
+	}
+
+	result, err := tx.Exec(`INSERT INTO notification_attempt (outbox_id, notifier_id, attempt, attempted_at, status_code, error, latency_ms)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		message.ID, message.NotifierID, attempt.Attempt, attempt.AttemptedAt.UTC(), attempt.StatusCode, attempt.Error,
+		attempt.Latency.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to log delivery attempt: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit delivery attempt: %w", err)
+	}
+
+	attempt.ID, _ = result.LastInsertId()
+	attempt.OutboxID = message.ID
+	attempt.NotifierID = message.NotifierID
+	return nil
+}
+
+// GetAttemptsByNotifierID lists the latest delivery attempts of a notifier,
+// newest first
+func (r *OutboxRepository) GetAttemptsByNotifierID(notifierID int64, limit int) ([]*model.DeliveryAttempt, error) {
+	query := `SELECT a.id, a.outbox_id, a.notifier_id, a.attempt, a.attempted_at, a.status_code, a.error, a.latency_ms,
+			         o.kind, o.state
+			  FROM notification_attempt a
+			  JOIN notification_outbox o ON o.id = a.outbox_id
+			  WHERE a.notifier_id = ?
+			  ORDER BY a.id DESC
+			  LIMIT ?`
+	rows, err := r.db.Query(query, notifierID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []*model.DeliveryAttempt
+	for rows.Next() {
+		var attempt model.DeliveryAttempt
+		var latencyMs int64
+		var message model.OutboxMessage
+		var state string
+		err := rows.Scan(
+			&attempt.ID,
+			&attempt.OutboxID,
+			&attempt.NotifierID,
+			&attempt.Attempt,
+			&attempt.AttemptedAt,
+			&attempt.StatusCode,
+			&attempt.Error,
+			&latencyMs,
+			&message.Kind,
+			&state,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery attempt: %w", err)
+		}
+		if err := json.Unmarshal([]byte(state), &message.State); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal notification state: %w", err)
+		}
+		attempt.Latency = time.Duration(latencyMs) * time.Millisecond
+		attempt.Summary = message.Summary()
+		attempts = append(attempts, &attempt)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating delivery attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// CancelHeld cancels the notifier's status changes to any of statuses that
+// are held back until after now and were never tried, about outages that
+// began after since, and returns how many were cancelled
+func (r *OutboxRepository) CancelHeld(notifierID int64, statuses []string, since, now time.Time) (int64, error) {
+	if len(statuses) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	args := []any{notifierID, model.OutboxPending, model.OutboxNotify, now.UTC()}
+	for _, status := range statuses {
+		args = append(args, status)
+	}
+	query := `SELECT id, state FROM notification_outbox
+			  WHERE notifier_id = ? AND status = ? AND kind = ? AND attempts = 0 AND next_attempt_at > ?
+			    AND json_extract(state, '$.Status') IN (?` + strings.Repeat(", ?", len(statuses)-1) + `)`
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get held notifications: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var raw []byte
+		var state notification.State
+		if err := rows.Scan(&id, &raw); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan held notification: %w", err)
+		}
+		if err := json.Unmarshal(raw, &state); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to decode held notification: %w", err)
+		}
+		if model.OutageStart(state).After(since) {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating held notifications: %w", err)
+	}
+
+	for _, id := range ids {
+		if _, err := tx.Exec(`UPDATE notification_outbox SET status = ?, finished_at = ? WHERE id = ?`, model.OutboxCancelled, now.UTC(), id); err != nil {
+			return 0, fmt.Errorf("failed to cancel held notification: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return int64(len(ids)), nil
+}
+
+// DeleteFinishedBefore removes messages delivered or given up on before the
+// given time, along with their attempts, and returns how many were removed
+func (r *OutboxRepository) DeleteFinishedBefore(before time.Time) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	finished := `SELECT id FROM notification_outbox WHERE status != ? AND finished_at < ?`
+	if _, err := tx.Exec(`DELETE FROM notification_attempt WHERE outbox_id IN (`+finished+`)`, model.OutboxPending, before.UTC()); err != nil {
+		return 0, fmt.Errorf("failed to delete delivery attempts: %w", err)
+	}
+	result, err := tx.Exec(`DELETE FROM notification_outbox WHERE status != ? AND finished_at < ?`, model.OutboxPending, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete notifications: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit cleanup: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func scanOutboxMessage(row rowScanner) (*model.OutboxMessage, error) {
+	var message model.OutboxMessage
+	var state string
+	var finishedAt sql.NullTime
+	var deliveredTo string
+
+	err := row.Scan(
+		&message.ID,
+		&message.NotifierID,
+		&message.Kind,
+		&state,
+		&message.Status,
+		&message.Attempts,
+		&message.NextAttemptAt,
+		&message.LastError,
+		&message.CreatedAt,
+		&finishedAt,
+		&deliveredTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(state), &message.State); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal notification state: %w", err)
+	}
+	if err := json.Unmarshal([]byte(deliveredTo), &message.DeliveredTo); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal delivered recipients: %w", err)
+	}
+	message.FinishedAt = finishedAt.Time
+	return &message, nil
+}
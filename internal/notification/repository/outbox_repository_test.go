@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRepository(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+
+	repo := NewOutboxRepository(db)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	enqueue := func(notifierID int64, status string, at time.Time) *model.OutboxMessage {
+		message, err := repo.Enqueue(&model.OutboxMessage{
+			NotifierID:    notifierID,
+			Kind:          model.OutboxNotify,
+			State:         notification.State{Name: "https://example.com", Status: status, TargetID: 7, UpdatedAt: now},
+			NextAttemptAt: at,
+			CreatedAt:     now,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return message
+	}
+
+	down := enqueue(1, "down", now)
+	up := enqueue(1, "up", now)
+	other := enqueue(2, "down", now)
+	later := enqueue(3, "down", now.Add(time.Minute))
+
+	t.Run("Enqueue", func(t *testing.T) {
+		assert.NotZero(t, down.ID)
+		assert.Equal(t, model.OutboxPending, down.Status)
+	})
+
+	t.Run("GetDue", func(t *testing.T) {
+		messages, err := repo.GetDue(now, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, messages, 2, "one message per notifier, once due") {
+			assert.Equal(t, down.ID, messages[0].ID)
+			assert.Equal(t, "down", messages[0].State.Status)
+			assert.Equal(t, 7, messages[0].State.TargetID)
+			assert.True(t, messages[0].State.UpdatedAt.Equal(now))
+			assert.Equal(t, other.ID, messages[1].ID)
+		}
+
+		messages, err = repo.GetDue(now.Add(time.Minute), 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 3)
+
+		messages, err = repo.GetDue(now, 1)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+	})
+
+	t.Run("RecordAttempt", func(t *testing.T) {
+		down.Attempts = 1
+		down.LastError = "slack API returned non-200 status code: 500"
+		down.NextAttemptAt = now.Add(30 * time.Second)
+		down.DeliveredTo = []string{"ops@example.com"}
+		attempt := &model.DeliveryAttempt{
+			Attempt:     1,
+			AttemptedAt: now,
+			StatusCode:  500,
+			Error:       down.LastError,
+			Latency:     120 * time.Millisecond,
+		}
+		assert.NoError(t, repo.RecordAttempt(down, attempt))
+		assert.NotZero(t, attempt.ID)
+
+		messages, err := repo.GetDue(now, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, messages, 1, "a message waiting to be retried holds back the ones after it") {
+			assert.Equal(t, other.ID, messages[0].ID)
+		}
+
+		messages, err = repo.GetDue(now.Add(30*time.Second), 10)
+		assert.NoError(t, err)
+		if assert.NotEmpty(t, messages) {
+			assert.Equal(t, down.ID, messages[0].ID)
+			assert.Equal(t, []string{"ops@example.com"}, messages[0].DeliveredTo, "recipients reached are kept for the retry")
+		}
+
+		down.Attempts = 2
+		down.Status = model.OutboxDelivered
+		down.LastError = ""
+		down.FinishedAt = now.Add(30 * time.Second)
+		assert.NoError(t, repo.RecordAttempt(down, &model.DeliveryAttempt{Attempt: 2, AttemptedAt: now.Add(30 * time.Second), StatusCode: 200}))
+
+		messages, err = repo.GetDue(now, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, messages, 2) {
+			assert.Equal(t, up.ID, messages[0].ID)
+		}
+	})
+
+	t.Run("GetAttemptsByNotifierID", func(t *testing.T) {
+		attempts, err := repo.GetAttemptsByNotifierID(1, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, attempts, 2) {
+			assert.Equal(t, 2, attempts[0].Attempt, "newest first")
+			assert.True(t, attempts[0].Succeeded())
+			assert.Equal(t, "https://example.com is down", attempts[0].Summary)
+
+			assert.False(t, attempts[1].Succeeded())
+			assert.Equal(t, 500, attempts[1].StatusCode)
+			assert.Equal(t, 120*time.Millisecond, attempts[1].Latency)
+			assert.Equal(t, down.ID, attempts[1].OutboxID)
+		}
+
+		attempts, err = repo.GetAttemptsByNotifierID(1, 1)
+		assert.NoError(t, err)
+		assert.Len(t, attempts, 1)
+
+		attempts, err = repo.GetAttemptsByNotifierID(later.NotifierID, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, attempts)
+	})
+
+	t.Run("DeleteFinishedBefore", func(t *testing.T) {
+		deleted, err := repo.DeleteFinishedBefore(now)
+		assert.NoError(t, err)
+		assert.Zero(t, deleted)
+
+		deleted, err = repo.DeleteFinishedBefore(now.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted, "pending messages are kept")
+
+		attempts, err := repo.GetAttemptsByNotifierID(1, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, attempts)
+	})
+}
+
+func TestOutboxRepository_GetDueHeld(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+
+	repo := NewOutboxRepository(db)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	enqueue := func(status string, at time.Time) *model.OutboxMessage {
+		message, err := repo.Enqueue(&model.OutboxMessage{
+			NotifierID:    1,
+			Kind:          model.OutboxNotify,
+			State:         notification.State{Name: "https://example.com", Status: status, TargetID: 7, UpdatedAt: now},
+			NextAttemptAt: at,
+			CreatedAt:     now,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return message
+	}
+
+	held := enqueue("down", now.Add(time.Hour))
+	escalation := enqueue("down", now)
+
+	messages, err := repo.GetDue(now, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 1, "a held message does not hold back the ones after it") {
+		assert.Equal(t, escalation.ID, messages[0].ID)
+	}
+
+	escalation.Status = model.OutboxDelivered
+	escalation.Attempts = 1
+	escalation.FinishedAt = now
+	assert.NoError(t, repo.RecordAttempt(escalation, &model.DeliveryAttempt{Attempt: 1, AttemptedAt: now, StatusCode: 200}))
+
+	messages, err = repo.GetDue(now.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, held.ID, messages[0].ID)
+	}
+}
+
+func TestOutboxRepository_CancelHeld(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+
+	repo := NewOutboxRepository(db)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	enqueue := func(notifierID int64, kind, status string, at time.Time) *model.OutboxMessage {
+		message, err := repo.Enqueue(&model.OutboxMessage{
+			NotifierID:    notifierID,
+			Kind:          kind,
+			State:         notification.State{Name: "https://example.com", Status: status, TargetID: 7, UpdatedAt: now, OutageStart: now.Add(-time.Minute)},
+			NextAttemptAt: at,
+			CreatedAt:     now,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return message
+	}
+
+	due := enqueue(1, model.OutboxNotify, "down", now)
+	held := enqueue(1, model.OutboxNotify, "error", now.Add(5*time.Minute))
+	enqueue(1, model.OutboxNotify, "certificate_expiring", now.Add(5*time.Minute))
+	enqueue(1, model.OutboxAcknowledge, "", now.Add(5*time.Minute))
+	other := enqueue(2, model.OutboxNotify, "down", now.Add(5*time.Minute))
+
+	cancelled, err := repo.CancelHeld(1, nil, now.Add(-5*time.Minute), now)
+	assert.NoError(t, err)
+	assert.Zero(t, cancelled)
+
+	cancelled, err = repo.CancelHeld(1, []string{"down", "error"}, now, now)
+	assert.NoError(t, err)
+	assert.Zero(t, cancelled, "outages that began before since are left alone")
+
+	cancelled, err = repo.CancelHeld(1, []string{"down", "error"}, now.Add(-5*time.Minute), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), cancelled, "only held status changes to the given statuses")
+
+	messages, err := repo.GetDue(now.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	ids := []int64{}
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	assert.Equal(t, []int64{due.ID, other.ID}, ids)
+	assert.NotContains(t, ids, held.ID)
+
+	deleted, err := repo.DeleteFinishedBefore(now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted, "cancelled messages are purged like finished ones")
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/provider"
+)
+
+// MaxDeliveryAttempts is how many times a message is tried before it is
+// given up on
+const MaxDeliveryAttempts = 8
+
+const (
+	// dispatchInterval is how often the outbox is checked for messages due
+	// to be retried
+	dispatchInterval = 5 * time.Second
+	dispatchBatch    = 50
+	dispatchWorkers  = 8
+	// deliveryTimeout bounds each request to a notification service
+	deliveryTimeout = 30 * time.Second
+
+	firstRetryDelay = 30 * time.Second
+	maxRetryDelay   = time.Hour
+
+	// deliveryRetention is how long finished messages and their attempts are
+	// kept for the delivery log, checked every purgeInterval
+	deliveryRetention = 30 * 24 * time.Hour
+	purgeInterval     = time.Hour
+)
+
+// Dispatcher delivers the messages queued in the outbox in the background,
+// retrying failed deliveries with exponential backoff and logging every
+// attempt. Each notifier receives its messages one at a time, in the order
+// they were queued.
+type Dispatcher struct {
+	notifiers *NotifierService
+	client    provider.HTTPClient
+	now       func() time.Time
+
+	// mu keeps dispatch rounds from overlapping
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDispatcher creates a dispatcher for the messages notifierService queues
+func NewDispatcher(notifierService *NotifierService) *Dispatcher {
+	return &Dispatcher{
+		notifiers: notifierService,
+		client:    &http.Client{Timeout: deliveryTimeout},
+		now:       time.Now,
+	}
+}
+
+// Start delivers messages in the background until Stop is called
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(dispatchInterval)
+		defer ticker.Stop()
+		purge := time.NewTicker(purgeInterval)
+		defer purge.Stop()
+
+		for {
+			if _, err := d.DispatchDue(); err != nil {
+				slog.Error("Failed to dispatch notifications", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-d.notifiers.queued:
+			case <-purge.C:
+				if _, err := d.notifiers.outboxRepo.DeleteFinishedBefore(d.now().Add(-deliveryRetention)); err != nil {
+					slog.Error("Failed to purge delivered notifications", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop waits for the current dispatch round to finish and stops delivering
+func (d *Dispatcher) Stop() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	<-d.done
+}
+
+// DispatchDue tries to deliver every message that is due and returns how
+// many attempts it made. The next message of a notifier becomes due as soon
+// as the one before it is delivered, so it goes out in the same call.
+func (d *Dispatcher) DispatchDue() (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	attempts := 0
+	for {
+		messages, err := d.notifiers.outboxRepo.GetDue(d.now(), dispatchBatch)
+		if err != nil {
+			return attempts, fmt.Errorf("failed to get due notifications: %w", err)
+		}
+		if len(messages) == 0 {
+			return attempts, nil
+		}
+
+		// Messages due together belong to different notifiers and can be
+		// delivered side by side
+		var wg sync.WaitGroup
+		queue := make(chan int)
+		errs := make([]error, len(messages))
+		for range min(dispatchWorkers, len(messages)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range queue {
+					errs[i] = d.deliver(messages[i])
+				}
+			}()
+		}
+		for i := range messages {
+			queue <- i
+		}
+		close(queue)
+		wg.Wait()
+
+		attempts += len(messages)
+		if err := errors.Join(errs...); err != nil {
+			return attempts, err
+		}
+	}
+}
+
+// deliver makes one attempt at delivering a message and records how it went
+func (d *Dispatcher) deliver(message *model.OutboxMessage) error {
+	recorder := &statusRecorder{client: d.client}
+	attemptedAt := d.now()
+	start := time.Now()
+	err := d.send(message, recorder)
+
+	message.Attempts++
+	attempt := &model.DeliveryAttempt{
+		Attempt:     message.Attempts,
+		AttemptedAt: attemptedAt,
+		StatusCode:  recorder.StatusCode(),
+		Latency:     time.Since(start),
+	}
+
+	switch {
+	case err == nil:
+		message.Status = model.OutboxDelivered
+		message.LastError = ""
+		message.FinishedAt = d.now()
+	case errors.As(err, new(permanentError)) || message.Attempts >= MaxDeliveryAttempts:
+		attempt.Error = err.Error()
+		message.Status = model.OutboxFailed
+		message.LastError = attempt.Error
+		message.FinishedAt = d.now()
+		slog.Error("Gave up delivering notification", "notifier", message.NotifierID, "attempts", message.Attempts, "error", err)
+	default:
+		attempt.Error = err.Error()
+		message.LastError = attempt.Error
+		message.NextAttemptAt = d.now().Add(retryDelay(message.Attempts))
+		slog.Warn("Failed to deliver notification, will retry", "notifier", message.NotifierID, "attempts", message.Attempts, "retry_at", message.NextAttemptAt, "error", err)
+	}
+
+	if err := d.notifiers.outboxRepo.RecordAttempt(message, attempt); err != nil {
+		return fmt.Errorf("failed to record delivery of notification %d: %w", message.ID, err)
+	}
+	return nil
+}
+
+// permanentError marks a failure that is bound to happen again, so
+// retrying the message is pointless
+type permanentError struct {
+	error
+}
+
+func (e permanentError) Unwrap() error {
+	return e.error
+}
+
+// send passes a message to its notifier's observer
+func (d *Dispatcher) send(message *model.OutboxMessage, client *statusRecorder) error {
+	notifier, err := d.notifiers.notifier(message.State.TargetID, message.NotifierID)
+	if err != nil {
+		return err
+	}
+	if notifier == nil {
+		return permanentError{errors.New("notifier no longer exists")}
+	}
+
+	observer, err := d.notifiers.newObserver(notifier, client)
+	if err != nil {
+		return permanentError{err}
+	}
+	if observer == nil {
+		return permanentError{ErrNoConfirmedRecipients}
+	}
+
+	if message.Kind == model.OutboxAcknowledge {
+		acknowledger, ok := observer.(provider.Acknowledger)
+		if !ok {
+			return nil
+		}
+		err = acknowledger.Acknowledge(message.State)
+	} else if recipients, ok := observer.(provider.RecipientNotifier); ok {
+		// Recipients an earlier attempt reached are not sent it again
+		var reached []string
+		reached, err = recipients.NotifyRecipients(message.State, message.DeliveredTo)
+		message.DeliveredTo = append(message.DeliveredTo, reached...)
+	} else {
+		err = observer.Notify(message.State)
+	}
+	if err != nil && isPermanentStatus(client.StatusCode()) {
+		return permanentError{err}
+	}
+	return err
+}
+
+// isPermanentStatus reports whether a service rejecting a request with
+// statusCode will keep rejecting it. Client errors mean the request or the
+// notifier's settings are wrong, except for timeouts and rate limits.
+func isPermanentStatus(statusCode int) bool {
+	if statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests {
+		return false
+	}
+	return statusCode >= 400 && statusCode < 500
+}
+
+// retryDelay is how long to wait before trying a message again after it
+// failed attempts times, doubling each time up to maxRetryDelay
+func retryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// statusRecorder passes requests on to client and remembers the status code
+// of the last response, so attempts can be logged with it
+type statusRecorder struct {
+	client provider.HTTPClient
+
+	mu         sync.Mutex
+	statusCode int
+}
+
+func (r *statusRecorder) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.client.Do(req)
+	if resp != nil {
+		r.mu.Lock()
+		r.statusCode = resp.StatusCode
+		r.mu.Unlock()
+	}
+	return resp, err
+}
+
+// StatusCode returns the status code of the last response, zero if there
+// was none
+func (r *statusRecorder) StatusCode() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.statusCode
+}
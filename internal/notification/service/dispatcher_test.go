@@ -0,0 +1,342 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/email"
+	"github.com/shuvo-paul/uptimebot/internal/email/mock"
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/provider"
+	"github.com/shuvo-paul/uptimebot/internal/notification/repository"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// slackFake stands in for a Slack webhook, answering with the queued status
+// codes and then 200, and records the target statuses it is sent
+type slackFake struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses []int
+	statuses  []string
+}
+
+func newSlackFake(t *testing.T, responses ...int) *slackFake {
+	f := &slackFake{responses: responses}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for _, field := range msg.Attachments[0].Fields {
+			if field.Title == "Status" {
+				f.statuses = append(f.statuses, field.Value)
+			}
+		}
+		status := http.StatusOK
+		if len(f.responses) > 0 {
+			status, f.responses = f.responses[0], f.responses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *slackFake) received() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.statuses...)
+}
+
+type slackMessage struct {
+	Attachments []struct {
+		Fields []struct {
+			Title string `json:"title"`
+			Value string `json:"value"`
+		} `json:"fields"`
+	} `json:"attachments"`
+}
+
+// newTestDispatcher returns a dispatcher for the given notifiers, of target 7
+// unless they say otherwise, whose clock the test controls
+func newTestDispatcher(t *testing.T, notifiers ...*model.Notifier) (*Dispatcher, *NotifierService, *repository.OutboxRepository, *time.Time) {
+	db := testutil.NewInMemoryDB()
+	// Every connection to :memory: opens a database of its own
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	mockRepo := &mockNotifierRepository{
+		getByTargetIDFunc: func(targetID int) ([]*model.Notifier, error) {
+			var attached []*model.Notifier
+			for _, notifier := range notifiers {
+				if notifier.TargetId == targetID {
+					attached = append(attached, notifier)
+				}
+			}
+			return attached, nil
+		},
+		getFunc: func(id int64) (*model.Notifier, error) {
+			for _, notifier := range notifiers {
+				if notifier.ID == id {
+					return notifier, nil
+				}
+			}
+			return nil, nil
+		},
+	}
+	outboxRepo := repository.NewOutboxRepository(db)
+	service := NewNotifierService(mockRepo, outboxRepo, nil, "")
+
+	// Start just ahead of the messages the test queues, so they are due
+	now := time.Now().Add(time.Second)
+	dispatcher := NewDispatcher(service)
+	dispatcher.now = func() time.Time { return now }
+	return dispatcher, service, outboxRepo, &now
+}
+
+func slackNotifier(id int64, url string) *model.Notifier {
+	return &model.Notifier{ID: id, TargetId: 7, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "` + url + `"}`)}
+}
+
+func TestDispatcher_DispatchDue(t *testing.T) {
+	down := notification.State{Name: "site", Status: "down", Message: "Target site is down", TargetID: 7}
+	up := notification.State{Name: "site", Status: "up", Message: "Target site is up", TargetID: 7}
+
+	t.Run("server error is retried in order", func(t *testing.T) {
+		slack := newSlackFake(t, http.StatusInternalServerError)
+		dispatcher, service, outboxRepo, now := newTestDispatcher(t, slackNotifier(1, slack.URL))
+
+		assert.NoError(t, service.Enqueue(7, down))
+		assert.NoError(t, service.Enqueue(7, up))
+
+		attempts, err := dispatcher.DispatchDue()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, attempts, "the recovery waits for the outage alert")
+
+		deliveries, err := outboxRepo.GetAttemptsByNotifierID(1, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, deliveries, 1) {
+			assert.Equal(t, http.StatusInternalServerError, deliveries[0].StatusCode)
+			assert.Equal(t, "slack API returned non-200 status code: 500", deliveries[0].Error)
+			assert.Equal(t, "site is down", deliveries[0].Summary)
+		}
+
+		attempts, err = dispatcher.DispatchDue()
+		assert.NoError(t, err)
+		assert.Zero(t, attempts, "the retry is not due yet")
+
+		*now = now.Add(firstRetryDelay)
+		attempts, err = dispatcher.DispatchDue()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, []string{"down", "down", "up"}, slack.received())
+
+		deliveries, err = outboxRepo.GetAttemptsByNotifierID(1, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, deliveries, 3) {
+			assert.True(t, deliveries[0].Succeeded())
+			assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+			assert.Equal(t, 2, deliveries[1].Attempt)
+		}
+	})
+
+	t.Run("client error is not retried", func(t *testing.T) {
+		slack := newSlackFake(t, http.StatusNotFound)
+		dispatcher, service, outboxRepo, _ := newTestDispatcher(t, slackNotifier(1, slack.URL))
+
+		assert.NoError(t, service.Enqueue(7, down))
+		assert.NoError(t, service.Enqueue(7, up))
+
+		attempts, err := dispatcher.DispatchDue()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+
+		deliveries, err := outboxRepo.GetAttemptsByNotifierID(1, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, deliveries, 2) {
+			assert.True(t, deliveries[0].Succeeded())
+			assert.Equal(t, http.StatusNotFound, deliveries[1].StatusCode)
+		}
+	})
+
+	t.Run("gives up after too many attempts", func(t *testing.T) {
+		statuses := make([]int, MaxDeliveryAttempts)
+		for i := range statuses {
+			statuses[i] = http.StatusServiceUnavailable
+		}
+		slack := newSlackFake(t, statuses...)
+		dispatcher, service, outboxRepo, now := newTestDispatcher(t, slackNotifier(1, slack.URL))
+
+		assert.NoError(t, service.Enqueue(7, down))
+		for range MaxDeliveryAttempts {
+			_, err := dispatcher.DispatchDue()
+			assert.NoError(t, err)
+			*now = now.Add(maxRetryDelay)
+		}
+
+		messages, err := outboxRepo.GetDue(now.Add(24*time.Hour), 10)
+		assert.NoError(t, err)
+		assert.Empty(t, messages)
+		assert.Len(t, slack.received(), MaxDeliveryAttempts)
+	})
+
+	t.Run("one notifier failing does not hold up another", func(t *testing.T) {
+		broken := newSlackFake(t, http.StatusInternalServerError)
+		working := newSlackFake(t)
+		dispatcher, service, _, _ := newTestDispatcher(t, slackNotifier(1, broken.URL), slackNotifier(2, working.URL))
+
+		assert.NoError(t, service.Enqueue(7, down))
+		assert.NoError(t, service.Enqueue(7, up))
+
+		_, err := dispatcher.DispatchDue()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"down"}, broken.received())
+		assert.Equal(t, []string{"down", "up"}, working.received())
+	})
+
+	t.Run("deleted notifier", func(t *testing.T) {
+		dispatcher, _, outboxRepo, now := newTestDispatcher(t)
+		_, err := outboxRepo.Enqueue(&model.OutboxMessage{NotifierID: 9, Kind: model.OutboxNotify, State: down, NextAttemptAt: *now, CreatedAt: *now})
+		assert.NoError(t, err)
+
+		attempts, err := dispatcher.DispatchDue()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, attempts)
+
+		deliveries, err := outboxRepo.GetAttemptsByNotifierID(9, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, deliveries, 1) {
+			assert.Equal(t, "notifier no longer exists", deliveries[0].Error)
+		}
+	})
+
+	t.Run("email retries only reach the recipients that failed", func(t *testing.T) {
+		var sent []string
+		failures := map[string]int{}
+		sender, err := provider.NewEmailSender(func() email.Mailer {
+			var to string
+			mailer := &mock.MailServiceMock{SetToFunc: func(address string) error {
+				to = address
+				return nil
+			}}
+			mailer.SendEmailFunc = func() error {
+				if failures[to] > 0 {
+					failures[to]--
+					return errors.New("450 mailbox unavailable")
+				}
+				sent = append(sent, to)
+				return nil
+			}
+			return mailer
+		}, templates.TemplateFS)
+		assert.NoError(t, err)
+
+		recipientRepo := &mockEmailRecipientRepository{}
+		recipientService := NewEmailRecipientService(recipientRepo, sender, "")
+		notifier := emailNotifier(1, "ops@example.com", "cto@example.com")
+		notifier.TargetId = 7
+		assert.NoError(t, recipientService.Sync(notifier))
+		for _, recipient := range recipientRepo.recipients {
+			_, err := recipientService.Verify(recipient.Token)
+			assert.NoError(t, err)
+		}
+
+		dispatcher, service, outboxRepo, now := newTestDispatcher(t, notifier)
+		service.recipientService = recipientService
+		sent = nil
+		failures["cto@example.com"] = 1
+
+		assert.NoError(t, service.Enqueue(7, down))
+		_, err = dispatcher.DispatchDue()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ops@example.com"}, sent)
+
+		*now = now.Add(firstRetryDelay)
+		_, err = dispatcher.DispatchDue()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ops@example.com", "cto@example.com"}, sent, "recipients already reached are not mailed again")
+
+		deliveries, err := outboxRepo.GetAttemptsByNotifierID(1, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, deliveries, 2) {
+			assert.True(t, deliveries[0].Succeeded())
+			assert.Contains(t, deliveries[1].Error, "cto@example.com")
+		}
+	})
+
+	t.Run("acknowledgement reaches paging notifier", func(t *testing.T) {
+		var actions []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var event struct {
+				EventAction string `json:"event_action"`
+			}
+			json.NewDecoder(r.Body).Decode(&event)
+			actions = append(actions, event.EventAction)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+		originalURL := PagerDutyEventsURL
+		PagerDutyEventsURL = server.URL
+		defer func() { PagerDutyEventsURL = originalURL }()
+
+		pager := &model.Notifier{ID: 1, TargetId: 7, Type: model.NotifierTypePagerDuty, Config: json.RawMessage(`{"routing_key": "0123456789abcdef0123456789abcdef"}`)}
+		dispatcher, service, _, _ := newTestDispatcher(t, pager)
+
+		assert.NoError(t, service.EnqueueAcknowledgement(7, notification.State{Name: "site", Message: "Incident #3 acknowledged", TargetID: 7}))
+		_, err := dispatcher.DispatchDue()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"acknowledge"}, actions)
+	})
+}
+
+func TestDispatcher_Start(t *testing.T) {
+	slack := newSlackFake(t)
+	dispatcher, service, _, _ := newTestDispatcher(t, slackNotifier(1, slack.URL))
+	dispatcher.now = time.Now
+
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "site", Status: "down", Message: "Target site is down", TargetID: 7}))
+	assert.Eventually(t, func() bool {
+		return len(slack.received()) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 7, want: 32 * time.Minute},
+		{attempts: 8, want: time.Hour},
+		{attempts: 50, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, retryDelay(tt.attempts), "attempts=%d", tt.attempts)
+	}
+}
+
+func TestIsPermanentStatus(t *testing.T) {
+	assert.False(t, isPermanentStatus(0), "no response")
+	assert.True(t, isPermanentStatus(http.StatusBadRequest))
+	assert.True(t, isPermanentStatus(http.StatusGone))
+	assert.False(t, isPermanentStatus(http.StatusRequestTimeout))
+	assert.False(t, isPermanentStatus(http.StatusTooManyRequests))
+	assert.False(t, isPermanentStatus(http.StatusBadGateway))
+}
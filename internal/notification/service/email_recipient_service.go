@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	notifCoer "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/provider"
+	"github.com/shuvo-paul/uptimebot/internal/notification/repository"
+)
+
+// ErrInvalidRecipientToken is returned for confirmation links that match no
+// recipient, such as those of addresses removed since
+var ErrInvalidRecipientToken = errors.New("invalid confirmation link")
+
+type EmailRecipientServiceInterface interface {
+	Sync(notifier *model.Notifier) error
+	GetByNotifierID(notifierID int64) ([]*model.EmailRecipient, error)
+	Verify(token string) (*model.EmailRecipient, error)
+	NewObserver(notifier *model.Notifier) (notifCoer.Observer, error)
+}
+
+var _ EmailRecipientServiceInterface = (*EmailRecipientService)(nil)
+
+// EmailRecipientService makes sure email notifiers only mail addresses
+// whose owners asked for it
+type EmailRecipientService struct {
+	repo    repository.EmailRecipientRepositoryInterface
+	sender  *provider.EmailSender
+	baseURL string
+	now     func() time.Time
+}
+
+func NewEmailRecipientService(
+	repo repository.EmailRecipientRepositoryInterface,
+	sender *provider.EmailSender,
+	baseURL string,
+) *EmailRecipientService {
+	return &EmailRecipientService{
+		repo:    repo,
+		sender:  sender,
+		baseURL: baseURL,
+		now:     time.Now,
+	}
+}
+
+// Sync matches the stored recipients to the notifier's configuration. New
+// addresses are sent a confirmation link; removed ones are forgotten, so
+// adding them back asks for confirmation again.
+func (s *EmailRecipientService) Sync(notifier *model.Notifier) error {
+	config, err := notifier.GetEmailConfig()
+	if err != nil || config == nil {
+		return err
+	}
+
+	existing, err := s.repo.GetByNotifierID(notifier.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range existing {
+		if !containsAddress(config.Recipients, recipient.Email) {
+			if err := s.repo.Delete(recipient.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, address := range config.Recipients {
+		if findRecipient(existing, address) != nil {
+			continue
+		}
+		if err := s.invite(notifier.ID, address); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// invite stores an unconfirmed recipient and mails them the confirmation
+// link. The recipient is dropped again if the mail cannot be sent, so the
+// next sync tries once more.
+func (s *EmailRecipientService) invite(notifierID int64, address string) error {
+	recipient, err := s.repo.Create(&model.EmailRecipient{
+		NotifierID: notifierID,
+		Email:      address,
+		Token:      uuid.New().String(),
+		CreatedAt:  s.now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		VerifyLink string
+	}{
+		VerifyLink: fmt.Sprintf("%s/notifiers/verify-email?token=%s", s.baseURL, url.QueryEscape(recipient.Token)),
+	}
+	if err := s.sender.Send(address, "Confirm UptimeBot alerts", provider.EmailTemplateVerifyRecipient, data); err != nil {
+		if deleteErr := s.repo.Delete(recipient.ID); deleteErr != nil {
+			return errors.Join(err, deleteErr)
+		}
+		return fmt.Errorf("failed to send confirmation to %s: %w", address, err)
+	}
+
+	return nil
+}
+
+// GetByNotifierID lists a notifier's recipients and whether each confirmed
+func (s *EmailRecipientService) GetByNotifierID(notifierID int64) ([]*model.EmailRecipient, error) {
+	return s.repo.GetByNotifierID(notifierID)
+}
+
+// Verify confirms the recipient the link with token was sent to. Following a
+// link twice is not an error.
+func (s *EmailRecipientService) Verify(token string) (*model.EmailRecipient, error) {
+	recipient, err := s.repo.GetByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if recipient == nil {
+		return nil, ErrInvalidRecipientToken
+	}
+	if recipient.IsVerified() {
+		return recipient, nil
+	}
+
+	recipient.VerifiedAt = s.now()
+	if err := s.repo.MarkVerified(recipient.ID, recipient.VerifiedAt); err != nil {
+		return nil, err
+	}
+	return recipient, nil
+}
+
+// NewObserver returns an observer mailing the notifier's confirmed
+// recipients, or nil if none have confirmed yet
+func (s *EmailRecipientService) NewObserver(notifier *model.Notifier) (notifCoer.Observer, error) {
+	config, err := notifier.GetEmailConfig()
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	recipients, err := s.repo.GetByNotifierID(notifier.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []string
+	for _, address := range config.Recipients {
+		if recipient := findRecipient(recipients, address); recipient != nil && recipient.IsVerified() {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	return provider.NewEmailObserver(addresses, s.sender, s.baseURL), nil
+}
+
+func findRecipient(recipients []*model.EmailRecipient, address string) *model.EmailRecipient {
+	for _, recipient := range recipients {
+		if strings.EqualFold(recipient.Email, address) {
+			return recipient
+		}
+	}
+	return nil
+}
+
+func containsAddress(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if strings.EqualFold(a, address) {
+			return true
+		}
+	}
+	return false
+}
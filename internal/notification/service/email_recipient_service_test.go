@@ -0,0 +1,199 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvo-paul/uptimebot/internal/email"
+	"github.com/shuvo-paul/uptimebot/internal/email/mock"
+	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/provider"
+	"github.com/shuvo-paul/uptimebot/internal/templates"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockEmailRecipientRepository keeps recipients in memory
+type mockEmailRecipientRepository struct {
+	recipients []*model.EmailRecipient
+	nextID     int64
+}
+
+func (m *mockEmailRecipientRepository) Create(recipient *model.EmailRecipient) (*model.EmailRecipient, error) {
+	m.nextID++
+	recipient.ID = m.nextID
+	m.recipients = append(m.recipients, recipient)
+	return recipient, nil
+}
+
+func (m *mockEmailRecipientRepository) GetByNotifierID(notifierID int64) ([]*model.EmailRecipient, error) {
+	var recipients []*model.EmailRecipient
+	for _, recipient := range m.recipients {
+		if recipient.NotifierID == notifierID {
+			copied := *recipient
+			recipients = append(recipients, &copied)
+		}
+	}
+	return recipients, nil
+}
+
+func (m *mockEmailRecipientRepository) GetByToken(token string) (*model.EmailRecipient, error) {
+	for _, recipient := range m.recipients {
+		if recipient.Token == token {
+			copied := *recipient
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockEmailRecipientRepository) MarkVerified(id int64, at time.Time) error {
+	for _, recipient := range m.recipients {
+		if recipient.ID == id {
+			recipient.VerifiedAt = at
+		}
+	}
+	return nil
+}
+
+func (m *mockEmailRecipientRepository) Delete(id int64) error {
+	for i, recipient := range m.recipients {
+		if recipient.ID == id {
+			m.recipients = append(m.recipients[:i], m.recipients[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// mailbox collects what an email sender sends
+type mailbox struct {
+	mailers []*mock.MailServiceMock
+	sendErr error
+}
+
+func (b *mailbox) sender(t *testing.T) *provider.EmailSender {
+	sender, err := provider.NewEmailSender(func() email.Mailer {
+		mailer := &mock.MailServiceMock{SendEmailFunc: func() error { return b.sendErr }}
+		b.mailers = append(b.mailers, mailer)
+		return mailer
+	}, templates.TemplateFS)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return sender
+}
+
+func (b *mailbox) recipients() []string {
+	var to []string
+	for _, mailer := range b.mailers {
+		to = append(to, mailer.GetSetToCalls()...)
+	}
+	return to
+}
+
+func emailNotifier(id int64, recipients ...string) *model.Notifier {
+	config, _ := json.Marshal(model.EmailConfig{Recipients: recipients})
+	return &model.Notifier{ID: id, TargetId: 1, Type: model.NotifierTypeEmail, Config: config}
+}
+
+func TestEmailRecipientService_Sync(t *testing.T) {
+	repo := &mockEmailRecipientRepository{}
+	box := &mailbox{}
+	service := NewEmailRecipientService(repo, box.sender(t), "https://uptime.example.com")
+
+	t.Run("new addresses are asked to confirm", func(t *testing.T) {
+		assert.NoError(t, service.Sync(emailNotifier(1, "ops@example.com", "cto@example.com")))
+
+		assert.Equal(t, []string{"ops@example.com", "cto@example.com"}, box.recipients())
+		assert.Equal(t, []string{"Confirm UptimeBot alerts"}, box.mailers[0].GetSetSubjectCalls())
+		assert.Contains(t, box.mailers[0].GetSetTextBodyCalls()[0], "https://uptime.example.com/notifiers/verify-email?token="+repo.recipients[0].Token)
+		assert.Len(t, repo.recipients, 2)
+	})
+
+	t.Run("known addresses are not asked again", func(t *testing.T) {
+		box.mailers = nil
+		assert.NoError(t, service.Sync(emailNotifier(1, "OPS@example.com", "dev@example.com")))
+
+		assert.Equal(t, []string{"dev@example.com"}, box.recipients())
+		recipients, _ := repo.GetByNotifierID(1)
+		var addresses []string
+		for _, recipient := range recipients {
+			addresses = append(addresses, recipient.Email)
+		}
+		assert.Equal(t, []string{"ops@example.com", "dev@example.com"}, addresses, "removed addresses are forgotten")
+	})
+
+	t.Run("a failed confirmation is tried again", func(t *testing.T) {
+		box.mailers = nil
+		box.sendErr = errors.New("connection refused")
+		err := service.Sync(emailNotifier(2, "ops@example.com"))
+		assert.Error(t, err)
+		recipients, _ := repo.GetByNotifierID(2)
+		assert.Empty(t, recipients)
+
+		box.sendErr = nil
+		assert.NoError(t, service.Sync(emailNotifier(2, "ops@example.com")))
+		recipients, _ = repo.GetByNotifierID(2)
+		assert.Len(t, recipients, 1)
+	})
+
+	t.Run("other notifier types are ignored", func(t *testing.T) {
+		box.mailers = nil
+		assert.NoError(t, service.Sync(&model.Notifier{ID: 3, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/x"}`)}))
+		assert.Empty(t, box.mailers)
+	})
+}
+
+func TestEmailRecipientService_Verify(t *testing.T) {
+	now := time.Date(2025, 6, 8, 12, 0, 0, 0, time.UTC)
+	repo := &mockEmailRecipientRepository{}
+	repo.Create(&model.EmailRecipient{NotifierID: 1, Email: "ops@example.com", Token: "token-1"})
+
+	service := NewEmailRecipientService(repo, nil, "")
+	service.now = func() time.Time { return now }
+
+	recipient, err := service.Verify("token-1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ops@example.com", recipient.Email)
+		assert.True(t, recipient.VerifiedAt.Equal(now))
+	}
+
+	service.now = func() time.Time { return now.Add(time.Hour) }
+	recipient, err = service.Verify("token-1")
+	if assert.NoError(t, err, "links can be followed twice") {
+		assert.True(t, recipient.VerifiedAt.Equal(now))
+	}
+
+	_, err = service.Verify("unknown")
+	assert.ErrorIs(t, err, ErrInvalidRecipientToken)
+}
+
+func TestEmailRecipientService_NewObserver(t *testing.T) {
+	repo := &mockEmailRecipientRepository{}
+	box := &mailbox{}
+	service := NewEmailRecipientService(repo, box.sender(t), "https://uptime.example.com")
+
+	notifier := emailNotifier(1, "ops@example.com", "cto@example.com")
+	assert.NoError(t, service.Sync(notifier))
+
+	observer, err := service.NewObserver(notifier)
+	assert.NoError(t, err)
+	assert.Nil(t, observer, "nobody has confirmed yet")
+
+	_, err = service.Verify(repo.recipients[1].Token)
+	assert.NoError(t, err)
+
+	observer, err = service.NewObserver(notifier)
+	if !assert.NoError(t, err) || !assert.NotNil(t, observer) {
+		return
+	}
+
+	box.mailers = nil
+	assert.NoError(t, observer.Notify(notification.State{Name: "https://example.com", Status: "down", TargetID: 1}))
+	assert.Equal(t, []string{"cto@example.com"}, box.recipients(), "only confirmed addresses are mailed")
+	assert.True(t, strings.HasSuffix(box.mailers[0].GetSetSubjectCalls()[0], "is down"))
+}
@@ -2,11 +2,13 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
+	"time"
 
 	notifCoer "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	"github.com/shuvo-paul/uptimebot/internal/notification/model"
@@ -19,40 +21,85 @@ type NotifierServiceInterface interface {
 	Get(id int64) (*model.Notifier, error)
 	Update(id int, config json.RawMessage) (*model.Notifier, error)
 	Delete(id int64) error
-	ConfigureObservers(targetID int) error
+	GetByTargetID(targetID int) ([]*model.Notifier, error)
+	UpdateFilter(id int64, filter model.NotifierFilter) error
+	Enqueue(targetID int, state notifCoer.State) error
+	EnqueueAcknowledgement(targetID int, state notifCoer.State) error
+	Escalate(notifierIDs []int64, state notifCoer.State) error
+	EscalateAcknowledgement(notifierIDs []int64, state notifCoer.State) error
+	GetDeliveries(notifierID int64, limit int) ([]*model.DeliveryAttempt, error)
+	SendTest(notifier *model.Notifier) error
 	HandleSlackCallback(code string, targetID int) (*model.Notifier, error)
 	ParseOAuthState(state string) (int, error)
-	GetSubject() *notifCoer.Subject
 }
 
 type NotifierService struct {
-	notifierRepo repository.NotifierRepositoryInterface
-	subject      *notifCoer.Subject
+	notifierRepo     repository.NotifierRepositoryInterface
+	outboxRepo       repository.OutboxRepositoryInterface
+	recipientService EmailRecipientServiceInterface
+	routes           *routeCache
+	baseURL          string
+	// client sends test notifications
+	client provider.HTTPClient
+	// queued wakes the dispatcher when messages are added to the outbox
+	queued chan struct{}
 }
 
+// ErrNoConfirmedRecipients is returned when testing an email notifier none of
+// whose recipients has confirmed their address
+var ErrNoConfirmedRecipients = errors.New("no recipient has confirmed their email address yet")
+
 var (
 	// SlackTokenURL is the Slack OAuth token URL, can be overridden in tests
 	SlackTokenURL = "https://slack.com/api/oauth.v2.access"
+	// TelegramAPIURL is the Telegram Bot API address, can be overridden to
+	// use a stand-in server
+	TelegramAPIURL = provider.DefaultTelegramAPIURL
+	// PagerDutyEventsURL is the PagerDuty Events API v2 address, can be
+	// overridden to use a stand-in server
+	PagerDutyEventsURL = provider.DefaultPagerDutyEventsURL
+	// OpsgenieAPIURL is the Opsgenie API address, can be overridden for
+	// EU accounts or to use a stand-in server
+	OpsgenieAPIURL = provider.DefaultOpsgenieAPIURL
 )
 
 func NewNotifierService(
 	notifierRepo repository.NotifierRepositoryInterface,
-	subject *notifCoer.Subject,
+	outboxRepo repository.OutboxRepositoryInterface,
+	recipientService EmailRecipientServiceInterface,
+	baseURL string,
 ) *NotifierService {
-	if subject == nil {
-		subject = notifCoer.NewSubject()
-	}
 	return &NotifierService{
-		notifierRepo: notifierRepo,
-		subject:      subject,
+		notifierRepo:     notifierRepo,
+		outboxRepo:       outboxRepo,
+		recipientService: recipientService,
+		routes:           newRouteCache(),
+		baseURL:          baseURL,
+		client:           &http.Client{Timeout: deliveryTimeout},
+		queued:           make(chan struct{}, 1),
 	}
 }
 
-// Create adds a new notifier
+// Create adds a new notifier and sets its ID. Webhooks created without a
+// signing secret are given one, and email recipients are asked to confirm
+// their address.
 func (s *NotifierService) Create(notifier *model.Notifier) error {
-	if _, err := s.notifierRepo.Create(notifier); err != nil {
+	if err := notifier.EnsureWebhookSecret(); err != nil {
+		return fmt.Errorf("failed to create notifier: %w", err)
+	}
+
+	created, err := s.notifierRepo.Create(notifier)
+	if err != nil {
 		return fmt.Errorf("failed to create notifier: %w", err)
 	}
+	notifier.ID = created.ID
+	s.routes.invalidateTarget(notifier.TargetId)
+
+	if notifier.Type == model.NotifierTypeEmail {
+		if err := s.recipientService.Sync(notifier); err != nil {
+			return fmt.Errorf("failed to add email recipients: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -65,12 +112,20 @@ func (s *NotifierService) Get(id int64) (*model.Notifier, error) {
 	return notifier, nil
 }
 
-// Update modifies an existing notifier's configuration
+// Update modifies an existing notifier's configuration. New email
+// recipients are asked to confirm their address.
 func (s *NotifierService) Update(id int, config json.RawMessage) (*model.Notifier, error) {
 	notifier, err := s.notifierRepo.Update(id, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update notifier: %w", err)
 	}
+	s.routes.invalidateTarget(notifier.TargetId)
+
+	if notifier.Type == model.NotifierTypeEmail {
+		if err := s.recipientService.Sync(notifier); err != nil {
+			return nil, fmt.Errorf("failed to update email recipients: %w", err)
+		}
+	}
 	return notifier, nil
 }
 
@@ -79,35 +134,268 @@ func (s *NotifierService) Delete(id int64) error {
 	if err := s.notifierRepo.Delete(id); err != nil {
 		return fmt.Errorf("failed to delete notifier: %w", err)
 	}
+	s.routes.invalidateNotifier(id)
 	return nil
 }
 
-// ConfigureObservers configures observers for a specific target
-func (s *NotifierService) ConfigureObservers(targetID int) error {
-	// First detach any existing observers
-	// This ensures we don't have duplicate observers if called multiple times
-	s.subject = notifCoer.NewSubject()
-
+// GetByTargetID lists the notifiers attached to a target
+func (s *NotifierService) GetByTargetID(targetID int) ([]*model.Notifier, error) {
 	notifiers, err := s.notifierRepo.GetByTargetID(targetID)
 	if err != nil {
-		return fmt.Errorf("failed to get notifiers: %w", err)
+		return nil, fmt.Errorf("failed to get notifiers: %w", err)
+	}
+	return notifiers, nil
+}
+
+// UpdateFilter changes which status changes a notifier is sent
+func (s *NotifierService) UpdateFilter(id int64, filter model.NotifierFilter) error {
+	if err := s.notifierRepo.UpdateFilter(id, filter); err != nil {
+		return fmt.Errorf("failed to update notifier filter: %w", err)
 	}
+	s.routes.invalidateNotifier(id)
+	return nil
+}
+
+// notifiersFor returns the notifiers attached to a target, from the cache
+// when they have not changed since they were last loaded
+func (s *NotifierService) notifiersFor(targetID int) ([]*model.Notifier, error) {
+	notifiers, err := s.routes.load(targetID, s.notifierRepo.GetByTargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifiers: %w", err)
+	}
+	return notifiers, nil
+}
 
+// notifier returns the notifier with that ID, looking among the target's
+// first, or nil if there is none. Escalation policies reach notifiers of
+// other targets too.
+func (s *NotifierService) notifier(targetID int, id int64) (*model.Notifier, error) {
+	notifiers, err := s.notifiersFor(targetID)
+	if err != nil {
+		return nil, err
+	}
 	for _, notifier := range notifiers {
-		switch notifier.Type {
-		case model.NotifierTypeSlack:
-			config, err := notifier.GetSlackConfig()
+		if notifier.ID == id {
+			return notifier, nil
+		}
+	}
+
+	notifier, err := s.notifierRepo.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifier: %w", err)
+	}
+	return notifier, nil
+}
+
+// Enqueue adds a message to the outbox for each of the target's notifiers
+// that wants to hear about state, held back for as long as its filter asks.
+// The dispatcher delivers them once due.
+func (s *NotifierService) Enqueue(targetID int, state notifCoer.State) error {
+	return s.enqueue(targetID, model.OutboxNotify, state, func(notifier *model.Notifier, now time.Time) (time.Time, bool, error) {
+		// An outage ending before it lasted the minimum is never announced.
+		// Messages only held for quiet hours are still sent once they end.
+		if notifier.Filter.MinOutageMinutes > 0 && endsOutage(state.Status) {
+			since := now.Add(-notifier.Filter.MinOutage())
+			cancelled, err := s.outboxRepo.CancelHeld(notifier.ID, model.OutageStatuses, since, now)
 			if err != nil {
-				return fmt.Errorf("failed to get slack config: %w", err)
+				return time.Time{}, false, fmt.Errorf("failed to cancel held alerts for notifier %d: %w", notifier.ID, err)
+			}
+			if cancelled > 0 && state.Status == "up" {
+				// The outage was never announced, so neither is its end
+				return time.Time{}, false, nil
 			}
-			observer := provider.NewSlackObserver(config.WebhookURL, http.DefaultClient)
-			s.subject.Attach(observer)
-		default:
-			return fmt.Errorf("unsupported notifier type: %s", notifier.Type)
+		}
+		if !notifier.Accepts(state) {
+			return time.Time{}, false, nil
+		}
+		return notifier.Filter.DeliverAt(state, now), true, nil
+	})
+}
+
+// endsOutage reports whether a target taking status is no longer failing
+func endsOutage(status string) bool {
+	return status == "up" || status == "degraded" || status == "paused"
+}
+
+// EnqueueAcknowledgement adds a message to the outbox for each of the
+// target's notifiers that tracks incidents, telling it someone is handling
+// the target's incident
+func (s *NotifierService) EnqueueAcknowledgement(targetID int, state notifCoer.State) error {
+	return s.enqueue(targetID, model.OutboxAcknowledge, state, func(notifier *model.Notifier, now time.Time) (time.Time, bool, error) {
+		return now, notifier.Type.IsPaging(), nil
+	})
+}
+
+// Escalate adds a message to the outbox for each of the given notifiers,
+// whatever their filters, for escalation policies paging people in turn.
+// Notifiers that no longer exist are skipped.
+func (s *NotifierService) Escalate(notifierIDs []int64, state notifCoer.State) error {
+	return s.enqueueTo(notifierIDs, model.OutboxNotify, state, func(notifier *model.Notifier) bool {
+		return true
+	})
+}
+
+// EscalateAcknowledgement tells those of the given notifiers that track
+// incidents that someone is handling the incident an escalation paged them
+// about
+func (s *NotifierService) EscalateAcknowledgement(notifierIDs []int64, state notifCoer.State) error {
+	return s.enqueueTo(notifierIDs, model.OutboxAcknowledge, state, func(notifier *model.Notifier) bool {
+		return notifier.Type.IsPaging()
+	})
+}
+
+func (s *NotifierService) enqueueTo(notifierIDs []int64, kind string, state notifCoer.State, wants func(*model.Notifier) bool) error {
+	var notifiers []*model.Notifier
+	for _, id := range notifierIDs {
+		notifier, err := s.notifierRepo.Get(id)
+		if err != nil {
+			return fmt.Errorf("failed to get notifier: %w", err)
+		}
+		if notifier != nil {
+			notifiers = append(notifiers, notifier)
 		}
 	}
 
-	return nil
+	return s.queue(notifiers, kind, state, func(notifier *model.Notifier, now time.Time) (time.Time, bool, error) {
+		return now, wants(notifier), nil
+	})
+}
+
+// enqueue adds a message to the outbox for each of the target's notifiers
+// schedule accepts, due when schedule says
+func (s *NotifierService) enqueue(targetID int, kind string, state notifCoer.State, schedule func(notifier *model.Notifier, now time.Time) (time.Time, bool, error)) error {
+	notifiers, err := s.notifiersFor(targetID)
+	if err != nil {
+		return err
+	}
+	return s.queue(notifiers, kind, state, schedule)
+}
+
+// queue adds a message to the outbox for each of notifiers schedule
+// accepts, and wakes the dispatcher
+func (s *NotifierService) queue(notifiers []*model.Notifier, kind string, state notifCoer.State, schedule func(notifier *model.Notifier, now time.Time) (time.Time, bool, error)) error {
+	now := time.Now()
+	var errs []error
+	for _, notifier := range notifiers {
+		at, ok, err := schedule(notifier, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		_, err = s.outboxRepo.Enqueue(&model.OutboxMessage{
+			NotifierID:    notifier.ID,
+			Kind:          kind,
+			State:         state,
+			NextAttemptAt: at,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to queue notification for notifier %d: %w", notifier.ID, err))
+		}
+	}
+
+	select {
+	case s.queued <- struct{}{}:
+	default:
+		// The dispatcher has already been woken
+	}
+	return errors.Join(errs...)
+}
+
+// GetDeliveries lists the latest attempts at delivering a notifier's
+// messages, newest first
+func (s *NotifierService) GetDeliveries(notifierID int64, limit int) ([]*model.DeliveryAttempt, error) {
+	attempts, err := s.outboxRepo.GetAttemptsByNotifierID(notifierID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+	return attempts, nil
+}
+
+// newObserver returns the observer delivering a notifier's messages through
+// client, or nil if it has nobody to deliver them to yet
+func (s *NotifierService) newObserver(notifier *model.Notifier, client provider.HTTPClient) (notifCoer.Observer, error) {
+	switch notifier.Type {
+	case model.NotifierTypeSlack:
+		config, err := notifier.GetSlackConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get slack config: %w", err)
+		}
+		return provider.NewSlackObserver(config.WebhookURL, client), nil
+	case model.NotifierTypeDiscord:
+		config, err := notifier.GetDiscordConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get discord config: %w", err)
+		}
+		return provider.NewDiscordObserver(config.WebhookURL, s.baseURL, client), nil
+	case model.NotifierTypeMSTeams:
+		config, err := notifier.GetMSTeamsConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get teams config: %w", err)
+		}
+		return provider.NewMSTeamsObserver(config.WebhookURL, s.baseURL, client), nil
+	case model.NotifierTypeTelegram:
+		config, err := notifier.GetTelegramConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get telegram config: %w", err)
+		}
+		return provider.NewTelegramObserver(TelegramAPIURL, config.BotToken, config.ChatID, config.SilentRecoveries, s.baseURL, client), nil
+	case model.NotifierTypePagerDuty:
+		config, err := notifier.GetPagerDutyConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pagerduty config: %w", err)
+		}
+		return provider.NewPagerDutyObserver(PagerDutyEventsURL, config.RoutingKey, s.baseURL, client), nil
+	case model.NotifierTypeOpsgenie:
+		config, err := notifier.GetOpsgenieConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get opsgenie config: %w", err)
+		}
+		return provider.NewOpsgenieObserver(OpsgenieAPIURL, config.APIKey, s.baseURL, client), nil
+	case model.NotifierTypeEmail:
+		observer, err := s.recipientService.NewObserver(notifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get email recipients: %w", err)
+		}
+		if observer == nil {
+			// Nobody has confirmed their address yet
+			return nil, nil
+		}
+		return observer, nil
+	case model.NotifierTypeWebhook:
+		config, err := notifier.GetWebhookConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get webhook config: %w", err)
+		}
+		timeout := time.Duration(config.TimeoutSeconds) * time.Second
+		return provider.NewWebhookObserver(config.URL, config.Secret, config.Headers, timeout, s.baseURL, client), nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier type: %s", notifier.Type)
+	}
+}
+
+// SendTest sends a sample message through one notifier, ignoring its
+// filter, so users can check it is set up right
+func (s *NotifierService) SendTest(notifier *model.Notifier) error {
+	observer, err := s.newObserver(notifier, s.client)
+	if err != nil {
+		return err
+	}
+	if observer == nil {
+		return ErrNoConfirmedRecipients
+	}
+
+	return observer.Notify(notifCoer.State{
+		Name:      "Test notification",
+		Status:    "up",
+		Message:   "This is a test notification from UptimeBot. If you can read it, the notifier works.",
+		UpdatedAt: time.Now(),
+		TargetID:  notifier.TargetId,
+		Test:      true,
+	})
 }
 
 func (s *NotifierService) HandleSlackCallback(code string, targetID int) (*model.Notifier, error) {
@@ -174,7 +462,3 @@ func (s *NotifierService) ParseOAuthState(state string) (int, error) {
 
 	return targetIdInt, nil
 }
-
-func (s *NotifierService) GetSubject() *notifCoer.Subject {
-	return s.subject
-}
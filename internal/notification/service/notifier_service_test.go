@@ -3,8 +3,11 @@ package service
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,6 +15,9 @@ import (
 
 	notification "github.com/shuvo-paul/uptimebot/internal/notification/core"
 	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/shuvo-paul/uptimebot/internal/notification/provider"
+	"github.com/shuvo-paul/uptimebot/internal/notification/repository"
+	"github.com/shuvo-paul/uptimebot/internal/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,6 +28,7 @@ type mockNotifierRepository struct {
 	getFunc           func(id int64) (*model.Notifier, error)
 	updateFunc        func(id int, config json.RawMessage) (*model.Notifier, error)
 	deleteFunc        func(id int64) error
+	updateFilterFunc  func(id int64, filter model.NotifierFilter) error
 }
 
 func (m *mockNotifierRepository) GetByTargetID(targetID int) ([]*model.Notifier, error) {
@@ -44,6 +51,10 @@ func (m *mockNotifierRepository) Delete(id int64) error {
 	return m.deleteFunc(id)
 }
 
+func (m *mockNotifierRepository) UpdateFilter(id int64, filter model.NotifierFilter) error {
+	return m.updateFilterFunc(id, filter)
+}
+
 // mockObserver is a mock implementation of the Observer interface
 type mockObserver struct {
 	state notification.State
@@ -64,7 +75,7 @@ func (m *mockObserver) Notify(state notification.State) error {
 
 func TestNotifierService_Create(t *testing.T) {
 	mockRepo := &mockNotifierRepository{}
-	service := NewNotifierService(mockRepo, nil)
+	service := NewNotifierService(mockRepo, nil, nil, "")
 
 	t.Run("successful creation", func(t *testing.T) {
 		mockRepo.createFunc = func(notifier *model.Notifier) (*model.Notifier, error) {
@@ -84,6 +95,43 @@ func TestNotifierService_Create(t *testing.T) {
 
 		err := service.Create(notifier)
 		assert.NoError(t, err)
+		assert.Equal(t, int64(1), notifier.ID)
+	})
+
+	t.Run("webhook is given a secret", func(t *testing.T) {
+		var stored *model.Notifier
+		mockRepo.createFunc = func(notifier *model.Notifier) (*model.Notifier, error) {
+			stored = notifier
+			return &model.Notifier{ID: 2}, nil
+		}
+
+		notifier := &model.Notifier{
+			TargetId: 1,
+			Type:     model.NotifierTypeWebhook,
+			Config:   json.RawMessage(`{"url": "https://tools.example.com/hooks"}`),
+		}
+
+		err := service.Create(notifier)
+		assert.NoError(t, err)
+		config, err := stored.GetWebhookConfig()
+		assert.NoError(t, err)
+		assert.NotEmpty(t, config.Secret)
+	})
+
+	t.Run("email recipients are asked to confirm", func(t *testing.T) {
+		box := &mailbox{}
+		recipientRepo := &mockEmailRecipientRepository{}
+		service := NewNotifierService(mockRepo, nil, NewEmailRecipientService(recipientRepo, box.sender(t), ""), "")
+		mockRepo.createFunc = func(notifier *model.Notifier) (*model.Notifier, error) {
+			return &model.Notifier{ID: 3}, nil
+		}
+
+		err := service.Create(emailNotifier(0, "ops@example.com"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ops@example.com"}, box.recipients())
+		if assert.Len(t, recipientRepo.recipients, 1) {
+			assert.Equal(t, int64(3), recipientRepo.recipients[0].NotifierID)
+		}
 	})
 
 	t.Run("creation fails", func(t *testing.T) {
@@ -99,7 +147,7 @@ func TestNotifierService_Create(t *testing.T) {
 
 func TestNotifierService_Get(t *testing.T) {
 	mockRepo := &mockNotifierRepository{}
-	service := NewNotifierService(mockRepo, nil)
+	service := NewNotifierService(mockRepo, nil, nil, "")
 
 	t.Run("successful retrieval", func(t *testing.T) {
 		expected := &model.Notifier{
@@ -132,7 +180,7 @@ func TestNotifierService_Get(t *testing.T) {
 
 func TestNotifierService_Update(t *testing.T) {
 	mockRepo := &mockNotifierRepository{}
-	service := NewNotifierService(mockRepo, nil)
+	service := NewNotifierService(mockRepo, nil, nil, "")
 
 	t.Run("successful update", func(t *testing.T) {
 		config := json.RawMessage(`{"webhook_url": "https://hooks.slack.com/new"}`)
@@ -167,7 +215,7 @@ func TestNotifierService_Update(t *testing.T) {
 
 func TestNotifierService_Delete(t *testing.T) {
 	mockRepo := &mockNotifierRepository{}
-	service := NewNotifierService(mockRepo, nil)
+	service := NewNotifierService(mockRepo, nil, nil, "")
 
 	t.Run("successful deletion", func(t *testing.T) {
 		mockRepo.deleteFunc = func(id int64) error {
@@ -189,67 +237,481 @@ func TestNotifierService_Delete(t *testing.T) {
 	})
 }
 
-func TestNotifierService_ConfigureObservers(t *testing.T) {
-	mockRepo := &mockNotifierRepository{}
-	subject := notification.NewSubject()
-	service := NewNotifierService(mockRepo, subject)
+func TestNotifierService_NewObserver(t *testing.T) {
+	t.Run("slack", func(t *testing.T) {
+		service := NewNotifierService(&mockNotifierRepository{}, nil, nil, "")
+		observer, err := service.newObserver(&model.Notifier{
+			ID:       1,
+			TargetId: 1,
+			Type:     model.NotifierTypeSlack,
+			Config:   json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
+		}, http.DefaultClient)
+		assert.NoError(t, err)
+		assert.NotNil(t, observer)
+	})
 
-	t.Run("successful configuration with slack observer", func(t *testing.T) {
-		mockRepo.getByTargetIDFunc = func(targetID int) ([]*model.Notifier, error) {
+	t.Run("webhook observer signs its payload", func(t *testing.T) {
+		var payload provider.WebhookPayload
+		var signed bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			signed = r.Header.Get(provider.WebhookSignatureHeader) == provider.SignWebhook("s3cret", r.Header.Get(provider.WebhookTimestampHeader), body)
+			json.Unmarshal(body, &payload)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		service := NewNotifierService(&mockNotifierRepository{}, nil, nil, "https://uptime.example.com")
+		observer, err := service.newObserver(&model.Notifier{
+			ID:       2,
+			TargetId: 1,
+			Type:     model.NotifierTypeWebhook,
+			Config:   json.RawMessage(`{"url": "` + server.URL + `", "secret": "s3cret", "timeout_seconds": 5}`),
+		}, http.DefaultClient)
+		assert.NoError(t, err)
+
+		assert.NoError(t, observer.Notify(notification.State{TargetID: 1, Name: "site", Status: "down", IncidentID: 4}))
+		assert.True(t, signed)
+		assert.Equal(t, "down", payload.Status)
+		assert.Equal(t, "https://uptime.example.com/incidents/4", payload.IncidentURL)
+	})
+
+	t.Run("email observer mails confirmed recipients", func(t *testing.T) {
+		box := &mailbox{}
+		recipientRepo := &mockEmailRecipientRepository{}
+		recipientService := NewEmailRecipientService(recipientRepo, box.sender(t), "")
+		service := NewNotifierService(&mockNotifierRepository{}, nil, recipientService, "")
+
+		notifier := emailNotifier(5, "ops@example.com")
+		assert.NoError(t, recipientService.Sync(notifier))
+		box.mailers = nil
+
+		observer, err := service.newObserver(notifier, http.DefaultClient)
+		assert.NoError(t, err)
+		assert.Nil(t, observer, "unconfirmed addresses are not mailed")
+
+		recipientService.Verify(recipientRepo.recipients[0].Token)
+		observer, err = service.newObserver(notifier, http.DefaultClient)
+		assert.NoError(t, err)
+		assert.NoError(t, observer.Notify(notification.State{Name: "site", Status: "down"}))
+		assert.Equal(t, []string{"ops@example.com"}, box.recipients())
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		service := NewNotifierService(&mockNotifierRepository{}, nil, nil, "")
+		_, err := service.newObserver(&model.Notifier{Type: "pager"}, http.DefaultClient)
+		assert.Error(t, err)
+	})
+}
+
+func TestNotifierService_PagingNotifiers(t *testing.T) {
+	var actions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event struct {
+			EventAction string `json:"event_action"`
+			DedupKey    string `json:"dedup_key"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		actions = append(actions, event.EventAction+" "+event.DedupKey)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	originalURL := PagerDutyEventsURL
+	PagerDutyEventsURL = server.URL
+	defer func() { PagerDutyEventsURL = originalURL }()
+
+	dispatcher, service, _, now := newTestDispatcher(t, &model.Notifier{
+		ID:       1,
+		TargetId: 7,
+		Type:     model.NotifierTypePagerDuty,
+		Config:   json.RawMessage(`{"routing_key": "R0123456789ABCDEF0123456789abcde"}`),
+		Filter:   model.NotifierFilter{Statuses: []string{"down"}},
+	})
+	dispatch := func() {
+		t.Helper()
+		*now = time.Now().Add(time.Second)
+		_, err := dispatcher.DispatchDue()
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "api", Status: "down", TargetID: 7}))
+	dispatch()
+	assert.NoError(t, service.EnqueueAcknowledgement(7, notification.State{Name: "api", TargetID: 7, IncidentID: 3}))
+	dispatch()
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "api", Status: "up", TargetID: 7}))
+	dispatch()
+
+	assert.Equal(t, []string{
+		"trigger uptimebot-target-7",
+		"acknowledge uptimebot-target-7",
+		"resolve uptimebot-target-7",
+	}, actions, "the recovery resolves the incident although the filter leaves it out")
+}
+
+func TestNotifierService_Enqueue(t *testing.T) {
+	db := testutil.NewInMemoryDB()
+	defer db.Close()
+
+	outboxRepo := repository.NewOutboxRepository(db)
+	mockRepo := &mockNotifierRepository{
+		getByTargetIDFunc: func(targetID int) ([]*model.Notifier, error) {
 			return []*model.Notifier{
-				{
-					ID:       1,
-					TargetId: 1,
-					Type:     model.NotifierTypeSlack,
-					Config:   json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`),
-				},
+				{ID: 1, TargetId: targetID, Type: model.NotifierTypeSlack, Filter: model.NotifierFilter{Statuses: []string{"down"}}},
+				{ID: 2, TargetId: targetID, Type: model.NotifierTypePagerDuty, Filter: model.NotifierFilter{Statuses: []string{"down"}}},
 			}, nil
-		}
+		},
+	}
+	service := NewNotifierService(mockRepo, outboxRepo, nil, "")
 
-		err := service.ConfigureObservers(1)
+	queued := func() map[int64]string {
+		messages, err := outboxRepo.GetDue(time.Now().Add(time.Second), 10)
 		assert.NoError(t, err)
-	})
+		kinds := map[int64]string{}
+		for _, message := range messages {
+			kinds[message.NotifierID] = message.Kind + " " + message.State.Status
+		}
+		return kinds
+	}
 
-	t.Run("repository error", func(t *testing.T) {
-		mockRepo.getByTargetIDFunc = func(targetID int) ([]*model.Notifier, error) {
-			return nil, fmt.Errorf("db error")
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "site", Status: "degraded", TargetID: 7}))
+	assert.Equal(t, map[int64]string{2: "notify degraded"}, queued(), "only paging notifiers hear about recoveries they did not ask for")
+	assert.Len(t, service.queued, 1, "the dispatcher is woken")
+
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "site", Status: "down", TargetID: 7}))
+	assert.Equal(t, map[int64]string{1: "notify down", 2: "notify degraded"}, queued())
+
+	assert.NoError(t, service.EnqueueAcknowledgement(7, notification.State{Name: "site", Message: "Incident #3 acknowledged", TargetID: 7}))
+	var acknowledged []int64
+	rows, err := db.Query(`SELECT notifier_id FROM notification_outbox WHERE kind = ?`, model.OutboxAcknowledge)
+	if assert.NoError(t, err) {
+		for rows.Next() {
+			var id int64
+			assert.NoError(t, rows.Scan(&id))
+			acknowledged = append(acknowledged, id)
 		}
+		rows.Close()
+	}
+	assert.Equal(t, []int64{2}, acknowledged, "only paging notifiers track incidents")
 
-		err := service.ConfigureObservers(1)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to get notifiers")
-	})
+	mockRepo.getByTargetIDFunc = func(targetID int) ([]*model.Notifier, error) {
+		return nil, fmt.Errorf("database error")
+	}
+	assert.Error(t, service.Enqueue(8, notification.State{Status: "down"}))
 }
 
-func TestNotifierService_Subject(t *testing.T) {
-	mockRepo := &mockNotifierRepository{}
-	subject := notification.NewSubject()
-	service := NewNotifierService(mockRepo, subject)
-
-	// Create and attach mock observers
-	observer1 := newMockObserver(nil)
-	observer2 := newMockObserver(fmt.Errorf("notification error"))
-	service.subject.Attach(observer1)
-	service.subject.Attach(observer2)
-
-	// Test notification using Subject directly
-	state := notification.State{
-		Name:      "test-system",
-		Status:    "up",
-		Message:   "System is up",
-		UpdatedAt: time.Now(),
+// TestNotifierService_HeldAlerts follows a channel that only wants outages
+// longer than five minutes next to one that wants every change
+func TestNotifierService_HeldAlerts(t *testing.T) {
+	patient := newSlackFake(t)
+	eager := newSlackFake(t)
+	management := slackNotifier(1, patient.URL)
+	management.Filter = model.NotifierFilter{MinOutageMinutes: 5}
+	dispatcher, service, _, now := newTestDispatcher(t, management, slackNotifier(2, eager.URL))
+
+	dispatch := func(after time.Duration) {
+		*now = time.Now().Add(after)
+		_, err := dispatcher.DispatchDue()
+		assert.NoError(t, err)
 	}
-	errors := service.subject.Notify(state)
 
-	// Verify results
-	assert.Len(t, errors, 1) // One observer should fail
-	assert.Equal(t, state, observer1.state)
-	assert.Empty(t, observer2.state) // Failed observer shouldn't have state
+	// A blip: down, then back up two minutes later
+	start := time.Now()
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "site", Status: "down", TargetID: 7, UpdatedAt: start, OutageStart: start}))
+	dispatch(time.Second)
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "site", Status: "up", PreviousStatus: "down", TargetID: 7, UpdatedAt: start}))
+	dispatch(10 * time.Minute)
+	assert.Empty(t, patient.received(), "a short outage and its recovery are never announced")
+	assert.Equal(t, []string{"down", "up"}, eager.received())
+
+	// A real outage that started earlier, so is due in a minute
+	start = time.Now().Add(-4 * time.Minute)
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "site", Status: "down", TargetID: 7, UpdatedAt: time.Now(), OutageStart: start}))
+	dispatch(30 * time.Second)
+	assert.Empty(t, patient.received())
+	dispatch(2 * time.Minute)
+	assert.Equal(t, []string{"down"}, patient.received())
+
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "site", Status: "up", PreviousStatus: "down", TargetID: 7, UpdatedAt: time.Now()}))
+	dispatch(3 * time.Minute)
+	assert.Equal(t, []string{"down", "up"}, patient.received(), "the recovery from an announced outage is sent")
+	assert.Equal(t, []string{"down", "up", "down", "up"}, eager.received())
+}
+
+// TestNotifierService_QuietHours follows a channel that is quiet for the hour
+// around now through an outage that ends while it is quiet
+func TestNotifierService_QuietHours(t *testing.T) {
+	fake := newSlackFake(t)
+	quiet := slackNotifier(1, fake.URL)
+	start := time.Now().UTC()
+	quiet.Filter = model.NotifierFilter{QuietHours: &model.QuietHours{
+		Start:    start.Add(-30 * time.Minute).Format("15:04"),
+		End:      start.Add(30 * time.Minute).Format("15:04"),
+		Timezone: "UTC",
+	}}
+	end, ok := quiet.Filter.QuietHours.EndOf(start)
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+	dispatcher, service, _, now := newTestDispatcher(t, quiet)
+
+	dispatch := func(at time.Time) {
+		*now = at
+		_, err := dispatcher.DispatchDue()
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "site", Status: "down", TargetID: 7, UpdatedAt: start, OutageStart: start}))
+	dispatch(start.Add(time.Minute))
+	assert.NoError(t, service.Enqueue(7, notification.State{Name: "site", Status: "up", PreviousStatus: "down", TargetID: 7, UpdatedAt: start}))
+	dispatch(start.Add(2 * time.Minute))
+	assert.Empty(t, fake.received(), "nothing is sent during quiet hours")
+
+	assert.NoError(t, service.Escalate([]int64{1}, notification.State{Name: "site", Status: "down", Message: "Target site is down", TargetID: 7}))
+	dispatch(start.Add(3 * time.Minute))
+	assert.Equal(t, []string{"down"}, fake.received(), "held messages do not hold back escalations")
+
+	dispatch(end.Add(time.Second))
+	assert.Equal(t, []string{"down", "down", "up"}, fake.received(), "held messages are sent once quiet hours end")
+}
+
+func TestNotifierService_Escalate(t *testing.T) {
+	own := newSlackFake(t)
+	secondary := newSlackFake(t)
+	filtered := slackNotifier(1, own.URL)
+	filtered.Filter = model.NotifierFilter{Statuses: []string{"up"}}
+	onCall := slackNotifier(2, secondary.URL)
+	onCall.TargetId = 9
+	dispatcher, service, _, _ := newTestDispatcher(t, filtered, onCall)
+
+	down := notification.State{Name: "site", Status: "down", Message: "Target site is down", TargetID: 7}
+	assert.NoError(t, service.Escalate([]int64{1, 2, 99}, down))
+	_, err := dispatcher.DispatchDue()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"down"}, own.received(), "escalations ignore filters")
+	assert.Equal(t, []string{"down"}, secondary.received(), "notifiers of other targets are reached")
+
+	assert.NoError(t, service.EscalateAcknowledgement([]int64{1, 2}, notification.State{Name: "site", Message: "Incident #3 acknowledged", TargetID: 7}))
+	attempts, err := dispatcher.DispatchDue()
+	assert.NoError(t, err)
+	assert.Zero(t, attempts, "only paging notifiers track incidents")
+}
+
+func TestNotifierService_Caching(t *testing.T) {
+	loads := 0
+	mockRepo := &mockNotifierRepository{
+		getByTargetIDFunc: func(targetID int) ([]*model.Notifier, error) {
+			loads++
+			return []*model.Notifier{{ID: int64(targetID * 10), TargetId: targetID, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{"webhook_url": "https://hooks.slack.com/test"}`)}}, nil
+		},
+		createFunc: func(notifier *model.Notifier) (*model.Notifier, error) {
+			return &model.Notifier{ID: 99}, nil
+		},
+		updateFunc: func(id int, config json.RawMessage) (*model.Notifier, error) {
+			return &model.Notifier{ID: int64(id), TargetId: id / 10, Type: model.NotifierTypeSlack, Config: config}, nil
+		},
+		deleteFunc:       func(id int64) error { return nil },
+		updateFilterFunc: func(id int64, filter model.NotifierFilter) error { return nil },
+	}
+	service := NewNotifierService(mockRepo, nil, nil, "")
+
+	load := func(targetID int) {
+		t.Helper()
+		_, err := service.notifiersFor(targetID)
+		assert.NoError(t, err)
+	}
+
+	load(1)
+	load(1)
+	load(2)
+	assert.Equal(t, 2, loads, "each target is loaded once")
+
+	tests := []struct {
+		name   string
+		change func() error
+	}{
+		{"create", func() error {
+			return service.Create(&model.Notifier{TargetId: 1, Type: model.NotifierTypeSlack, Config: json.RawMessage(`{}`)})
+		}},
+		{"update", func() error {
+			_, err := service.Update(10, json.RawMessage(`{"webhook_url": "https://hooks.slack.com/other"}`))
+			return err
+		}},
+		{"update filter", func() error { return service.UpdateFilter(10, model.NotifierFilter{Statuses: []string{"down"}}) }},
+		{"delete", func() error { return service.Delete(10) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			load(1)
+			load(2)
+			before := loads
+
+			assert.NoError(t, tt.change())
+			load(1)
+			load(2)
+			assert.Equal(t, before+1, loads, "only the changed target is loaded again")
+		})
+	}
+}
+
+// TestNotifierService_ConcurrentTargets flips many targets at once while
+// their notifiers are being edited, checking every channel hears only about
+// its own target and in the right order. Run it with -race.
+func TestNotifierService_ConcurrentTargets(t *testing.T) {
+	const targets = 40
+	const flips = 6
+
+	var mu sync.Mutex
+	received := make(map[int][]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		var channel int
+		fmt.Sscanf(r.URL.Path, "/channels/%d", &channel)
+
+		mu.Lock()
+		defer mu.Unlock()
+		var name, status string
+		for _, field := range msg.Attachments[0].Fields {
+			switch field.Title {
+			case "Name":
+				name = field.Value
+			case "Status":
+				status = field.Value
+			}
+		}
+		received[channel] = append(received[channel], name+" "+status)
+	}))
+	defer server.Close()
+
+	db := testutil.NewInMemoryDB()
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	mockRepo := &mockNotifierRepository{
+		getByTargetIDFunc: func(targetID int) ([]*model.Notifier, error) {
+			return []*model.Notifier{{
+				ID:       int64(targetID),
+				TargetId: targetID,
+				Type:     model.NotifierTypeSlack,
+				Config:   json.RawMessage(fmt.Sprintf(`{"webhook_url": "%s/channels/%d"}`, server.URL, targetID)),
+			}}, nil
+		},
+		updateFilterFunc: func(id int64, filter model.NotifierFilter) error { return nil },
+	}
+	service := NewNotifierService(mockRepo, repository.NewOutboxRepository(db), nil, "")
+	dispatcher := NewDispatcher(service)
+	dispatcher.Start()
+
+	statuses := []string{"down", "up"}
+	var wg sync.WaitGroup
+	for id := 1; id <= targets; id++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range flips {
+				state := notification.State{Name: fmt.Sprintf("target-%d", id), Status: statuses[i%2], TargetID: id}
+				assert.NoError(t, service.Enqueue(id, state))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range flips {
+				assert.NoError(t, service.UpdateFilter(int64(id), model.NotifierFilter{}))
+				notifiers, err := service.notifiersFor(id)
+				if assert.NoError(t, err) && assert.Len(t, notifiers, 1) {
+					assert.Equal(t, id, notifiers[0].TargetId)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for id := 1; id <= targets; id++ {
+			if len(received[id]) < flips {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	dispatcher.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for id := 1; id <= targets; id++ {
+		name := fmt.Sprintf("target-%d", id)
+		var queued []string
+		for _, message := range received[id] {
+			assert.True(t, strings.HasPrefix(message, name+" "), "channel %d was sent %q", id, message)
+			queued = append(queued, strings.TrimPrefix(message, name+" "))
+		}
+		assert.Equal(t, []string{"down", "up", "down", "up", "down", "up"}, queued, "channel %d", id)
+	}
+}
+
+func TestNotifierService_SendTest(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	box := &mailbox{}
+	service := NewNotifierService(&mockNotifierRepository{}, nil, NewEmailRecipientService(&mockEmailRecipientRepository{}, box.sender(t), ""), "")
+
+	t.Run("filter is ignored", func(t *testing.T) {
+		err := service.SendTest(&model.Notifier{
+			ID:     1,
+			Type:   model.NotifierTypeDiscord,
+			Config: json.RawMessage(`{"webhook_url": "` + server.URL + `"}`),
+			Filter: model.NotifierFilter{Statuses: []string{"down"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("teams", func(t *testing.T) {
+		err := service.SendTest(&model.Notifier{ID: 2, Type: model.NotifierTypeMSTeams, Config: json.RawMessage(`{"webhook_url": "` + server.URL + `"}`)})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("telegram", func(t *testing.T) {
+		originalURL := TelegramAPIURL
+		TelegramAPIURL = server.URL
+		defer func() { TelegramAPIURL = originalURL }()
+
+		err := service.SendTest(&model.Notifier{ID: 4, Type: model.NotifierTypeTelegram, Config: json.RawMessage(`{"bot_token": "123:abc", "chat_id": "42"}`)})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("email without confirmed recipients", func(t *testing.T) {
+		err := service.SendTest(emailNotifier(3, "ops@example.com"))
+		assert.ErrorIs(t, err, ErrNoConfirmedRecipients)
+		assert.Empty(t, box.mailers)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		err := service.SendTest(&model.Notifier{Type: "pager"})
+		assert.Error(t, err)
+	})
+
+	t.Run("requests time out", func(t *testing.T) {
+		client, ok := service.client.(*http.Client)
+		if assert.True(t, ok) {
+			assert.Equal(t, deliveryTimeout, client.Timeout)
+		}
+	})
 }
 
 func TestNotifierService_ParseOAuthState(t *testing.T) {
 	mockRepo := &mockNotifierRepository{}
-	service := NewNotifierService(mockRepo, nil)
+	service := NewNotifierService(mockRepo, nil, nil, "")
 
 	t.Run("successful parsing", func(t *testing.T) {
 		state := "target_id=1"
@@ -345,7 +807,7 @@ func TestNotifierService_HandleSlackCallback(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create service with mock repository
 			mockRepo := &mockNotifierRepository{}
-			service := NewNotifierService(mockRepo, nil)
+			service := NewNotifierService(mockRepo, nil, nil, "")
 
 			// Override the Slack API URL to point to our mock server
 			originalURL := SlackTokenURL
@@ -371,3 +833,28 @@ func TestNotifierService_HandleSlackCallback(t *testing.T) {
 		})
 	}
 }
+
+func TestNotifierService_UpdateFilter(t *testing.T) {
+	mockRepo := &mockNotifierRepository{}
+	service := NewNotifierService(mockRepo, nil, nil, "")
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.updateFilterFunc = func(id int64, filter model.NotifierFilter) error {
+			assert.Equal(t, int64(3), id)
+			assert.Equal(t, []string{"down", "degraded"}, filter.Statuses)
+			return nil
+		}
+
+		err := service.UpdateFilter(3, model.NotifierFilter{Statuses: []string{"down", "degraded"}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.updateFilterFunc = func(id int64, filter model.NotifierFilter) error {
+			return fmt.Errorf("db error")
+		}
+
+		err := service.UpdateFilter(3, model.NotifierFilter{})
+		assert.Error(t, err)
+	})
+}
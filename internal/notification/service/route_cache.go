@@ -0,0 +1,73 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+)
+
+// routeCache keeps the notifiers of each target between events so every
+// status change does not have to load them again. A target's entry is
+// dropped whenever one of its notifiers is added, edited or removed.
+//
+// The notifiers handed out are shared by every caller and must not be
+// modified.
+type routeCache struct {
+	mu      sync.RWMutex
+	targets map[int][]*model.Notifier
+	// generation changes on every invalidation, so a load that raced with
+	// one does not store what it read before the change
+	generation uint64
+}
+
+func newRouteCache() *routeCache {
+	return &routeCache{targets: make(map[int][]*model.Notifier)}
+}
+
+// load returns the target's notifiers, calling fetch to read them if they
+// are not cached
+func (c *routeCache) load(targetID int, fetch func(targetID int) ([]*model.Notifier, error)) ([]*model.Notifier, error) {
+	c.mu.RLock()
+	notifiers, ok := c.targets[targetID]
+	generation := c.generation
+	c.mu.RUnlock()
+	if ok {
+		return notifiers, nil
+	}
+
+	notifiers, err := fetch(targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.targets[targetID] = notifiers
+	}
+	c.mu.Unlock()
+	return notifiers, nil
+}
+
+// invalidateTarget forgets the notifiers of a target
+func (c *routeCache) invalidateTarget(targetID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	delete(c.targets, targetID)
+}
+
+// invalidateNotifier forgets the notifiers of whichever target the notifier
+// belongs to
+func (c *routeCache) invalidateNotifier(notifierID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for targetID, notifiers := range c.targets {
+		for _, notifier := range notifiers {
+			if notifier.ID == notifierID {
+				delete(c.targets, targetID)
+				break
+			}
+		}
+	}
+}
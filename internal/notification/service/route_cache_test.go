@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/shuvo-paul/uptimebot/internal/notification/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteCache(t *testing.T) {
+	loads := map[int]int{}
+	fetch := func(targetID int) ([]*model.Notifier, error) {
+		loads[targetID]++
+		return []*model.Notifier{{ID: int64(targetID * 10), TargetId: targetID}}, nil
+	}
+
+	t.Run("load", func(t *testing.T) {
+		cache := newRouteCache()
+		clear(loads)
+
+		notifiers, err := cache.load(1, fetch)
+		assert.NoError(t, err)
+		if assert.Len(t, notifiers, 1) {
+			assert.Equal(t, int64(10), notifiers[0].ID)
+		}
+		cache.load(1, fetch)
+		cache.load(2, fetch)
+		assert.Equal(t, map[int]int{1: 1, 2: 1}, loads)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		cache := newRouteCache()
+		failures := 0
+		failing := func(targetID int) ([]*model.Notifier, error) {
+			failures++
+			return nil, errors.New("database error")
+		}
+
+		_, err := cache.load(1, failing)
+		assert.Error(t, err)
+		_, err = cache.load(1, failing)
+		assert.Error(t, err)
+		assert.Equal(t, 2, failures)
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		cache := newRouteCache()
+		clear(loads)
+		cache.load(1, fetch)
+		cache.load(2, fetch)
+
+		cache.invalidateTarget(1)
+		cache.load(1, fetch)
+		cache.load(2, fetch)
+		assert.Equal(t, map[int]int{1: 2, 2: 1}, loads)
+
+		cache.invalidateNotifier(20)
+		cache.load(1, fetch)
+		cache.load(2, fetch)
+		assert.Equal(t, map[int]int{1: 2, 2: 2}, loads)
+
+		cache.invalidateNotifier(99)
+		cache.load(1, fetch)
+		cache.load(2, fetch)
+		assert.Equal(t, map[int]int{1: 2, 2: 2}, loads, "unknown notifiers leave the cache alone")
+	})
+
+	t.Run("change during load", func(t *testing.T) {
+		cache := newRouteCache()
+		clear(loads)
+
+		// The notifier is edited after the load read the old settings
+		cache.load(1, func(targetID int) ([]*model.Notifier, error) {
+			notifiers, err := fetch(targetID)
+			cache.invalidateTarget(targetID)
+			return notifiers, err
+		})
+		cache.load(1, fetch)
+		assert.Equal(t, 2, loads[1], "what was read before the change is not kept")
+	})
+}
+
+func TestRouteCache_Concurrent(t *testing.T) {
+	cache := newRouteCache()
+	fetch := func(targetID int) ([]*model.Notifier, error) {
+		return []*model.Notifier{{ID: int64(targetID), TargetId: targetID}}, nil
+	}
+
+	var wg sync.WaitGroup
+	for id := 1; id <= 50; id++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				notifiers, err := cache.load(id, fetch)
+				if assert.NoError(t, err) && assert.Len(t, notifiers, 1) {
+					assert.Equal(t, id, notifiers[0].TargetId)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				cache.invalidateNotifier(int64(id))
+				cache.invalidateTarget(id)
+			}
+		}()
+	}
+	wg.Wait()
+}
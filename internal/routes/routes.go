@@ -3,7 +3,9 @@ package routes
 import (
 	"net/http"
 
+	"github.com/shuvo-paul/uptimebot/internal/api"
 	authHandler "github.com/shuvo-paul/uptimebot/internal/auth/handler"
+	authModel "github.com/shuvo-paul/uptimebot/internal/auth/model"
 	authService "github.com/shuvo-paul/uptimebot/internal/auth/service"
 	"github.com/shuvo-paul/uptimebot/internal/middleware"
 	uptimeHandler "github.com/shuvo-paul/uptimebot/internal/monitor/handler"
@@ -18,7 +20,15 @@ func SetupRoutes(
 	sessionService authService.SessionService,
 	authService authService.AuthService,
 	targetHandler *uptimeHandler.TargetHandler,
+	incidentHandler *uptimeHandler.IncidentHandler,
+	maintenanceHandler *uptimeHandler.MaintenanceHandler,
+	statusPageHandler *uptimeHandler.StatusPageHandler,
+	escalationHandler *uptimeHandler.EscalationHandler,
+	badgeHandler *uptimeHandler.BadgeHandler,
 	notifierHandler *eventHandler.NotifierHandler,
+	apiHandler *api.Handler,
+	apiTokenHandler *authHandler.APITokenHandler,
+	apiTokenService authService.APITokenServiceInterface,
 ) http.Handler {
 	// Setup routes
 	mux := http.NewServeMux()
@@ -30,6 +40,8 @@ func SetupRoutes(
 	mux.HandleFunc("GET /login", userHandler.ShowLoginForm)
 	mux.HandleFunc("POST /login", userHandler.Login)
 	mux.HandleFunc("POST /logout", userHandler.Logout)
+	mux.HandleFunc("GET /status/{slug}", statusPageHandler.Public)
+	mux.HandleFunc("GET /notifiers/verify-email", notifierHandler.VerifyEmail)
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "GET" {
 			return
@@ -46,7 +58,29 @@ func SetupRoutes(
 	protected.HandleFunc("GET /{id}/edit", targetHandler.Edit)
 	protected.HandleFunc("POST /{id}/edit", targetHandler.Edit)
 	protected.HandleFunc("POST /{id}/delete", targetHandler.Delete)
+	protected.HandleFunc("POST /{id}/enable", targetHandler.Enable)
+	protected.HandleFunc("POST /{id}/disable", targetHandler.Disable)
+	protected.HandleFunc("GET /reports/monthly", targetHandler.MonthlyReport)
 
+	protected.HandleFunc("GET /{targetId}/maintenance", maintenanceHandler.List)
+	protected.HandleFunc("POST /{targetId}/maintenance", maintenanceHandler.Create)
+	protected.HandleFunc("POST /{targetId}/maintenance/{id}/delete", maintenanceHandler.Delete)
+
+	protected.HandleFunc("GET /{targetId}/badges", badgeHandler.Settings)
+	protected.HandleFunc("POST /{targetId}/badges", badgeHandler.Enable)
+	protected.HandleFunc("POST /{targetId}/badges/delete", badgeHandler.Disable)
+
+	protected.HandleFunc("GET /{targetId}/notifiers", notifierHandler.List)
+	protected.HandleFunc("POST /{targetId}/notifiers/{notifierId}/filter", notifierHandler.UpdateFilter)
+	protected.HandleFunc("POST /{targetId}/notifiers/webhook", notifierHandler.CreateWebhook)
+	protected.HandleFunc("POST /{targetId}/notifiers/discord", notifierHandler.CreateDiscord)
+	protected.HandleFunc("POST /{targetId}/notifiers/msteams", notifierHandler.CreateMSTeams)
+	protected.HandleFunc("POST /{targetId}/notifiers/telegram", notifierHandler.CreateTelegram)
+	protected.HandleFunc("POST /{targetId}/notifiers/pagerduty", notifierHandler.CreatePagerDuty)
+	protected.HandleFunc("POST /{targetId}/notifiers/opsgenie", notifierHandler.CreateOpsgenie)
+	protected.HandleFunc("POST /{targetId}/notifiers/email", notifierHandler.AddEmailRecipient)
+	protected.HandleFunc("POST /{targetId}/notifiers/{notifierId}/test", notifierHandler.SendTest)
+	protected.HandleFunc("POST /{targetId}/notifiers/{notifierId}/recipients/delete", notifierHandler.RemoveEmailRecipient)
 	protected.HandleFunc("GET /auth/slack/{targetId}", notifierHandler.AuthSlack)
 	protected.HandleFunc("GET /auth/slack/callback", notifierHandler.AuthSlackCallback)
 
@@ -56,11 +90,114 @@ func SetupRoutes(
 		authService,
 	))
 
+	incidents := http.NewServeMux()
+	incidents.HandleFunc("GET /", incidentHandler.List)
+	incidents.HandleFunc("GET /{id}", incidentHandler.Show)
+	incidents.HandleFunc("POST /{id}/acknowledge", incidentHandler.Acknowledge)
+	incidents.HandleFunc("POST /{id}/updates", incidentHandler.AddUpdate)
+
+	mux.Handle("/incidents/", middleware.RequireAuth(
+		http.StripPrefix("/incidents", incidents),
+		sessionService,
+		authService,
+	))
+
+	statusPages := http.NewServeMux()
+	statusPages.HandleFunc("GET /", statusPageHandler.List)
+	statusPages.HandleFunc("GET /create", statusPageHandler.Create)
+	statusPages.HandleFunc("POST /create", statusPageHandler.Create)
+	statusPages.HandleFunc("GET /{id}/edit", statusPageHandler.Edit)
+	statusPages.HandleFunc("POST /{id}/edit", statusPageHandler.Edit)
+	statusPages.HandleFunc("POST /{id}/delete", statusPageHandler.Delete)
+
+	mux.Handle("/status-pages/", middleware.RequireAuth(
+		http.StripPrefix("/status-pages", statusPages),
+		sessionService,
+		authService,
+	))
+
+	escalations := http.NewServeMux()
+	escalations.HandleFunc("GET /", escalationHandler.List)
+	escalations.HandleFunc("GET /create", escalationHandler.Create)
+	escalations.HandleFunc("POST /create", escalationHandler.Create)
+	escalations.HandleFunc("GET /{id}/edit", escalationHandler.Edit)
+	escalations.HandleFunc("POST /{id}/edit", escalationHandler.Edit)
+	escalations.HandleFunc("POST /{id}/delete", escalationHandler.Delete)
+
+	mux.Handle("/escalation-policies/", middleware.RequireAuth(
+		http.StripPrefix("/escalation-policies", escalations),
+		sessionService,
+		authService,
+	))
+
+	settings := http.NewServeMux()
+	settings.HandleFunc("GET /tokens", apiTokenHandler.List)
+	settings.HandleFunc("POST /tokens", apiTokenHandler.Create)
+	settings.HandleFunc("POST /tokens/{id}/delete", apiTokenHandler.Revoke)
+
+	mux.Handle("/settings/", middleware.RequireAuth(
+		http.StripPrefix("/settings", settings),
+		sessionService,
+		authService,
+	))
+
 	mws := middleware.CreateStack(
 		flash.Middleware,
 		csrf.Middleware,
 		middleware.ErrorHandler,
 		middleware.Logger,
 	)
-	return mws(mux)
+
+	// Heartbeat pings come from cron jobs and workers, which carry neither a
+	// session nor a CSRF token; the secret token in the URL authenticates them
+	pings := http.NewServeMux()
+	pings.HandleFunc("/ping/{token}", targetHandler.Ping)
+	pings.HandleFunc("/ping/{token}/{event}", targetHandler.Ping)
+
+	// Badges are embedded in READMEs and wikis, so they are served without
+	// sessions or cookies; the badge token in the URL selects the target
+	badges := http.NewServeMux()
+	badges.HandleFunc("GET /badge/{token}/{badge}", badgeHandler.Badge)
+
+	apiRoutes := http.NewServeMux()
+	apiRoutes.Handle("GET /targets", api.RequireScope(authModel.ScopeTargetsRead, apiHandler.ListTargets))
+	apiRoutes.Handle("POST /targets", api.RequireScope(authModel.ScopeTargetsWrite, apiHandler.CreateTarget))
+	apiRoutes.Handle("GET /targets/{id}", api.RequireScope(authModel.ScopeTargetsRead, apiHandler.GetTarget))
+	apiRoutes.Handle("PUT /targets/{id}", api.RequireScope(authModel.ScopeTargetsWrite, apiHandler.UpdateTarget))
+	apiRoutes.Handle("DELETE /targets/{id}", api.RequireScope(authModel.ScopeTargetsWrite, apiHandler.DeleteTarget))
+	apiRoutes.Handle("POST /targets/{id}/pause", api.RequireScope(authModel.ScopeTargetsWrite, apiHandler.PauseTarget))
+	apiRoutes.Handle("POST /targets/{id}/resume", api.RequireScope(authModel.ScopeTargetsWrite, apiHandler.ResumeTarget))
+	apiRoutes.Handle("GET /targets/{id}/results", api.RequireScope(authModel.ScopeTargetsRead, apiHandler.ListCheckResults))
+	apiRoutes.Handle("GET /targets/{id}/notifiers", api.RequireScope(authModel.ScopeNotifiersRead, apiHandler.ListNotifiers))
+	apiRoutes.Handle("POST /targets/{id}/notifiers", api.RequireScope(authModel.ScopeNotifiersWrite, apiHandler.CreateNotifier))
+	apiRoutes.Handle("GET /notifiers/{id}", api.RequireScope(authModel.ScopeNotifiersRead, apiHandler.GetNotifier))
+	apiRoutes.Handle("PUT /notifiers/{id}", api.RequireScope(authModel.ScopeNotifiersWrite, apiHandler.UpdateNotifier))
+	apiRoutes.Handle("DELETE /notifiers/{id}", api.RequireScope(authModel.ScopeNotifiersWrite, apiHandler.DeleteNotifier))
+	apiRoutes.Handle("GET /incidents", api.RequireScope(authModel.ScopeIncidentsRead, apiHandler.ListIncidents))
+	apiRoutes.Handle("GET /incidents/{id}", api.RequireScope(authModel.ScopeIncidentsRead, apiHandler.GetIncident))
+	apiRoutes.HandleFunc("/", apiHandler.NotFound)
+
+	// The API answers in JSON throughout and keeps out of the flash cookies
+	// pages use. Scripts authenticate with API tokens; the session cookie
+	// works too, so the app's own pages can call it.
+	apiV1 := http.NewServeMux()
+	apiV1.HandleFunc("GET /api/v1/openapi.json", apiHandler.OpenAPI)
+	apiV1.Handle("/api/v1/", http.StripPrefix("/api/v1", api.RequireAuth(apiRoutes, &sessionService, &authService, apiTokenService)))
+
+	root := http.NewServeMux()
+	root.Handle("/ping/", middleware.CreateStack(
+		middleware.ErrorHandler,
+		middleware.Logger,
+	)(pings))
+	root.Handle("/badge/", middleware.CreateStack(
+		middleware.ErrorHandler,
+		middleware.Logger,
+	)(badges))
+	root.Handle("/api/v1/", middleware.CreateStack(
+		api.CSRF,
+		middleware.ErrorHandler,
+		middleware.Logger,
+	)(apiV1))
+	root.Handle("/", mws(mux))
+	return root
 }
@@ -5,5 +5,11 @@ import "embed"
 //go:embed layouts/*.html
 //go:embed pages/*.html
 //go:embed pages/targets/*.html
+//go:embed pages/incidents/*.html
+//go:embed pages/status/*.html
+//go:embed pages/escalations/*.html
+//go:embed pages/settings/*.html
+//go:embed pages/notifiers/*.html
 //go:embed emails/*.html
+//go:embed emails/*.txt
 var TemplateFS embed.FS
@@ -0,0 +1,80 @@
+// Package badge renders flat, shields.io style SVG badges
+package badge
+
+import (
+	"fmt"
+	"html"
+)
+
+// Colours used by shields.io, which badge readers already know
+const (
+	ColorBrightGreen = "#4c1"
+	ColorGreen       = "#97ca00"
+	ColorYellow      = "#dfb317"
+	ColorOrange      = "#fe7d37"
+	ColorRed         = "#e05d44"
+	ColorBlue        = "#007ec6"
+	ColorLightGrey   = "#9f9f9f"
+)
+
+// Badge is a label on the left and a coloured message on the right
+type Badge struct {
+	Label   string
+	Message string
+	Color   string
+}
+
+// horizontal padding on each side of the label and the message
+const padding = 6
+
+// SVG renders the badge. Text is measured approximately, which is close
+// enough for the short labels badges carry.
+func (b Badge) SVG() []byte {
+	labelWidth := textWidth(b.Label) + 2*padding
+	messageWidth := textWidth(b.Message) + 2*padding
+	width := labelWidth + messageWidth
+
+	label := html.EscapeString(b.Label)
+	message := html.EscapeString(b.Message)
+	color := html.EscapeString(b.Color)
+
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[7]d" y="14">%[4]s</text>`+
+		`<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text><text x="%[8]d" y="14">%[5]s</text>`+
+		`</g></svg>`,
+		width, labelWidth, messageWidth, label, message, color, labelWidth/2, labelWidth+messageWidth/2,
+	))
+}
+
+// narrowChars are noticeably narrower than the average glyph in 11px Verdana
+var narrowChars = map[rune]int{
+	' ': 4, '.': 4, ',': 4, ':': 4, ';': 4, '!': 4, '|': 4, '\'': 3,
+	'i': 3, 'j': 3, 'l': 3, 'f': 4, 't': 4, 'r': 5, 'I': 4,
+	'(': 5, ')': 5, '[': 5, ']': 5, '-': 5,
+}
+
+// wideChars are noticeably wider than the average glyph in 11px Verdana
+var wideChars = map[rune]int{
+	'm': 11, 'w': 9, 'M': 10, 'W': 11, '%': 12, '@': 11,
+}
+
+// textWidth estimates the rendered width of s in pixels
+func textWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case narrowChars[r] > 0:
+			width += narrowChars[r]
+		case wideChars[r] > 0:
+			width += wideChars[r]
+		default:
+			width += 7
+		}
+	}
+	return width
+}
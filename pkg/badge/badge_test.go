@@ -0,0 +1,32 @@
+package badge
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadge_SVG(t *testing.T) {
+	svg := string(Badge{Label: "uptime 30d", Message: "99.95%", Color: ColorBrightGreen}.SVG())
+
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	assert.Contains(t, svg, `aria-label="uptime 30d: 99.95%"`)
+	assert.Contains(t, svg, `fill="#4c1"`)
+	assert.NoError(t, xml.Unmarshal([]byte(svg), new(any)), "the badge is well-formed XML")
+}
+
+func TestBadge_SVGEscapesText(t *testing.T) {
+	svg := string(Badge{Label: "<script>", Message: `"&"`, Color: ColorRed}.SVG())
+
+	assert.NotContains(t, svg, "<script>")
+	assert.Contains(t, svg, "&lt;script&gt;")
+	assert.NoError(t, xml.Unmarshal([]byte(svg), new(any)))
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.Equal(t, 0, textWidth(""))
+	assert.Less(t, textWidth("iii"), textWidth("aaa"))
+	assert.Less(t, textWidth("aaa"), textWidth("mmm"))
+}